@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// doctorSources are the files croptop reads, by what they feed. Patterns
+// match hardware that may be absent, which is not a problem.
+var doctorSources = []struct {
+	path    string
+	feature string
+}{
+	{"/proc/stat", "CPU usage, fork rate"},
+	{"/proc/cpuinfo", "CPU model and frequency"},
+	{"/proc/loadavg", "load average"},
+	{"/proc/meminfo", "memory and swap"},
+	{"/proc/vmstat", "swap activity"},
+	{"/proc/self/smaps_rollup", "PSS/USS (accurate memory mode)"},
+	{"/proc/pressure/memory", "memory pressure (PSI)"},
+	{"/proc/sysvipc/shm", "System V shared memory"},
+	{"/proc/slabinfo", "slab caches"},
+	{"/proc/net/dev", "network rates"},
+	{"/proc/net/snmp", "TCP/UDP protocol health"},
+	{"/proc/net/tcp", "listening sockets"},
+	{"/proc/diskstats", "disk I/O"},
+	{"/proc/mounts", "disk usage"},
+	{"/proc/self/cgroup", "cgroup limits"},
+	{"/sys/class/hwmon/hwmon*/temp*_input", "temperatures"},
+	{"/sys/class/thermal/thermal_zone*/temp", "temperatures (fallback)"},
+	{"/sys/class/power_supply/BAT*", "battery"},
+	{"/sys/class/backlight/*", "backlight"},
+	{"/sys/class/powercap/intel-rapl:*/energy_uj", "CPU power (RAPL)"},
+	{"/dev/kmsg", "kernel log"},
+	{"/dev/kvm", "VMs tab"},
+	{"/var/log/containers", "Pods tab (Kubernetes nodes)"},
+	{"/var/log/auth.log", "failed SSH logins (without the journal)"},
+	{"/var/run/docker.sock", "Containers tab (Docker)"},
+	{"/run/podman/podman.sock", "Containers tab (rootful Podman)"},
+}
+
+// doctorTools are the programs croptop runs
+var doctorTools = []struct {
+	name    string
+	feature string
+}{
+	{"journalctl", "kernel log fallback, failed SSH logins"},
+	{"upower", "peripheral batteries"},
+	{"powerprofilesctl", "switching power profiles"},
+	{"busctl", "logind inhibitor locks, systemd unit actions"},
+	{"loginctl", "login sessions (Users tab)"},
+	{"systemd-analyze", "Boot tab"},
+	{"ip", "neighbor table"},
+	{"pw-dump", "PipeWire audio"},
+	{"pactl", "PulseAudio audio"},
+	{"dpkg-query", "owning package of executables (Debian)"},
+	{"rpm", "owning package of executables (RPM)"},
+	{"sqlite3", "sqlite history backend"},
+	{"notify-send", "desktop notifications of alerts"},
+	{"systemctl", "install-agent"},
+	{"setcap", "grant-caps"},
+}
+
+// runDoctor implements `croptop doctor`, reporting which data sources,
+// tools and permissions croptop has, to explain N/A values
+func runDoctor(args []string) int {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	configPath := flags.String("config", config.DefaultPath(), "path to the config file")
+	flags.Parse(args)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "Config")
+	if _, err := config.Load(*configPath); err != nil {
+		fmt.Fprintf(w, "  ✗\t%s\t%v\n", *configPath, err)
+	} else if _, err := os.Stat(*configPath); errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(w, "  -\t%s\tnot present, using the defaults\n", *configPath)
+	} else {
+		fmt.Fprintf(w, "  ✓\t%s\tvalid\n", *configPath)
+	}
+
+	switch version := collector.WSLVersion(); version {
+	case 1:
+		fmt.Fprintln(w, "\nEnvironment")
+		fmt.Fprintf(w, "  ✓\tWSL %d\tmemory is Windows', no battery, process and disk data are translated\n", version)
+	case 2:
+		fmt.Fprintln(w, "\nEnvironment")
+		fmt.Fprintf(w, "  ✓\tWSL %d\tmemory and disks are the VM's, no battery\n", version)
+	}
+
+	fmt.Fprintln(w, "\nSources")
+	for _, source := range doctorSources {
+		mark, detail := checkSource(source.path)
+		fmt.Fprintf(w, "  %s\t%s\t%s%s\n", mark, source.path, source.feature, detail)
+	}
+
+	fmt.Fprintln(w, "\nTools")
+	for _, tool := range doctorTools {
+		if path, err := exec.LookPath(tool.name); err == nil {
+			fmt.Fprintf(w, "  ✓\t%s\t%s (%s)\n", tool.name, tool.feature, path)
+		} else {
+			fmt.Fprintf(w, "  -\t%s\t%s: not installed\n", tool.name, tool.feature)
+		}
+	}
+
+	stats := collector.NewStatsCollector()
+	// Failing shows up in the permission report
+	stats.StartProcEvents()
+	fmt.Fprintln(w, "\nPermissions")
+	for _, privilege := range stats.DetectPrivileges() {
+		if privilege.Available {
+			fmt.Fprintf(w, "  ✓\t%s\t%s\n", privilege.Feature, privilege.Description)
+		} else {
+			fmt.Fprintf(w, "  ✗\t%s\t%s: %s\n", privilege.Feature, privilege.Description, privilege.Hint)
+		}
+	}
+
+	fmt.Fprintln(w, "\nCollectors")
+	stats.GetSystemStats()
+	for _, domain := range stats.CollectorHealth() {
+		if domain.Consecutive == 0 {
+			fmt.Fprintf(w, "  ✓\t%s\tanswered\n", domain.Name)
+		} else {
+			fmt.Fprintf(w, "  ✗\t%s\ttimed out after %s\n", domain.Name, collector.DomainTimeout)
+		}
+	}
+
+	fmt.Fprintln(w, "\n✓ available  - absent on this machine  ✗ present but unusable")
+	return 0
+}
+
+// checkSource reads a file, or the first match of a pattern
+func checkSource(pattern string) (string, string) {
+	matches, _ := filepath.Glob(pattern)
+	if len(matches) == 0 {
+		return "-", ": not present"
+	}
+
+	info, err := os.Stat(matches[0])
+	if err != nil {
+		return "✗", ": " + err.Error()
+	}
+	// Sockets do not open like files, the API behind them must answer
+	if info.Mode()&fs.ModeSocket != 0 {
+		conn, err := net.DialTimeout("unix", matches[0], time.Second)
+		if err != nil {
+			return "✗", ": " + err.Error()
+		}
+		conn.Close()
+		return "✓", ""
+	}
+	if info.IsDir() {
+		if _, err := os.ReadDir(matches[0]); err != nil {
+			return "✗", ": " + err.Error()
+		}
+		return "✓", ""
+	}
+
+	// Non-blocking, /dev/kmsg waits for the next message otherwise
+	fd, err := syscall.Open(matches[0], syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return "✗", ": " + err.Error()
+	}
+	defer syscall.Close(fd)
+	// sysfs attributes may open fine and fail on read; devices need no read
+	if info.Mode()&fs.ModeDevice != 0 {
+		return "✓", ""
+	}
+	if _, err := syscall.Read(fd, make([]byte, 8192)); err != nil && err != syscall.EAGAIN {
+		return "✗", ": " + err.Error()
+	}
+	return "✓", ""
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fileCapabilities are the capabilities that unlock croptop's privileged
+// features (see collector.DetectPrivileges), each granted only when its
+// flag asks for it
+var fileCapabilities = []struct {
+	flag   string
+	name   string
+	reason string
+}{
+	{"process-io", "cap_sys_ptrace", "read /proc/<pid>/io of other users' processes (I/O tab)"},
+	{"root-files", "cap_dac_read_search", "read root-only files: RAPL energy counters, /proc/slabinfo, /var/log/auth.log"},
+	{"kernel-log", "cap_syslog", "read the kernel log from /dev/kmsg (Kernel tab)"},
+	{"exec-events", "cap_net_admin", "subscribe to exec events of the proc connector (Processes tab)"},
+	{"capture", "cap_net_raw", "capture packet headers for the top talkers (Network tab, with -capture-traffic)"},
+	{"signal", "cap_kill", "send signals to other users' processes (Processes tab)"},
+	{"renice", "cap_sys_nice", "renice other users' processes and raise priorities (Processes tab)"},
+}
+
+// runGrantCaps implements `croptop grant-caps [flags]`, setting the file
+// capabilities of the features asked for on the croptop binary with setcap
+func runGrantCaps(args []string) int {
+	fs := flag.NewFlagSet("grant-caps", flag.ExitOnError)
+	wanted := make([]*bool, len(fileCapabilities))
+	for i, capability := range fileCapabilities {
+		wanted[i] = fs.Bool(capability.flag, false, fmt.Sprintf("grant %s to %s", capability.name, capability.reason))
+	}
+	group := fs.String("group", "", "only let members of this group run croptop (chgrp and mode 0750), so no one else gains the capabilities")
+	printOnly := fs.Bool("print", false, "explain and print the commands without running them")
+	remove := fs.Bool("remove", false, "remove the capabilities again")
+	fs.Parse(args)
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop grant-caps: locating the croptop binary: %v\n", err)
+		return 1
+	}
+
+	var commands [][]string
+	if *remove {
+		commands = append(commands, []string{"setcap", "-r", executable})
+	} else {
+		var names []string
+		for i, capability := range fileCapabilities {
+			if *wanted[i] {
+				names = append(names, capability.name)
+			}
+		}
+		if len(names) == 0 {
+			fmt.Fprintln(os.Stderr, "croptop grant-caps: name the features to unlock:")
+			for _, capability := range fileCapabilities {
+				fmt.Fprintf(os.Stderr, "  -%-12s %-20s %s\n", capability.flag, capability.name, capability.reason)
+			}
+			return 2
+		}
+
+		if *group != "" {
+			fmt.Fprintf(os.Stderr, "croptop gets these capabilities, members of %s running it gain them:\n", *group)
+		} else {
+			fmt.Fprintln(os.Stderr, "croptop gets these capabilities, every user running it gains them (-group limits that):")
+		}
+		for i, capability := range fileCapabilities {
+			if *wanted[i] {
+				fmt.Fprintf(os.Stderr, "  %-20s %s\n", capability.name, capability.reason)
+			}
+		}
+		// Changing the owner of a file clears its capabilities, so the group
+		// comes first
+		if *group != "" {
+			commands = append(commands,
+				[]string{"chgrp", *group, executable},
+				[]string{"chmod", "0750", executable})
+		}
+		commands = append(commands, []string{"setcap", strings.Join(names, ",") + "+ep", executable})
+	}
+
+	if *printOnly {
+		for _, command := range commands {
+			fmt.Println(strings.Join(command, " "))
+		}
+		return 0
+	}
+	if os.Geteuid() != 0 {
+		fmt.Fprintln(os.Stderr, "croptop grant-caps: must run as root (try -print to see the commands)")
+		return 1
+	}
+
+	if *group != "" && !*remove {
+		if err := restrictToGroup(executable, *group); err != nil {
+			fmt.Fprintf(os.Stderr, "croptop grant-caps: %v\n", err)
+			return 1
+		}
+	}
+	command := commands[len(commands)-1]
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "croptop grant-caps: %s: %v (setcap is in the libcap2-bin or libcap package)\n", strings.Join(command, " "), err)
+		return 1
+	}
+
+	if *remove {
+		fmt.Fprintf(os.Stderr, "Removed the capabilities of %s\n", executable)
+	} else {
+		fmt.Fprintf(os.Stderr, "Granted to %s. Replacing the binary, e.g. on upgrade, drops them; run grant-caps again then.\n", executable)
+	}
+	return 0
+}
+
+// restrictToGroup gives the binary to group and makes it executable only by
+// its owner and the group's members
+func restrictToGroup(executable, group string) error {
+	found, err := user.LookupGroup(group)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(found.Gid)
+	if err != nil {
+		return fmt.Errorf("group %s: %w", group, err)
+	}
+	if err := os.Chown(executable, -1, gid); err != nil {
+		return err
+	}
+	return os.Chmod(executable, 0o750)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// The system-wide agent service
+const (
+	agentUnit       = "croptop-agent.service"
+	agentUnitDir    = "/etc/systemd/system"
+	agentConfigPath = "/etc/croptop/config.json"
+)
+
+// agentUnitTemplate runs `croptop serve` as a throwaway user in a sandbox.
+// The agent only reads /proc and /sys and talks to the network, so the
+// filesystem is read-only but for the state directory, and every capability
+// is dropped; processes of other users are still listed, but without their
+// per-process I/O.
+var agentUnitTemplate = template.Must(template.New("unit").Parse(`[Unit]
+Description=croptop agent
+Documentation=https://github.com/prabalesh/croptop
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+ExecStart={{.ExecStart}}
+Restart=on-failure
+RestartSec=5s
+DynamicUser=yes
+CapabilityBoundingSet=
+AmbientCapabilities=
+NoNewPrivileges=yes
+ProtectSystem=strict
+ProtectHome=yes
+PrivateTmp=yes
+# Where a self-signed TLS certificate and the history are kept
+StateDirectory=croptop
+PrivateDevices=yes
+ProtectKernelTunables=yes
+ProtectKernelModules=yes
+ProtectKernelLogs=yes
+ProtectControlGroups=yes
+ProtectClock=yes
+ProtectHostname=yes
+RestrictAddressFamilies=AF_UNIX AF_INET AF_INET6 AF_NETLINK
+RestrictNamespaces=yes
+RestrictRealtime=yes
+RestrictSUIDSGID=yes
+LockPersonality=yes
+MemoryDenyWriteExecute=yes
+SystemCallArchitectures=native
+SystemCallFilter=@system-service
+SystemCallFilter=~@privileged @resources
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// runInstallAgent implements `croptop install-agent [flags]`, installing and
+// starting a systemd service running `croptop serve` at boot
+func runInstallAgent(args []string) int {
+	fs := flag.NewFlagSet("install-agent", flag.ExitOnError)
+	configPath := fs.String("config", agentConfigPath, "config file of the agent")
+	listen := fs.String("listen", "", "address of the /metrics endpoint (overrides the config file, \"off\" disables it)")
+	printUnit := fs.Bool("print", false, "print the unit instead of installing it")
+	noStart := fs.Bool("no-start", false, "install and enable the service without starting it")
+	fs.Parse(args)
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop install-agent: locating the croptop binary: %v\n", err)
+		return 1
+	}
+	config, err := filepath.Abs(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop install-agent: %v\n", err)
+		return 2
+	}
+
+	command := []string{executable, "serve", "-config", config}
+	if *listen != "" {
+		command = append(command, "-listen", *listen)
+	}
+	var unit strings.Builder
+	agentUnitTemplate.Execute(&unit, struct{ ExecStart string }{unitCommand(command)})
+
+	if *printUnit {
+		fmt.Print(unit.String())
+		return 0
+	}
+	if os.Geteuid() != 0 {
+		fmt.Fprintln(os.Stderr, "croptop install-agent: must run as root (try -print to see the unit)")
+		return 1
+	}
+
+	path := filepath.Join(agentUnitDir, agentUnit)
+	if err := os.WriteFile(path, []byte(unit.String()), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "croptop install-agent: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+
+	enable := []string{"enable", agentUnit}
+	if !*noStart {
+		enable = []string{"enable", "--now", agentUnit}
+	}
+	for _, args := range [][]string{{"daemon-reload"}, enable} {
+		if err := systemctl(args...); err != nil {
+			fmt.Fprintf(os.Stderr, "croptop install-agent: %v\n", err)
+			return 1
+		}
+	}
+
+	if _, err := os.Stat(config); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "%s does not exist, the agent runs with the default settings\n", config)
+	}
+	fmt.Fprintf(os.Stderr, "Follow the agent with: journalctl -u %s -f\n", agentUnit)
+	return 0
+}
+
+// runUninstallAgent implements `croptop uninstall-agent`, stopping the
+// service and removing its unit. The config file is left in place.
+func runUninstallAgent(args []string) int {
+	fs := flag.NewFlagSet("uninstall-agent", flag.ExitOnError)
+	fs.Parse(args)
+
+	if os.Geteuid() != 0 {
+		fmt.Fprintln(os.Stderr, "croptop uninstall-agent: must run as root")
+		return 1
+	}
+
+	path := filepath.Join(agentUnitDir, agentUnit)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "croptop uninstall-agent: %s is not installed\n", agentUnit)
+		return 1
+	}
+	if err := systemctl("disable", "--now", agentUnit); err != nil {
+		fmt.Fprintf(os.Stderr, "croptop uninstall-agent: %v\n", err)
+		return 1
+	}
+	if err := os.Remove(path); err != nil {
+		fmt.Fprintf(os.Stderr, "croptop uninstall-agent: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Removed %s\n", path)
+	if err := systemctl("daemon-reload"); err != nil {
+		fmt.Fprintf(os.Stderr, "croptop uninstall-agent: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func systemctl(args ...string) error {
+	cmd := exec.Command("systemctl", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// unitCommand quotes a command line for ExecStart. systemd expands % and $
+// even inside quotes, so those are escaped separately.
+func unitCommand(command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		arg = strings.ReplaceAll(arg, "%", "%%")
+		arg = strings.ReplaceAll(arg, "$", "$$")
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\;") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
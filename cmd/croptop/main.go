@@ -1,20 +1,131 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/crash"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/logging"
 	"github.com/prabalesh/croptop/internal/ui"
 )
 
 func main() {
-	app := ui.NewApp()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "watch":
+			os.Exit(runWatch(os.Args[2:]))
+		case "serve":
+			os.Exit(runServe(os.Args[2:]))
+		case "install-agent":
+			os.Exit(runInstallAgent(os.Args[2:]))
+		case "uninstall-agent":
+			os.Exit(runUninstallAgent(os.Args[2:]))
+		case "doctor":
+			os.Exit(runDoctor(os.Args[2:]))
+		case "grant-caps":
+			os.Exit(runGrantCaps(os.Args[2:]))
+		case "token":
+			os.Exit(runToken(os.Args[2:]))
+		}
+	}
+
+	configPath := flag.String("config", config.DefaultPath(), "path to the config file")
+	logLevel := flag.String("log-level", "", "log level: debug, info, warn or error (overrides the config file)")
+	logFile := flag.String("log-file", "", "path to the log file (default "+logging.DefaultPath()+")")
+	readOnly := flag.Bool("read-only", false, "disable every action that changes the system")
+	captureTraffic := flag.Bool("capture-traffic", false, "capture packet headers to show the top talkers in the Network tab (needs CAP_NET_RAW)")
+	flag.Parse()
 
-	p := tea.NewProgram(app, tea.WithAltScreen())
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop: %v\n", err)
+		os.Exit(2)
+	}
 
-	if _, err := p.Run(); err != nil {
+	if *logLevel != "" {
+		if err := cfg.Log.Level.UnmarshalText([]byte(*logLevel)); err != nil {
+			fmt.Fprintf(os.Stderr, "croptop: -log-level: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	if *readOnly {
+		cfg.ReadOnly = true
+	}
+	if *captureTraffic {
+		cfg.CaptureTraffic = true
+	}
+	if *logFile != "" {
+		cfg.Log.File = *logFile
+	}
+	if cfg.Log.File == "" {
+		cfg.Log.File = logging.DefaultPath()
+	}
+	if closer, err := logging.Setup(cfg.Log.File, cfg.Log.Level); err != nil {
+		fmt.Fprintf(os.Stderr, "croptop: logging disabled: %v\n", err)
+	} else {
+		defer closer.Close()
+	}
+	slog.Info("starting", "config", cfg.Path, "log_level", cfg.Log.Level)
+
+	if err := i18n.SetLocale(cfg.Locale); err != nil {
+		fmt.Fprintf(os.Stderr, "croptop: %v\n", err)
+		os.Exit(2)
+	}
+
+	app, err := ui.NewApp(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop: %s: %v\n", *configPath, err)
+		os.Exit(2)
+	}
+
+	// Panics are handled by crashHandler, which also covers the collector's
+	// own goroutines that Bubble Tea knows nothing about
+	p := tea.NewProgram(crash.Model{Model: app}, tea.WithAltScreen(), tea.WithReportFocus(), tea.WithoutCatchPanics())
+	crash.SetHandler(crashHandler(p, app))
+
+	err = run(p)
+	app.Close()
+	if err != nil {
+		slog.Error("program failed", "err", err)
 		log.Printf("Error running program: %v", err)
 		os.Exit(1)
 	}
+	slog.Info("exiting")
+}
+
+// run runs the program, recovering panics of Update and View
+func run(p *tea.Program) error {
+	defer crash.Recover()
+	_, err := p.Run()
+	return err
+}
+
+// crashHandler restores the terminal, writes a crash report and exits. Only
+// the first of several panicking goroutines gets to report.
+func crashHandler(p *tea.Program, app *ui.App) func(value any, stack []byte) {
+	var once sync.Once
+	return func(value any, stack []byte) {
+		once.Do(func() {
+			_ = p.ReleaseTerminal()
+
+			slog.Error("panic", "value", value, "stack", string(stack))
+			path, err := crash.WriteReport(crash.DefaultDir(), value, stack, app.Snapshot())
+			fmt.Fprintf(os.Stderr, "croptop crashed: %v\n", value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not write the crash report: %v\n\n%s", err, stack)
+			} else {
+				fmt.Fprintf(os.Stderr, "a crash report was written to %s\n", path)
+			}
+			os.Exit(1)
+		})
+		// Another goroutine is reporting and exits the process
+		select {}
+	}
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/prabalesh/croptop/internal/agent"
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// runServe implements `croptop serve [flags]`, running headless as an agent
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := fs.String("config", config.DefaultPath(), "path to the config file")
+	listen := fs.String("listen", "", "address of the /metrics endpoint (overrides the config file, \"off\" disables it)")
+	announce := fs.Bool("announce", false, "announce the agent on the LAN with mDNS, for the Hosts tab to find")
+	logLevel := fs.String("log-level", "", "log level: debug, info, warn or error (overrides the config file)")
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop serve: %v\n", err)
+		return 2
+	}
+	switch *listen {
+	case "":
+	case "off":
+		cfg.Serve.Listen = ""
+	default:
+		cfg.Serve.Listen = *listen
+	}
+	if *announce {
+		cfg.Serve.Announce = true
+	}
+	if *logLevel != "" {
+		if err := cfg.Log.Level.UnmarshalText([]byte(*logLevel)); err != nil {
+			fmt.Fprintf(os.Stderr, "croptop serve: -log-level: %v\n", err)
+			return 2
+		}
+	}
+
+	// Without a TUI, the log goes to stderr (and the journal under systemd)
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: cfg.Log.Level})))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	headless, err := agent.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop serve: %s: %v\n", cfg.Path, err)
+		return 2
+	}
+	if err := headless.Run(ctx); err != nil {
+		slog.Error("agent failed", "err", err)
+		return 1
+	}
+	return 0
+}
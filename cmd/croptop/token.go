@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// tokenUsage describes the actions of `croptop token`
+const tokenUsage = `usage: croptop token [-config path] new | list | revoke <prefix>
+  new     creates a token, adds it to serve.auth.tokens and prints it
+  list    lists the tokens by their first characters
+  revoke  removes the token starting with prefix`
+
+// tokenPrefix is how much of a token list shows, enough to tell tokens
+// apart and to revoke one
+const tokenPrefix = 8
+
+// runToken implements `croptop token [flags] <action>`, managing the bearer
+// tokens `croptop serve` accepts in the config file. A running agent picks
+// changes up on restart.
+func runToken(args []string) int {
+	fs := flag.NewFlagSet("token", flag.ExitOnError)
+	configPath := fs.String("config", config.DefaultPath(), "path to the config file")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, tokenUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop token: %v\n", err)
+		return 2
+	}
+	auth := cfg.Serve.Auth
+
+	switch fs.Arg(0) {
+	case "new":
+		secret := make([]byte, 32)
+		rand.Read(secret)
+		token := hex.EncodeToString(secret)
+		auth.Tokens = append(auth.Tokens, token)
+		if _, err := config.SaveServeAuth(cfg.Path, auth); err != nil {
+			fmt.Fprintf(os.Stderr, "croptop token: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Added a token to %s; give it to clients as \"Authorization: Bearer <token>\":\n", cfg.Path)
+		fmt.Println(token)
+	case "list":
+		for _, token := range auth.Tokens {
+			fmt.Printf("%s…\n", token[:min(tokenPrefix, len(token))])
+		}
+	case "revoke":
+		prefix := fs.Arg(1)
+		if prefix == "" {
+			fs.Usage()
+			return 2
+		}
+		var matches []int
+		for i, token := range auth.Tokens {
+			if strings.HasPrefix(token, prefix) {
+				matches = append(matches, i)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			fmt.Fprintf(os.Stderr, "croptop token: no token starts with %q\n", prefix)
+			return 1
+		case 1:
+		default:
+			fmt.Fprintf(os.Stderr, "croptop token: %d tokens start with %q, give more of it\n", len(matches), prefix)
+			return 1
+		}
+		auth.Tokens = slices.Delete(auth.Tokens, matches[0], matches[0]+1)
+		if _, err := config.SaveServeAuth(cfg.Path, auth); err != nil {
+			fmt.Fprintf(os.Stderr, "croptop token: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Revoked the token starting with %s\n", prefix)
+	default:
+		fs.Usage()
+		return 2
+	}
+	return 0
+}
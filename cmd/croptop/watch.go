@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/watch"
+)
+
+// runWatch implements `croptop watch [flags] -- <command> [args...]`
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", time.Second, "sampling interval")
+	output := fs.String("o", "", "write the JSON resource profile to this file")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: croptop watch [flags] -- <command> [args...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	profile, err := watch.Run(fs.Args(), watch.Options{
+		Interval: *interval,
+		Output:   *output,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "croptop watch: %v\n", err)
+		if profile.ExitCode < 0 {
+			return 1
+		}
+	}
+
+	watch.PrintProfile(os.Stderr, profile)
+	if *output != "" && err == nil {
+		fmt.Fprintf(os.Stderr, "Profile written to %s\n", *output)
+	}
+
+	return profile.ExitCode
+}
@@ -0,0 +1,388 @@
+// Package agent runs croptop headless: it collects stats on an interval,
+// serves them over HTTP, pushes them to the configured exporters and sends
+// alerts and detected problems as events.
+package agent
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/alert"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/events"
+	"github.com/prabalesh/croptop/internal/export"
+	"github.com/prabalesh/croptop/internal/history"
+	"github.com/prabalesh/croptop/internal/mdns"
+	"github.com/prabalesh/croptop/internal/models"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// Agent holds the most recent collection for the HTTP endpoints and streams
+// it to subscribers
+type Agent struct {
+	collector *collector.StatsCollector
+	cfg       *config.Config
+	labels    []export.Label
+	exporters []export.Exporter
+	alerts    *alert.Engine
+	events    *events.Emitter
+	detector  events.Detector
+	host      string
+	// Nil unless history is enabled
+	history      *history.Store
+	historyExprs []alert.Expr
+
+	mutex       sync.Mutex
+	samples     []export.Sample
+	last        snapshot
+	subscribers map[chan snapshot]struct{}
+}
+
+// snapshot is one collection
+type snapshot struct {
+	time      time.Time
+	stats     models.SystemStats
+	processes models.ProcessList
+	// The alerts firing after this collection
+	alerts []alert.Alert
+}
+
+func New(cfg *config.Config) (*Agent, error) {
+	rules, err := alert.ParseRules(cfg.Alerts)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]export.Label, 0, len(cfg.Export.Labels))
+	for name, value := range cfg.Export.Labels {
+		labels = append(labels, export.Label{Name: name, Value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+
+	host, _ := os.Hostname()
+	a := &Agent{
+		collector:   collector.NewStatsCollector(),
+		host:        host,
+		cfg:         cfg,
+		labels:      labels,
+		exporters:   export.New(cfg.Export),
+		alerts:      alert.NewEngine(rules),
+		events:      events.New(cfg.Events),
+		subscribers: make(map[chan snapshot]struct{}),
+	}
+	if cfg.History.Enabled {
+		if a.historyExprs, err = historyExprs(cfg.History.Metrics); err != nil {
+			return nil, err
+		}
+		if a.history, err = history.Open(cfg.History, a.historyPath()); err != nil {
+			return nil, fmt.Errorf("opening the history: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// Run collects and exports until ctx is done. The HTTP server only runs
+// when an address to listen on is configured.
+func (a *Agent) Run(ctx context.Context) error {
+	errs := make(chan error, 1)
+	defer a.closeSubscribers()
+	defer a.events.Close(eventsCloseTimeout)
+	if a.history != nil {
+		defer func() {
+			if err := a.history.Close(); err != nil {
+				slog.Warn("closing the history failed", "err", err)
+			}
+		}()
+	}
+	if listen := a.cfg.Serve.Listen; listen != "" {
+		server := &http.Server{Addr: listen, Handler: a.Handler()}
+		// gRPC clients connect with HTTP/2 prior knowledge without TLS, and
+		// negotiate it with TLS
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+		if a.cfg.Serve.TLS.Enabled() {
+			tlsConfig, err := a.tlsConfig()
+			if err != nil {
+				return err
+			}
+			server.TLSConfig = tlsConfig
+			server.Protocols.SetHTTP2(true)
+		}
+		if !a.cfg.Serve.Auth.Enabled() && !isLoopback(listen) {
+			slog.Warn("serving without authentication beyond localhost, anyone reaching the port can read the stats", "addr", listen)
+		}
+		go func() {
+			slog.Info("serving metrics", "addr", listen)
+			var err error
+			if server.TLSConfig != nil {
+				err = server.ListenAndServeTLS("", "")
+			} else {
+				err = server.ListenAndServe()
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+		defer server.Close()
+
+		if a.cfg.Serve.Announce {
+			a.announce(ctx, listen)
+		}
+	}
+
+	// Needs CAP_NET_ADMIN, crash loops go undetected otherwise
+	if err := a.collector.StartProcEvents(); err != nil {
+		slog.Info("proc connector unavailable, not detecting crash loops", "err", err)
+	}
+	for _, privilege := range a.collector.DetectPrivileges() {
+		if !privilege.Available {
+			slog.Info("feature locked", "feature", privilege.Feature, "hint", privilege.Hint)
+		}
+	}
+
+	// The first sample only sets the baseline of the rates
+	a.collect()
+
+	collect := time.NewTicker(time.Duration(a.cfg.Interval))
+	defer collect.Stop()
+	push := time.NewTicker(time.Duration(a.cfg.Export.Interval))
+	defer push.Stop()
+
+	busy := make([]atomic.Bool, len(a.exporters))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case <-collect.C:
+			a.collect()
+		case <-push.C:
+			samples, updated := a.latest()
+			for i, exporter := range a.exporters {
+				// A slow backend must not pile up pushes
+				if !busy[i].CompareAndSwap(false, true) {
+					slog.Warn("export still running, skipping", "exporter", exporter.Name())
+					continue
+				}
+				go func() {
+					defer busy[i].Store(false)
+					ctx, cancel := context.WithTimeout(ctx, time.Duration(a.cfg.Export.Interval))
+					defer cancel()
+					if err := exporter.Export(ctx, updated, samples); err != nil {
+						slog.Warn("export failed", "exporter", exporter.Name(), "err", err)
+					}
+				}()
+			}
+		}
+	}
+}
+
+func (a *Agent) collect() {
+	update := snapshot{
+		stats:     a.collector.GetSystemStats(),
+		processes: a.collector.GetProcessList(),
+		time:      time.Now(),
+	}
+	samples := export.WithLabels(export.Samples(update.stats, update.processes), a.labels)
+	a.report(update)
+	update.alerts = a.alerts.Active()
+	if a.history != nil {
+		a.record(update)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.samples = samples
+	a.last = update
+	for updates := range a.subscribers {
+		// A subscriber that has not taken the previous update skips this one
+		select {
+		case updates <- update:
+		default:
+		}
+	}
+}
+
+// stateDir is where the agent keeps the files it creates. systemd gives the
+// service a writable state directory, the config file's may be read-only.
+func (a *Agent) stateDir() string {
+	if dir := os.Getenv("STATE_DIRECTORY"); dir != "" {
+		return dir
+	}
+	return filepath.Dir(a.cfg.Path)
+}
+
+// announce advertises the agent serving on listen with mDNS in the
+// background. Failing to is not fatal, the agent can still be added by
+// address.
+func (a *Agent) announce(ctx context.Context, listen string) {
+	host, portText, err := net.SplitHostPort(listen)
+	port, _ := strconv.Atoi(portText)
+	if err != nil || port == 0 {
+		slog.Warn("not announcing with mDNS, no port to announce", "addr", listen)
+		return
+	}
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil && !ip.IsUnspecified() {
+		if ip.IsLoopback() || ip.To4() == nil {
+			slog.Warn("not announcing with mDNS, the agent only listens on a loopback or IPv6 address", "addr", listen)
+			return
+		}
+		ips = []net.IP{ip}
+	}
+
+	responder := mdns.NewResponder(a.host, port, a.cfg.Serve.TLS.Enabled(), ips)
+	go func() {
+		slog.Info("announcing with mDNS", "service", mdns.ServiceType, "name", a.host, "port", port)
+		if err := responder.Run(ctx); err != nil {
+			slog.Warn("mDNS announcement stopped", "err", err)
+		}
+	}()
+}
+
+// isLoopback reports whether listen only accepts local connections
+func isLoopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// eventsCloseTimeout is how long queued events may delay shutting down
+const eventsCloseTimeout = 5 * time.Second
+
+// report evaluates the alert rules and looks for problems, sending what it
+// finds as events. Only called from the collection loop.
+func (a *Agent) report(update snapshot) {
+	for _, event := range a.alerts.Evaluate(update.time, update.stats, update.processes) {
+		if event.Type == alert.EventFired {
+			slog.Info("alert fired", "rule", event.Alert.Rule.Source, "value", event.Alert.Value)
+		} else {
+			slog.Info("alert cleared", "rule", event.Alert.Rule.Source, "value", event.Alert.Value, "peak", event.Alert.Peak)
+		}
+		a.events.Emit(events.FromAlert(event))
+		if a.history != nil {
+			a.recordAlert(events.FromAlert(event))
+		}
+	}
+	if a.events.Enabled() {
+		for _, event := range a.detector.Check(update.time, update.stats, update.processes, a.collector.OOMKillCount()) {
+			a.events.Emit(event)
+		}
+	}
+}
+
+// latest returns the last collected samples and when they were taken
+func (a *Agent) latest() ([]export.Sample, time.Time) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.samples, a.last.time
+}
+
+// handleSnapshot returns the latest collection as a schema.Snapshot, as
+// narrowed down by the query parameters
+func (a *Agent) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSnapshotQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.mutex.Lock()
+	last := a.last
+	a.mutex.Unlock()
+	if last.time.IsZero() {
+		http.Error(w, "no data collected yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	document := schema.NewSnapshot(last.time, last.stats, query.processes(last.processes))
+	document.Host = a.host
+	document.Alerts = schema.NewAlerts(last.alerts)
+	query.filter(&document)
+	projected, err := query.project(document)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(projected); err != nil {
+		slog.Debug("writing snapshot failed", "err", err)
+	}
+}
+
+// subscribe returns a channel receiving every collection, starting with the
+// latest one. The channel is closed when the agent stops.
+func (a *Agent) subscribe() (<-chan snapshot, func()) {
+	updates := make(chan snapshot, 1)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.subscribers[updates] = struct{}{}
+	if !a.last.time.IsZero() {
+		updates <- a.last
+	}
+
+	return updates, func() {
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		delete(a.subscribers, updates)
+	}
+}
+
+func (a *Agent) closeSubscribers() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for updates := range a.subscribers {
+		close(updates)
+		delete(a.subscribers, updates)
+	}
+}
+
+// dashboardPage is the live dashboard fed by /ws
+//
+//go:embed web/index.html
+var dashboardPage []byte
+
+// Handler serves the agent's HTTP endpoints, behind authentication when
+// credentials are configured
+func (a *Agent) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		samples, _ := a.latest()
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := export.WriteText(w, samples); err != nil {
+			slog.Debug("writing metrics failed", "err", err)
+		}
+	})
+	mux.HandleFunc("POST "+subscribePath, a.handleSubscribe)
+	mux.HandleFunc("GET /ws", a.handleWebSocket)
+	mux.HandleFunc("GET /api/v1/snapshot", a.rateLimit(a.handleSnapshot))
+	mux.HandleFunc("GET /api/history", a.rateLimit(a.handleHistory))
+	mux.HandleFunc("GET /api/history/alerts", a.rateLimit(a.handleHistoryAlerts))
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(dashboardPage)
+	})
+	return a.requireAuth(mux)
+}
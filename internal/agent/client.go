@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// FetchSnapshot gets the latest collection, with the process list and the
+// firing alerts, of the agent serving at host.URL, such as
+// "http://nas.lan:9101", with the host's credentials
+func FetchSnapshot(ctx context.Context, host config.Host) (schema.Snapshot, error) {
+	var snapshot schema.Snapshot
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(host.URL, "/")+"/api/v1/snapshot", nil)
+	if err != nil {
+		return snapshot, err
+	}
+	switch {
+	case host.Token != "":
+		request.Header.Set("Authorization", "Bearer "+host.Token)
+	case host.Username != "":
+		request.SetBasicAuth(host.Username, host.Password)
+	}
+
+	client := http.DefaultClient
+	if host.Fingerprint != "" {
+		transport := pinnedTransport(host.Fingerprint)
+		defer transport.CloseIdleConnections()
+		client = &http.Client{Transport: transport}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return snapshot, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return snapshot, fmt.Errorf("%s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(response.Body).Decode(&snapshot); err != nil {
+		return snapshot, fmt.Errorf("reading snapshot: %w", err)
+	}
+	// Older versions only lack fields, newer ones may have changed them
+	if snapshot.SchemaVersion > schema.Version {
+		return snapshot, fmt.Errorf("agent speaks schema version %d, this croptop only %d", snapshot.SchemaVersion, schema.Version)
+	}
+	return snapshot, nil
+}
+
+// pinnedTransport trusts the one certificate whose SHA-256 is fingerprint,
+// given in hex with or without colons, whoever signed it
+func pinnedTransport(fingerprint string) *http.Transport {
+	want := strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		// Verified below, by fingerprint instead of by chain
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(certs [][]byte, _ [][]*x509.Certificate) error {
+			if len(certs) == 0 || Fingerprint(certs[0]) != want {
+				return errors.New("certificate fingerprint does not match the host's")
+			}
+			return nil
+		},
+	}
+	return transport
+}
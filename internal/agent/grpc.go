@@ -0,0 +1,220 @@
+package agent
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/prabalesh/croptop/internal/models"
+	"github.com/prabalesh/croptop/internal/pb"
+)
+
+// subscribePath is croptop.v1.Croptop/Subscribe of api/croptop/v1/croptop.proto
+const subscribePath = "/croptop.v1.Croptop/Subscribe"
+
+// gRPC status codes
+const (
+	grpcInvalidArgument = 3
+	grpcUnavailable     = 14
+)
+
+// maxRequestSize bounds the SubscribeRequest read from a client
+const maxRequestSize = 4096
+
+// handleSubscribe implements the server side of the Subscribe stream. It
+// speaks gRPC directly over net/http's HTTP/2: length-prefixed protobuf
+// messages in the body, the status in the trailers.
+func (a *Agent) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || r.Header.Get("Content-Type") != "application/grpc" && r.Header.Get("Content-Type") != "application/grpc+proto" {
+		http.Error(w, "gRPC requires HTTP/2 and application/grpc", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	status := func(code int, message string) {
+		w.Header().Set("Grpc-Status", strconv.Itoa(code))
+		w.Header().Set("Grpc-Message", message)
+	}
+
+	request, err := readSubscribeRequest(r.Body)
+	if err != nil {
+		status(grpcInvalidArgument, err.Error())
+		return
+	}
+
+	updates, unsubscribe := a.subscribe()
+	defer unsubscribe()
+
+	flusher, _ := w.(http.Flusher)
+	var frame pb.Buffer
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				status(grpcUnavailable, "agent shutting down")
+				return
+			}
+			frame.Reset()
+			encodeUpdate(&frame, update, request)
+			if err := writeFrame(w, frame.Bytes()); err != nil {
+				slog.Debug("subscriber gone", "remote", r.RemoteAddr, "err", err)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// subscribeRequest is the decoded croptop.v1.SubscribeRequest
+type subscribeRequest struct {
+	includeProcesses bool
+	maxProcesses     int
+}
+
+func readSubscribeRequest(body io.Reader) (subscribeRequest, error) {
+	var request subscribeRequest
+
+	var header [5]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		return request, errors.New("missing request message")
+	}
+	if header[0] != 0 {
+		return request, errors.New("compressed requests are not supported")
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > maxRequestSize {
+		return request, errors.New("request too large")
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(body, message); err != nil {
+		return request, errors.New("truncated request message")
+	}
+
+	fields, err := pb.Decode(message)
+	if err != nil {
+		return request, err
+	}
+	for _, field := range fields {
+		switch field.Number {
+		case 1:
+			request.includeProcesses = field.Value != 0
+		case 2:
+			request.maxProcesses = int(field.Value)
+		}
+	}
+	return request, nil
+}
+
+// writeFrame writes a length-prefixed, uncompressed gRPC message
+func writeFrame(w io.Writer, message []byte) error {
+	var header [5]byte
+	binary.BigEndian.PutUint32(header[1:], uint32(len(message)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(message)
+	return err
+}
+
+// encodeUpdate encodes a croptop.v1.Update. Field numbers follow
+// api/croptop/v1/croptop.proto.
+func encodeUpdate(m *pb.Buffer, update snapshot, request subscribeRequest) {
+	stats := update.stats
+	m.Int64(1, update.time.UnixMilli())
+	m.Message(2, func(m *pb.Buffer) {
+		m.Message(1, func(m *pb.Buffer) {
+			cpu := stats.CPU
+			m.Double(1, cpu.Usage)
+			m.PackedDoubles(2, cpu.Cores)
+			m.Double(3, cpu.Frequency)
+			m.Double(4, float64(cpu.Temp))
+			m.String(5, cpu.Model)
+			m.Double(6, cpu.Power.PackageWatts)
+			m.PackedDoubles(7, cpu.Load[:])
+			m.PackedUint32s(8, cpu.CoreIDs)
+		})
+		m.Message(2, func(m *pb.Buffer) {
+			memory := stats.Memory
+			m.Double(1, memory.Total*1024)
+			m.Double(2, memory.Used*1024)
+			m.Double(3, memory.Free*1024)
+			m.Double(4, memory.Available*1024)
+			m.Double(5, memory.UsagePercent)
+			m.Double(6, memory.SwapTotal*1024)
+			m.Double(7, memory.SwapUsed*1024)
+		})
+		m.Message(3, func(m *pb.Buffer) {
+			for _, iface := range stats.Network.Interfaces {
+				m.Message(1, func(m *pb.Buffer) {
+					m.String(1, iface.Name)
+					m.Uint64(2, iface.RxBytes)
+					m.Uint64(3, iface.TxBytes)
+					m.Double(4, iface.RxRate)
+					m.Double(5, iface.TxRate)
+					m.String(6, iface.Status)
+				})
+			}
+			m.Double(2, stats.Network.RxRate)
+			m.Double(3, stats.Network.TxRate)
+		})
+		for _, disk := range stats.Disk {
+			m.Message(4, func(m *pb.Buffer) {
+				m.String(1, disk.Device)
+				m.String(2, disk.Mountpoint)
+				m.String(3, disk.Filesystem)
+				m.Uint64(4, disk.Total)
+				m.Uint64(5, disk.Used)
+				m.Uint64(6, disk.Free)
+				m.Double(7, disk.UsagePercent)
+			})
+		}
+		if battery := stats.Battery; battery.Status != "Not Available" {
+			m.Message(5, func(m *pb.Buffer) {
+				m.Bool(1, true)
+				m.Int64(2, int64(battery.Level))
+				m.String(3, battery.Status)
+				m.Bool(4, battery.IsCharging)
+			})
+		}
+		m.Double(6, stats.Uptime.Seconds())
+	})
+
+	if request.includeProcesses {
+		m.Message(3, func(m *pb.Buffer) {
+			encodeProcesses(m, update.processes, request.maxProcesses)
+		})
+	}
+}
+
+func encodeProcesses(m *pb.Buffer, list models.ProcessList, limit int) {
+	processes := list.Processes
+	if limit > 0 && limit < len(processes) {
+		processes = processes[:limit]
+	}
+	for _, proc := range processes {
+		m.Message(1, func(m *pb.Buffer) {
+			m.Int64(1, int64(proc.PID))
+			m.String(2, proc.Name)
+			m.String(3, proc.Command)
+			m.Double(4, proc.CPUPercent)
+			m.Double(5, proc.MemPercent)
+			m.Uint64(6, proc.MemRSS*1024)
+			m.String(7, proc.Status)
+			m.String(8, proc.User)
+			m.Double(9, proc.ReadRate)
+			m.Double(10, proc.WriteRate)
+		})
+	}
+	m.Int64(2, int64(list.Total))
+	m.Int64(3, int64(list.Running))
+	m.Int64(4, int64(list.Sleeping))
+	m.Int64(5, int64(list.Zombie))
+}
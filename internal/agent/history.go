@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/alert"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/history"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// historyPoints is how many points a history query returns by default, and
+// maxHistoryPoints how many it may ask for
+const (
+	historyPoints    = 300
+	maxHistoryPoints = 10000
+)
+
+// historyAliases are short names of the most charted metrics
+var historyAliases = map[string]string{
+	"cpu":     "cpu.usage",
+	"memory":  "memory.usage_percent",
+	"swap":    "swap.usage_percent",
+	"disk":    "disk.usage_percent",
+	"battery": "battery.level",
+}
+
+// historyExprs returns the expressions the history store records: every
+// named metric and the configured expressions
+func historyExprs(extra []string) ([]alert.Expr, error) {
+	var exprs []alert.Expr
+	for _, source := range append(alert.MetricNames(), extra...) {
+		expr, err := alert.ParseExpr(source)
+		if err != nil {
+			return nil, fmt.Errorf("history metric: %w", err)
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// record adds a collection to the history store. Expressions selecting
+// nothing, such as the disk of an unmounted filesystem, get no value.
+func (a *Agent) record(update snapshot) {
+	values := make(map[string]float64, len(a.historyExprs))
+	for _, expr := range a.historyExprs {
+		if value, ok := expr.Eval(update.stats, update.processes); ok {
+			values[expr.Source] = value
+		}
+	}
+	if err := a.history.Record(update.time, values); err != nil {
+		slog.Warn("recording history failed", "err", err)
+	}
+}
+
+// recordAlert adds an alert firing or clearing to the history store, so
+// /api/history/alerts still has it after a restart
+func (a *Agent) recordAlert(event schema.Event) {
+	event.SchemaVersion = schema.Version
+	event.Host = a.host
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = a.history.RecordEvent(history.Event{Time: event.Time, Data: data})
+	}
+	if err != nil {
+		slog.Warn("recording alert history failed", "err", err)
+	}
+}
+
+// historyPath is the file of the history backends keeping it on disk
+func (a *Agent) historyPath() string {
+	return history.Path(a.cfg.History, a.stateDir())
+}
+
+// handleHistory returns a metric over the range before now, such as
+// /api/history?metric=cpu&range=1h, averaged down to the points asked for
+// or to steps of step
+func (a *Agent) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if a.history == nil {
+		http.Error(w, "history is not enabled, see history in the config", http.StatusNotFound)
+		return
+	}
+	query := r.URL.Query()
+
+	metric := query.Get("metric")
+	if alias, ok := historyAliases[metric]; ok {
+		metric = alias
+	}
+	i := slices.IndexFunc(a.historyExprs, func(expr alert.Expr) bool { return expr.Source == metric })
+	if i < 0 {
+		http.Error(w, fmt.Sprintf("metric %q is not recorded", query.Get("metric")), http.StatusNotFound)
+		return
+	}
+
+	span := time.Hour
+	if value := query.Get("range"); value != "" {
+		var err error
+		if span, err = parseRange(value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	span = min(span, a.history.Retention())
+
+	count := historyPoints
+	if value := query.Get("points"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxHistoryPoints {
+			http.Error(w, fmt.Sprintf("points %q is not between 1 and %d", value, maxHistoryPoints), http.StatusBadRequest)
+			return
+		}
+		count = n
+	}
+	step := span / time.Duration(count)
+	if value := query.Get("step"); value != "" {
+		var err error
+		if step, err = time.ParseDuration(value); err != nil || step <= 0 {
+			http.Error(w, fmt.Sprintf("step %q is not a duration such as 1m", value), http.StatusBadRequest)
+			return
+		}
+		if span/step > maxHistoryPoints {
+			http.Error(w, fmt.Sprintf("step %s makes more than %d points", step, maxHistoryPoints), http.StatusBadRequest)
+			return
+		}
+	}
+	// Steps shorter than the interval would be empty or hold one value
+	step = max(step, time.Duration(a.cfg.Interval))
+
+	to := time.Now()
+	series, err := a.history.Query(metric, to.Add(-span), to, step)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	document := schema.NewHistory(metric, a.historyExprs[i].Unit, series.From, to, series.Step, series.Points)
+	document.Host = a.host
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(document); err != nil {
+		slog.Debug("writing history failed", "err", err)
+	}
+}
+
+// handleHistoryAlerts returns the alerts that fired and cleared over the
+// range before now, such as /api/history/alerts?range=24h
+func (a *Agent) handleHistoryAlerts(w http.ResponseWriter, r *http.Request) {
+	if a.history == nil {
+		http.Error(w, "history is not enabled, see history in the config", http.StatusNotFound)
+		return
+	}
+	span := time.Hour
+	if value := r.URL.Query().Get("range"); value != "" {
+		var err error
+		if span, err = parseRange(value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	span = min(span, a.history.Retention())
+
+	to := time.Now()
+	from := to.Add(-span)
+	document := schema.NewHistoryEvents(from, to, a.history.Events(from, to))
+	document.Host = a.host
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(document); err != nil {
+		slog.Debug("writing alert history failed", "err", err)
+	}
+}
+
+// parseRange parses a duration that may also be given in days, such as 7d
+func parseRange(value string) (time.Duration, error) {
+	span, err := config.ParseDuration(value)
+	if err != nil || span <= 0 {
+		return 0, fmt.Errorf("range %q is not a duration such as 1h or 7d", value)
+	}
+	return span, nil
+}
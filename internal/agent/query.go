@@ -0,0 +1,199 @@
+package agent
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// processSorts orders processes by the sort query parameter: the heaviest
+// first, except for pid and name
+var processSorts = map[string]func(a, b models.Process) int{
+	"cpu":     func(a, b models.Process) int { return cmp.Compare(b.CPUPercent, a.CPUPercent) },
+	"memory":  func(a, b models.Process) int { return cmp.Compare(b.MemPercent, a.MemPercent) },
+	"rss":     func(a, b models.Process) int { return cmp.Compare(b.MemRSS, a.MemRSS) },
+	"swap":    func(a, b models.Process) int { return cmp.Compare(b.Swap, a.Swap) },
+	"io":      func(a, b models.Process) int { return cmp.Compare(b.ReadRate+b.WriteRate, a.ReadRate+a.WriteRate) },
+	"runtime": func(a, b models.Process) int { return cmp.Compare(b.Runtime, a.Runtime) },
+	"pid":     func(a, b models.Process) int { return cmp.Compare(a.PID, b.PID) },
+	"name":    func(a, b models.Process) int { return cmp.Compare(a.Name, b.Name) },
+}
+
+// snapshotFields are the parts of a snapshot the fields query parameter
+// selects, by their key in the document. system selects all of the system
+// stats.
+var snapshotFields = map[string]string{
+	"uptime":    "uptime_seconds",
+	"cpu":       "cpu",
+	"memory":    "memory",
+	"network":   "network",
+	"disks":     "disks",
+	"battery":   "battery",
+	"cgroup":    "cgroup",
+	"processes": "processes",
+	"alerts":    "alerts",
+	"system":    "",
+}
+
+// snapshotQuery narrows down what GET /api/v1/snapshot returns, so a client
+// polling often does not pull the full process list every time
+type snapshotQuery struct {
+	// Number of processes kept after sorting, 0 for all
+	top  int
+	sort string
+	// Selected parts of the document, all when empty
+	fields []string
+	// Interfaces by name and disks by mountpoint or device, all when empty
+	interfaces []string
+	disks      []string
+}
+
+// parseSnapshotQuery reads the query parameters of a snapshot request. List
+// parameters take comma separated values or may be repeated.
+func parseSnapshotQuery(values url.Values) (snapshotQuery, error) {
+	query := snapshotQuery{
+		sort:       values.Get("sort"),
+		fields:     listParam(values, "fields"),
+		interfaces: listParam(values, "interface"),
+		disks:      listParam(values, "disk"),
+	}
+	if top := values.Get("top"); top != "" {
+		n, err := strconv.Atoi(top)
+		if err != nil || n < 0 {
+			return query, fmt.Errorf("top %q is not a number of processes", top)
+		}
+		query.top = n
+	}
+	if _, ok := processSorts[query.sort]; query.sort != "" && !ok {
+		return query, fmt.Errorf("unknown sort %q (want one of %s)", query.sort, strings.Join(sortedKeys(processSorts), ", "))
+	}
+	for _, field := range query.fields {
+		if _, ok := snapshotFields[field]; !ok {
+			return query, fmt.Errorf("unknown field %q (want one of %s)", field, strings.Join(sortedKeys(snapshotFields), ", "))
+		}
+	}
+	return query, nil
+}
+
+func listParam(values url.Values, name string) []string {
+	var list []string
+	for _, value := range values[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// wants reports whether the document part keyed key was selected
+func (q snapshotQuery) wants(key string) bool {
+	return len(q.fields) == 0 || slices.ContainsFunc(q.fields, func(field string) bool {
+		return snapshotFields[field] == key
+	})
+}
+
+// wantsSystem reports whether any of the system stats were selected
+func (q snapshotQuery) wantsSystem() bool {
+	return len(q.fields) == 0 || slices.ContainsFunc(q.fields, func(field string) bool {
+		return field != "processes" && field != "alerts"
+	})
+}
+
+// processes returns the processes of list sorted and cut to the query, or
+// nil when they were not asked for
+func (q snapshotQuery) processes(list models.ProcessList) *models.ProcessList {
+	if !q.wants("processes") {
+		return nil
+	}
+	if sortBy, ok := processSorts[q.sort]; ok {
+		// The collection is shared with other requests, sorted by CPU
+		list.Processes = slices.Clone(list.Processes)
+		slices.SortStableFunc(list.Processes, sortBy)
+	}
+	if q.top > 0 {
+		list.Processes = list.Processes[:min(q.top, len(list.Processes))]
+	}
+	return &list
+}
+
+// filter drops the interfaces and disks not asked for from document
+func (q snapshotQuery) filter(document *schema.Snapshot) {
+	if len(q.interfaces) > 0 {
+		document.System.Network.Interfaces = slices.DeleteFunc(document.System.Network.Interfaces, func(iface schema.Interface) bool {
+			return !slices.Contains(q.interfaces, iface.Name)
+		})
+	}
+	if len(q.disks) > 0 {
+		document.System.Disks = slices.DeleteFunc(document.System.Disks, func(disk schema.Disk) bool {
+			return !slices.Contains(q.disks, disk.Mountpoint) && !slices.Contains(q.disks, disk.Device)
+		})
+	}
+}
+
+// project returns document with only the selected parts. schema_version,
+// time, host and suspended are always kept, as they say how to read the
+// rest.
+func (q snapshotQuery) project(document schema.Snapshot) (any, error) {
+	if len(q.fields) == 0 {
+		return document, nil
+	}
+	whole, err := toObject(document)
+	if err != nil {
+		return nil, err
+	}
+	projected := map[string]json.RawMessage{
+		"schema_version": whole["schema_version"],
+		"time":           whole["time"],
+	}
+	for _, key := range []string{"host", "processes", "alerts"} {
+		if value, ok := whole[key]; ok && (key == "host" || q.wants(key)) {
+			projected[key] = value
+		}
+	}
+	if !q.wantsSystem() {
+		return projected, nil
+	}
+
+	system, err := toObject(document.System)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(q.fields, "system") {
+		for key := range system {
+			if key != "suspended" && !q.wants(key) {
+				delete(system, key)
+			}
+		}
+	}
+	if projected["system"], err = json.Marshal(system); err != nil {
+		return nil, err
+	}
+	return projected, nil
+}
+
+func toObject(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]json.RawMessage
+	return object, json.Unmarshal(data, &object)
+}
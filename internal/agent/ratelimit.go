@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// limiterIdle is how long a client goes without requests before its bucket
+// is forgotten
+const limiterIdle = time.Minute
+
+// rateLimiter gives every client address a token bucket refilled at rate
+// tokens per second, holding up to a second's worth
+type rateLimiter struct {
+	rate    float64
+	burst   float64
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: max(1, rate), buckets: make(map[string]*bucket)}
+}
+
+// allow takes a token from client's bucket. When it is empty, it returns
+// how long until the next token.
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.buckets[client]
+	if !ok {
+		// Forgotten buckets are full again anyway
+		for address, old := range l.buckets {
+			if now.Sub(old.last) > limiterIdle {
+				delete(l.buckets, address)
+			}
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// rateLimit answers 429 Too Many Requests to clients asking more often than
+// the configured rate_limit
+func (a *Agent) rateLimit(next http.HandlerFunc) http.HandlerFunc {
+	if a.cfg.Serve.RateLimit == 0 {
+		return next
+	}
+	limiter := newRateLimiter(a.cfg.Serve.RateLimit)
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if ok, wait := limiter.allow(client, time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,181 @@
+package agent
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/caps"
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// selfSignedValidity is how long a self-signed certificate lasts. Clients
+// pin its fingerprint rather than trust its dates, so it may be long.
+const selfSignedValidity = 10 * 365 * 24 * time.Hour
+
+// Fingerprint is the SHA-256 of a certificate in hex, as the fingerprint of
+// a host in the config file takes it
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// tlsConfig returns the TLS config of the serve endpoint
+func (a *Agent) tlsConfig() (*tls.Config, error) {
+	settings := a.cfg.Serve.TLS
+	certPath, keyPath := settings.Cert, settings.Key
+	if certPath == "" {
+		certPath, keyPath = filepath.Join(a.stateDir(), "serve-cert.pem"), filepath.Join(a.stateDir(), "serve-key.pem")
+	}
+
+	cert, err := loadKeyPair(certPath, keyPath)
+	if errors.Is(err, os.ErrNotExist) && settings.SelfSigned {
+		cert, err = a.selfSigned(certPath, keyPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading the TLS certificate: %w", err)
+	}
+	slog.Info("serving over TLS", "cert", certPath, "fingerprint", Fingerprint(cert.Certificate[0]))
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// loadKeyPair is tls.LoadX509KeyPair reading the files without
+// CAP_DAC_READ_SEARCH, as they come from the config
+func loadKeyPair(certPath, keyPath string) (tls.Certificate, error) {
+	certPEM, err := caps.ReadFile(certPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := caps.ReadFile(keyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// selfSigned creates a self-signed certificate for the host's names and
+// addresses and stores it at certPath and keyPath. If it cannot be stored
+// it is used anyway, but a new one with a new fingerprint is made on every
+// start.
+func (a *Agent) selfSigned(certPath, keyPath string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: a.host, Organization: []string{"croptop"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+	}
+	if a.host != "" {
+		short, _, _ := strings.Cut(a.host, ".")
+		template.DNSNames = append(template.DNSNames, a.host, short+".local")
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok {
+				template.IPAddresses = append(template.IPAddresses, ipnet.IP)
+			}
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := writeKeyPair(certPath, certPEM, keyPath, keyPEM); err != nil {
+		slog.Warn("self-signed certificate not stored, its fingerprint changes on every start", "path", certPath, "err", err)
+	} else {
+		slog.Info("created a self-signed certificate", "cert", certPath, "key", keyPath)
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// writeKeyPair stores a certificate and its key, the key readable by its
+// owner only
+func writeKeyPair(certPath string, certPEM []byte, keyPath string, keyPEM []byte) error {
+	if err := os.MkdirAll(filepath.Dir(certPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certPath, certPEM, 0o644)
+}
+
+// requireAuth lets through the requests carrying one of the configured
+// tokens or the basic auth credentials. The token may also be given as the
+// token query parameter, for the dashboard link in a browser.
+func (a *Agent) requireAuth(next http.Handler) http.Handler {
+	auth := a.cfg.Serve.Auth
+	if !auth.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authorized(auth, r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if auth.Username != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="croptop", charset="UTF-8"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="croptop"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func authorized(auth config.ServeAuth, r *http.Request) bool {
+	if username, password, ok := r.BasicAuth(); ok && auth.Username != "" {
+		// Both compared, so the time taken does not tell which was wrong
+		userOK := subtle.ConstantTimeCompare([]byte(username), []byte(auth.Username))
+		passwordOK := subtle.ConstantTimeCompare([]byte(password), []byte(auth.Password))
+		return userOK&passwordOK == 1
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		return false
+	}
+	for _, valid := range auth.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(valid)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package agent
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// websocketGUID is appended to the client key to accept a handshake (RFC 6455)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// Limits of the live dashboard stream
+const (
+	wsProcesses     = 10
+	wsWriteTimeout  = 10 * time.Second
+	wsMaxClientData = 1 << 16
+)
+
+// handleWebSocket streams every collection as a JSON text message to the
+// dashboard page
+func (a *Agent) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "expected a WebSocket upgrade", http.StatusBadRequest)
+		return
+	}
+	// Browsers let any page open a WebSocket to the agent, even on
+	// loopback, so only its own dashboard and the allowed origins may
+	if !a.originAllowed(r) {
+		http.Error(w, "cross-origin WebSocket not allowed", http.StatusForbidden)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket needs HTTP/1.1", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		slog.Debug("websocket hijack failed", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	accept := sha1.Sum([]byte(key + websocketGUID))
+	buffered.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(accept[:]) + "\r\n\r\n")
+	if err := buffered.Flush(); err != nil {
+		return
+	}
+
+	ws := &wsConn{conn: conn}
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		ws.readLoop(buffered.Reader)
+	}()
+
+	updates, unsubscribe := a.subscribe()
+	defer unsubscribe()
+	for {
+		select {
+		case <-closed:
+			return
+		case update, ok := <-updates:
+			if !ok {
+				ws.write(opClose, nil)
+				return
+			}
+			// A schema.Snapshot with the heaviest processes only
+			processes := update.processes
+			processes.Processes = processes.Processes[:min(wsProcesses, len(processes.Processes))]
+			payload, err := json.Marshal(schema.NewSnapshot(update.time, update.stats, &processes))
+			if err != nil {
+				slog.Warn("encoding websocket frame failed", "err", err)
+				return
+			}
+			if err := ws.write(opText, payload); err != nil {
+				slog.Debug("websocket client gone", "remote", r.RemoteAddr, "err", err)
+				return
+			}
+		}
+	}
+}
+
+// wsConn writes server frames, which are never masked, from both the
+// stream and the control replies of the read loop
+type wsConn struct {
+	conn  net.Conn
+	mutex sync.Mutex
+}
+
+func (c *wsConn) write(opcode byte, payload []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := c.conn.Write(header); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(payload)
+	return err
+}
+
+// readLoop answers pings and returns when the client closes the connection.
+// The dashboard sends no data, whatever arrives is discarded.
+func (c *wsConn) readLoop(r *bufio.Reader) {
+	for {
+		opcode, payload, err := readWSFrame(r)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case opPing:
+			c.write(opPong, payload)
+		case opClose:
+			c.write(opClose, nil)
+			return
+		}
+	}
+}
+
+// readWSFrame reads a client frame, unmasking its payload
+func readWSFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > wsMaxClientData {
+		return 0, nil, errors.New("websocket frame too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+// originAllowed accepts clients without an Origin, which are no browsers,
+// pages served by the agent itself and the configured origins
+func (a *Agent) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range a.cfg.Serve.AllowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	slog.Debug("websocket origin refused", "origin", origin, "host", r.Host)
+	return false
+}
+
+// headerContains reports whether a comma separated header lists token
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
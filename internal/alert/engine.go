@@ -0,0 +1,139 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Alert is a rule whose condition currently holds for its full duration
+type Alert struct {
+	Rule  Rule
+	Since time.Time
+	Value float64
+	Peak  float64
+}
+
+type EventType int
+
+const (
+	EventFired EventType = iota
+	EventCleared
+)
+
+// Event reports a rule starting or stopping to fire
+type Event struct {
+	Type  EventType
+	Alert Alert
+	Time  time.Time
+}
+
+// ruleState tracks how long a rule condition has held
+type ruleState struct {
+	pendingSince time.Time
+	firing       bool
+	alert        Alert
+}
+
+// MaxHistory bounds the number of events kept for the session
+const MaxHistory = 500
+
+// Engine evaluates alert rules against each stats sample
+type Engine struct {
+	rules   []Rule
+	states  []ruleState
+	history []Event
+}
+
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules:  rules,
+		states: make([]ruleState, len(rules)),
+	}
+}
+
+// Rules returns the rules being evaluated
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// SetRules replaces the rules, e.g. after the config was reloaded. Rules that
+// did not change keep their pending or firing state; the history is kept.
+func (e *Engine) SetRules(rules []Rule) {
+	previous := make(map[string]ruleState, len(e.rules))
+	for i, rule := range e.rules {
+		previous[rule.Source] = e.states[i]
+	}
+
+	e.rules = rules
+	e.states = make([]ruleState, len(rules))
+	for i, rule := range rules {
+		e.states[i] = previous[rule.Source]
+	}
+}
+
+// Evaluate checks every rule against a sample and returns the alerts that
+// fired or cleared since the previous sample
+func (e *Engine) Evaluate(now time.Time, stats models.SystemStats, processes models.ProcessList) []Event {
+	var events []Event
+
+	for i, rule := range e.rules {
+		state := &e.states[i]
+		// Whatever the expression selects may be gone, e.g. an unmounted
+		// filesystem, which clears the alert
+		value, ok := rule.Expr.Eval(stats, processes)
+
+		if !ok || !rule.matches(value) {
+			if state.firing {
+				cleared := state.alert
+				cleared.Value = value
+				events = append(events, Event{Type: EventCleared, Alert: cleared, Time: now})
+			}
+			*state = ruleState{}
+			continue
+		}
+
+		if state.pendingSince.IsZero() {
+			state.pendingSince = now
+		}
+
+		if state.firing {
+			state.alert.Value = value
+			if rule.worse(value, state.alert.Peak) {
+				state.alert.Peak = value
+			}
+			continue
+		}
+
+		if now.Sub(state.pendingSince) >= rule.Duration {
+			state.firing = true
+			state.alert = Alert{Rule: rule, Since: now, Value: value, Peak: value}
+			events = append(events, Event{Type: EventFired, Alert: state.alert, Time: now})
+		}
+	}
+
+	e.history = append(e.history, events...)
+	if len(e.history) > MaxHistory {
+		e.history = e.history[len(e.history)-MaxHistory:]
+	}
+
+	return events
+}
+
+// History returns the events of the session, oldest first
+func (e *Engine) History() []Event {
+	return e.history
+}
+
+// Active returns the currently firing alerts, critical ones first
+func (e *Engine) Active() []Alert {
+	var active []Alert
+	for _, severity := range []Severity{SeverityCritical, SeverityWarning} {
+		for i, state := range e.states {
+			if state.firing && e.rules[i].Severity == severity {
+				active = append(active, state.alert)
+			}
+		}
+	}
+	return active
+}
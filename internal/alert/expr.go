@@ -0,0 +1,377 @@
+package alert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Units of expression values, for display and threshold suffixes
+const (
+	UnitNone      = ""
+	UnitPercent   = "%"
+	UnitBytes     = "B"
+	UnitBytesRate = "B/s"
+	UnitRate      = "/s"
+	UnitCelsius   = "°C"
+	UnitWatts     = "W"
+)
+
+// Expr is a metric expression of alert rules and watches: a metric name
+// such as cpu.usage, or a selector and a field such as
+//
+//	disk["/home"].usage_percent
+//	net["eth0"].rx_rate
+//	core[3].usage
+//	proc[name="postgres"].rss_sum
+//	proc[user="1000", command~"java"].cpu_sum
+//
+// proc filters match with = exactly and with ~ as a substring.
+type Expr struct {
+	Source string
+	Unit   string
+	eval   func(models.SystemStats, models.ProcessList) (float64, bool)
+}
+
+// Eval returns the value of the expression in a sample, and false when
+// what it selects does not exist, e.g. an unmounted filesystem
+func (e Expr) Eval(stats models.SystemStats, processes models.ProcessList) (float64, bool) {
+	return e.eval(stats, processes)
+}
+
+// ParseExpr parses a metric expression
+func ParseExpr(source string) (Expr, error) {
+	source = strings.TrimSpace(source)
+	open := strings.IndexByte(source, '[')
+	if open < 0 {
+		m, ok := metrics[source]
+		if !ok {
+			return Expr{}, fmt.Errorf("unknown metric %q", source)
+		}
+		return Expr{Source: source, Unit: m.unit, eval: func(s models.SystemStats, p models.ProcessList) (float64, bool) {
+			return m.value(s, p), true
+		}}, nil
+	}
+
+	kind := source[:open]
+	end := closingBracket(source, open)
+	if end < 0 {
+		return Expr{}, fmt.Errorf("missing ] in %q", source)
+	}
+	field, ok := strings.CutPrefix(source[end+1:], ".")
+	if !ok || field == "" {
+		return Expr{}, fmt.Errorf("expected .<field> after %s", source[:end+1])
+	}
+	args := strings.TrimSpace(source[open+1 : end])
+
+	expr := Expr{Source: source}
+	var err error
+	switch kind {
+	case "disk":
+		expr.Unit, expr.eval, err = diskExpr(args, field)
+	case "net":
+		expr.Unit, expr.eval, err = netExpr(args, field)
+	case "core":
+		expr.Unit, expr.eval, err = coreExpr(args, field)
+	case "proc":
+		expr.Unit, expr.eval, err = procExpr(args, field)
+	default:
+		err = fmt.Errorf("unknown selector %q (want disk, net, core or proc)", kind)
+	}
+	return expr, err
+}
+
+// closingBracket returns the index of the ] closing the [ at open, skipping
+// quoted strings, or -1
+func closingBracket(source string, open int) int {
+	quoted := false
+	for i := open + 1; i < len(source); i++ {
+		switch {
+		case source[i] == '\\' && quoted:
+			i++
+		case source[i] == '"':
+			quoted = !quoted
+		case source[i] == ']' && !quoted:
+			return i
+		}
+	}
+	return -1
+}
+
+// splitExpr splits the expression at the start of text from the rest, at
+// the first space outside quotes and brackets
+func splitExpr(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	quoted, depth := false, 0
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case (c == ' ' || c == '\t') && depth == 0:
+			return text[:i], text[i+1:]
+		}
+	}
+	return text, ""
+}
+
+// argument unquotes a selector argument; quotes are optional
+func argument(arg string) (string, error) {
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(arg, `"`) {
+		return strconv.Unquote(arg)
+	}
+	if arg == "" {
+		return "", fmt.Errorf("empty selector")
+	}
+	return arg, nil
+}
+
+type evalFunc = func(models.SystemStats, models.ProcessList) (float64, bool)
+
+func diskExpr(args, field string) (string, evalFunc, error) {
+	mountpoint, err := argument(args)
+	if err != nil {
+		return "", nil, err
+	}
+	fields := map[string]struct {
+		unit  string
+		value func(models.DiskStats) float64
+	}{
+		"usage_percent": {UnitPercent, func(d models.DiskStats) float64 { return d.UsagePercent }},
+		"used":          {UnitBytes, func(d models.DiskStats) float64 { return float64(d.Used) }},
+		"free":          {UnitBytes, func(d models.DiskStats) float64 { return float64(d.Free) }},
+		"total":         {UnitBytes, func(d models.DiskStats) float64 { return float64(d.Total) }},
+	}
+	f, ok := fields[field]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown disk field %q (want usage_percent, used, free or total)", field)
+	}
+	return f.unit, func(s models.SystemStats, _ models.ProcessList) (float64, bool) {
+		for _, disk := range s.Disk {
+			if disk.Mountpoint == mountpoint {
+				return f.value(disk), true
+			}
+		}
+		return 0, false
+	}, nil
+}
+
+func netExpr(args, field string) (string, evalFunc, error) {
+	name, err := argument(args)
+	if err != nil {
+		return "", nil, err
+	}
+	fields := map[string]struct {
+		unit  string
+		value func(models.NetworkInterface) float64
+	}{
+		"rx_rate":  {UnitBytesRate, func(n models.NetworkInterface) float64 { return n.RxRate }},
+		"tx_rate":  {UnitBytesRate, func(n models.NetworkInterface) float64 { return n.TxRate }},
+		"rx_bytes": {UnitBytes, func(n models.NetworkInterface) float64 { return float64(n.RxBytes) }},
+		"tx_bytes": {UnitBytes, func(n models.NetworkInterface) float64 { return float64(n.TxBytes) }},
+	}
+	f, ok := fields[field]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown net field %q (want rx_rate, tx_rate, rx_bytes or tx_bytes)", field)
+	}
+	return f.unit, func(s models.SystemStats, _ models.ProcessList) (float64, bool) {
+		for _, iface := range s.Network.Interfaces {
+			if iface.Name == name {
+				return f.value(iface), true
+			}
+		}
+		return 0, false
+	}, nil
+}
+
+func coreExpr(args, field string) (string, evalFunc, error) {
+	arg, err := argument(args)
+	if err != nil {
+		return "", nil, err
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid CPU number %q", arg)
+	}
+	if field != "usage" {
+		return "", nil, fmt.Errorf("unknown core field %q (want usage)", field)
+	}
+	return UnitPercent, func(s models.SystemStats, _ models.ProcessList) (float64, bool) {
+		for i, usage := range s.CPU.Cores {
+			if s.CPU.CoreID(i) == id {
+				return usage, true
+			}
+		}
+		return 0, false
+	}, nil
+}
+
+// procFilter matches a process attribute exactly (=) or as a substring (~)
+type procFilter struct {
+	value     func(models.Process) string
+	want      string
+	substring bool
+}
+
+func procExpr(args, field string) (string, evalFunc, error) {
+	var filters []procFilter
+	for _, arg := range splitArgs(args) {
+		i := strings.IndexAny(arg, "=~")
+		if i < 0 {
+			return "", nil, fmt.Errorf("expected key=\"value\" or key~\"value\", got %q", arg)
+		}
+		want, err := argument(arg[i+1:])
+		if err != nil {
+			return "", nil, err
+		}
+		filter := procFilter{want: want, substring: arg[i] == '~'}
+		switch key := strings.TrimSpace(arg[:i]); key {
+		case "name":
+			filter.value = func(p models.Process) string { return p.Name }
+		case "user":
+			filter.value = func(p models.Process) string { return p.User }
+		case "command":
+			filter.value = func(p models.Process) string { return p.Command }
+		default:
+			return "", nil, fmt.Errorf("unknown process filter %q (want name, user or command)", key)
+		}
+		filters = append(filters, filter)
+	}
+
+	// Per process values, summed or maxed over the matches
+	values := map[string]struct {
+		unit  string
+		value func(models.Process) float64
+	}{
+		"cpu":        {UnitPercent, func(p models.Process) float64 { return p.CPUPercent }},
+		"mem":        {UnitPercent, func(p models.Process) float64 { return p.MemPercent }},
+		"rss":        {UnitBytes, func(p models.Process) float64 { return float64(p.MemRSS) * 1024 }},
+		"swap":       {UnitBytes, func(p models.Process) float64 { return float64(p.Swap) * 1024 }},
+		"read_rate":  {UnitBytesRate, func(p models.Process) float64 { return p.ReadRate }},
+		"write_rate": {UnitBytesRate, func(p models.Process) float64 { return p.WriteRate }},
+	}
+
+	match := func(p models.Process) bool {
+		for _, filter := range filters {
+			value := filter.value(p)
+			if filter.substring && !strings.Contains(value, filter.want) || !filter.substring && value != filter.want {
+				return false
+			}
+		}
+		return true
+	}
+
+	if field == "count" {
+		return UnitNone, func(_ models.SystemStats, p models.ProcessList) (float64, bool) {
+			var count float64
+			for _, proc := range p.Processes {
+				if match(proc) {
+					count++
+				}
+			}
+			return count, true
+		}, nil
+	}
+
+	i := strings.LastIndexByte(field, '_')
+	v, ok := values[field[:max(0, i)]]
+	aggregate := field[i+1:]
+	if !ok || aggregate != "sum" && aggregate != "max" {
+		return "", nil, fmt.Errorf("unknown process field %q (want count, or cpu, mem, rss, swap, read_rate or write_rate with _sum or _max)", field)
+	}
+	return v.unit, func(_ models.SystemStats, p models.ProcessList) (float64, bool) {
+		var result float64
+		found := false
+		for _, proc := range p.Processes {
+			if !match(proc) {
+				continue
+			}
+			found = true
+			if aggregate == "sum" {
+				result += v.value(proc)
+			} else {
+				result = max(result, v.value(proc))
+			}
+		}
+		return result, found
+	}, nil
+}
+
+// splitArgs splits selector arguments at commas outside quotes
+func splitArgs(args string) []string {
+	var parts []string
+	quoted, start := false, 0
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == '\\' && quoted:
+			i++
+		case args[i] == '"':
+			quoted = !quoted
+		case args[i] == ',' && !quoted:
+			parts = append(parts, args[start:i])
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(args[start:]); rest != "" {
+		parts = append(parts, rest)
+	}
+	return parts
+}
+
+// parseThreshold parses a rule threshold. Percent signs are allowed, and
+// K, M, G and T suffixes (powers of 1024) for byte values.
+func parseThreshold(text string) (float64, error) {
+	text = strings.TrimSuffix(text, "%")
+	multiplier := 1.0
+	if n := len(text); n > 1 {
+		if i := strings.IndexByte("KMGT", text[n-1]); i >= 0 {
+			multiplier = float64(uint64(1) << (10 * (i + 1)))
+			text = text[:n-1]
+		}
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid threshold %q", text)
+	}
+	return value * multiplier, nil
+}
+
+// Watch is an expression shown in the footer every tick, with an optional
+// label: "pg memory: proc[name=\"postgres\"].rss_sum"
+type Watch struct {
+	Label string
+	Expr  Expr
+}
+
+// ParseWatches parses the watch expressions of the config
+func ParseWatches(sources []string) ([]Watch, error) {
+	watches := make([]Watch, 0, len(sources))
+	for i, source := range sources {
+		watch := Watch{}
+		text := strings.TrimSpace(source)
+		// A colon before any selector or quote ends a label
+		if colon := strings.IndexByte(text, ':'); colon > 0 && !strings.ContainsAny(text[:colon], `["`) {
+			watch.Label = strings.TrimSpace(text[:colon])
+			text = text[colon+1:]
+		}
+		expr, err := ParseExpr(text)
+		if err != nil {
+			return nil, fmt.Errorf("watch %d: %w", i+1, err)
+		}
+		watch.Expr = expr
+		if watch.Label == "" {
+			watch.Label = expr.Source
+		}
+		watches = append(watches, watch)
+	}
+	return watches, nil
+}
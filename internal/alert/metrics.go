@@ -0,0 +1,97 @@
+package alert
+
+import (
+	"slices"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// metric is a named value of a stats sample
+type metric struct {
+	unit  string
+	value func(models.SystemStats, models.ProcessList) float64
+}
+
+// metrics maps rule metric names to their value in a stats sample
+var metrics = map[string]metric{
+	"cpu.usage": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.CPU.Usage
+	}},
+	"cpu.core_max": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		var highest float64
+		for _, usage := range s.CPU.Cores {
+			if usage > highest {
+				highest = usage
+			}
+		}
+		return highest
+	}},
+	"cpu.temperature": {UnitCelsius, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return float64(s.CPU.Temp)
+	}},
+	"cpu.power": {UnitWatts, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.CPU.Power.PackageWatts
+	}},
+	"memory.usage_percent": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.Memory.UsagePercent
+	}},
+	"swap.usage_percent": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		if s.Memory.SwapTotal == 0 {
+			return 0
+		}
+		return s.Memory.SwapUsed / s.Memory.SwapTotal * 100
+	}},
+	"disk.usage_percent": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		// The fullest filesystem
+		var highest float64
+		for _, disk := range s.Disk {
+			if disk.UsagePercent > highest {
+				highest = disk.UsagePercent
+			}
+		}
+		return highest
+	}},
+	"network.tcp_retrans_percent": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.Network.Protocol.RetransPercent
+	}},
+	"network.listen_overflows": {UnitRate, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.Network.Protocol.ListenOverflows.Rate
+	}},
+	"network.udp_rcvbuf_errors": {UnitRate, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.Network.Protocol.UDPRcvbufErrors.Rate
+	}},
+	"battery.level": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return float64(s.Battery.Level)
+	}},
+	"cgroup.cpu_usage": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.Cgroup.CPUUsage
+	}},
+	"cgroup.memory_percent": {UnitPercent, func(s models.SystemStats, _ models.ProcessList) float64 {
+		return s.Cgroup.MemoryPercent
+	}},
+	"processes.total": {UnitNone, func(_ models.SystemStats, p models.ProcessList) float64 {
+		return float64(p.Total)
+	}},
+	"processes.zombie": {UnitNone, func(_ models.SystemStats, p models.ProcessList) float64 {
+		return float64(p.Zombie)
+	}},
+	"processes.crash_loops": {UnitNone, func(_ models.SystemStats, p models.ProcessList) float64 {
+		return float64(len(p.CrashLoops))
+	}},
+}
+
+// IsKnownMetric reports whether rules can refer to the metric by name
+func IsKnownMetric(name string) bool {
+	_, ok := metrics[name]
+	return ok
+}
+
+// MetricNames returns the names of the metrics, sorted
+func MetricNames() []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
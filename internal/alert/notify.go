@@ -0,0 +1,23 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// Notify sends a desktop notification for a fired alert through notify-send
+func Notify(a Alert) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	urgency := "normal"
+	if a.Rule.Severity == SeverityCritical {
+		urgency = "critical"
+	}
+
+	body := fmt.Sprintf("%s (now %.1f)", a.Rule.Source, a.Value)
+	return exec.CommandContext(ctx, "notify-send", "--app-name=croptop",
+		"--urgency="+urgency, "croptop alert", body).Run()
+}
@@ -0,0 +1,161 @@
+package alert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	if s == SeverityCritical {
+		return "critical"
+	}
+	return "warning"
+}
+
+// Actions a rule can trigger when it fires, besides showing it in the UI
+const (
+	ActionShow   = "show"
+	ActionNotify = "notify"
+)
+
+// Rule is a parsed alert rule of the form
+//
+//	<metric> <op> <threshold> [for <duration>] [as <severity>] [-> <action>]
+//
+// e.g. "cpu.usage > 90 for 60s as critical -> notify". The metric may be
+// any expression, such as disk["/home"].usage_percent (see Expr).
+type Rule struct {
+	Source    string
+	Metric    string
+	Expr      Expr
+	Operator  string
+	Threshold float64
+	Duration  time.Duration
+	Severity  Severity
+	Action    string
+}
+
+// ParseRule parses a rule expression
+func ParseRule(source string) (Rule, error) {
+	rule := Rule{
+		Source:   strings.TrimSpace(source),
+		Severity: SeverityWarning,
+		Action:   ActionShow,
+	}
+
+	expr, action, hasAction := strings.Cut(rule.Source, "->")
+	if hasAction {
+		rule.Action = strings.TrimSpace(action)
+		switch rule.Action {
+		case ActionShow, ActionNotify:
+		default:
+			return rule, fmt.Errorf("unknown action %q (want %s or %s)", rule.Action, ActionShow, ActionNotify)
+		}
+	}
+
+	// The metric may contain quoted spaces, e.g. proc[name="Web Content"]
+	metric, tail := splitExpr(expr)
+	fields := append([]string{metric}, strings.Fields(tail)...)
+	if len(fields) < 3 {
+		return rule, fmt.Errorf("expected \"<metric> <op> <threshold>\", got %q", expr)
+	}
+
+	rule.Metric = fields[0]
+	parsed, err := ParseExpr(rule.Metric)
+	if err != nil {
+		return rule, err
+	}
+	rule.Expr = parsed
+
+	rule.Operator = fields[1]
+	switch rule.Operator {
+	case ">", ">=", "<", "<=", "==", "!=":
+	default:
+		return rule, fmt.Errorf("unknown operator %q", rule.Operator)
+	}
+
+	threshold, err := parseThreshold(fields[2])
+	if err != nil {
+		return rule, err
+	}
+	rule.Threshold = threshold
+
+	// Optional "for <duration>" and "as <severity>" clauses in any order
+	rest := fields[3:]
+	for len(rest) > 0 {
+		if len(rest) < 2 {
+			return rule, fmt.Errorf("unexpected %q", rest[0])
+		}
+
+		switch rest[0] {
+		case "for":
+			duration, err := time.ParseDuration(rest[1])
+			if err != nil {
+				return rule, fmt.Errorf("invalid duration %q", rest[1])
+			}
+			rule.Duration = duration
+		case "as":
+			switch rest[1] {
+			case "warning":
+				rule.Severity = SeverityWarning
+			case "critical":
+				rule.Severity = SeverityCritical
+			default:
+				return rule, fmt.Errorf("unknown severity %q", rest[1])
+			}
+		default:
+			return rule, fmt.Errorf("unexpected %q", rest[0])
+		}
+		rest = rest[2:]
+	}
+
+	return rule, nil
+}
+
+// ParseRules parses all rules, reporting the first invalid one
+func ParseRules(sources []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(sources))
+	for i, source := range sources {
+		rule, err := ParseRule(source)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// matches reports whether the value satisfies the rule condition
+func (r Rule) matches(value float64) bool {
+	switch r.Operator {
+	case ">":
+		return value > r.Threshold
+	case ">=":
+		return value >= r.Threshold
+	case "<":
+		return value < r.Threshold
+	case "<=":
+		return value <= r.Threshold
+	case "==":
+		return value == r.Threshold
+	case "!=":
+		return value != r.Threshold
+	}
+	return false
+}
+
+// worse reports whether a is further past the threshold than b
+func (r Rule) worse(a, b float64) bool {
+	if r.Operator == "<" || r.Operator == "<=" {
+		return a < b
+	}
+	return a > b
+}
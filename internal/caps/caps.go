@@ -0,0 +1,80 @@
+// Package caps opens the files a user names, in the config or on the command
+// line, without the CAP_DAC_READ_SEARCH that `croptop grant-caps` may give
+// the binary. Otherwise anyone who can run croptop could point, say,
+// serve.tls.key at /etc/shadow and have it read.
+package caps
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	linuxCapabilityVersion3 = 0x20080522
+	capDACReadSearch        = 2
+)
+
+type capHeader struct {
+	version uint32
+	pid     int32
+}
+
+type capData struct {
+	effective   uint32
+	permitted   uint32
+	inheritable uint32
+}
+
+// Open opens path for reading as the user running croptop could.
+// Capabilities belong to a thread, so the file is opened on a thread of its
+// own that drops CAP_DAC_READ_SEARCH and is never used again: a goroutine
+// that ends locked to its thread takes the thread with it.
+func Open(path string) (*os.File, error) {
+	type result struct {
+		file *os.File
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := dropDACReadSearch(); err != nil {
+			done <- result{err: &os.PathError{Op: "open", Path: path, Err: err}}
+			return
+		}
+		file, err := os.Open(path)
+		done <- result{file, err}
+	}()
+	r := <-done
+	return r.file, r.err
+}
+
+// ReadFile reads path as the user running croptop could, like os.ReadFile
+func ReadFile(path string) ([]byte, error) {
+	file, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// dropDACReadSearch clears CAP_DAC_READ_SEARCH from the effective set of
+// the calling thread, if it has it
+func dropDACReadSearch() error {
+	header := capHeader{version: linuxCapabilityVersion3}
+	var data [2]capData
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CAPGET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return errno
+	}
+	if data[0].effective&(1<<capDACReadSearch) == 0 {
+		return nil
+	}
+	data[0].effective &^= 1 << capDACReadSearch
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CAPSET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// SignalProcess sends sig to the process pid
+func (s *StatsCollector) SignalProcess(pid int, sig syscall.Signal) error {
+	return syscall.Kill(pid, sig)
+}
+
+// ReniceProcess sets the nice value of every thread of the process pid.
+// Linux keeps a nice value per thread, setpriority on the PID alone would
+// leave the other threads of a multithreaded process as they were.
+func (s *StatsCollector) ReniceProcess(pid, nice int) error {
+	tasks, err := os.ReadDir(fmt.Sprintf("/proc/%d/task", pid))
+	if err != nil {
+		return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		// Threads may exit meanwhile
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, nice); err != nil && err != syscall.ESRCH {
+			return err
+		}
+	}
+	return nil
+}
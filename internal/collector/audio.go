@@ -0,0 +1,257 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// audioServerNames are the processes of the sound servers
+var audioServerNames = map[string]bool{
+	"pipewire": true, "pipewire-pulse": true, "wireplumber": true,
+	"pipewire-media-session": true, "pulseaudio": true,
+}
+
+// GetAudioStats describes the running PipeWire or PulseAudio server, from
+// pw-dump or pactl
+func (s *StatsCollector) GetAudioStats(processes models.ProcessList) models.AudioStats {
+	var stats models.AudioStats
+	names := make(map[int]string)
+	for _, proc := range processes.Processes {
+		names[proc.PID] = proc.Name
+		if !audioServerNames[proc.Name] {
+			continue
+		}
+		stats.Processes = append(stats.Processes, models.AudioProcess{PID: proc.PID, Name: proc.Name, CPUPercent: proc.CPUPercent})
+		switch {
+		case proc.Name == "pulseaudio":
+			stats.Server = "PulseAudio"
+		case stats.Server == "":
+			stats.Server = "PipeWire"
+		}
+	}
+	sort.Slice(stats.Processes, func(i, j int) bool { return stats.Processes[i].Name < stats.Processes[j].Name })
+
+	switch stats.Server {
+	case "PipeWire":
+		readPipeWire(&stats)
+	case "PulseAudio":
+		readPulseAudio(&stats)
+	}
+	for i := range stats.Streams {
+		stats.Streams[i].Process = names[stats.Streams[i].PID]
+	}
+	return stats
+}
+
+// runAudioTool returns the output of a sound server tool
+func runAudioTool(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		slog.Debug("reading the sound server failed", "command", name, "err", err)
+	}
+	return output, err
+}
+
+// pipeWireObject is the part of a pw-dump object croptop reads
+type pipeWireObject struct {
+	Type string `json:"type"`
+	Info struct {
+		State  string         `json:"state"`
+		Props  map[string]any `json:"props"`
+		Params struct {
+			Format []struct {
+				Rate     int `json:"rate"`
+				Channels int `json:"channels"`
+			} `json:"Format"`
+		} `json:"params"`
+	} `json:"info"`
+	// Metadata objects have these instead
+	Props    map[string]any `json:"props"`
+	Metadata []struct {
+		Key   string `json:"key"`
+		Value any    `json:"value"`
+	} `json:"metadata"`
+}
+
+func readPipeWire(stats *models.AudioStats) {
+	output, err := runAudioTool("pw-dump")
+	if err != nil {
+		return
+	}
+	var objects []pipeWireObject
+	if err := json.Unmarshal(output, &objects); err != nil {
+		slog.Debug("parsing pw-dump failed", "err", err)
+		return
+	}
+
+	settings := make(map[string]int)
+	for _, object := range objects {
+		switch object.Type {
+		case "PipeWire:Interface:Metadata":
+			if object.Props["metadata.name"] != "settings" {
+				continue
+			}
+			for _, entry := range object.Metadata {
+				if value, ok := propInt(entry.Value); ok {
+					settings[entry.Key] = value
+				}
+			}
+		case "PipeWire:Interface:Node":
+			props := object.Info.Props
+			class := propString(props["media.class"])
+			if class != "Stream/Output/Audio" && class != "Stream/Input/Audio" {
+				continue
+			}
+			stream := models.AudioStream{
+				Application: propString(props["application.name"]),
+				Media:       propString(props["media.name"]),
+				Capture:     class == "Stream/Input/Audio",
+				State:       object.Info.State,
+				Latency:     propString(props["node.latency"]),
+			}
+			stream.PID, _ = propInt(props["application.process.id"])
+			if formats := object.Info.Params.Format; len(formats) > 0 {
+				stream.SampleRate, stream.Channels = formats[0].Rate, formats[0].Channels
+			}
+			stats.Streams = append(stats.Streams, stream)
+		}
+	}
+
+	stats.SampleRate = settings["clock.rate"]
+	if forced := settings["clock.force-rate"]; forced > 0 {
+		stats.SampleRate = forced
+	}
+	stats.Quantum = pipeWireQuantum(settings, stats.Streams, stats.SampleRate)
+}
+
+// pipeWireQuantum estimates the quantum the graph runs at: the forced one,
+// or else the lowest latency a running stream asks for, rounded down to a
+// power of two as PipeWire does, within the allowed range, or the default
+// when none asks
+func pipeWireQuantum(settings map[string]int, streams []models.AudioStream, rate int) int {
+	if forced := settings["clock.force-quantum"]; forced > 0 {
+		return forced
+	}
+	quantum := 0
+	for _, stream := range streams {
+		if stream.State != "running" {
+			continue
+		}
+		// "256/48000" is 256 frames at 48 kHz, scaled to the graph's rate
+		frames, streamRate, ok := strings.Cut(stream.Latency, "/")
+		n, err1 := strconv.Atoi(frames)
+		r, err2 := strconv.Atoi(streamRate)
+		if !ok || err1 != nil || err2 != nil || r == 0 || rate == 0 {
+			continue
+		}
+		if scaled := n * rate / r; quantum == 0 || scaled < quantum {
+			quantum = scaled
+		}
+	}
+	if quantum == 0 {
+		return settings["clock.quantum"]
+	}
+	for quantum&(quantum-1) != 0 {
+		quantum &= quantum - 1
+	}
+	if minimum := settings["clock.min-quantum"]; minimum > 0 {
+		quantum = max(quantum, minimum)
+	}
+	if maximum := settings["clock.max-quantum"]; maximum > 0 {
+		quantum = min(quantum, maximum)
+	}
+	return quantum
+}
+
+func readPulseAudio(stats *models.AudioStats) {
+	if output, err := runAudioTool("pactl", "-f", "json", "info"); err == nil {
+		var info struct {
+			SampleSpec string `json:"default_sample_specification"`
+		}
+		if json.Unmarshal(output, &info) == nil {
+			stats.SampleRate, _ = parseSampleSpec(info.SampleSpec)
+		}
+	}
+
+	for _, list := range []string{"sink-inputs", "source-outputs"} {
+		output, err := runAudioTool("pactl", "-f", "json", "list", list)
+		if err != nil {
+			continue
+		}
+		var entries []struct {
+			SampleSpec    string            `json:"sample_specification"`
+			Corked        bool              `json:"corked"`
+			BufferLatency float64           `json:"buffer_latency_usec"`
+			Properties    map[string]string `json:"properties"`
+		}
+		if err := json.Unmarshal(output, &entries); err != nil {
+			slog.Debug("parsing pactl failed", "list", list, "err", err)
+			continue
+		}
+		for _, entry := range entries {
+			stream := models.AudioStream{
+				Application: entry.Properties["application.name"],
+				Media:       entry.Properties["media.name"],
+				Capture:     list == "source-outputs",
+				State:       "running",
+			}
+			if entry.Corked {
+				stream.State = "corked"
+			}
+			stream.PID, _ = strconv.Atoi(entry.Properties["application.process.id"])
+			stream.SampleRate, stream.Channels = parseSampleSpec(entry.SampleSpec)
+			if entry.BufferLatency > 0 {
+				stream.Latency = fmt.Sprintf("%.0fms", entry.BufferLatency/1000)
+			}
+			stats.Streams = append(stats.Streams, stream)
+		}
+	}
+}
+
+// parseSampleSpec reads a PulseAudio sample specification, "s16le 2ch
+// 44100Hz"
+func parseSampleSpec(spec string) (rate, channels int) {
+	for _, field := range strings.Fields(spec) {
+		if value, ok := strings.CutSuffix(field, "Hz"); ok {
+			rate, _ = strconv.Atoi(value)
+		}
+		if value, ok := strings.CutSuffix(field, "ch"); ok {
+			channels, _ = strconv.Atoi(value)
+		}
+	}
+	return rate, channels
+}
+
+// propString and propInt read PipeWire properties, which pw-dump prints
+// as strings or numbers
+func propString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func propInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
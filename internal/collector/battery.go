@@ -2,6 +2,7 @@ package collector
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -11,16 +12,33 @@ import (
 )
 
 func (s *StatsCollector) getBatteryStats() models.BatteryStats {
+	stats := s.readBatteryStats()
+	stats.Inhibitors = s.getInhibitors()
+	stats.Power = s.getPowerSettings()
+	return stats
+}
+
+func (s *StatsCollector) readBatteryStats() models.BatteryStats {
+	// Prefer UPower's calibrated estimates, it also knows peripheral batteries
+	if stats, ok := s.getUPowerBatteryStats(); ok {
+		if len(stats.Peripherals) == 0 {
+			stats.Peripherals = s.getPeripheralBatteries()
+		}
+		return stats
+	}
+
 	// Find battery directory
 	batteryDirs, err := filepath.Glob("/sys/class/power_supply/BAT*")
 	if err != nil || len(batteryDirs) == 0 {
 		// No battery found (desktop system)
 		return models.BatteryStats{
-			Level:      100,
-			Status:     "Not Available",
-			TimeLeft:   "N/A",
-			IsCharging: false,
-			Health:     100,
+			Level:       100,
+			Status:      "Not Available",
+			TimeLeft:    "N/A",
+			IsCharging:  false,
+			Health:      100,
+			Source:      "sysfs",
+			Peripherals: s.getPeripheralBatteries(),
 		}
 	}
 
@@ -42,12 +60,112 @@ func (s *StatsCollector) getBatteryStats() models.BatteryStats {
 	health := s.getBatteryHealth(batteryDir)
 
 	return models.BatteryStats{
-		Level:      level,
-		Status:     status,
-		TimeLeft:   timeLeft,
-		IsCharging: isCharging,
-		Health:     health,
+		Level:       level,
+		Status:      status,
+		TimeLeft:    timeLeft,
+		IsCharging:  isCharging,
+		Health:      health,
+		Source:      "sysfs",
+		Watts:       s.readBatteryWatts(batteryDir),
+		Peripherals: s.getPeripheralBatteries(),
+	}
+}
+
+// readBatteryWatts returns the charge or discharge rate of a battery.
+// Batteries report either power_now or current_now with voltage_now, in
+// micro units, and some report discharging as negative.
+func (s *StatsCollector) readBatteryWatts(batteryDir string) float64 {
+	if power := s.readBatteryInt(batteryDir + "/power_now"); power != 0 {
+		return math.Abs(float64(power)) / 1e6
+	}
+	current := s.readBatteryInt(batteryDir + "/current_now")
+	voltage := s.readBatteryInt(batteryDir + "/voltage_now")
+	return math.Abs(float64(current)) * float64(voltage) / 1e12
+}
+
+// getPeripheralBatteries lists device batteries (bluetooth mice, keyboards,
+// earbuds, ...) which the kernel marks with scope "Device"
+func (s *StatsCollector) getPeripheralBatteries() []models.PeripheralBattery {
+	supplyDirs, err := filepath.Glob("/sys/class/power_supply/*")
+	if err != nil {
+		return nil
+	}
+
+	var peripherals []models.PeripheralBattery
+	for _, dir := range supplyDirs {
+		name := filepath.Base(dir)
+		if strings.HasPrefix(name, "BAT") {
+			continue
+		}
+		if s.readBatteryString(dir+"/type") != "Battery" || s.readBatteryString(dir+"/scope") != "Device" {
+			continue
+		}
+
+		level, ok := readCapacity(dir)
+		if !ok {
+			continue
+		}
+
+		if model := s.readBatteryString(dir + "/model_name"); model != "Unknown" && model != "" {
+			name = model
+		}
+
+		peripherals = append(peripherals, models.PeripheralBattery{
+			Name:   name,
+			Type:   peripheralType(filepath.Base(dir)),
+			Level:  level,
+			Status: s.readBatteryString(dir + "/status"),
+		})
+	}
+
+	return peripherals
+}
+
+// readCapacity reads the exact capacity, or approximates it from
+// capacity_level for devices that only report coarse levels
+func readCapacity(dir string) (int, bool) {
+	if content, err := os.ReadFile(dir + "/capacity"); err == nil {
+		if val, err := strconv.Atoi(strings.TrimSpace(string(content))); err == nil {
+			return val, true
+		}
+	}
+
+	content, err := os.ReadFile(dir + "/capacity_level")
+	if err != nil {
+		return 0, false
+	}
+
+	switch strings.TrimSpace(string(content)) {
+	case "Full":
+		return 100, true
+	case "High":
+		return 80, true
+	case "Normal":
+		return 50, true
+	case "Low":
+		return 20, true
+	case "Critical":
+		return 5, true
+	}
+	return 0, false
+}
+
+// peripheralType guesses the device kind from the power supply name
+func peripheralType(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "mouse"):
+		return "mouse"
+	case strings.Contains(name, "keyboard") || strings.Contains(name, "kbd"):
+		return "keyboard"
+	case strings.Contains(name, "headset") || strings.Contains(name, "headphone"):
+		return "headset"
+	case strings.Contains(name, "controller") || strings.Contains(name, "sony") || strings.Contains(name, "nintendo"):
+		return "gaming-input"
+	case strings.HasPrefix(name, "hid-") || strings.HasPrefix(name, "hidpp_"):
+		return "hid"
 	}
+	return "device"
 }
 
 func (s *StatsCollector) readBatteryInt(path string) int {
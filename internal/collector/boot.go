@@ -0,0 +1,263 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// bootCache keeps the systemd-analyze results, which only change with the
+// next boot, and the previous cpu usage of the units' cgroups
+type bootCache struct {
+	mutex sync.Mutex
+	stats models.BootStats
+	// Set once the boot finished and was analyzed
+	analyzed bool
+	usage    map[string]uint64
+	sampled  time.Time
+}
+
+// HasSystemd reports whether the machine was booted with systemd and
+// systemd-analyze is installed
+func (s *StatsCollector) HasSystemd() bool {
+	if _, err := os.Stat("/run/systemd/system"); err != nil {
+		return false
+	}
+	_, err := exec.LookPath("systemd-analyze")
+	return err == nil
+}
+
+// GetBootStats returns how long the last boot took, the units that slowed
+// it down and the critical chain, from systemd-analyze, with what each
+// unit's cgroup uses now
+func (s *StatsCollector) GetBootStats() models.BootStats {
+	cache := &s.boot
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if !cache.analyzed {
+		cache.stats = analyzeBoot()
+		cache.analyzed = cache.stats.Error == ""
+	}
+
+	stats := cache.stats
+	stats.Units = make([]models.BootUnit, len(cache.stats.Units))
+	copy(stats.Units, cache.stats.Units)
+
+	cpuRoot, memRoot, version := systemSliceRoots()
+	now := time.Now()
+	elapsed := now.Sub(cache.sampled).Microseconds()
+	current := make(map[string]uint64)
+	for i := range stats.Units {
+		unit := &stats.Units[i]
+		if memRoot == "" {
+			break
+		}
+		// Units without processes, such as mounts and services that
+		// exited, have no cgroup
+		memDir := filepath.Join(memRoot, unit.Name)
+		if _, err := os.Stat(memDir); err != nil {
+			continue
+		}
+		unit.Running = true
+		unit.MemoryUsed, _ = cgroupMemory(memDir, version)
+		if pressure, ok := cgroupPressure(memDir); ok && version == 2 {
+			unit.Pressure = &pressure
+		}
+
+		usage, ok := cgroupCPUUsage(filepath.Join(cpuRoot, unit.Name), version)
+		if !ok {
+			continue
+		}
+		current[unit.Name] = usage
+		if prev, ok := cache.usage[unit.Name]; ok && elapsed > 0 && usage >= prev {
+			unit.CPUPercent = float64(usage-prev) / float64(elapsed) * 100
+		}
+	}
+	cache.usage = current
+	cache.sampled = now
+	return stats
+}
+
+// systemSliceRoots returns the cpu and memory cgroup directories of
+// system.slice, where systemd puts the system services
+func systemSliceRoots() (string, string, int) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		dir := filepath.Join(cgroupRoot, "system.slice")
+		return dir, dir, 2
+	}
+
+	memDir := filepath.Join(cgroupRoot, "memory", "system.slice")
+	if _, err := os.Stat(memDir); err != nil {
+		return "", "", 1
+	}
+	for _, cpuMount := range []string{"cpu,cpuacct", "cpu", "cpuacct"} {
+		cpuDir := filepath.Join(cgroupRoot, cpuMount, "system.slice")
+		if _, err := os.Stat(cpuDir); err == nil {
+			return cpuDir, memDir, 1
+		}
+	}
+	return "", memDir, 1
+}
+
+// analyzeBoot runs systemd-analyze time, blame and critical-chain
+func analyzeBoot() models.BootStats {
+	var stats models.BootStats
+
+	output, err := runSystemdAnalyze("time")
+	if err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+	stats.Phases, stats.Total = parseBootTime(output)
+
+	if output, err := runSystemdAnalyze("blame"); err == nil {
+		stats.Units = parseBootBlame(output)
+	}
+	if output, err := runSystemdAnalyze("critical-chain"); err == nil {
+		stats.Chain = parseCriticalChain(output)
+	}
+	return stats
+}
+
+// runSystemdAnalyze returns the output of a systemd-analyze command, or what
+// it printed on failure, e.g. that the boot is not finished yet
+func runSystemdAnalyze(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "systemd-analyze", "--no-pager", command).Output()
+	if err != nil {
+		slog.Debug("systemd-analyze failed", "command", command, "err", err)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// parseBootTime reads the phases from the first line of systemd-analyze
+// time: "Startup finished in 4.1s (firmware) + 1.2s (loader) + 1.5s
+// (kernel) + 10.2s (userspace) = 17.0s"
+func parseBootTime(output string) ([]models.BootPhase, time.Duration) {
+	line, _, _ := strings.Cut(output, "\n")
+	_, line, ok := strings.Cut(line, "finished in ")
+	if !ok {
+		return nil, 0
+	}
+	phases, total, _ := strings.Cut(line, " = ")
+
+	var result []models.BootPhase
+	for _, phase := range strings.Split(phases, " + ") {
+		open := strings.LastIndexByte(phase, '(')
+		if open < 0 {
+			continue
+		}
+		duration, ok := parseSystemdDuration(phase[:open])
+		if !ok {
+			continue
+		}
+		result = append(result, models.BootPhase{
+			Name:     strings.TrimSuffix(phase[open+1:], ")"),
+			Duration: duration,
+		})
+	}
+	totalDuration, _ := parseSystemdDuration(total)
+	return result, totalDuration
+}
+
+// parseBootBlame reads the lines of systemd-analyze blame, "1min 2.5s
+// foo.service", slowest first
+func parseBootBlame(output string) []models.BootUnit {
+	var units []models.BootUnit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		duration, ok := parseSystemdDuration(strings.Join(fields[:len(fields)-1], " "))
+		if !ok {
+			continue
+		}
+		units = append(units, models.BootUnit{Name: fields[len(fields)-1], StartTime: duration})
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].StartTime > units[j].StartTime })
+	return units
+}
+
+// parseCriticalChain reads the tree of systemd-analyze critical-chain,
+// where each unit is indented under the one it waited for:
+//
+//	graphical.target @8.2s
+//	└─multi-user.target @8.2s
+//	  └─docker.service @5.1s +3.1s
+func parseCriticalChain(output string) []models.CriticalUnit {
+	var chain []models.CriticalUnit
+	for _, line := range strings.Split(output, "\n") {
+		at := strings.Index(line, " @")
+		if at < 0 {
+			continue
+		}
+		// Tree drawing before the name, two columns per level
+		name := strings.TrimLeft(line[:at], " │└├─")
+		if name == "" {
+			continue
+		}
+		prefix := []rune(line[:strings.Index(line, name)])
+
+		active, took, _ := strings.Cut(line[at+2:], " +")
+		unit := models.CriticalUnit{Name: name, Depth: len(prefix) / 2}
+		unit.Active, _ = parseSystemdDuration(active)
+		unit.Took, _ = parseSystemdDuration(took)
+		chain = append(chain, unit)
+	}
+	return chain
+}
+
+// systemdUnits are the units of the durations systemd prints
+var systemdUnits = map[string]time.Duration{
+	"us":    time.Microsecond,
+	"ms":    time.Millisecond,
+	"s":     time.Second,
+	"min":   time.Minute,
+	"h":     time.Hour,
+	"d":     24 * time.Hour,
+	"w":     7 * 24 * time.Hour,
+	"month": 2629800 * time.Second,
+	"y":     31557600 * time.Second,
+}
+
+// parseSystemdDuration parses durations such as "345ms", "2.5s" and
+// "1min 2.345s"
+func parseSystemdDuration(s string) (time.Duration, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	var total time.Duration
+	for _, field := range fields {
+		split := strings.IndexFunc(field, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if split <= 0 {
+			return 0, false
+		}
+		value, err := strconv.ParseFloat(field[:split], 64)
+		unit, ok := systemdUnits[field[split:]]
+		if err != nil || !ok {
+			return 0, false
+		}
+		total += time.Duration(value * float64(unit))
+	}
+	return total, true
+}
@@ -38,7 +38,9 @@ type CPUCache struct {
 	previousTime     time.Time
 	cachedUsage      float64
 	cachedCoreUsages []float64
-	usageTime        time.Time
+	// CPU numbers of the cached core usages, the online CPUs
+	cachedCoreIDs []int
+	usageTime     time.Time
 
 	mutex sync.RWMutex
 }
@@ -116,20 +118,30 @@ func (c *CPUCache) SetCachedTemperature(temp float32) {
 	c.temperatureTime = time.Now()
 }
 
-func (c *CPUCache) GetCachedUsage() (float64, []float64) {
+func (c *CPUCache) GetCachedUsage() (float64, []float64, []int) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	return c.cachedUsage, c.cachedCoreUsages
+	return c.cachedUsage, c.cachedCoreUsages, c.cachedCoreIDs
 }
 
-func (c *CPUCache) SetCachedUsage(usage float64, coreUsages []float64) {
+func (c *CPUCache) SetCachedUsage(usage float64, coreUsages []float64, coreIDs []int) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	c.cachedUsage = usage
 	c.cachedCoreUsages = coreUsages
+	c.cachedCoreIDs = coreIDs
 	c.usageTime = time.Now()
 }
 
+// InvalidateModel forces the model and frequency to be read again, e.g.
+// after CPUs were hotplugged
+func (c *CPUCache) InvalidateModel() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.model = ""
+	c.frequency = 0
+}
+
 func (c *CPUCache) GetPreviousStats() (map[string]CPUTimes, time.Time) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
@@ -159,6 +171,7 @@ func (c *CPUCache) Clear() {
 	c.temperature = 0
 	c.cachedUsage = 0
 	c.cachedCoreUsages = nil
+	c.cachedCoreIDs = nil
 	c.previousStats = make(map[string]CPUTimes)
 }
 
@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// capabilityNames are the capabilities by their bit, from
+// linux/capability.h
+var capabilityNames = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_DAC_READ_SEARCH", "CAP_FOWNER",
+	"CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID",
+	"CAP_SETPCAP", "CAP_LINUX_IMMUTABLE", "CAP_NET_BIND_SERVICE", "CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN", "CAP_NET_RAW", "CAP_IPC_LOCK", "CAP_IPC_OWNER",
+	"CAP_SYS_MODULE", "CAP_SYS_RAWIO", "CAP_SYS_CHROOT", "CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT", "CAP_SYS_ADMIN", "CAP_SYS_BOOT", "CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE", "CAP_SYS_TIME", "CAP_SYS_TTY_CONFIG", "CAP_MKNOD",
+	"CAP_LEASE", "CAP_AUDIT_WRITE", "CAP_AUDIT_CONTROL", "CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE", "CAP_MAC_ADMIN", "CAP_SYSLOG", "CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND", "CAP_AUDIT_READ", "CAP_PERFMON", "CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// seccompModes are the Seccomp values of /proc/[pid]/status
+var seccompModes = map[string]string{"0": "disabled", "1": "strict", "2": "filter"}
+
+// readProcessSecurity decodes the effective capabilities, seccomp mode and
+// no_new_privs flag from the status fields of a process. Known is false
+// when the status was not readable.
+func readProcessSecurity(status map[string]string) models.ProcessSecurity {
+	effective, err := strconv.ParseUint(status["CapEff"], 16, 64)
+	if err != nil {
+		return models.ProcessSecurity{}
+	}
+	security := models.ProcessSecurity{Known: true, NoNewPrivs: status["NoNewPrivs"] == "1"}
+	security.Capabilities, security.MissingCapabilities = decodeCapabilities(effective, lastCapability())
+	if mode, ok := seccompModes[status["Seccomp"]]; ok {
+		security.Seccomp = mode
+	}
+	security.SeccompFilters, _ = strconv.Atoi(status["Seccomp_filters"])
+	return security
+}
+
+// decodeCapabilities names the capabilities set in mask and those of the
+// kernel's, up to bit last, that are not
+func decodeCapabilities(mask uint64, last int) (set, missing []string) {
+	for bit := 0; bit <= last; bit++ {
+		name := "CAP_" + strconv.Itoa(bit)
+		if bit < len(capabilityNames) {
+			name = capabilityNames[bit]
+		}
+		if mask&(1<<bit) != 0 {
+			set = append(set, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	return set, missing
+}
+
+// lastCapability is the highest capability the kernel knows
+func lastCapability() int {
+	content, err := os.ReadFile("/proc/sys/kernel/cap_last_cap")
+	if err != nil {
+		return len(capabilityNames) - 1
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || last < 0 || last > 63 {
+		return len(capabilityNames) - 1
+	}
+	return last
+}
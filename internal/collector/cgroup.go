@@ -0,0 +1,291 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupInfo locates the cgroup directories croptop itself belongs to
+type cgroupInfo struct {
+	version   int
+	path      string
+	cpuDir    string
+	cpuRoot   string
+	memoryDir string
+	memRoot   string
+
+	// previous cpu usage reading in microseconds
+	mutex        sync.Mutex
+	lastUsage    uint64
+	lastSampled  time.Time
+	lastCPUUsage float64
+}
+
+// detectCgroup resolves the cgroup directories from /proc/self/cgroup
+func detectCgroup() *cgroupInfo {
+	content, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return nil
+	}
+
+	// cgroup v2 exposes a single unified hierarchy
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			if path, ok := strings.CutPrefix(line, "0::"); ok {
+				dir := resolveCgroupDir(cgroupRoot, path)
+				return &cgroupInfo{
+					version:   2,
+					path:      path,
+					cpuDir:    dir,
+					cpuRoot:   cgroupRoot,
+					memoryDir: dir,
+					memRoot:   cgroupRoot,
+				}
+			}
+		}
+		return nil
+	}
+
+	info := &cgroupInfo{version: 1}
+	for _, line := range strings.Split(string(content), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		for _, controller := range strings.Split(parts[1], ",") {
+			switch controller {
+			case "memory":
+				info.path = parts[2]
+				info.memRoot = filepath.Join(cgroupRoot, "memory")
+				info.memoryDir = resolveCgroupDir(info.memRoot, parts[2])
+			case "cpu":
+				info.cpuRoot = filepath.Join(cgroupRoot, parts[1])
+				if _, err := os.Stat(info.cpuRoot); err != nil {
+					info.cpuRoot = filepath.Join(cgroupRoot, "cpu")
+				}
+				info.cpuDir = resolveCgroupDir(info.cpuRoot, parts[2])
+			}
+		}
+	}
+
+	if info.memoryDir == "" && info.cpuDir == "" {
+		return nil
+	}
+	return info
+}
+
+// resolveCgroupDir maps a cgroup path to its directory. Inside a cgroup
+// namespace the hierarchy is mounted at our own cgroup, so fall back to the root.
+func resolveCgroupDir(root, path string) string {
+	dir := filepath.Join(root, path)
+	if _, err := os.Stat(dir); err == nil {
+		return dir
+	}
+	return root
+}
+
+func (s *StatsCollector) getCgroupStats(hostMemTotal float64) models.CgroupStats {
+	info := s.cgroup
+	if info == nil {
+		return models.CgroupStats{}
+	}
+
+	stats := models.CgroupStats{
+		Path:    info.path,
+		Version: info.version,
+	}
+
+	stats.CPULimit = info.cpuLimit()
+	if stats.CPULimit > 0 {
+		stats.CPUUsage = info.cpuUsagePercent(stats.CPULimit)
+	}
+
+	limit, used := info.memory()
+	// Limits at or above the physical memory size do not constrain anything
+	if limit > 0 && (hostMemTotal == 0 || limit < hostMemTotal) {
+		stats.MemoryLimit = limit
+		stats.MemoryUsed = used
+		stats.MemoryPercent = used / limit * 100
+	}
+
+	return stats
+}
+
+// cpuLimit returns the effective quota in cores, the smallest along the hierarchy
+func (c *cgroupInfo) cpuLimit() float64 {
+	var limit float64
+	walkCgroupDirs(c.cpuDir, c.cpuRoot, func(dir string) {
+		var quota, period float64
+		if c.version == 2 {
+			fields := strings.Fields(readCgroupString(filepath.Join(dir, "cpu.max")))
+			if len(fields) != 2 || fields[0] == "max" {
+				return
+			}
+			quota, _ = strconv.ParseFloat(fields[0], 64)
+			period, _ = strconv.ParseFloat(fields[1], 64)
+		} else {
+			quota, _ = strconv.ParseFloat(readCgroupString(filepath.Join(dir, "cpu.cfs_quota_us")), 64)
+			period, _ = strconv.ParseFloat(readCgroupString(filepath.Join(dir, "cpu.cfs_period_us")), 64)
+		}
+
+		if quota > 0 && period > 0 {
+			cores := quota / period
+			if limit == 0 || cores < limit {
+				limit = cores
+			}
+		}
+	})
+	return limit
+}
+
+// cpuUsagePercent returns the cgroup's cpu usage as a percentage of its quota
+func (c *cgroupInfo) cpuUsagePercent(cores float64) float64 {
+	usage, ok := cgroupCPUUsage(c.cpuDir, c.version)
+	if !ok && c.version == 1 {
+		// cpuacct may be mounted separately from cpu
+		usage, _ = cgroupCPUUsage(resolveCgroupDir(filepath.Join(cgroupRoot, "cpuacct"), c.path), 1)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(c.lastSampled).Microseconds()
+	if !c.lastSampled.IsZero() && elapsed > 0 && usage >= c.lastUsage {
+		percent := float64(usage-c.lastUsage) / float64(elapsed) / cores * 100
+		if percent > 100 {
+			percent = 100
+		}
+		c.lastCPUUsage = percent
+	}
+	c.lastUsage = usage
+	c.lastSampled = now
+
+	return c.lastCPUUsage
+}
+
+// memory returns the effective memory limit and current usage in KB
+func (c *cgroupInfo) memory() (float64, float64) {
+	limitFile, usageFile := "memory.limit_in_bytes", "memory.usage_in_bytes"
+	if c.version == 2 {
+		limitFile, usageFile = "memory.max", "memory.current"
+	}
+
+	var limit float64
+	walkCgroupDirs(c.memoryDir, c.memRoot, func(dir string) {
+		value := readCgroupString(filepath.Join(dir, limitFile))
+		if value == "" || value == "max" {
+			return
+		}
+		if bytes, err := strconv.ParseFloat(value, 64); err == nil && bytes > 0 {
+			if limit == 0 || bytes < limit {
+				limit = bytes
+			}
+		}
+	})
+
+	used, _ := strconv.ParseFloat(readCgroupString(filepath.Join(c.memoryDir, usageFile)), 64)
+	return limit / 1024, used / 1024
+}
+
+// cgroupCPUUsage returns the total cpu time consumed by a cgroup in microseconds
+func cgroupCPUUsage(dir string, version int) (uint64, bool) {
+	if version == 2 {
+		for _, line := range strings.Split(readCgroupString(filepath.Join(dir, "cpu.stat")), "\n") {
+			if value, ok := strings.CutPrefix(line, "usage_usec "); ok {
+				usage, err := strconv.ParseUint(value, 10, 64)
+				return usage, err == nil
+			}
+		}
+		return 0, false
+	}
+
+	// cpuacct.usage is reported in nanoseconds
+	nanos, err := strconv.ParseUint(readCgroupString(filepath.Join(dir, "cpuacct.usage")), 10, 64)
+	return nanos / 1000, err == nil
+}
+
+// cgroupPressure reads the pressure stall information of a cgroup v2:
+// the share of the last 10 seconds some of its tasks waited for CPU,
+// memory or I/O. ok is false without PSI, on v1 or with psi=0.
+func cgroupPressure(dir string) (pressure models.CgroupPressure, ok bool) {
+	for file, value := range map[string]*float64{
+		"cpu.pressure":    &pressure.CPU,
+		"memory.pressure": &pressure.Memory,
+		"io.pressure":     &pressure.IO,
+	} {
+		// "some avg10=1.23 avg60=0.50 avg300=0.10 total=123456"
+		line, _, _ := strings.Cut(readCgroupString(filepath.Join(dir, file)), "\n")
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "some" {
+			continue
+		}
+		if avg10, found := strings.CutPrefix(fields[1], "avg10="); found {
+			if parsed, err := strconv.ParseFloat(avg10, 64); err == nil {
+				*value, ok = parsed, true
+			}
+		}
+	}
+	return pressure, ok
+}
+
+// walkCgroupDirs calls fn for dir and each of its parents up to root
+func walkCgroupDirs(dir, root string, fn func(string)) {
+	if dir == "" {
+		return
+	}
+	for {
+		fn(dir)
+		if dir == root || !strings.HasPrefix(dir, root) {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func readCgroupString(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// AddCgroups fills in the cgroup path of the processes from
+// /proc/[pid]/cgroup: the unified hierarchy on cgroup v2, otherwise the
+// first controller hierarchy listed
+func (s *StatsCollector) AddCgroups(processes []models.Process) {
+	for i := range processes {
+		processes[i].Cgroup = readProcessCgroup(processes[i].PID)
+	}
+}
+
+func readProcessCgroup(pid int) string {
+	content, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	first := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if first == "" {
+			first = parts[2]
+		}
+	}
+	return first
+}
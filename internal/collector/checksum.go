@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// maxChecksums bounds the executables whose checksum is remembered
+const maxChecksums = 1000
+
+// fileIdentity tells a file apart from the one replacing it at the same path
+type fileIdentity struct {
+	dev, ino uint64
+	size     int64
+	mtime    int64
+}
+
+// checksumCache remembers the SHA256 of executables, so the detail view of
+// a big binary hashes it once rather than every refresh
+type checksumCache struct {
+	mutex     sync.Mutex
+	checksums map[fileIdentity]string
+}
+
+// executableSHA256 hashes the executable of a process through
+// /proc/[pid]/exe, which still opens a deleted one and the file a
+// containerized process runs. Empty when it is not allowed to read it.
+func (s *StatsCollector) executableSHA256(pid int) string {
+	path := fmt.Sprintf("/proc/%d/exe", pid)
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ""
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	id := fileIdentity{dev: stat.Dev, ino: stat.Ino, size: info.Size(), mtime: info.ModTime().UnixNano()}
+
+	cache := &s.checksums
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if checksum, ok := cache.checksums[id]; ok {
+		return checksum
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		slog.Debug("hashing executable failed", "pid", pid, "err", err)
+		return ""
+	}
+	if cache.checksums == nil || len(cache.checksums) >= maxChecksums {
+		cache.checksums = make(map[fileIdentity]string)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	cache.checksums[id] = checksum
+	return checksum
+}
@@ -12,6 +12,80 @@ type StatsCollector struct {
 	lastCPUTimes []uint64
 	bootTime     time.Time
 	cpuCache     *CPUCache
+	cgroup       *cgroupInfo
+
+	// packages and cores of the online CPUs
+	topology cpuTopology
+
+	// previous per-process I/O counters for rate calculation
+	procIOMutex    sync.Mutex
+	lastProcIO     map[int]procIOSample
+	lastProcIOTime time.Time
+
+	// RSS of every process over the last GrowthWindow
+	rssHistory rssHistory
+
+	// previous cpu usage of kubernetes pod cgroups
+	kubeSamples kubeCPUSamples
+
+	// previous counters of qemu/KVM guest processes
+	vmSamples vmSamples
+
+	// kernel process events, nil when the proc connector is unavailable
+	procEvents        *procEventMonitor
+	procEventCounters procEventCounters
+
+	// captured packet headers, nil unless StartTrafficCapture succeeded
+	traffic *trafficMonitor
+
+	// cached `upower --dump` output
+	upower upowerCache
+
+	// cached logind inhibitor locks
+	inhibitors inhibitorCache
+
+	// owning packages and checksums of executables
+	packages  packageCache
+	checksums checksumCache
+
+	// previous GPU engine busy times of the DRM clients
+	gpuSamples gpuSamples
+
+	// cached systemd-analyze results
+	boot bootCache
+
+	// cached power-profiles-daemon state
+	powerProfiles powerProfileCache
+
+	// previous RAPL energy counters
+	rapl raplSamples
+
+	// previous /proc/diskstats counters of whole disks
+	diskSamples diskSamples
+
+	// kernel ring buffer messages read so far
+	kernelLog kernelLog
+
+	// cached failed SSH logins
+	failedLogins failedLoginCache
+
+	// previous TCP/IP protocol counters
+	snmp snmpSamples
+
+	// previous interface byte counters
+	netSamples netSamples
+
+	// cached ARP/NDP neighbor table
+	neighbors neighborCache
+
+	// clock comparison to notice suspend/resume
+	suspend suspendDetector
+
+	// last statfs answers and hung calls per mountpoint
+	statfsCache statfsCache
+
+	// supervised domains of GetSystemStats or a Pipeline
+	domains []*domain
 }
 
 func NewStatsCollector() *StatsCollector {
@@ -20,56 +94,66 @@ func NewStatsCollector() *StatsCollector {
 		lastUpdate: time.Now(),
 		bootTime:   bootTime,
 		cpuCache:   NewCPUCache(),
+		cgroup:     detectCgroup(),
+		domains:    newDomains(),
 	}
 }
 
+// GetSystemStats collects every domain concurrently and consolidates them,
+// on the caller's schedule; see Pipeline for the domains' own. A domain that hangs, e.g. on a
+// dead NFS mount, contributes its last good result and is listed in
+// SystemStats.Stale.
 func (s *StatsCollector) GetSystemStats() models.SystemStats {
+	// Counters jump or stall across a suspend, start every rate afresh
+	suspendedFor := s.checkSuspend()
+	if suspendedFor > 0 {
+		s.resetRateSamples()
+	}
+
 	var (
-		wg      sync.WaitGroup
-		cpu     models.CPUStats
-		mem     models.MemoryStats
-		net     models.NetworkStats
-		disk    []models.DiskStats
-		battery models.BatteryStats
+		wg     sync.WaitGroup
+		values = make([]any, len(s.domains))
+		stale  = make([]bool, len(s.domains))
 	)
-
-	wg.Add(5)
-
-	go func() {
-		defer wg.Done()
-		cpu = s.getCPUStats()
-	}()
-
-	go func() {
-		defer wg.Done()
-		mem = s.getMemoryStats()
-	}()
-
-	go func() {
-		defer wg.Done()
-		net = s.getNetworkStats()
-	}()
-
-	go func() {
-		defer wg.Done()
-		disk = s.getDiskStats()
-	}()
-
-	go func() {
-		defer wg.Done()
-		battery = s.getBatteryStats()
-	}()
-
+	for i, d := range s.domains {
+		wg.Add(1)
+		// A panic in a domain is recovered by the domain itself
+		go func() {
+			defer wg.Done()
+			values[i], stale[i] = d.refresh(s)
+		}()
+	}
 	wg.Wait()
+	return s.consolidate(values, stale, suspendedFor)
+}
 
-	return models.SystemStats{
-		CPU:     cpu,
-		Memory:  mem,
-		Network: net,
-		Disk:    disk,
-		Battery: battery,
-		Uptime:  time.Since(s.bootTime),
+// consolidate builds the SystemStats of the domains' results
+func (s *StatsCollector) consolidate(values []any, stale []bool, suspendedFor time.Duration) models.SystemStats {
+	stats := models.SystemStats{
+		Uptime:       time.Since(s.bootTime),
+		SuspendedFor: suspendedFor,
+	}
+	for i, d := range s.domains {
+		if stale[i] {
+			stats.Stale = append(stats.Stale, d.name)
+		}
+		// A domain that never finished leaves its zero value
+		switch value := values[i].(type) {
+		case models.CPUStats:
+			stats.CPU = value
+		case models.MemoryStats:
+			stats.Memory = value
+		case models.NetworkStats:
+			stats.Network = value
+		case []models.DiskStats:
+			stats.Disk = value
+		case models.BatteryStats:
+			stats.Battery = value
+		}
 	}
+	stats.Cgroup = s.getCgroupStats(stats.Memory.Total)
+	stats.Limits = s.getSystemLimits()
+	return stats
 }
 
 func (s *StatsCollector) ClearCPUCache() {
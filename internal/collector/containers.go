@@ -0,0 +1,236 @@
+package collector
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// containerRequestTimeout bounds listing containers and reading logs;
+// stopping one waits for it to exit on its own first
+const (
+	containerRequestTimeout = 5 * time.Second
+	containerStopTimeout    = 30 * time.Second
+)
+
+// ContainerSocket returns the socket of the Docker or Podman API, the one
+// DOCKER_HOST names or else the first found of Docker's, rootful Podman's
+// and the user's rootless Podman's. Empty when there is none.
+func ContainerSocket() string {
+	if host, ok := strings.CutPrefix(os.Getenv("DOCKER_HOST"), "unix://"); ok {
+		return host
+	}
+	candidates := []string{"/var/run/docker.sock", "/run/podman/podman.sock"}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append(candidates, filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	for _, socket := range candidates {
+		if info, err := os.Stat(socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return socket
+		}
+	}
+	return ""
+}
+
+// HasContainers reports whether a Docker or Podman API is reachable
+func (s *StatsCollector) HasContainers() bool {
+	return ContainerSocket() != ""
+}
+
+// containerAPI sends a request to the Docker API on socket, which Podman
+// serves as well, and decodes the JSON answer into result unless it is nil
+func containerAPI(ctx context.Context, socket, method, path string, result any) error {
+	body, err := containerRequest(ctx, socket, method, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(body).Decode(result)
+}
+
+// containerRequest returns the body of a successful API request
+func containerRequest(ctx context.Context, socket, method, path string) (io.ReadCloser, error) {
+	client := &http.Client{Transport: &http.Transport{
+		DisableKeepAlives: true,
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	request, err := http.NewRequestWithContext(ctx, method, "http://container-runtime"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if errors.Is(err, syscall.EACCES) {
+		return nil, fmt.Errorf("not allowed to use %s, join the docker group or run croptop with sudo", socket)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 300 && response.StatusCode != http.StatusNotModified {
+		defer response.Body.Close()
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(response.Body).Decode(&failure) == nil && failure.Message != "" {
+			return nil, errors.New(failure.Message)
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, path, response.Status)
+	}
+	return response.Body, nil
+}
+
+// GetContainers lists the containers of the runtime, running ones first
+func (s *StatsCollector) GetContainers() ([]models.Container, error) {
+	socket := ContainerSocket()
+	if socket == "" {
+		return nil, errors.New("no Docker or Podman socket found")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), containerRequestTimeout)
+	defer cancel()
+
+	var listed []struct {
+		ID     string   `json:"Id"`
+		Names  []string `json:"Names"`
+		Image  string   `json:"Image"`
+		State  string   `json:"State"`
+		Status string   `json:"Status"`
+	}
+	if err := containerAPI(ctx, socket, http.MethodGet, "/containers/json?all=1", &listed); err != nil {
+		return nil, err
+	}
+
+	containers := make([]models.Container, 0, len(listed))
+	for _, c := range listed {
+		container := models.Container{ID: c.ID, Name: shortContainerID(c.ID), Image: c.Image, State: c.State, Status: c.Status}
+		if len(c.Names) > 0 {
+			container.Name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		containers = append(containers, container)
+	}
+	addContainerPressure(containers)
+	sort.Slice(containers, func(i, j int) bool {
+		if running := containers[i].State == "running"; running != (containers[j].State == "running") {
+			return running
+		}
+		return containers[i].Name < containers[j].Name
+	})
+	return containers, nil
+}
+
+// addContainerPressure fills in the pressure of the containers found in
+// the cgroup v2 hierarchy, in a directory named after the container ID
+// whatever the runtime and its cgroup driver: docker/<id>,
+// system.slice/docker-<id>.scope or machine.slice/libpod-<id>.scope
+func addContainerPressure(containers []models.Container) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return
+	}
+	byID := make(map[string]*models.Container)
+	for i := range containers {
+		if containers[i].State == "running" && len(containers[i].ID) == 64 {
+			byID[containers[i].ID] = &containers[i]
+		}
+	}
+	if len(byID) == 0 {
+		return
+	}
+
+	filepath.WalkDir(cgroupRoot, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		name := entry.Name()
+		// Podman's conmon monitor runs in a scope named after the
+		// container as well
+		if strings.Contains(name, "conmon") {
+			return filepath.SkipDir
+		}
+		for _, id := range []string{strings.TrimSuffix(strings.TrimPrefix(name, "docker-"), ".scope"),
+			strings.TrimSuffix(strings.TrimPrefix(name, "libpod-"), ".scope")} {
+			container, ok := byID[id]
+			if !ok {
+				continue
+			}
+			if pressure, ok := cgroupPressure(path); ok {
+				container.Pressure = &pressure
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// StopContainer stops a container, killing it when it has not exited
+// after the runtime's grace period
+func (s *StatsCollector) StopContainer(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), containerStopTimeout)
+	defer cancel()
+	return containerAPI(ctx, ContainerSocket(), http.MethodPost, "/containers/"+url.PathEscape(id)+"/stop", nil)
+}
+
+// RestartContainer stops a container and starts it again
+func (s *StatsCollector) RestartContainer(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), containerStopTimeout)
+	defer cancel()
+	return containerAPI(ctx, ContainerSocket(), http.MethodPost, "/containers/"+url.PathEscape(id)+"/restart", nil)
+}
+
+// GetContainerLogs returns the last lines of a container's output, stdout
+// and stderr interleaved
+func (s *StatsCollector) GetContainerLogs(id string, lines int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), containerRequestTimeout)
+	defer cancel()
+	body, err := containerRequest(ctx, ContainerSocket(), http.MethodGet,
+		fmt.Sprintf("/containers/%s/logs?stdout=1&stderr=1&tail=%d", url.PathEscape(id), lines))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	output, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.ReplaceAll(string(demuxContainerLogs(output)), "\r", "")
+	if text == "" {
+		return nil, nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n"), nil
+}
+
+// demuxContainerLogs strips the headers the API puts before each chunk of
+// the output of a container without a terminal: the stream (1 stdout, 2
+// stderr), three zero bytes and the chunk's length. The output of a
+// container with a terminal comes as is.
+func demuxContainerLogs(output []byte) []byte {
+	if len(output) < 8 || output[0] > 2 || output[1] != 0 || output[2] != 0 || output[3] != 0 {
+		return output
+	}
+	var text []byte
+	for len(output) >= 8 {
+		size := int(binary.BigEndian.Uint32(output[4:8]))
+		output = output[8:]
+		size = min(size, len(output))
+		text = append(text, output[:size]...)
+		output = output[size:]
+	}
+	return text
+}
@@ -4,9 +4,10 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -36,14 +37,18 @@ func (s *StatsCollector) getCPUStats() models.CPUStats {
 	// Get cached or fresh data efficiently
 	model, frequency := s.getCPUCachedInfo(ctx)
 	temp := s.getCachedTemperature(ctx)
-	usage, cores := s.getCachedCPUUsage(ctx)
+	usage, cores, coreIDs := s.getCachedCPUUsage(ctx)
 
 	return models.CPUStats{
 		Usage:     usage,
 		Cores:     cores,
+		CoreIDs:   coreIDs,
+		Topology:  s.getCPUTopology(coreIDs),
 		Frequency: frequency,
 		Temp:      temp,
 		Model:     model,
+		Power:     s.getRAPLStats(),
+		Load:      getLoadAverage(),
 	}
 }
 
@@ -173,6 +178,14 @@ func (s *StatsCollector) getCPUTemperature(ctx context.Context) (float32, error)
 		"/sys/devices/platform/coretemp.*/hwmon/hwmon*/temp*_input",
 	}
 
+	// A zone that says it measures the CPU beats thermal_zone0, which is
+	// often the chipset or a board sensor
+	if path, ok := cpuThermalZone(); ok {
+		if temp, err := s.readTemperatureFromPath(path); err == nil {
+			return temp, nil
+		}
+	}
+
 	// Try direct paths first (faster)
 	for _, path := range tempPaths {
 		select {
@@ -237,7 +250,7 @@ func (s *StatsCollector) readTemperatureFromPath(path string) (float32, error) {
 	return temp32, nil
 }
 
-func (s *StatsCollector) getCachedCPUUsage(ctx context.Context) (float64, []float64) {
+func (s *StatsCollector) getCachedCPUUsage(ctx context.Context) (float64, []float64, []int) {
 	// Check if usage is cached and valid
 	if s.cpuCache.IsUsageCacheValid() {
 		return s.cpuCache.GetCachedUsage()
@@ -245,21 +258,21 @@ func (s *StatsCollector) getCachedCPUUsage(ctx context.Context) (float64, []floa
 
 	select {
 	case <-ctx.Done():
-		return 0, nil
+		return 0, nil, nil
 	default:
 	}
 
 	// Get current CPU stats
 	currentStats, err := s.getCurrentCPUStats()
 	if err != nil {
-		return 0, nil
+		return 0, nil, nil
 	}
+	coreIDs := onlineCores(currentStats)
 
 	// If we don't have previous stats, store current and return zero
 	if !s.cpuCache.HasPreviousStats() {
 		s.cpuCache.SetPreviousStats(currentStats)
-		coreCount := max(len(currentStats)-1, 0) // -1 because "cpu" is overall
-		return 0, make([]float64, coreCount)
+		return 0, make([]float64, len(coreIDs)), coreIDs
 	}
 
 	// Get previous stats for comparison
@@ -271,26 +284,43 @@ func (s *StatsCollector) getCachedCPUUsage(ctx context.Context) (float64, []floa
 		return s.cpuCache.GetCachedUsage()
 	}
 
+	// CPUs going offline or online, e.g. a VM being resized, change the
+	// model and frequency read from /proc/cpuinfo as well
+	if _, _, cachedIDs := s.cpuCache.GetCachedUsage(); cachedIDs != nil && !slices.Equal(cachedIDs, coreIDs) {
+		slog.Info("CPUs hotplugged", "online", len(coreIDs), "was", len(cachedIDs))
+		s.cpuCache.InvalidateModel()
+	}
+
 	// Calculate overall usage
 	overallUsage := s.calculateUsageWithValidation(previousStats["cpu"], currentStats["cpu"])
 
-	// Calculate per-core usage efficiently
-	coreUsages := make([]float64, 0, runtime.NumCPU())
-	for i := 0; i < runtime.NumCPU(); i++ {
-		cpuKey := fmt.Sprintf("cpu%d", i)
-		if current, exists := currentStats[cpuKey]; exists {
-			if previous, exists := previousStats[cpuKey]; exists {
-				usage := s.calculateUsageWithValidation(previous, current)
-				coreUsages = append(coreUsages, usage)
-			}
+	// A CPU that just came online has no previous sample and shows as idle
+	coreUsages := make([]float64, len(coreIDs))
+	for i, id := range coreIDs {
+		cpuKey := "cpu" + strconv.Itoa(id)
+		if previous, exists := previousStats[cpuKey]; exists {
+			coreUsages[i] = s.calculateUsageWithValidation(previous, currentStats[cpuKey])
 		}
 	}
 
 	// Update cache with new data
 	s.cpuCache.SetPreviousStats(currentStats)
-	s.cpuCache.SetCachedUsage(overallUsage, coreUsages)
+	s.cpuCache.SetCachedUsage(overallUsage, coreUsages, coreIDs)
+
+	return overallUsage, coreUsages, coreIDs
+}
 
-	return overallUsage, coreUsages
+// onlineCores returns the numbers of the CPUs with a line in /proc/stat,
+// in order. Offline CPUs have none, so they may have gaps.
+func onlineCores(stats map[string]CPUTimes) []int {
+	var ids []int
+	for key := range stats {
+		if id, err := strconv.Atoi(strings.TrimPrefix(key, "cpu")); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
 }
 
 func (s *StatsCollector) getCurrentCPUStats() (map[string]CPUTimes, error) {
@@ -301,7 +331,7 @@ func (s *StatsCollector) getCurrentCPUStats() (map[string]CPUTimes, error) {
 	defer file.Close()
 
 	// Pre-allocate map with expected capacity
-	stats := make(map[string]CPUTimes, runtime.NumCPU()+1)
+	stats := make(map[string]CPUTimes)
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
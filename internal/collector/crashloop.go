@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"slices"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const (
+	// Exits of processes that ran longer than this are not crashes
+	crashLoopLifetime = time.Minute
+	// A start this soon after a failed exit of the same command is a restart
+	crashLoopRestartGap = 30 * time.Second
+	// Restarts are counted over this window, and a command restarted at
+	// least crashLoopRestarts times in it is crash looping
+	crashLoopWindow   = 5 * time.Minute
+	crashLoopRestarts = 3
+)
+
+// restartHistory is what the proc connector saw of one command failing and
+// starting again
+type restartHistory struct {
+	name     string
+	command  string
+	failedAt time.Time
+	// Raw wait status of the last failed exit
+	status   int
+	restarts []time.Time
+}
+
+// crashLoopKey identifies a command the way the process list shows it, so
+// the list's processes can be matched to their history
+func crashLoopKey(name, command string) string {
+	return name + "\x00" + truncateCommand(command)
+}
+
+// recordFailure notes a process exiting with a non-zero status. Called with
+// the monitor locked.
+func (m *procEventMonitor) recordFailure(record execRecord, status int, now time.Time) {
+	key := crashLoopKey(record.name, record.command)
+	history, ok := m.restarts[key]
+	if !ok {
+		history = &restartHistory{name: record.name, command: record.command}
+		m.restarts[key] = history
+	}
+	history.failedAt = now
+	history.status = status
+}
+
+// recordStart counts a restart when the command failed shortly before.
+// Called with the monitor locked.
+func (m *procEventMonitor) recordStart(record execRecord) {
+	history, ok := m.restarts[crashLoopKey(record.name, record.command)]
+	if ok && record.started.Sub(history.failedAt) <= crashLoopRestartGap {
+		history.restarts = append(history.restarts, record.started)
+	}
+}
+
+// crashLoops returns the commands crash looping now, most restarts first,
+// and forgets the restarts that left the window. Called with the monitor
+// locked.
+func (m *procEventMonitor) crashLoops(now time.Time) []models.CrashLoop {
+	var loops []models.CrashLoop
+	for key, history := range m.restarts {
+		history.restarts = slices.DeleteFunc(history.restarts, func(t time.Time) bool {
+			return now.Sub(t) > crashLoopWindow
+		})
+		if len(history.restarts) == 0 && now.Sub(history.failedAt) > crashLoopWindow {
+			delete(m.restarts, key)
+			continue
+		}
+		if len(history.restarts) < crashLoopRestarts {
+			continue
+		}
+		loops = append(loops, models.CrashLoop{
+			Name:     history.name,
+			Command:  history.command,
+			Restarts: len(history.restarts),
+			LastExit: history.failedAt,
+			ExitCode: history.status >> 8,
+			Signal:   history.status & 0x7f,
+		})
+	}
+	slices.SortFunc(loops, func(a, b models.CrashLoop) int {
+		if a.Restarts != b.Restarts {
+			return b.Restarts - a.Restarts
+		}
+		return b.LastExit.Compare(a.LastExit)
+	})
+	return loops
+}
+
+// addCrashLoops lists the crash looping commands and flags their running
+// processes with the restart count
+func (s *StatsCollector) addCrashLoops(list *models.ProcessList) {
+	if s.procEvents == nil {
+		return
+	}
+	m := s.procEvents
+	m.mutex.Lock()
+	list.CrashLoops = m.crashLoops(time.Now())
+	m.mutex.Unlock()
+	if len(list.CrashLoops) == 0 {
+		return
+	}
+
+	restarts := make(map[string]int, len(list.CrashLoops))
+	for _, loop := range list.CrashLoops {
+		restarts[crashLoopKey(loop.Name, loop.Command)] = loop.Restarts
+	}
+	for i, proc := range list.Processes {
+		list.Processes[i].Restarts = restarts[crashLoopKey(proc.Name, proc.Command)]
+	}
+}
@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// GetProcessDetail reads what the process detail view shows of one process.
+// It fails only when the process is gone; what it is not allowed to read is
+// left empty.
+func (s *StatsCollector) GetProcessDetail(pid int) (models.ProcessDetail, error) {
+	dir := fmt.Sprintf("/proc/%d", pid)
+	statContent, err := os.ReadFile(dir + "/stat")
+	if err != nil {
+		return models.ProcessDetail{}, err
+	}
+	// The name in parentheses may contain spaces, the fields after it don't
+	statFields := strings.Fields(string(statContent[strings.LastIndexByte(string(statContent), ')')+1:]))
+
+	detail := models.ProcessDetail{PID: pid}
+	if cmdline, err := os.ReadFile(dir + "/cmdline"); err == nil {
+		detail.Args = strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+	}
+	detail.Executable, _ = os.Readlink(dir + "/exe")
+	detail.Cwd, _ = os.Readlink(dir + "/cwd")
+	detail.SHA256 = s.executableSHA256(pid)
+	// The executable of a containerized process is a file of its image,
+	// which the host's package manager knows nothing about
+	if detail.Executable != "" && sameMountNamespace(pid) {
+		// An executable replaced by an upgrade is shown as deleted, the
+		// package owns the file at the same path
+		detail.Package, detail.PackageKnown = s.packageOwnerOf(strings.TrimSuffix(detail.Executable, " (deleted)"))
+	}
+	// Fields after the name start at state, the third field of stat
+	if len(statFields) > 19 {
+		detail.Threads, _ = strconv.Atoi(statFields[17])
+		startTicks, _ := strconv.ParseUint(statFields[19], 10, 64)
+		detail.Started = time.Unix(int64(s.getSystemBootTime()+startTicks/100), 0)
+	}
+	detail.Cgroup = readProcessCgroup(pid)
+	detail.WaitChannel = readWaitChannel(pid)
+	detail.Namespaces = readNamespaces(strconv.Itoa(pid))
+	status := readStatusFields(pid)
+	detail.Limits = readProcessLimits(pid, status)
+	detail.Security = readProcessSecurity(status)
+	return detail, nil
+}
+
+// sameMountNamespace reports whether a process sees the files croptop sees
+func sameMountNamespace(pid int) bool {
+	own, err := os.Readlink("/proc/self/ns/mnt")
+	if err != nil {
+		return true
+	}
+	theirs, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/mnt", pid))
+	return err != nil || theirs == own
+}
@@ -5,11 +5,22 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
 
 	"github.com/prabalesh/croptop/internal/models"
 )
 
+// networkFilesystems are listed besides block devices. Their statfs goes to
+// the server, which may not answer.
+var networkFilesystems = map[string]bool{
+	"nfs":        true,
+	"nfs4":       true,
+	"cifs":       true,
+	"smb3":       true,
+	"fuse.sshfs": true,
+	"ceph":       true,
+	"glusterfs":  true,
+}
+
 func (s *StatsCollector) getDiskStats() []models.DiskStats {
 	content, err := os.ReadFile("/proc/mounts")
 	if err != nil {
@@ -32,10 +43,9 @@ func (s *StatsCollector) getDiskStats() []models.DiskStats {
 		// Skip special filesystems
 		if strings.HasPrefix(device, "/dev") &&
 			!strings.Contains(device, "loop") &&
-			filesystem != "tmpfs" {
+			filesystem != "tmpfs" || networkFilesystems[filesystem] {
 
-			var stat syscall.Statfs_t
-			if err := syscall.Statfs(mountpoint, &stat); err == nil {
+			if stat, stalled, err := s.statfs(mountpoint); err == nil {
 				total := uint64(stat.Blocks) * uint64(stat.Bsize)
 				free := uint64(stat.Bavail) * uint64(stat.Bsize)
 				used := total - free
@@ -60,6 +70,7 @@ func (s *StatsCollector) getDiskStats() []models.DiskStats {
 					WriteBytes:   writeBytes,
 					ReadOps:      readOps,
 					WriteOps:     writeOps,
+					Stalled:      stalled,
 				})
 			}
 		}
@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/crash"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// systemd-resolved points resolv.conf at its local stub and writes the
+// upstream servers it uses to a separate file
+const (
+	resolvedStubAddress = "127.0.0.53"
+	resolvedUpstream    = "/run/systemd/resolve/resolv.conf"
+)
+
+// DNSCheckTimeout bounds each test lookup
+const DNSCheckTimeout = 2 * time.Second
+
+// GetDNSStats returns the configured resolvers
+func (s *StatsCollector) GetDNSStats() models.DNSStats {
+	stats := readResolvConf("/etc/resolv.conf")
+	stats.Source = "resolv.conf"
+
+	for _, resolver := range stats.Resolvers {
+		if resolver == resolvedStubAddress {
+			if upstream := readResolvConf(resolvedUpstream); len(upstream.Resolvers) > 0 {
+				stats.Resolvers = upstream.Resolvers
+				stats.Source = "systemd-resolved"
+			}
+			break
+		}
+	}
+
+	return stats
+}
+
+func readResolvConf(path string) models.DNSStats {
+	var stats models.DNSStats
+
+	file, err := os.Open(path)
+	if err != nil {
+		return stats
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			stats.Resolvers = append(stats.Resolvers, fields[1])
+		case "search", "domain":
+			stats.SearchDomains = append(stats.SearchDomains, fields[1:]...)
+		}
+	}
+	return stats
+}
+
+// CheckDNS resolves query against every resolver in parallel and reports
+// the latency of each
+func (s *StatsCollector) CheckDNS(resolvers []string, query string) []models.DNSCheck {
+	checks := make([]models.DNSCheck, len(resolvers))
+
+	var wg sync.WaitGroup
+	for i, resolver := range resolvers {
+		wg.Add(1)
+		go func(i int, resolver string) {
+			defer wg.Done()
+			defer crash.Recover()
+			checks[i] = checkResolver(resolver, query)
+		}(i, resolver)
+	}
+	wg.Wait()
+
+	return checks
+}
+
+func checkResolver(resolver, query string) models.DNSCheck {
+	// Talk to this resolver only instead of the system's choice
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, net.JoinHostPort(resolver, "53"))
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DNSCheckTimeout)
+	defer cancel()
+
+	check := models.DNSCheck{Resolver: resolver, Time: time.Now()}
+	_, err := r.LookupHost(ctx, query)
+	check.Latency = time.Since(check.Time)
+	if err != nil {
+		check.Error = err.Error()
+		if dnsErr, ok := err.(*net.DNSError); ok {
+			check.Error = dnsErr.Err
+		}
+	}
+	return check
+}
@@ -0,0 +1,276 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// ProcessFilter is a parsed process filter expression, terms separated by
+// spaces that a process has to match all of:
+//
+//	user:www-data cpu>5 name~nginx.* !status:S "worker pool"
+//
+// field:value matches a text field exactly or a number equally,
+// field~regexp matches a regular expression, and >, >=, < and <= compare
+// numbers. A term without a field looks for the word in the name and
+// command line, ignoring case, and ! in front of a term negates it.
+type ProcessFilter struct {
+	Source string
+	terms  []filterTerm
+}
+
+type filterTerm struct {
+	negate bool
+	match  func(models.Process) bool
+}
+
+// Text fields of the filter; user matches the user name or the UID, wchan
+// is only known for processes in D state
+var filterTextFields = map[string]func(models.Process) []string{
+	"name":    func(p models.Process) []string { return []string{p.Name} },
+	"user":    func(p models.Process) []string { return []string{p.User, LookupUsername(p.User)} },
+	"command": func(p models.Process) []string { return []string{p.Command} },
+	"status":  func(p models.Process) []string { return []string{p.Status} },
+	"tag":     func(p models.Process) []string { return []string{p.Tag} },
+	"wchan":   func(p models.Process) []string { return []string{p.WaitChannel} },
+}
+
+// Number fields of the filter. cpu is of one core, wait the run delay share
+// of the main thread, rss, swap and growth take K, M and G suffixes, time
+// takes durations such as 90s or 2h.
+var filterNumberFields = map[string]struct {
+	value func(models.Process) float64
+	parse func(string) (float64, error)
+}{
+	"pid":    {func(p models.Process) float64 { return float64(p.PID) }, parseFilterNumber},
+	"ppid":   {func(p models.Process) float64 { return float64(p.PPID) }, parseFilterNumber},
+	"cpu":    {func(p models.Process) float64 { return p.CPUPercent }, parseFilterNumber},
+	"wait":   {func(p models.Process) float64 { return p.RunDelayPercent }, parseFilterNumber},
+	"mem":    {func(p models.Process) float64 { return p.MemPercent }, parseFilterNumber},
+	"rss":    {func(p models.Process) float64 { return float64(p.MemRSS) * 1024 }, parseFilterBytes},
+	"swap":   {func(p models.Process) float64 { return float64(p.Swap) * 1024 }, parseFilterBytes},
+	"growth": {func(p models.Process) float64 { return float64(p.RSSGrowth) * 1024 }, parseFilterBytes},
+	"time":   {func(p models.Process) float64 { return p.Runtime.Seconds() }, parseFilterDuration},
+}
+
+// filterOperators are tried longest first, so >= is not read as >
+var filterOperators = []string{">=", "<=", ":", "~", ">", "<"}
+
+// ParseProcessFilter parses a filter expression. An empty expression
+// matches every process.
+func ParseProcessFilter(text string) (ProcessFilter, error) {
+	filter := ProcessFilter{Source: strings.TrimSpace(text)}
+	tokens, err := splitFilter(filter.Source)
+	if err != nil {
+		return ProcessFilter{}, err
+	}
+	for _, token := range tokens {
+		term, err := parseFilterTerm(token)
+		if err != nil {
+			return ProcessFilter{}, err
+		}
+		filter.terms = append(filter.terms, term)
+	}
+	return filter, nil
+}
+
+// Empty reports whether the filter matches every process
+func (f ProcessFilter) Empty() bool {
+	return len(f.terms) == 0
+}
+
+// Match reports whether proc matches every term of the filter
+func (f ProcessFilter) Match(proc models.Process) bool {
+	for _, term := range f.terms {
+		if term.match(proc) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the processes matching the filter
+func (f ProcessFilter) Apply(processes []models.Process) []models.Process {
+	if f.Empty() {
+		return processes
+	}
+	var matched []models.Process
+	for _, proc := range processes {
+		if f.Match(proc) {
+			matched = append(matched, proc)
+		}
+	}
+	return matched
+}
+
+// splitFilter splits an expression at spaces outside double quotes
+func splitFilter(text string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	quoted, started := false, false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+			token.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if started {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				started = false
+			}
+		default:
+			token.WriteRune(r)
+			started = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("missing closing quote")
+	}
+	if started {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
+
+func parseFilterTerm(token string) (filterTerm, error) {
+	term := filterTerm{}
+	if rest, ok := strings.CutPrefix(token, "!"); ok {
+		term.negate = true
+		token = rest
+	}
+	if token == "" {
+		return term, fmt.Errorf("empty term")
+	}
+
+	// A field name is letters up to the operator; quoted words are
+	// plain text whatever they contain
+	field, operator, value := "", "", token
+	if !strings.HasPrefix(token, `"`) {
+		end := strings.IndexFunc(token, func(r rune) bool { return r < 'a' || r > 'z' })
+		if end > 0 {
+			for _, op := range filterOperators {
+				if strings.HasPrefix(token[end:], op) {
+					field, operator, value = token[:end], op, token[end+len(op):]
+					break
+				}
+			}
+		}
+	}
+	value = unquoteFilter(value)
+
+	if operator == "" {
+		word := strings.ToLower(value)
+		term.match = func(p models.Process) bool {
+			return strings.Contains(strings.ToLower(p.Name), word) || strings.Contains(strings.ToLower(p.Command), word)
+		}
+		return term, nil
+	}
+
+	if texts, ok := filterTextFields[field]; ok {
+		switch operator {
+		case ":":
+			term.match = func(p models.Process) bool {
+				for _, text := range texts(p) {
+					if text == value {
+						return true
+					}
+				}
+				return false
+			}
+		case "~":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return term, fmt.Errorf("%s: %w", token, err)
+			}
+			term.match = func(p models.Process) bool {
+				for _, text := range texts(p) {
+					if re.MatchString(text) {
+						return true
+					}
+				}
+				return false
+			}
+		default:
+			return term, fmt.Errorf("%s: %s is text, use : or ~", token, field)
+		}
+		return term, nil
+	}
+
+	number, ok := filterNumberFields[field]
+	if !ok {
+		return term, fmt.Errorf("unknown field %q (want name, user, command, status, tag, wchan, pid, ppid, cpu, wait, mem, rss, swap, growth or time)", field)
+	}
+	if operator == "~" {
+		return term, fmt.Errorf("%s: %s is a number, use :, >, >=, < or <=", token, field)
+	}
+	want, err := number.parse(value)
+	if err != nil {
+		return term, fmt.Errorf("%s: %w", token, err)
+	}
+	term.match = func(p models.Process) bool {
+		v := number.value(p)
+		switch operator {
+		case ">":
+			return v > want
+		case ">=":
+			return v >= want
+		case "<":
+			return v < want
+		case "<=":
+			return v <= want
+		}
+		return v == want
+	}
+	return term, nil
+}
+
+func unquoteFilter(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func parseFilterNumber(text string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", text)
+	}
+	return value, nil
+}
+
+// parseFilterBytes reads a size with an optional K, M or G suffix, powers
+// of 1024
+func parseFilterBytes(text string) (float64, error) {
+	multiplier := 1.0
+	if n := len(text); n > 1 {
+		if i := strings.IndexByte("KMG", text[n-1]&^0x20); i >= 0 {
+			multiplier = float64(uint64(1) << (10 * (i + 1)))
+			text = text[:n-1]
+		}
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+	return value * multiplier, nil
+}
+
+// parseFilterDuration reads seconds or a duration such as 90s or 2h
+func parseFilterDuration(text string) (float64, error) {
+	if seconds, err := strconv.ParseFloat(text, 64); err == nil {
+		return seconds, nil
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	return duration.Seconds(), nil
+}
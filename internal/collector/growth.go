@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// GrowthWindow is how far back Process.RSSGrowth looks
+const GrowthWindow = 10 * time.Minute
+
+// rssSampleInterval spaces the RSS samples kept per process, a window holds
+// about 20 of them
+const rssSampleInterval = 30 * time.Second
+
+type rssSample struct {
+	time time.Time
+	rss  uint64
+}
+
+// rssHistory is the RSS of every process over the last GrowthWindow
+type rssHistory struct {
+	mutex sync.Mutex
+	procs map[int]*rssTrack
+}
+
+// rssTrack is the history of one PID; the start time tells a reused PID
+// from the process it belonged to
+type rssTrack struct {
+	started time.Time
+	samples []rssSample
+}
+
+// updateRSSGrowth records the RSS of the processes and sets their growth
+// over the window, or since first seen for processes younger than croptop's
+// history of them. Exited processes are forgotten.
+func (s *StatsCollector) updateRSSGrowth(processes []models.Process) {
+	s.rssHistory.mutex.Lock()
+	defer s.rssHistory.mutex.Unlock()
+
+	now := time.Now()
+	current := make(map[int]*rssTrack, len(processes))
+	for i := range processes {
+		proc := &processes[i]
+		started := now.Add(-proc.Runtime)
+
+		// Runtime is in whole seconds, so the start time wobbles a little
+		track, ok := s.rssHistory.procs[proc.PID]
+		if !ok || track.started.Sub(started).Abs() > 2*time.Second {
+			track = &rssTrack{started: started}
+		}
+		if len(track.samples) == 0 || now.Sub(track.samples[len(track.samples)-1].time) >= rssSampleInterval {
+			track.samples = append(track.samples, rssSample{now, proc.MemRSS})
+		}
+		// Keep the newest sample at least a window old as the baseline
+		for len(track.samples) > 1 && now.Sub(track.samples[1].time) >= GrowthWindow {
+			track.samples = track.samples[1:]
+		}
+		proc.RSSGrowth = int64(proc.MemRSS) - int64(track.samples[0].rss)
+		current[proc.PID] = track
+	}
+	s.rssHistory.procs = current
+}
@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"bufio"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// procHidePID returns the hidepid= mode of the /proc mount and the gid= of
+// the group exempt from it, or an empty mode when /proc shows every process.
+// Kernels before 5.8 print the mode as a number: 1 is noaccess, 2 invisible.
+func procHidePID() (mode, gid string) {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return "", ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[1] != "/proc" || fields[2] != "proc" {
+			continue
+		}
+		// The last mount over /proc is the one in effect
+		mode, gid = "", ""
+		for _, option := range strings.Split(fields[3], ",") {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "hidepid":
+				mode = value
+			case "gid":
+				gid = value
+			}
+		}
+	}
+	if mode == "0" || mode == "off" {
+		mode = ""
+	}
+	return mode, gid
+}
+
+// hidePIDPrivilege describes the processes hidepid keeps from croptop, with
+// ok false when /proc is mounted without it
+func hidePIDPrivilege() (models.Privilege, bool) {
+	mode, gid := procHidePID()
+	if mode == "" {
+		return models.Privilege{}, false
+	}
+	hint := "run with sudo or grant CAP_SYS_PTRACE"
+	if gid != "" {
+		group := gid
+		if g, err := user.LookupGroupId(gid); err == nil {
+			group = g.Name
+		}
+		hint += ", or join the group " + group + " exempt from it"
+	}
+	return models.Privilege{
+		Feature:     models.FeatureAllProcesses,
+		Description: "Other users' processes, hidden by hidepid=" + mode + " on /proc",
+		Available:   canSeeOthersProcesses(),
+		Hint:        hint,
+	}, true
+}
+
+// canSeeOthersProcesses reads the status of init, which belongs to root and
+// which hidepid hides or locks from everyone else
+func canSeeOthersProcesses() bool {
+	_, err := os.ReadFile("/proc/1/stat")
+	return err == nil
+}
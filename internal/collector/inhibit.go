@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const InhibitorCacheDuration = 5 * time.Second
+
+type inhibitorCache struct {
+	mutex      sync.Mutex
+	inhibitors []models.Inhibitor
+	updated    time.Time
+}
+
+// getInhibitors lists logind inhibitor locks, the usual answer to "why won't
+// my laptop suspend". It asks logind over D-Bus through busctl.
+func (s *StatsCollector) getInhibitors() []models.Inhibitor {
+	cache := &s.inhibitors
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if !cache.updated.IsZero() && time.Since(cache.updated) < InhibitorCacheDuration {
+		return cache.inhibitors
+	}
+	cache.updated = time.Now()
+	cache.inhibitors = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "busctl", "--json=short", "call",
+		"org.freedesktop.login1", "/org/freedesktop/login1",
+		"org.freedesktop.login1.Manager", "ListInhibitors").Output()
+	if err != nil {
+		slog.Debug("listing logind inhibitors failed", "err", err)
+		return nil
+	}
+
+	// The reply signature is a(ssssuu): what, who, why, mode, uid, pid
+	var reply struct {
+		Data [][][]any `json:"data"`
+	}
+	if err := json.Unmarshal(output, &reply); err != nil || len(reply.Data) == 0 {
+		return nil
+	}
+
+	for _, entry := range reply.Data[0] {
+		if len(entry) != 6 {
+			continue
+		}
+
+		inhibitor := models.Inhibitor{}
+		inhibitor.What, _ = entry[0].(string)
+		inhibitor.Who, _ = entry[1].(string)
+		inhibitor.Why, _ = entry[2].(string)
+		inhibitor.Mode, _ = entry[3].(string)
+		if uid, ok := entry[4].(float64); ok {
+			inhibitor.UID = int(uid)
+		}
+		if pid, ok := entry[5].(float64); ok {
+			inhibitor.PID = int(pid)
+		}
+		cache.inhibitors = append(cache.inhibitors, inhibitor)
+	}
+
+	return cache.inhibitors
+}
+
+// IsSleepBlocker reports whether an inhibitor keeps the machine from suspending
+func IsSleepBlocker(inhibitor models.Inhibitor) bool {
+	if inhibitor.Mode != "block" {
+		return false
+	}
+	for _, what := range strings.Split(inhibitor.What, ":") {
+		if what == "sleep" || what == "idle" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package collector
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// IOSortBy represents the sorting options of the I/O process list
+type IOSortBy int
+
+const (
+	IOSortByTotal IOSortBy = iota
+	IOSortByRead
+	IOSortByWrite
+	IOSortByName
+)
+
+func (i IOSortBy) String() string {
+	switch i {
+	case IOSortByRead:
+		return "Read"
+	case IOSortByWrite:
+		return "Write"
+	case IOSortByName:
+		return "Name"
+	default:
+		return "Total"
+	}
+}
+
+// diskSample holds the /proc/diskstats counters of a device
+type diskSample struct {
+	reads, writes               uint64
+	sectorsRead, sectorsWritten uint64
+	ioTicks                     uint64 // milliseconds spent doing I/O
+}
+
+type diskSamples struct {
+	mutex   sync.Mutex
+	disks   map[string]diskSample
+	sampled time.Time
+}
+
+// GetIOStats returns read/write rates of every whole block device since the
+// previous call, and their sum
+func (s *StatsCollector) GetIOStats() models.IOStats {
+	content, err := os.ReadFile("/proc/diskstats")
+	if err != nil {
+		return models.IOStats{}
+	}
+
+	samples := &s.diskSamples
+	samples.mutex.Lock()
+	defer samples.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(samples.sampled).Seconds()
+	current := make(map[string]diskSample)
+
+	var stats models.IOStats
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 14 {
+			continue
+		}
+
+		// Partitions would count the same I/O twice
+		name := fields[2]
+		if !isWholeDisk(name) {
+			continue
+		}
+
+		parse := func(i int) uint64 {
+			value, _ := strconv.ParseUint(fields[i], 10, 64)
+			return value
+		}
+		sample := diskSample{
+			reads:          parse(3),
+			sectorsRead:    parse(5),
+			writes:         parse(7),
+			sectorsWritten: parse(9),
+			ioTicks:        parse(12),
+		}
+		current[name] = sample
+
+		device := models.DeviceIO{Name: name, InFlight: parse(11)}
+		if prev, ok := samples.disks[name]; ok && elapsed > 0 {
+			// diskstats sectors are always 512 bytes
+			device.ReadRate = counterRate(prev.sectorsRead, sample.sectorsRead, elapsed) * 512
+			device.WriteRate = counterRate(prev.sectorsWritten, sample.sectorsWritten, elapsed) * 512
+			device.ReadIOPS = counterRate(prev.reads, sample.reads, elapsed)
+			device.WriteIOPS = counterRate(prev.writes, sample.writes, elapsed)
+			device.Utilization = counterRate(prev.ioTicks, sample.ioTicks, elapsed) / 10
+			if device.Utilization > 100 {
+				device.Utilization = 100
+			}
+		}
+
+		stats.ReadRate += device.ReadRate
+		stats.WriteRate += device.WriteRate
+		stats.Devices = append(stats.Devices, device)
+	}
+
+	samples.disks = current
+	samples.sampled = now
+
+	return stats
+}
+
+// isWholeDisk reports whether a diskstats entry is a real disk rather than a
+// partition or a loop/ram device
+func isWholeDisk(name string) bool {
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		return false
+	}
+	_, err := os.Stat("/sys/block/" + strings.ReplaceAll(name, "/", "!"))
+	return err == nil
+}
+
+// TopIOProcesses returns the processes doing disk I/O, sorted
+func (s *StatsCollector) TopIOProcesses(processes models.ProcessList, sortBy IOSortBy, descending bool) []models.Process {
+	var active []models.Process
+	for _, proc := range processes.Processes {
+		if proc.ReadRate > 0 || proc.WriteRate > 0 {
+			active = append(active, proc)
+		}
+	}
+
+	less := func(i, j int) bool {
+		switch sortBy {
+		case IOSortByRead:
+			return active[i].ReadRate < active[j].ReadRate
+		case IOSortByWrite:
+			return active[i].WriteRate < active[j].WriteRate
+		case IOSortByName:
+			return active[i].Name < active[j].Name
+		default:
+			return active[i].ReadRate+active[i].WriteRate < active[j].ReadRate+active[j].WriteRate
+		}
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		if descending {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+
+	return active
+}
@@ -0,0 +1,153 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// MaxKernelLogRecords bounds the kernel messages kept in memory
+const MaxKernelLogRecords = 2000
+
+// JournalCacheDuration limits how often journalctl is run when /dev/kmsg is
+// not readable
+const JournalCacheDuration = 5 * time.Second
+
+// kernelLog follows the kernel ring buffer through /dev/kmsg. Every read of
+// the non-blocking descriptor returns one record, so each poll only picks up
+// the messages logged since the previous one.
+type kernelLog struct {
+	mutex   sync.Mutex
+	fd      int
+	opened  bool
+	err     error
+	records []models.KernelMessage
+	updated time.Time // last journalctl run, when falling back
+}
+
+// GetKernelLog returns the kernel messages of this boot, oldest first. It
+// reads /dev/kmsg and falls back to `journalctl -k` when kernel.dmesg_restrict
+// keeps unprivileged users out.
+func (s *StatsCollector) GetKernelLog() ([]models.KernelMessage, error) {
+	klog := &s.kernelLog
+	klog.mutex.Lock()
+	defer klog.mutex.Unlock()
+
+	if !klog.opened {
+		klog.opened = true
+		klog.fd, klog.err = syscall.Open("/dev/kmsg", syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+		if klog.err != nil {
+			slog.Info("cannot open /dev/kmsg, using journalctl -k", "err", klog.err)
+		}
+	}
+
+	if klog.err != nil {
+		if !klog.updated.IsZero() && time.Since(klog.updated) < JournalCacheDuration {
+			return klog.records, nil
+		}
+		klog.updated = time.Now()
+
+		records, err := readJournalKernelLog()
+		if err != nil {
+			slog.Debug("journalctl -k failed", "err", err)
+			return nil, errors.New("kernel log needs root or CAP_SYSLOG (kernel.dmesg_restrict), journalctl -k failed too")
+		}
+		klog.records = records
+		return klog.records, nil
+	}
+
+	buf := make([]byte, 8192)
+	for {
+		n, err := syscall.Read(klog.fd, buf)
+		if err == syscall.EPIPE {
+			// Records were overwritten before we read them, keep going
+			continue
+		}
+		if err != nil || n <= 0 {
+			break // EAGAIN, no new records
+		}
+
+		if record, ok := s.parseKmsgRecord(string(buf[:n])); ok {
+			klog.records = append(klog.records, record)
+		} else {
+			slog.Warn("unparsable /dev/kmsg record", "record", string(buf[:n]))
+		}
+	}
+
+	if len(klog.records) > MaxKernelLogRecords {
+		klog.records = klog.records[len(klog.records)-MaxKernelLogRecords:]
+	}
+
+	return klog.records, nil
+}
+
+// parseKmsgRecord parses "<prio>,<seq>,<usec>,<flags>[,...];<message>"
+func (s *StatsCollector) parseKmsgRecord(record string) (models.KernelMessage, bool) {
+	header, message, found := strings.Cut(record, ";")
+	if !found {
+		return models.KernelMessage{}, false
+	}
+
+	fields := strings.Split(header, ",")
+	if len(fields) < 3 {
+		return models.KernelMessage{}, false
+	}
+
+	prio, _ := strconv.Atoi(fields[0])
+	usec, _ := strconv.ParseInt(fields[2], 10, 64)
+
+	// Continuation lines (" KEY=value" device properties) follow the first line
+	message, _, _ = strings.Cut(message, "\n")
+
+	return models.KernelMessage{
+		Time:     s.bootTime.Add(time.Duration(usec) * time.Microsecond),
+		Priority: prio & 7, // the upper bits carry the syslog facility
+		Message:  message,
+	}, true
+}
+
+func readJournalKernelLog() ([]models.KernelMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "journalctl", "-k", "-b", "--no-pager",
+		"-o", "json", "-n", strconv.Itoa(MaxKernelLogRecords)).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []models.KernelMessage
+	for _, line := range strings.Split(string(output), "\n") {
+		var entry struct {
+			Message   any    `json:"MESSAGE"` // an array of bytes when not valid UTF-8
+			Priority  string `json:"PRIORITY"`
+			Timestamp string `json:"__REALTIME_TIMESTAMP"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			continue
+		}
+		message, ok := entry.Message.(string)
+		if !ok {
+			continue
+		}
+
+		prio, _ := strconv.Atoi(entry.Priority)
+		usec, _ := strconv.ParseInt(entry.Timestamp, 10, 64)
+		records = append(records, models.KernelMessage{
+			Time:     time.UnixMicro(usec),
+			Priority: prio,
+			Message:  message,
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,356 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const (
+	kubeletPodLogsDir       = "/var/log/pods"
+	kubeletContainerLogsDir = "/var/log/containers"
+)
+
+// podCgroup is a pod or container cgroup found under the kubepods hierarchy
+type podCgroup struct {
+	cpuDir    string
+	memoryDir string
+}
+
+// kubeCPUSamples keeps the previous cpu usage reading of each pod/container cgroup
+type kubeCPUSamples struct {
+	mutex   sync.Mutex
+	usage   map[string]uint64
+	sampled time.Time
+}
+
+// kubepodsRoots returns the cpu and memory kubepods cgroup directories
+func kubepodsRoots() (string, string, int) {
+	version := 1
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		version = 2
+	}
+
+	for _, name := range []string{"kubepods.slice", "kubepods"} {
+		if version == 2 {
+			dir := filepath.Join(cgroupRoot, name)
+			if _, err := os.Stat(dir); err == nil {
+				return dir, dir, version
+			}
+			continue
+		}
+
+		memDir := filepath.Join(cgroupRoot, "memory", name)
+		if _, err := os.Stat(memDir); err != nil {
+			continue
+		}
+		for _, cpuMount := range []string{"cpu,cpuacct", "cpu", "cpuacct"} {
+			cpuDir := filepath.Join(cgroupRoot, cpuMount, name)
+			if _, err := os.Stat(cpuDir); err == nil {
+				return cpuDir, memDir, version
+			}
+		}
+		return "", memDir, version
+	}
+
+	return "", "", version
+}
+
+// HasKubernetes reports whether this machine runs Kubernetes pods
+func (s *StatsCollector) HasKubernetes() bool {
+	_, memRoot, _ := kubepodsRoots()
+	return memRoot != ""
+}
+
+// GetPodStats returns per-pod usage against requests and limits, derived from
+// the kubepods cgroup hierarchy and the kubelet log directory naming
+func (s *StatsCollector) GetPodStats() []models.PodStats {
+	cpuRoot, memRoot, version := kubepodsRoots()
+	if memRoot == "" {
+		return nil
+	}
+
+	podNames := readPodLogNames()
+	containerNames := readContainerLogNames()
+
+	s.kubeSamples.mutex.Lock()
+	defer s.kubeSamples.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(s.kubeSamples.sampled).Microseconds()
+	current := make(map[string]uint64)
+
+	cpuUsage := func(dir string) float64 {
+		usage, ok := cgroupCPUUsage(dir, version)
+		if !ok {
+			return 0
+		}
+		current[dir] = usage
+
+		prev, ok := s.kubeSamples.usage[dir]
+		if !ok || elapsed <= 0 || usage < prev {
+			return 0
+		}
+		return float64(usage-prev) / float64(elapsed)
+	}
+
+	var pods []models.PodStats
+	for uid, cgroup := range findPodCgroups(memRoot, cpuRoot) {
+		pod := models.PodStats{
+			UID:      uid,
+			Name:     uid,
+			QoSClass: podQoSClass(cgroup.memoryDir),
+		}
+		if meta, ok := podNames[uid]; ok {
+			pod.Namespace, pod.Name = meta[0], meta[1]
+		}
+
+		pod.CPUUsage = cpuUsage(cgroup.cpuDir)
+		pod.CPURequest = cgroupCPURequest(cgroup.cpuDir, version)
+		pod.CPULimit = cgroupCPUQuota(cgroup.cpuDir, version)
+		pod.MemoryUsed, pod.MemoryLimit = cgroupMemory(cgroup.memoryDir, version)
+
+		for id, container := range findContainerCgroups(cgroup) {
+			stats := models.ContainerStats{
+				ID:       id,
+				Name:     shortContainerID(id),
+				CPUUsage: cpuUsage(container.cpuDir),
+				CPULimit: cgroupCPUQuota(container.cpuDir, version),
+			}
+			if name, ok := containerNames[id]; ok {
+				stats.Name = name
+			}
+			stats.MemoryUsed, stats.MemoryLimit = cgroupMemory(container.memoryDir, version)
+
+			// Pause/sandbox containers carry no log file and no workload
+			if _, ok := containerNames[id]; !ok && stats.MemoryUsed < 1024 {
+				continue
+			}
+			pod.Containers = append(pod.Containers, stats)
+		}
+
+		sort.Slice(pod.Containers, func(i, j int) bool {
+			return pod.Containers[i].Name < pod.Containers[j].Name
+		})
+		pods = append(pods, pod)
+	}
+
+	s.kubeSamples.usage = current
+	s.kubeSamples.sampled = now
+
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return pods
+}
+
+// findPodCgroups locates pod cgroups by their pod UID, which the kubelet
+// embeds in the directory name for both the cgroupfs and systemd drivers
+func findPodCgroups(memRoot, cpuRoot string) map[string]podCgroup {
+	pods := make(map[string]podCgroup)
+
+	filepath.WalkDir(memRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(memRoot, path)
+		if strings.Count(rel, string(filepath.Separator)) > 2 {
+			return filepath.SkipDir
+		}
+
+		uid, ok := parsePodUID(d.Name())
+		if !ok {
+			return nil
+		}
+
+		cgroup := podCgroup{memoryDir: path, cpuDir: path}
+		if cpuRoot != memRoot && cpuRoot != "" {
+			cgroup.cpuDir = filepath.Join(cpuRoot, rel)
+		}
+		pods[uid] = cgroup
+		return filepath.SkipDir
+	})
+
+	return pods
+}
+
+// parsePodUID extracts the UID from "pod<uid>" or "kubepods-<qos>-pod<uid>.slice"
+func parsePodUID(name string) (string, bool) {
+	name = strings.TrimSuffix(name, ".slice")
+	idx := strings.LastIndex(name, "pod")
+	if idx < 0 || (idx > 0 && name[idx-1] != '-') {
+		return "", false
+	}
+
+	uid := strings.ReplaceAll(name[idx+3:], "_", "-")
+	if len(uid) != 36 {
+		return "", false
+	}
+	return uid, true
+}
+
+func findContainerCgroups(pod podCgroup) map[string]podCgroup {
+	containers := make(map[string]podCgroup)
+
+	entries, err := os.ReadDir(pod.memoryDir)
+	if err != nil {
+		return containers
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		// cri-containerd-<id>.scope, crio-<id>.scope, docker-<id>.scope or <id>
+		id := strings.TrimSuffix(entry.Name(), ".scope")
+		if idx := strings.LastIndex(id, "-"); idx >= 0 {
+			id = id[idx+1:]
+		}
+		if len(id) != 64 {
+			continue
+		}
+
+		containers[id] = podCgroup{
+			memoryDir: filepath.Join(pod.memoryDir, entry.Name()),
+			cpuDir:    filepath.Join(pod.cpuDir, entry.Name()),
+		}
+	}
+
+	return containers
+}
+
+func podQoSClass(dir string) string {
+	switch {
+	case strings.Contains(dir, "burstable"):
+		return "Burstable"
+	case strings.Contains(dir, "besteffort"):
+		return "BestEffort"
+	default:
+		return "Guaranteed"
+	}
+}
+
+// cgroupCPURequest converts cpu shares (v1) or cpu weight (v2) back into the
+// requested cores, reversing the kubelet's conversion
+func cgroupCPURequest(dir string, version int) float64 {
+	var shares float64
+	if version == 2 {
+		weight, err := strconv.ParseFloat(readCgroupString(filepath.Join(dir, "cpu.weight")), 64)
+		if err != nil {
+			return 0
+		}
+		shares = (weight-1)*262142/9999 + 2
+	} else {
+		value, err := strconv.ParseFloat(readCgroupString(filepath.Join(dir, "cpu.shares")), 64)
+		if err != nil {
+			return 0
+		}
+		shares = value
+	}
+
+	// 2 shares is the minimum assigned to pods without a request
+	if shares <= 2 {
+		return 0
+	}
+	return shares / 1024
+}
+
+// cgroupCPUQuota returns the cpu limit of a single cgroup in cores
+func cgroupCPUQuota(dir string, version int) float64 {
+	var quota, period float64
+	if version == 2 {
+		fields := strings.Fields(readCgroupString(filepath.Join(dir, "cpu.max")))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0
+		}
+		quota, _ = strconv.ParseFloat(fields[0], 64)
+		period, _ = strconv.ParseFloat(fields[1], 64)
+	} else {
+		quota, _ = strconv.ParseFloat(readCgroupString(filepath.Join(dir, "cpu.cfs_quota_us")), 64)
+		period, _ = strconv.ParseFloat(readCgroupString(filepath.Join(dir, "cpu.cfs_period_us")), 64)
+	}
+
+	if quota <= 0 || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// cgroupMemory returns the memory usage and limit of a single cgroup in KB
+func cgroupMemory(dir string, version int) (float64, float64) {
+	limitFile, usageFile := "memory.limit_in_bytes", "memory.usage_in_bytes"
+	if version == 2 {
+		limitFile, usageFile = "memory.max", "memory.current"
+	}
+
+	used, _ := strconv.ParseFloat(readCgroupString(filepath.Join(dir, usageFile)), 64)
+	limit, err := strconv.ParseFloat(readCgroupString(filepath.Join(dir, limitFile)), 64)
+	// v1 reports "no limit" as a huge page-aligned number
+	if err != nil || limit >= 1<<62 {
+		limit = 0
+	}
+	return used / 1024, limit / 1024
+}
+
+// readPodLogNames maps pod UIDs to namespace and name from
+// /var/log/pods/<namespace>_<name>_<uid>
+func readPodLogNames() map[string][2]string {
+	names := make(map[string][2]string)
+
+	entries, err := os.ReadDir(kubeletPodLogsDir)
+	if err != nil {
+		return names
+	}
+
+	for _, entry := range entries {
+		parts := strings.Split(entry.Name(), "_")
+		if len(parts) != 3 {
+			continue
+		}
+		names[parts[2]] = [2]string{parts[0], parts[1]}
+	}
+	return names
+}
+
+// readContainerLogNames maps container IDs to container names from
+// /var/log/containers/<pod>_<namespace>_<container>-<id>.log
+func readContainerLogNames() map[string]string {
+	names := make(map[string]string)
+
+	entries, err := os.ReadDir(kubeletContainerLogsDir)
+	if err != nil {
+		return names
+	}
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".log")
+		parts := strings.Split(name, "_")
+		if len(parts) != 3 {
+			continue
+		}
+
+		idx := strings.LastIndex(parts[2], "-")
+		if idx < 0 {
+			continue
+		}
+		names[parts[2][idx+1:]] = parts[2][:idx]
+	}
+	return names
+}
+
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// shownLimits are the limits of /proc/[pid]/limits the detail view shows,
+// by their name there, in order. They are the ones incidents run into:
+// "too many open files", fork failing with EAGAIN and mlock failing.
+var shownLimits = []struct{ line, name string }{
+	{"Max open files", "nofile"},
+	{"Max processes", "nproc"},
+	{"Max locked memory", "memlock"},
+}
+
+// readProcessLimits reads the shown limits of a process and what it uses
+// of them, nil when its limits are not readable. status is its
+// readStatusFields.
+func readProcessLimits(pid int, status map[string]string) []models.ResourceLimit {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", pid))
+	if err != nil {
+		return nil
+	}
+	limits := parseLimits(content)
+	for i := range limits {
+		limits[i].Used = -1
+		switch limits[i].Name {
+		case "nofile":
+			if fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid)); err == nil {
+				limits[i].Used = int64(len(fds))
+			}
+		case "nproc":
+			// The kernel counts the threads of every process of the real UID
+			if uid := strings.Fields(status["Uid"]); len(uid) > 0 {
+				limits[i].Used = int64(userThreads(uid[0]))
+			}
+		case "memlock":
+			if kb, ok := statusKB(status["VmLck"]); ok {
+				limits[i].Used = int64(kb) * 1024
+			}
+		}
+	}
+	return limits
+}
+
+// parseLimits reads the shown limits from the content of a limits file.
+// Its columns are padded with spaces, and names contain spaces too, so the
+// values are found after the known name.
+func parseLimits(content []byte) []models.ResourceLimit {
+	var limits []models.ResourceLimit
+	for _, shown := range shownLimits {
+		for _, line := range strings.Split(string(content), "\n") {
+			rest, ok := strings.CutPrefix(line, shown.line)
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(rest)
+			if len(fields) < 2 {
+				break
+			}
+			limit := models.ResourceLimit{Name: shown.name, Soft: parseLimit(fields[0]), Hard: parseLimit(fields[1])}
+			if len(fields) > 2 {
+				limit.Unit = fields[2]
+			}
+			limits = append(limits, limit)
+			break
+		}
+	}
+	return limits
+}
+
+// parseLimit reads one limit value, -1 for unlimited
+func parseLimit(value string) int64 {
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return limit
+}
+
+// readStatusFields reads /proc/[pid]/status into its fields by name
+func readStatusFields(pid int) map[string]string {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil
+	}
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if name, value, ok := strings.Cut(scanner.Text(), ":"); ok {
+			fields[name] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// statusKB reads a status value such as "1024 kB"
+func statusKB(value string) (uint64, bool) {
+	kb, err := strconv.ParseUint(strings.TrimSuffix(value, " kB"), 10, 64)
+	return kb, err == nil
+}
+
+// userThreads counts the threads of the processes whose real UID is uid
+func userThreads(uid string) int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0
+	}
+	threads := 0
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		status := readStatusFields(pid)
+		if ids := strings.Fields(status["Uid"]); len(ids) == 0 || ids[0] != uid {
+			continue
+		}
+		n, _ := strconv.Atoi(status["Threads"])
+		threads += n
+	}
+	return threads
+}
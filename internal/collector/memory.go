@@ -2,6 +2,7 @@ package collector
 
 import (
 	"bufio"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ func (s *StatsCollector) getMemoryStats() models.MemoryStats {
 
 	// fields we need to collect
 	var memTotal, memFree, memAvailable, swapTotal, swapFree float64
+	var buffers, cached, reclaimable float64
 	var foundFields uint8
 	var requiredFields uint8 = 3
 
@@ -61,6 +63,12 @@ func (s *StatsCollector) getMemoryStats() models.MemoryStats {
 			swapTotal = value
 		case "SwapFree":
 			swapFree = value
+		case "Buffers":
+			buffers = value
+		case "Cached":
+			cached = value
+		case "SReclaimable":
+			reclaimable = value
 		}
 
 		// Early exit if we have all required fields
@@ -83,12 +91,11 @@ func (s *StatsCollector) getMemoryStats() models.MemoryStats {
 		return models.MemoryStats{}
 	}
 
-	// Handle case where MemAvailable doesn't exist (older kernels)
-	// Fallback: approximate as MemFree + Buffers + Cached
+	// Kernels before 3.14 and WSL 1 have no MemAvailable; without it the
+	// scan went through the whole file, so the cache sizes are known.
+	// Approximate it the way free did before the kernel estimated it.
 	if memAvailable == 0 {
-		memAvailable = memFree
-		// Note: This is a simplified fallback. For older kernels, you might want to
-		// re-scan for Buffers and Cached values for a more accurate calculation
+		memAvailable = math.Min(memTotal, memFree+buffers+cached+reclaimable)
 	}
 
 	// Calculate derived values
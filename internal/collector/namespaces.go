@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// AddNamespaces fills in the namespaces of the processes
+func (s *StatsCollector) AddNamespaces(processes []models.Process) {
+	for i := range processes {
+		processes[i].Namespaces = readNamespaces(strconv.Itoa(processes[i].PID))
+	}
+}
+
+// HostNamespaces are the namespaces of init, which the processes of the
+// host share. Without the privilege to read init's, croptop's own stand in
+// for them.
+func HostNamespaces() models.Namespaces {
+	host, own := readNamespaces("1"), readNamespaces("self")
+	if host.PID == 0 {
+		host.PID = own.PID
+	}
+	if host.Net == 0 {
+		host.Net = own.Net
+	}
+	if host.Mnt == 0 {
+		host.Mnt = own.Mnt
+	}
+	if host.User == 0 {
+		host.User = own.User
+	}
+	return host
+}
+
+// readNamespaces reads the namespaces of /proc/[pid]/ns, pid being a PID or
+// self
+func readNamespaces(pid string) models.Namespaces {
+	dir := "/proc/" + pid + "/ns/"
+	return models.Namespaces{
+		PID:  readNamespace(dir + "pid"),
+		Net:  readNamespace(dir + "net"),
+		Mnt:  readNamespace(dir + "mnt"),
+		User: readNamespace(dir + "user"),
+	}
+}
+
+// readNamespace reads the inode number of a namespace link such as
+// net:[4026531840], 0 when the link is not readable
+func readNamespace(path string) uint64 {
+	link, err := os.Readlink(path)
+	if err != nil {
+		return 0
+	}
+	_, inode, ok := strings.Cut(link, ":[")
+	if !ok {
+		return 0
+	}
+	id, err := strconv.ParseUint(strings.TrimSuffix(inode, "]"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
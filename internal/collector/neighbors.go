@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const NeighborCacheDuration = 5 * time.Second
+
+type neighborCache struct {
+	mutex     sync.Mutex
+	neighbors []models.Neighbor
+	updated   time.Time
+}
+
+// GetNeighbors returns the ARP and NDP neighbor tables. The neighbor states
+// are only exposed over netlink, so it asks iproute2 and falls back to the
+// IPv4-only /proc/net/arp.
+func (s *StatsCollector) GetNeighbors() []models.Neighbor {
+	cache := &s.neighbors
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if !cache.updated.IsZero() && time.Since(cache.updated) < NeighborCacheDuration {
+		return cache.neighbors
+	}
+	cache.updated = time.Now()
+
+	if neighbors, ok := readIPNeighbors(); ok {
+		cache.neighbors = neighbors
+	} else {
+		cache.neighbors = readProcARP()
+	}
+	return cache.neighbors
+}
+
+func readIPNeighbors() ([]models.Neighbor, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "ip", "-j", "neigh", "show").Output()
+	if err != nil {
+		slog.Debug("ip neigh failed, using /proc/net/arp", "err", err)
+		return nil, false
+	}
+
+	// Flags such as "router" are keys with a null value
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(output, &entries); err != nil {
+		slog.Warn("parsing ip neigh output failed", "err", err)
+		return nil, false
+	}
+
+	neighbors := make([]models.Neighbor, 0, len(entries))
+	for _, entry := range entries {
+		var neighbor models.Neighbor
+		var state []string
+		json.Unmarshal(entry["dst"], &neighbor.IP)
+		json.Unmarshal(entry["lladdr"], &neighbor.MAC)
+		json.Unmarshal(entry["dev"], &neighbor.Interface)
+		json.Unmarshal(entry["state"], &state)
+		neighbor.State = strings.Join(state, ",")
+		_, neighbor.Router = entry["router"]
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors, true
+}
+
+// readProcARP parses /proc/net/arp, which only knows complete, permanent
+// and incomplete entries
+func readProcARP() []models.Neighbor {
+	content, err := os.ReadFile("/proc/net/arp")
+	if err != nil {
+		return nil
+	}
+
+	var neighbors []models.Neighbor
+	lines := strings.Split(string(content), "\n")
+	for _, line := range lines[1:] {
+		// IP address, HW type, Flags, HW address, Mask, Device
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+
+		state := "INCOMPLETE"
+		switch fields[2] {
+		case "0x2":
+			state = "REACHABLE"
+		case "0x6":
+			state = "PERMANENT"
+		}
+		neighbors = append(neighbors, models.Neighbor{
+			IP:        fields[0],
+			MAC:       fields[3],
+			Interface: fields[5],
+			State:     state,
+		})
+	}
+	return neighbors
+}
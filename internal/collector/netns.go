@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// tcpStates name the socket states of /proc/net/tcp
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED", "02": "SYN_SENT", "03": "SYN_RECV", "04": "FIN_WAIT1",
+	"05": "FIN_WAIT2", "06": "TIME_WAIT", "07": "CLOSE", "08": "CLOSE_WAIT",
+	"09": "LAST_ACK", "0A": "LISTEN", "0B": "CLOSING",
+}
+
+// NetNamespaces lists the network namespaces of the processes other than
+// croptop's own, by the lowest PID in each. The namespaces of other users'
+// processes are only visible to root.
+func (s *StatsCollector) NetNamespaces(processes []models.Process) []models.NetNamespace {
+	own := readNamespace("/proc/self/ns/net")
+	byID := make(map[uint64]*models.NetNamespace)
+	for _, proc := range processes {
+		id := readNamespace(fmt.Sprintf("/proc/%d/ns/net", proc.PID))
+		if id == 0 || id == own {
+			continue
+		}
+		ns, ok := byID[id]
+		if !ok {
+			ns = &models.NetNamespace{ID: id, PID: proc.PID, Process: proc.Name}
+			byID[id] = ns
+		}
+		if proc.PID < ns.PID {
+			ns.PID, ns.Process = proc.PID, proc.Name
+		}
+		ns.Processes++
+	}
+
+	namespaces := make([]models.NetNamespace, 0, len(byID))
+	for _, ns := range byID {
+		namespaces = append(namespaces, *ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].PID < namespaces[j].PID })
+	return namespaces
+}
+
+// GetNamespaceNetwork reads the interfaces, local addresses and sockets of a
+// network namespace through /proc/[pid]/net of a process in it, which shows
+// the namespace of the process rather than croptop's. It fails once that
+// process is gone or its PID was reused outside the namespace.
+func (s *StatsCollector) GetNamespaceNetwork(ns models.NetNamespace) (models.NamespaceNetwork, error) {
+	if readNamespace(fmt.Sprintf("/proc/%d/ns/net", ns.PID)) != ns.ID {
+		return models.NamespaceNetwork{}, errors.New("the namespace's process exited, pick the namespace again")
+	}
+	dir := fmt.Sprintf("/proc/%d/net/", ns.PID)
+	interfaces, err := readNetDev(dir + "dev")
+	if err != nil {
+		return models.NamespaceNetwork{}, err
+	}
+	globalIPv6 := getGlobalIPv6Addresses(dir + "if_inet6")
+	network := models.NamespaceNetwork{Interfaces: interfaces, Addresses: readLocalIPv4(dir + "fib_trie")}
+	for i := range network.Interfaces {
+		network.Interfaces[i].GlobalIPv6 = globalIPv6[network.Interfaces[i].Name]
+		network.Addresses = append(network.Addresses, globalIPv6[network.Interfaces[i].Name]...)
+	}
+	for _, table := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		network.Connections = append(network.Connections, readConnections(dir+table, table)...)
+	}
+	// Listening sockets first
+	listening := func(c models.Connection) bool { return c.State == "LISTEN" || c.State == "UNCONN" }
+	sort.SliceStable(network.Connections, func(i, j int) bool {
+		return listening(network.Connections[i]) && !listening(network.Connections[j])
+	})
+	return network, nil
+}
+
+// readLocalIPv4 reads the local IPv4 addresses but loopback from a fib_trie
+// file, where each is a leaf followed by a "/32 host LOCAL" line
+func readLocalIPv4(path string) []string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var addresses []string
+	seen := make(map[string]bool)
+	leaf := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if ip, ok := strings.CutPrefix(line, "|-- "); ok {
+			leaf = ip
+			continue
+		}
+		if strings.HasPrefix(line, "/32 host LOCAL") && !strings.HasPrefix(leaf, "127.") && !seen[leaf] {
+			seen[leaf] = true
+			addresses = append(addresses, leaf)
+		}
+	}
+	return addresses
+}
+
+// readConnections reads the sockets of a /proc/net/{tcp,udp}{,6} file.
+// Unconnected UDP sockets, state 07, are the listening ones.
+func readConnections(path, protocol string) []models.Connection {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var connections []models.Connection
+	lines := strings.Split(string(content), "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		local, localPort, ok := parseSocketAddress(fields[1])
+		if !ok {
+			continue
+		}
+		remote, remotePort, ok := parseSocketAddress(fields[2])
+		if !ok {
+			continue
+		}
+		state := tcpStates[fields[3]]
+		if strings.HasPrefix(protocol, "udp") {
+			state = "ESTABLISHED"
+			if fields[3] == udpUnconned {
+				state = "UNCONN"
+			}
+		}
+		connection := models.Connection{
+			Protocol: protocol,
+			Local:    net.JoinHostPort(local, strconv.Itoa(localPort)),
+			Remote:   net.JoinHostPort(remote, strconv.Itoa(remotePort)),
+			State:    state,
+		}
+		if remotePort == 0 {
+			connection.Remote = "*"
+		}
+		connections = append(connections, connection)
+	}
+	return connections
+}
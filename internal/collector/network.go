@@ -1,31 +1,76 @@
 package collector
 
 import (
+	"encoding/hex"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/prabalesh/croptop/internal/models"
 )
 
+// netSamples keeps the previous byte counters of each interface
+type netSamples struct {
+	mutex   sync.Mutex
+	bytes   map[string][2]uint64 // rx, tx
+	sampled time.Time
+}
+
 func (s *StatsCollector) getNetworkStats() models.NetworkStats {
-	content, err := os.ReadFile("/proc/net/dev")
+	interfaces, err := readNetDev("/proc/net/dev")
 	if err != nil {
 		return models.NetworkStats{}
 	}
 
-	lines := strings.Split(string(content), "\n")
-	var interfaces []models.NetworkInterface
 	var totalRx, totalTx uint64
+	globalIPv6 := getGlobalIPv6Addresses("/proc/net/if_inet6")
+	for i := range interfaces {
+		iface := &interfaces[i]
+		iface.Status = s.getInterfaceStatus(iface.Name)
+		iface.Speed = s.getInterfaceSpeed(iface.Name)
+		iface.IPv6 = ipv6Traffic(readSNMPCounters("/proc/net/dev_snmp6/" + iface.Name))
+		iface.GlobalIPv6 = globalIPv6[iface.Name]
 
-	for i, line := range lines {
-		if i < 2 { // Skip header lines
-			continue
-		}
+		totalRx += iface.RxBytes
+		totalTx += iface.TxBytes
+	}
+
+	rxRate, txRate := s.updateNetworkRates(interfaces)
+
+	var talkers []models.Talker
+	if s.traffic != nil {
+		talkers = s.traffic.sample(topTalkers)
+	}
+
+	return models.NetworkStats{
+		Interfaces: interfaces,
+		TotalRx:    totalRx,
+		TotalTx:    totalTx,
+		RxRate:     rxRate,
+		TxRate:     txRate,
+		IPv4:       getIPv4Traffic(),
+		IPv6:       ipv6Traffic(readSNMPCounters("/proc/net/snmp6")),
+		Protocol:   s.getProtocolStats(),
+		Sockets:    getSocketStats(),
+		Talkers:    talkers,
+	}
+}
 
-		line = strings.TrimSpace(line)
-		if line == "" {
+// readNetDev reads the byte and packet counters of the interfaces but
+// loopback from a /proc/net/dev file
+func readNetDev(path string) ([]models.NetworkInterface, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []models.NetworkInterface
+	for i, line := range strings.Split(string(content), "\n") {
+		if i < 2 { // Skip header lines
 			continue
 		}
 
@@ -45,28 +90,134 @@ func (s *StatsCollector) getNetworkStats() models.NetworkStats {
 		txBytes, _ := strconv.ParseUint(parts[9], 10, 64)
 		txPackets, _ := strconv.ParseUint(parts[10], 10, 64)
 
-		status := s.getInterfaceStatus(name)
-		speed := s.getInterfaceSpeed(name)
-
 		interfaces = append(interfaces, models.NetworkInterface{
 			Name:      name,
 			RxBytes:   rxBytes,
 			TxBytes:   txBytes,
 			RxPackets: rxPackets,
 			TxPackets: txPackets,
-			Status:    status,
-			Speed:     speed,
 		})
+	}
+	return interfaces, nil
+}
 
-		totalRx += rxBytes
-		totalTx += txBytes
+// updateNetworkRates sets the per-second rates of each interface from the
+// previous sample and returns their sums
+func (s *StatsCollector) updateNetworkRates(interfaces []models.NetworkInterface) (float64, float64) {
+	samples := &s.netSamples
+	samples.mutex.Lock()
+	defer samples.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(samples.sampled).Seconds()
+
+	var rxRate, txRate float64
+	current := make(map[string][2]uint64, len(interfaces))
+	for i := range interfaces {
+		iface := &interfaces[i]
+		current[iface.Name] = [2]uint64{iface.RxBytes, iface.TxBytes}
+		if prev, ok := samples.bytes[iface.Name]; ok {
+			iface.RxRate = counterRate(prev[0], iface.RxBytes, elapsed)
+			iface.TxRate = counterRate(prev[1], iface.TxBytes, elapsed)
+			rxRate += iface.RxRate
+			txRate += iface.TxRate
+		}
 	}
 
-	return models.NetworkStats{
-		Interfaces: interfaces,
-		TotalRx:    totalRx,
-		TotalTx:    totalTx,
+	samples.bytes = current
+	samples.sampled = now
+	return rxRate, txRate
+}
+
+// readSNMPCounters parses the "name value" lines of snmp6 style files
+func readSNMPCounters(path string) map[string]uint64 {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	counters := make(map[string]uint64)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			counters[fields[0]], _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return counters
+}
+
+func ipv6Traffic(counters map[string]uint64) models.IPTraffic {
+	return models.IPTraffic{
+		RxBytes:   counters["Ip6InOctets"],
+		TxBytes:   counters["Ip6OutOctets"],
+		RxPackets: counters["Ip6InReceives"],
+		TxPackets: counters["Ip6OutRequests"],
+	}
+}
+
+// getIPv4Traffic reads the system-wide IPv4 counters. Packets are in the Ip
+// section of /proc/net/snmp, bytes in the IpExt section of /proc/net/netstat;
+// both files pair a header line with a value line.
+func getIPv4Traffic() models.IPTraffic {
+	snmp := readSNMPTable("/proc/net/snmp", "Ip:")
+	netstat := readSNMPTable("/proc/net/netstat", "IpExt:")
+	return models.IPTraffic{
+		RxBytes:   netstat["InOctets"],
+		TxBytes:   netstat["OutOctets"],
+		RxPackets: snmp["InReceives"],
+		TxPackets: snmp["OutRequests"],
+	}
+}
+
+func readSNMPTable(path, section string) map[string]uint64 {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var header []string
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != section {
+			continue
+		}
+		if header == nil {
+			header = fields
+			continue
+		}
+
+		counters := make(map[string]uint64, len(header))
+		for i := 1; i < len(header) && i < len(fields); i++ {
+			counters[header[i]], _ = strconv.ParseUint(fields[i], 10, 64)
+		}
+		return counters
+	}
+	return nil
+}
+
+// getGlobalIPv6Addresses returns the global scope IPv6 addresses per
+// interface from an if_inet6 file
+func getGlobalIPv6Addresses(path string) map[string][]string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	// "<address> <ifindex> <prefix length> <scope> <flags> <name>"
+	addresses := make(map[string][]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[3] != "00" {
+			continue
+		}
+		raw, err := hex.DecodeString(fields[0])
+		if err != nil || len(raw) != net.IPv6len {
+			continue
+		}
+		prefix, _ := strconv.ParseUint(fields[2], 16, 8)
+		addresses[fields[5]] = append(addresses[fields[5]], fmt.Sprintf("%s/%d", net.IP(raw), prefix))
 	}
+	return addresses
 }
 
 func (s *StatsCollector) getInterfaceStatus(name string) string {
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// MaxOOMCandidates is the number of processes listed by OOM score
+const MaxOOMCandidates = 10
+
+// Matches both global and memory cgroup kills, e.g.
+// "Out of memory: Killed process 4242 (stress) total-vm:..., anon-rss:1024kB, file-rss:0kB, ..."
+var oomKillPattern = regexp.MustCompile(`Killed process (\d+) \((.*?)\).*?anon-rss:(\d+)kB, file-rss:(\d+)kB`)
+
+// GetMemoryPressure collects what is needed to investigate swapping and
+// OOM kills: processes by swap usage, the likeliest OOM victims and the
+// OOM kills logged by the kernel since boot
+func (s *StatsCollector) GetMemoryPressure(processes models.ProcessList) models.MemoryPressure {
+	var pressure models.MemoryPressure
+
+	for _, proc := range processes.Processes {
+		if proc.Swap > 0 {
+			pressure.SwapProcesses = append(pressure.SwapProcesses, proc)
+		}
+	}
+	sort.SliceStable(pressure.SwapProcesses, func(i, j int) bool {
+		return pressure.SwapProcesses[i].Swap > pressure.SwapProcesses[j].Swap
+	})
+
+	pressure.OOMCandidates = getOOMCandidates(processes)
+	pressure.OOMKillCount = readVMStatCounter("oom_kill")
+
+	records, err := s.GetKernelLog()
+	if err != nil {
+		pressure.KernelLogError = err.Error()
+	}
+	for _, record := range records {
+		match := oomKillPattern.FindStringSubmatch(record.Message)
+		if match == nil {
+			continue
+		}
+		pid, _ := strconv.Atoi(match[1])
+		anonRSS, _ := strconv.ParseUint(match[3], 10, 64)
+		fileRSS, _ := strconv.ParseUint(match[4], 10, 64)
+		pressure.OOMKills = append(pressure.OOMKills, models.OOMKill{
+			Time:    record.Time,
+			PID:     pid,
+			Name:    match[2],
+			AnonRSS: anonRSS,
+			FileRSS: fileRSS,
+			Cgroup:  strings.HasPrefix(record.Message, "Memory cgroup"),
+		})
+	}
+
+	return pressure
+}
+
+// getOOMCandidates returns the processes the OOM killer would pick first
+func getOOMCandidates(processes models.ProcessList) []models.OOMScore {
+	var scores []models.OOMScore
+	for _, proc := range processes.Processes {
+		score, err := readProcInt(fmt.Sprintf("/proc/%d/oom_score", proc.PID))
+		if err != nil {
+			continue
+		}
+		adj, _ := readProcInt(fmt.Sprintf("/proc/%d/oom_score_adj", proc.PID))
+		scores = append(scores, models.OOMScore{
+			PID:      proc.PID,
+			Name:     proc.Name,
+			Score:    score,
+			ScoreAdj: adj,
+			MemRSS:   proc.MemRSS,
+		})
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	if len(scores) > MaxOOMCandidates {
+		scores = scores[:MaxOOMCandidates]
+	}
+	return scores
+}
+
+func readProcInt(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
+
+// readVMStatCounter returns a counter of /proc/vmstat
+func readVMStatCounter(name string) uint64 {
+	content, err := os.ReadFile("/proc/vmstat")
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == name {
+			value, _ := strconv.ParseUint(fields[1], 10, 64)
+			return value
+		}
+	}
+	return 0
+}
+
+// OOMKillCount returns the number of OOM kills since boot, from /proc/vmstat
+func (s *StatsCollector) OOMKillCount() uint64 {
+	return readVMStatCounter("oom_kill")
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// packageOwner is what the package manager said about a file
+type packageOwner struct {
+	name string
+	// The package manager answered, name is empty for files no package owns
+	known bool
+}
+
+// packageCache remembers the package owning each executable asked about,
+// the package databases are slow to query
+type packageCache struct {
+	mutex  sync.Mutex
+	owners map[string]packageOwner
+}
+
+// packageOwnerOf returns the package that installed a file, through dpkg
+// or rpm. known is false when neither is installed or answered.
+func (s *StatsCollector) packageOwnerOf(path string) (name string, known bool) {
+	cache := &s.packages
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if owner, ok := cache.owners[path]; ok {
+		return owner.name, owner.known
+	}
+
+	var owner packageOwner
+	for _, query := range []func(string) (string, error){queryDpkg, queryRPM} {
+		name, err := query(path)
+		if err != nil {
+			continue
+		}
+		owner.known = true
+		if name != "" {
+			owner.name = name
+			break
+		}
+	}
+	if cache.owners == nil {
+		cache.owners = make(map[string]packageOwner)
+	}
+	cache.owners[path] = owner
+	return owner.name, owner.known
+}
+
+// errNoPackageManager is returned by the queries of package managers that
+// are not installed
+var errNoPackageManager = errors.New("package manager not installed")
+
+// queryDpkg asks dpkg which package installed path, "" for none. Before
+// the /usr merge dpkg knows /usr/bin/ls as /bin/ls, so that is tried too.
+func queryDpkg(path string) (string, error) {
+	if _, err := exec.LookPath("dpkg-query"); err != nil {
+		return "", errNoPackageManager
+	}
+	paths := []string{path}
+	if rest, ok := strings.CutPrefix(path, "/usr"); ok {
+		paths = append(paths, rest)
+	}
+	for _, path := range paths {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		output, err := exec.CommandContext(ctx, "dpkg-query", "-S", path).Output()
+		cancel()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Exit status 1 is a file no package owns
+			continue
+		}
+		if err != nil {
+			slog.Debug("dpkg-query failed", "path", path, "err", err)
+			return "", err
+		}
+		// "coreutils: /bin/ls", or "libc6:amd64, libc6:i386: /lib/..."
+		// for files of several packages, after lines on diversions
+		for _, line := range strings.Split(string(output), "\n") {
+			if strings.HasPrefix(line, "diversion by") {
+				continue
+			}
+			if packages, _, ok := strings.Cut(line, ": "); ok {
+				return packages, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// queryRPM asks rpm which package installed path, "" for none
+func queryRPM(path string) (string, error) {
+	if _, err := exec.LookPath("rpm"); err != nil {
+		return "", errNoPackageManager
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "rpm", "-qf", "--queryformat", "%{NAME}-%{VERSION}-%{RELEASE}.%{ARCH}\n", path).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// "file ... is not owned by any package"
+		return "", nil
+	}
+	if err != nil {
+		slog.Debug("rpm failed", "path", path, "err", err)
+		return "", err
+	}
+	// One line per package owning the file
+	return strings.Join(strings.Fields(string(output)), ", "), nil
+}
@@ -0,0 +1,408 @@
+package collector
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// DomainTimeout is how long a snapshot waits for a domain before it serves
+// the domain's last good result instead
+const DomainTimeout = time.Second
+
+// domain supervises one part of the system stats. A collection that runs
+// past DomainTimeout is left to finish in the background while the last
+// good result is served; until it returns the domain counts as stalled and
+// is not started again, so a hung syscall costs a single goroutine.
+//
+// Domains are collected either by a Pipeline, each on a ticker of its own,
+// or when GetSystemStats is called, not both.
+type domain struct {
+	name string
+	// interval is the domain's own schedule; within it the last result is
+	// reused. Zero collects on every call.
+	interval time.Duration
+	collect  func(s *StatsCollector) any
+
+	mutex       sync.Mutex
+	value       any
+	updated     time.Time
+	running     bool
+	started     time.Time
+	failures    int
+	consecutive int
+	// Signalled by expireDomain to wake the domain's ticker
+	wake chan struct{}
+}
+
+// newDomains returns the domains of the system stats
+func newDomains() []*domain {
+	domains := []*domain{
+		{name: "cpu", collect: func(s *StatsCollector) any { return s.getCPUStats() }},
+		{name: "memory", collect: func(s *StatsCollector) any { return s.getMemoryStats() }},
+		{name: "network", collect: func(s *StatsCollector) any { return s.getNetworkStats() }},
+		{name: "disk", collect: func(s *StatsCollector) any { return s.getDiskStats() }},
+		// Battery level and the upower/logind state change slowly
+		{name: "battery", interval: 5 * time.Second, collect: func(s *StatsCollector) any { return s.getBatteryStats() }},
+	}
+	for _, d := range domains {
+		d.wake = make(chan struct{}, 1)
+	}
+	return domains
+}
+
+// refresh collects the domain unless its interval has not passed yet, and
+// returns the latest good result and whether it is stale
+func (d *domain) refresh(s *StatsCollector) (any, bool) {
+	d.mutex.Lock()
+	if d.running {
+		// Still hung from an earlier call
+		d.failures++
+		d.consecutive++
+		value := d.value
+		d.mutex.Unlock()
+		return value, true
+	}
+	if !d.updated.IsZero() && time.Since(d.updated) < d.interval {
+		value := d.value
+		d.mutex.Unlock()
+		return value, false
+	}
+	d.mutex.Unlock()
+	d.begin()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		d.run(s)
+	}()
+
+	timeout := time.NewTimer(DomainTimeout)
+	defer timeout.Stop()
+	select {
+	case <-done:
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		return d.value, false
+	case <-timeout.C:
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		d.failures++
+		d.consecutive++
+		slog.Warn("collector domain timed out, serving its last result", "domain", d.name, "timeout", DomainTimeout)
+		return d.value, true
+	}
+}
+
+// begin marks the domain running before run
+func (d *domain) begin() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.running = true
+	d.started = time.Now()
+}
+
+// run collects the domain, which the caller marked running. A panic in the
+// collection counts as a failure and leaves the last good result in place,
+// rather than taking croptop down with one domain.
+func (d *domain) run(s *StatsCollector) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("collector domain panicked, serving its last result", "domain", d.name,
+				"panic", r, "stack", string(debug.Stack()))
+			d.mutex.Lock()
+			defer d.mutex.Unlock()
+			d.running = false
+			d.failures++
+			d.consecutive++
+		}
+	}()
+	value := d.collect(s)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.consecutive > 0 {
+		slog.Info("collector domain recovered", "domain", d.name, "after", time.Since(d.started).Round(time.Millisecond))
+	}
+	d.value = value
+	d.updated = time.Now()
+	d.running = false
+	d.consecutive = 0
+}
+
+func (d *domain) health() models.DomainHealth {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	health := models.DomainHealth{
+		Name:        d.name,
+		LastSuccess: d.updated,
+		Failures:    d.failures,
+		Consecutive: d.consecutive,
+	}
+	if d.running && time.Since(d.started) > DomainTimeout {
+		health.StalledFor = time.Since(d.started)
+	}
+	return health
+}
+
+// latest returns the last good result of the domain and whether it is
+// stalled
+func (d *domain) latest() (any, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.value, d.running && time.Since(d.started) > DomainTimeout
+}
+
+// CollectorHealth reports the failure counters of every domain
+func (s *StatsCollector) CollectorHealth() []models.DomainHealth {
+	health := make([]models.DomainHealth, len(s.domains))
+	for i, d := range s.domains {
+		health[i] = d.health()
+	}
+	return health
+}
+
+// expireDomain collects a domain within its interval, at the next
+// GetSystemStats or right away in a Pipeline, after a change the user should
+// see right away
+func (s *StatsCollector) expireDomain(name string) {
+	for _, d := range s.domains {
+		if d.name == name {
+			d.mutex.Lock()
+			d.updated = time.Time{}
+			d.mutex.Unlock()
+			select {
+			case d.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Pipeline collects every domain on a ticker of its own and consolidates
+// their results into one SystemStats per round, which it sends on
+// Snapshots. A round ends once every domain without an interval of its own
+// has reported, or DomainTimeout after the first did; the domains that did
+// not report by then are served from their last good result and listed in
+// SystemStats.Stale. Domains with an interval of their own, such as the
+// battery, contribute their latest result to every round.
+//
+// Snapshots holds only the newest SystemStats, so a slow reader skips
+// rounds rather than falling behind.
+type Pipeline struct {
+	s         *StatsCollector
+	snapshots chan models.SystemStats
+	// Index of each domain that finished a collection
+	reports chan int
+	stop    chan struct{}
+	stopped sync.Once
+
+	mutex    sync.Mutex
+	interval time.Duration
+	paused   bool
+	// Closed and replaced when the interval or pause changes
+	changed chan struct{}
+	// Sleep noticed by a domain since the last snapshot
+	suspendedFor time.Duration
+}
+
+// StartPipeline starts collecting the system stats every interval. The
+// collector's domains belong to the pipeline until Stop; GetSystemStats must
+// not be called meanwhile.
+func (s *StatsCollector) StartPipeline(interval time.Duration) *Pipeline {
+	p := &Pipeline{
+		s:         s,
+		snapshots: make(chan models.SystemStats, 1),
+		reports:   make(chan int, len(s.domains)),
+		stop:      make(chan struct{}),
+		interval:  interval,
+		changed:   make(chan struct{}),
+	}
+	for i, d := range s.domains {
+		go p.runDomain(i, d)
+	}
+	go p.consolidate()
+	return p
+}
+
+// Snapshots delivers the newest consolidated SystemStats
+func (p *Pipeline) Snapshots() <-chan models.SystemStats {
+	return p.snapshots
+}
+
+// SetInterval changes how often the domains are collected, starting a
+// round right away
+func (p *Pipeline) SetInterval(interval time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if interval == p.interval && !p.paused {
+		return
+	}
+	p.interval = interval
+	p.paused = false
+	p.reschedule()
+}
+
+// Pause stops collecting until the next SetInterval, so nothing wakes the
+// machine while no one looks
+func (p *Pipeline) Pause() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.paused {
+		return
+	}
+	p.paused = true
+	p.reschedule()
+}
+
+// reschedule wakes the domains to pick up a new schedule. The caller holds
+// mutex.
+func (p *Pipeline) reschedule() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+func (p *Pipeline) schedule() (time.Duration, bool, chan struct{}) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.interval, p.paused, p.changed
+}
+
+// Stop ends the tickers. A collection still running finishes in the
+// background.
+func (p *Pipeline) Stop() {
+	p.stopped.Do(func() { close(p.stop) })
+}
+
+// runDomain collects a domain every interval, or its own if longer
+func (p *Pipeline) runDomain(i int, d *domain) {
+	for {
+		interval, paused, changed := p.schedule()
+		var next *time.Timer
+		if !paused {
+			started := time.Now()
+			p.checkSuspend()
+			d.begin()
+			d.run(p.s)
+			select {
+			case p.reports <- i:
+			case <-p.stop:
+				return
+			}
+			if d.interval > interval {
+				interval = d.interval
+			}
+			next = time.NewTimer(interval - time.Since(started))
+		} else {
+			// Only the schedule or stop end a pause
+			next = time.NewTimer(0)
+			next.Stop()
+		}
+
+		select {
+		case <-p.stop:
+			next.Stop()
+			return
+		case <-changed:
+		case <-d.wake:
+		case <-next.C:
+		}
+		next.Stop()
+	}
+}
+
+// checkSuspend restarts every rate once a domain notices that the machine
+// slept, before that domain collects, and keeps the sleep for the next
+// snapshot
+func (p *Pipeline) checkSuspend() {
+	suspendedFor := p.s.checkSuspend()
+	if suspendedFor == 0 {
+		return
+	}
+	p.s.resetRateSamples()
+	p.mutex.Lock()
+	p.suspendedFor += suspendedFor
+	p.mutex.Unlock()
+}
+
+// consolidate publishes a snapshot at the end of every round
+func (p *Pipeline) consolidate() {
+	reported := make([]bool, len(p.s.domains))
+	var timeout *time.Timer
+	var deadline <-chan time.Time
+	for {
+		select {
+		case <-p.stop:
+			if timeout != nil {
+				timeout.Stop()
+			}
+			return
+		case i := <-p.reports:
+			reported[i] = true
+			if p.s.domains[i].interval > 0 {
+				// Its latest result joins the next round without starting one
+				continue
+			}
+			if deadline == nil {
+				timeout = time.NewTimer(DomainTimeout)
+				deadline = timeout.C
+			}
+			if !p.roundDone(reported) {
+				continue
+			}
+			timeout.Stop()
+		case <-deadline:
+		}
+		p.publish(reported)
+		clear(reported)
+		timeout, deadline = nil, nil
+	}
+}
+
+// roundDone reports whether every domain without an interval of its own
+// reported
+func (p *Pipeline) roundDone(reported []bool) bool {
+	for i, d := range p.s.domains {
+		if d.interval == 0 && !reported[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Pipeline) publish(reported []bool) {
+	var (
+		values = make([]any, len(p.s.domains))
+		stale  = make([]bool, len(p.s.domains))
+	)
+	for i, d := range p.s.domains {
+		var stalled bool
+		values[i], stalled = d.latest()
+		late := d.interval == 0 && !reported[i]
+		if late {
+			d.mutex.Lock()
+			d.failures++
+			d.consecutive++
+			d.mutex.Unlock()
+			slog.Warn("collector domain timed out, serving its last result", "domain", d.name, "timeout", DomainTimeout)
+		}
+		stale[i] = late || stalled
+	}
+
+	p.mutex.Lock()
+	suspendedFor := p.suspendedFor
+	p.suspendedFor = 0
+	p.mutex.Unlock()
+
+	stats := p.s.consolidate(values, stale, suspendedFor)
+	// Replace a snapshot no one took yet; only this goroutine sends
+	select {
+	case <-p.snapshots:
+	default:
+	}
+	p.snapshots <- stats
+}
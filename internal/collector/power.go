@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const PowerProfileCacheDuration = 5 * time.Second
+
+type powerProfileCache struct {
+	mutex    sync.Mutex
+	active   string
+	profiles []string
+	updated  time.Time
+}
+
+func (s *StatsCollector) getPowerSettings() models.PowerSettings {
+	settings := models.PowerSettings{Backlight: -1}
+	settings.BacklightDevice, settings.Backlight = s.getBacklight()
+	settings.PowerProfile, settings.PowerProfiles = s.getPowerProfiles()
+	return settings
+}
+
+// getBacklight returns the first backlight device and its level in percent
+func (s *StatsCollector) getBacklight() (string, int) {
+	dirs, err := filepath.Glob("/sys/class/backlight/*")
+	if err != nil || len(dirs) == 0 {
+		return "", -1
+	}
+
+	for _, dir := range dirs {
+		brightness := s.readBatteryInt(dir + "/brightness")
+		maxBrightness := s.readBatteryInt(dir + "/max_brightness")
+		if maxBrightness > 0 {
+			return filepath.Base(dir), brightness * 100 / maxBrightness
+		}
+	}
+	return "", -1
+}
+
+// getPowerProfiles returns the active profile and all available profiles
+// from power-profiles-daemon
+func (s *StatsCollector) getPowerProfiles() (string, []string) {
+	cache := &s.powerProfiles
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if !cache.updated.IsZero() && time.Since(cache.updated) < PowerProfileCacheDuration {
+		return cache.active, cache.profiles
+	}
+	cache.updated = time.Now()
+	cache.active, cache.profiles = "", nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "powerprofilesctl", "list").Output()
+	if err != nil {
+		slog.Debug("powerprofilesctl list failed", "err", err)
+		return "", nil
+	}
+
+	// Profiles are listed as "* balanced:" (active) or "  performance:"
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasSuffix(line, ":") || strings.HasPrefix(line, "    ") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "*")), ":")
+		cache.profiles = append(cache.profiles, name)
+		if strings.HasPrefix(line, "*") {
+			cache.active = name
+		}
+	}
+
+	return cache.active, cache.profiles
+}
+
+// SetPowerProfile switches the active power-profiles-daemon profile
+func (s *StatsCollector) SetPowerProfile(profile string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "powerprofilesctl", "set", profile).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+
+	// Force a re-read on the next collection
+	s.powerProfiles.mutex.Lock()
+	s.powerProfiles.updated = time.Time{}
+	s.powerProfiles.mutex.Unlock()
+	s.expireDomain("battery")
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// DetectPrivileges probes the features that need elevated permissions, so
+// the UI can tell a locked feature from one with nothing to show. Call it
+// after StartProcEvents. Features the machine lacks altogether, such as RAPL
+// on ARM, are left out, and so are other users' processes when /proc does
+// not hide them.
+func (s *StatsCollector) DetectPrivileges() []models.Privilege {
+	privileges := []models.Privilege{
+		{
+			Feature:     models.FeatureProcessIO,
+			Description: "Disk I/O of other users' processes",
+			Available:   canReadOthersProcIO(),
+			Hint:        "run with sudo or grant CAP_SYS_PTRACE",
+		},
+		{
+			Feature:     models.FeatureKernelLog,
+			Description: "Kernel log from /dev/kmsg",
+			Available:   canOpen("/dev/kmsg"),
+			Hint:        "run with sudo, grant CAP_SYSLOG or set kernel.dmesg_restrict=0",
+		},
+		{
+			Feature:     models.FeatureExecEvents,
+			Description: "Exec events and short-lived processes",
+			Available:   s.procEvents != nil,
+			Hint:        "run with sudo or grant CAP_NET_ADMIN",
+		},
+		{
+			Feature:     models.FeatureSlabinfo,
+			Description: "Slab caches from /proc/slabinfo",
+			Available:   canOpen("/proc/slabinfo"),
+			Hint:        "run with sudo or grant CAP_DAC_READ_SEARCH",
+		},
+	}
+
+	if zones, _ := filepath.Glob(raplZonePattern); len(zones) > 0 {
+		privileges = append(privileges, models.Privilege{
+			Feature:     models.FeatureRAPL,
+			Description: "CPU power from RAPL energy counters",
+			Available:   canOpen(filepath.Join(zones[0], "energy_uj")),
+			Hint:        "run with sudo or grant CAP_DAC_READ_SEARCH (energy counters are root-only since the PLATYPUS fix)",
+		})
+	}
+
+	if privilege, ok := hidePIDPrivilege(); ok {
+		privileges = append(privileges, privilege)
+	}
+
+	for i := range privileges {
+		if privileges[i].Available {
+			privileges[i].Hint = ""
+		}
+	}
+	return privileges
+}
+
+// canReadOthersProcIO reads the I/O counters of init, which belongs to root
+func canReadOthersProcIO() bool {
+	_, err := os.ReadFile("/proc/1/io")
+	return err == nil
+}
+
+func canOpen(path string) bool {
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return false
+	}
+	syscall.Close(fd)
+	return true
+}
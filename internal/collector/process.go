@@ -11,6 +11,18 @@ import (
 	"github.com/prabalesh/croptop/internal/models"
 )
 
+// procIOSample is the cumulative I/O, context switches, page faults and run
+// delay of a process at the last collection
+type procIOSample struct {
+	readBytes    uint64
+	writeBytes   uint64
+	voluntary    uint64
+	nonvoluntary uint64
+	minorFaults  uint64
+	majorFaults  uint64
+	runDelay     time.Duration
+}
+
 // SortBy represents different sorting options
 type SortBy int
 
@@ -19,8 +31,24 @@ const (
 	SortByCPU
 	SortByMemory
 	SortByName
+	SortByGrowth
 )
 
+func (s SortBy) String() string {
+	switch s {
+	case SortByPID:
+		return "PID"
+	case SortByMemory:
+		return "Memory"
+	case SortByName:
+		return "Name"
+	case SortByGrowth:
+		return "Growth"
+	default:
+		return "CPU"
+	}
+}
+
 // GetProcessList returns unsorted process list (maintains backward compatibility)
 func (s *StatsCollector) GetProcessList() models.ProcessList {
 	return s.GetProcessListSorted(SortByCPU, true)
@@ -63,22 +91,27 @@ func (s *StatsCollector) GetProcessListSorted(sortBy SortBy, descending bool) mo
 		}
 	}
 
+	s.updateProcessIORates(processes)
+	s.updateRSSGrowth(processes)
+
 	// Sort processes based on criteria
-	s.sortProcesses(processes, sortBy, descending)
+	SortProcesses(processes, sortBy, descending)
 
 	total = len(processes)
 
-	return models.ProcessList{
+	list := models.ProcessList{
 		Processes: processes,
 		Total:     total,
 		Running:   running,
 		Sleeping:  sleeping,
 		Zombie:    zombie,
 	}
+	s.addCrashLoops(&list)
+	return list
 }
 
-// sortProcesses sorts the process slice based on the specified criteria
-func (s *StatsCollector) sortProcesses(processes []models.Process, sortBy SortBy, descending bool) {
+// SortProcesses sorts the process slice based on the specified criteria
+func SortProcesses(processes []models.Process, sortBy SortBy, descending bool) {
 	switch sortBy {
 	case SortByCPU:
 		sort.Slice(processes, func(i, j int) bool {
@@ -101,6 +134,13 @@ func (s *StatsCollector) sortProcesses(processes []models.Process, sortBy SortBy
 			}
 			return processes[i].Name < processes[j].Name
 		})
+	case SortByGrowth:
+		sort.Slice(processes, func(i, j int) bool {
+			if descending {
+				return processes[i].RSSGrowth > processes[j].RSSGrowth
+			}
+			return processes[i].RSSGrowth < processes[j].RSSGrowth
+		})
 	case SortByPID:
 		fallthrough
 	default:
@@ -136,27 +176,90 @@ func (s *StatsCollector) getProcessInfo(pid int) models.Process {
 	// Parse process information
 	name := s.getProcessName(statusContent)
 	status := statFields[2]
+	ppid, _ := strconv.Atoi(statFields[3])
 	user := s.getProcessUser(pid)
 	command := s.getProcessCommand(pid)
 	cpuPercent := s.getProcessCPUPercent(statFields)
 	memPercent, memRSS := s.getProcessMemory(statusContent)
+	swap := getProcessSwap(statusContent)
 	runtime := s.getProcessRuntime(statFields)
 	priority := s.getProcessPriority(statFields)
+	readBytes, writeBytes := readProcIO(pid)
+	voluntary, nonvoluntary := getProcessCtxSwitches(statusContent)
+	minorFaults, _ := strconv.ParseUint(statFields[9], 10, 64)
+	majorFaults, _ := strconv.ParseUint(statFields[11], 10, 64)
+	runDelay := readRunDelay(pid)
+	// Only the blocked ones, so a refresh does not read it for every process
+	var waitChannel string
+	if status == "D" {
+		waitChannel = readWaitChannel(pid)
+	}
 
 	return models.Process{
 		PID:        pid,
+		PPID:       ppid,
 		Name:       name,
 		Command:    command,
 		CPUPercent: cpuPercent,
 		MemPercent: memPercent,
 		MemRSS:     memRSS,
+		Swap:       swap,
 		Status:     status,
 		User:       user,
 		Runtime:    runtime,
 		Priority:   priority,
+		ReadBytes:  readBytes,
+		WriteBytes: writeBytes,
+
+		VoluntaryCtxSwitches:    voluntary,
+		NonvoluntaryCtxSwitches: nonvoluntary,
+		MinorFaults:             minorFaults,
+		MajorFaults:             majorFaults,
+		RunDelay:                runDelay,
+		WaitChannel:             waitChannel,
 	}
 }
 
+// updateProcessIORates derives per-second I/O, context switch and page fault
+// rates and the run delay share from the previous sample
+func (s *StatsCollector) updateProcessIORates(processes []models.Process) {
+	s.procIOMutex.Lock()
+	defer s.procIOMutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(s.lastProcIOTime).Seconds()
+
+	current := make(map[int]procIOSample, len(processes))
+	for i := range processes {
+		proc := &processes[i]
+		current[proc.PID] = procIOSample{
+			proc.ReadBytes, proc.WriteBytes,
+			proc.VoluntaryCtxSwitches, proc.NonvoluntaryCtxSwitches,
+			proc.MinorFaults, proc.MajorFaults,
+			proc.RunDelay,
+		}
+
+		prev, ok := s.lastProcIO[proc.PID]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+		if proc.ReadBytes >= prev.readBytes {
+			proc.ReadRate = float64(proc.ReadBytes-prev.readBytes) / elapsed
+		}
+		if proc.WriteBytes >= prev.writeBytes {
+			proc.WriteRate = float64(proc.WriteBytes-prev.writeBytes) / elapsed
+		}
+		proc.VoluntaryCtxRate = counterRate(prev.voluntary, proc.VoluntaryCtxSwitches, elapsed)
+		proc.NonvoluntaryCtxRate = counterRate(prev.nonvoluntary, proc.NonvoluntaryCtxSwitches, elapsed)
+		proc.MinorFaultRate = counterRate(prev.minorFaults, proc.MinorFaults, elapsed)
+		proc.MajorFaultRate = counterRate(prev.majorFaults, proc.MajorFaults, elapsed)
+		proc.RunDelayPercent = counterRate(uint64(prev.runDelay), uint64(proc.RunDelay), elapsed) / float64(time.Second) * 100
+	}
+
+	s.lastProcIO = current
+	s.lastProcIOTime = now
+}
+
 func (s *StatsCollector) getProcessName(statusContent []byte) string {
 	lines := strings.Split(string(statusContent), "\n")
 	for _, line := range lines {
@@ -199,9 +302,12 @@ func (s *StatsCollector) getProcessCommand(pid int) string {
 	}
 
 	// Replace null bytes with spaces
-	cmdline := strings.ReplaceAll(string(content), "\x00", " ")
-	cmdline = strings.TrimSpace(cmdline)
+	return truncateCommand(strings.ReplaceAll(string(content), "\x00", " "))
+}
 
+// truncateCommand shortens a command line for display
+func truncateCommand(cmdline string) string {
+	cmdline = strings.TrimSpace(cmdline)
 	if cmdline == "" {
 		return "unknown"
 	}
@@ -269,16 +375,9 @@ func (s *StatsCollector) getProcessCPUPercent(statFields []string) float64 {
 		return 0
 	}
 
-	// Calculate CPU usage as percentage of single core
-	// This gives a more realistic percentage similar to htop
-	cpuUsage := (processCPUTime / processRuntime) * 100.0
-
-	// Cap at 100% per core (htop style)
-	if cpuUsage > 100.0 {
-		cpuUsage = 100.0
-	}
-
-	return cpuUsage
+	// Calculate CPU usage as percentage of single core, like top's Irix
+	// mode; multithreaded processes exceed 100%
+	return (processCPUTime / processRuntime) * 100.0
 }
 
 func (s *StatsCollector) getProcessMemory(statusContent []byte) (float64, uint64) {
@@ -309,7 +408,60 @@ func (s *StatsCollector) getProcessMemory(statusContent []byte) (float64, uint64
 	return memPercent, rss
 }
 
-func (s *StatsCollector) getProcessRuntime(statFields []string) string {
+// getProcessSwap returns the VmSwap of a process in KB
+func getProcessSwap(statusContent []byte) uint64 {
+	for _, line := range strings.Split(string(statusContent), "\n") {
+		if value, ok := strings.CutPrefix(line, "VmSwap:"); ok {
+			fields := strings.Fields(value)
+			if len(fields) > 0 {
+				swap, _ := strconv.ParseUint(fields[0], 10, 64)
+				return swap
+			}
+		}
+	}
+	return 0
+}
+
+// getProcessCtxSwitches returns the voluntary and nonvoluntary context
+// switches of a process
+func getProcessCtxSwitches(statusContent []byte) (voluntary, nonvoluntary uint64) {
+	for _, line := range strings.Split(string(statusContent), "\n") {
+		if value, ok := strings.CutPrefix(line, "voluntary_ctxt_switches:"); ok {
+			voluntary, _ = strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		} else if value, ok := strings.CutPrefix(line, "nonvoluntary_ctxt_switches:"); ok {
+			nonvoluntary, _ = strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	return voluntary, nonvoluntary
+}
+
+// readRunDelay returns how long the main thread of a process waited on a
+// runqueue, the second field of schedstat. Kernels without schedstats
+// report zero.
+func readRunDelay(pid int) time.Duration {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/schedstat", pid))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) < 2 {
+		return 0
+	}
+	delay, _ := strconv.ParseUint(fields[1], 10, 64)
+	return time.Duration(delay)
+}
+
+// readWaitChannel returns the kernel function a process sleeps in, empty
+// when it runs or the kernel does not tell
+func readWaitChannel(pid int) string {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/wchan", pid))
+	if err != nil || string(content) == "0" {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func (s *StatsCollector) getProcessRuntime(statFields []string) time.Duration {
 	if len(statFields) > 21 {
 		startTime, _ := strconv.ParseUint(statFields[21], 10, 64)
 
@@ -318,15 +470,9 @@ func (s *StatsCollector) getProcessRuntime(statFields []string) string {
 		currentTime := uint64(time.Now().Unix())
 		processStart := bootTime + (startTime / 100) // startTime is in clock ticks
 
-		runtime := time.Duration(currentTime-processStart) * time.Second
-
-		hours := int(runtime.Hours())
-		minutes := int(runtime.Minutes()) % 60
-		seconds := int(runtime.Seconds()) % 60
-
-		return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+		return time.Duration(currentTime-processStart) * time.Second
 	}
-	return "00:00:00"
+	return 0
 }
 
 func (s *StatsCollector) getProcessPriority(statFields []string) int {
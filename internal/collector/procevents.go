@@ -0,0 +1,282 @@
+package collector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/crash"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Process events connector constants from linux/connector.h and linux/cn_proc.h
+const (
+	cnIdxProc         = 1
+	cnValProc         = 1
+	procCnMcastListen = 1
+
+	procEventFork = 0x00000001
+	procEventExec = 0x00000002
+	procEventExit = 0x80000000
+
+	nlmsgHeaderLen = 16
+	cnMsgHeaderLen = 20
+)
+
+const (
+	// processes living shorter than this are likely missed by the 1s tick
+	shortLivedThreshold = 2 * time.Second
+	maxShortLived       = 50
+)
+
+// execRecord is a process seen through an exec event that has not exited yet
+type execRecord struct {
+	ppid    int
+	name    string
+	command string
+	started time.Time
+}
+
+// procEventMonitor listens to the kernel proc connector for exec/exit events
+type procEventMonitor struct {
+	fd int
+
+	mutex      sync.Mutex
+	running    map[int]execRecord
+	shortLived []models.ShortLivedProcess
+	execs      uint64
+	// Failed exits and restarts by crashLoopKey
+	restarts map[string]*restartHistory
+}
+
+// procEventCounters keeps the previous counters for rate calculation
+type procEventCounters struct {
+	mutex   sync.Mutex
+	forks   uint64
+	execs   uint64
+	sampled time.Time
+}
+
+// StartProcEvents subscribes to kernel process events. It needs CAP_NET_ADMIN;
+// without it exec activity falls back to the fork counter in /proc/stat.
+func (s *StatsCollector) StartProcEvents() error {
+	if s.procEvents != nil {
+		return nil
+	}
+
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, syscall.NETLINK_CONNECTOR)
+	if err != nil {
+		return err
+	}
+
+	addr := &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+		Groups: cnIdxProc,
+		Pid:    uint32(os.Getpid()),
+	}
+	if err := syscall.Bind(fd, addr); err != nil {
+		syscall.Close(fd)
+		return err
+	}
+
+	// nlmsghdr + cn_msg + PROC_CN_MCAST_LISTEN
+	msg := make([]byte, nlmsgHeaderLen+cnMsgHeaderLen+4)
+	binary.LittleEndian.PutUint32(msg[0:], uint32(len(msg)))
+	binary.LittleEndian.PutUint16(msg[4:], syscall.NLMSG_DONE)
+	binary.LittleEndian.PutUint32(msg[12:], uint32(os.Getpid()))
+	binary.LittleEndian.PutUint32(msg[16:], cnIdxProc)
+	binary.LittleEndian.PutUint32(msg[20:], cnValProc)
+	binary.LittleEndian.PutUint16(msg[32:], 4)
+	binary.LittleEndian.PutUint32(msg[36:], procCnMcastListen)
+
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return err
+	}
+
+	s.procEvents = &procEventMonitor{
+		fd:       fd,
+		running:  make(map[int]execRecord),
+		restarts: make(map[string]*restartHistory),
+	}
+	go s.procEvents.listen()
+
+	return nil
+}
+
+func (m *procEventMonitor) listen() {
+	defer crash.Recover()
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := syscall.Recvfrom(m.fd, buf, 0)
+		if err != nil {
+			if err == syscall.EINTR || err == syscall.ENOBUFS {
+				continue
+			}
+			return
+		}
+
+		// A single datagram may carry several netlink messages
+		data := buf[:n]
+		for len(data) >= nlmsgHeaderLen {
+			msgLen := int(binary.LittleEndian.Uint32(data[0:]))
+			if msgLen < nlmsgHeaderLen || msgLen > len(data) {
+				break
+			}
+			// Messages without a cn_msg, such as NLMSG_NOOP or errors, carry no event
+			if msgLen >= nlmsgHeaderLen+cnMsgHeaderLen {
+				m.handleEvent(data[nlmsgHeaderLen+cnMsgHeaderLen : msgLen])
+			}
+			data = data[min((msgLen+3)&^3, len(data)):]
+		}
+	}
+}
+
+// handleEvent decodes a struct proc_event
+func (m *procEventMonitor) handleEvent(event []byte) {
+	if len(event) < 16 {
+		return
+	}
+
+	what := binary.LittleEndian.Uint32(event[0:])
+	payload := event[16:]
+
+	switch what {
+	case procEventExec:
+		if len(payload) < 8 {
+			return
+		}
+		pid := int(binary.LittleEndian.Uint32(payload[0:]))
+		tgid := int(binary.LittleEndian.Uint32(payload[4:]))
+		if pid != tgid {
+			return // thread, not a process
+		}
+
+		// Read the details right away, the process may be gone by the next tick
+		record := execRecord{started: time.Now()}
+		if stat, ok := readProcStatEntry(pid); ok {
+			record.ppid = stat.ppid
+		}
+		if comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid)); err == nil {
+			record.name = strings.TrimSpace(string(comm))
+		}
+		record.command = strings.Join(readCmdline(pid), " ")
+
+		m.mutex.Lock()
+		m.execs++
+		m.running[pid] = record
+		m.recordStart(record)
+		m.mutex.Unlock()
+
+	case procEventExit:
+		if len(payload) < 12 {
+			return
+		}
+		pid := int(binary.LittleEndian.Uint32(payload[0:]))
+		tgid := int(binary.LittleEndian.Uint32(payload[4:]))
+		exitCode := int(binary.LittleEndian.Uint32(payload[8:]))
+		if pid != tgid {
+			return
+		}
+
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		record, ok := m.running[pid]
+		if !ok {
+			return
+		}
+		delete(m.running, pid)
+
+		lifetime := time.Since(record.started)
+		if exitCode != 0 && lifetime < crashLoopLifetime {
+			m.recordFailure(record, exitCode, time.Now())
+		}
+		if lifetime >= shortLivedThreshold {
+			return
+		}
+
+		m.shortLived = append(m.shortLived, models.ShortLivedProcess{
+			PID:      pid,
+			PPID:     record.ppid,
+			Name:     record.name,
+			Command:  record.command,
+			Lifetime: lifetime,
+			ExitedAt: time.Now(),
+			ExitCode: exitCode >> 8,
+		})
+		if len(m.shortLived) > maxShortLived {
+			m.shortLived = m.shortLived[len(m.shortLived)-maxShortLived:]
+		}
+	}
+}
+
+// snapshot returns the exec count and recent short-lived processes, newest first
+func (m *procEventMonitor) snapshot() (uint64, []models.ShortLivedProcess) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Long running processes are visible in the process list already, and
+	// their exits are no crashes
+	for pid, record := range m.running {
+		if time.Since(record.started) > crashLoopLifetime {
+			delete(m.running, pid)
+		}
+	}
+
+	recent := make([]models.ShortLivedProcess, len(m.shortLived))
+	for i, proc := range m.shortLived {
+		recent[len(m.shortLived)-1-i] = proc
+	}
+	return m.execs, recent
+}
+
+// GetExecActivity returns fork/exec rates and, when the proc connector is
+// available, the processes that started and exited since the last ticks
+func (s *StatsCollector) GetExecActivity() models.ExecActivity {
+	activity := models.ExecActivity{Source: "/proc/stat"}
+	forks := readForkCount()
+
+	var execs uint64
+	if s.procEvents != nil {
+		activity.Source = "proc connector"
+		execs, activity.ShortLived = s.procEvents.snapshot()
+	}
+
+	counters := &s.procEventCounters
+	counters.mutex.Lock()
+	defer counters.mutex.Unlock()
+
+	now := time.Now()
+	if !counters.sampled.IsZero() {
+		elapsed := now.Sub(counters.sampled).Seconds()
+		activity.ForkRate = counterRate(counters.forks, forks, elapsed)
+		activity.ExecRate = counterRate(counters.execs, execs, elapsed)
+	}
+	counters.forks = forks
+	counters.execs = execs
+	counters.sampled = now
+
+	return activity
+}
+
+// readForkCount returns the number of forks since boot
+func readForkCount() uint64 {
+	content, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if value, ok := strings.CutPrefix(line, "processes "); ok {
+			count, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+			return count
+		}
+	}
+	return 0
+}
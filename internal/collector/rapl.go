@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// powercap zones are registered by intel_rapl, which also drives AMD Zen parts.
+// The pattern matches packages (intel-rapl:0) and their subzones (intel-rapl:0:0).
+const raplZonePattern = "/sys/class/powercap/intel-rapl:*"
+
+// raplSamples keeps the previous energy counter of each zone
+type raplSamples struct {
+	mutex   sync.Mutex
+	energy  map[string]uint64
+	sampled time.Time
+	session float64
+}
+
+func (s *StatsCollector) getRAPLStats() models.RAPLStats {
+	zones, _ := filepath.Glob(raplZonePattern)
+	sort.Strings(zones)
+	if len(zones) == 0 {
+		return models.RAPLStats{}
+	}
+
+	samples := &s.rapl
+	samples.mutex.Lock()
+	defer samples.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(samples.sampled).Seconds()
+	current := make(map[string]uint64, len(zones))
+
+	var stats models.RAPLStats
+	for _, zone := range zones {
+		// energy_uj is root-only on kernels patched for PLATYPUS
+		energy, err := readUint(filepath.Join(zone, "energy_uj"))
+		if err != nil {
+			continue
+		}
+		current[zone] = energy
+
+		prev, ok := samples.energy[zone]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+
+		delta := energy - prev
+		if energy < prev {
+			// The counter wrapped around at max_energy_range_uj
+			maxRange, _ := readUint(filepath.Join(zone, "max_energy_range_uj"))
+			delta = maxRange - prev + energy
+		}
+		watts := float64(delta) / 1e6 / elapsed
+
+		name := strings.TrimSpace(readCgroupString(filepath.Join(zone, "name")))
+		stats.Domains = append(stats.Domains, models.PowerDomain{Name: name, Watts: watts})
+
+		// Top level zones are packages (and psys); the rest are subdomains
+		if strings.Count(filepath.Base(zone), ":") == 1 && strings.HasPrefix(name, "package") {
+			stats.PackageWatts += watts
+			samples.session += float64(delta) / 1e6
+		}
+	}
+
+	samples.energy = current
+	samples.sampled = now
+	stats.SessionEnergy = samples.session
+
+	return stats
+}
+
+func readUint(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
@@ -0,0 +1,245 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Auth logs are large, re-read them at most twice a minute
+const FailedLoginCacheDuration = 30 * time.Second
+
+// FailedLoginWindow is how far back failed logins are counted
+const FailedLoginWindow = 24 * time.Hour
+
+// sudo's default timestamp_timeout
+const sudoCredentialTimeout = 15 * time.Minute
+
+// "Failed password for invalid user admin from 203.0.113.7 port 51234 ssh2"
+// "Invalid user admin from 203.0.113.7 port 51234"
+var (
+	sshFailedPattern  = regexp.MustCompile(`Failed \S+ for (?:invalid user )?(\S+) from (\S+)(?: port (\d+))?`)
+	sshInvalidPattern = regexp.MustCompile(`^Invalid user (\S*) from (\S+)(?: port (\d+))?`)
+)
+
+type failedLoginCache struct {
+	mutex   sync.Mutex
+	logins  []models.FailedLogin
+	source  string
+	updated time.Time
+}
+
+// GetSecurityStats summarizes failed SSH logins, listening sockets and
+// active sudo sessions
+func (s *StatsCollector) GetSecurityStats(processes models.ProcessList) models.SecurityStats {
+	stats := models.SecurityStats{
+		Listeners:    getListeners(processes),
+		SudoSessions: getSudoSessions(processes),
+	}
+	stats.FailedLogins, stats.FailedLoginsSource = s.getFailedLogins()
+	return stats
+}
+
+func (s *StatsCollector) getFailedLogins() ([]models.FailedLogin, string) {
+	cache := &s.failedLogins
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if !cache.updated.IsZero() && time.Since(cache.updated) < FailedLoginCacheDuration {
+		return cache.logins, cache.source
+	}
+	cache.updated = time.Now()
+
+	bySource := make(map[string]*models.FailedLogin)
+	// Connections counted by their "Invalid user" line, whose first
+	// "Failed … for invalid user" line is the same attempt
+	invalid := make(map[string]bool)
+	record := func(when time.Time, message string) {
+		counted := false
+		match := sshFailedPattern.FindStringSubmatch(message)
+		if match != nil {
+			connection := match[2] + " " + match[3]
+			counted = match[3] != "" && invalid[connection]
+			delete(invalid, connection)
+		} else if match = sshInvalidPattern.FindStringSubmatch(message); match != nil && match[3] != "" {
+			invalid[match[2]+" "+match[3]] = true
+		}
+		if match == nil {
+			return
+		}
+
+		login, ok := bySource[match[2]]
+		if !ok {
+			login = &models.FailedLogin{Source: match[2]}
+			bySource[match[2]] = login
+		}
+		if !counted {
+			login.Count++
+		}
+		if when.After(login.Last) {
+			login.Last = when
+		}
+		for _, user := range login.Users {
+			if user == match[1] {
+				return
+			}
+		}
+		login.Users = append(login.Users, match[1])
+	}
+
+	// Machines without persistent journald still log sshd to files
+	entries, journalOK := readJournalSSHLog(record)
+	cache.source = ""
+	if entries > 0 {
+		cache.source = "journald"
+	} else if cache.source = readAuthLogFile(record); cache.source == "" && journalOK {
+		cache.source = "journald"
+	}
+
+	cache.logins = make([]models.FailedLogin, 0, len(bySource))
+	for _, login := range bySource {
+		cache.logins = append(cache.logins, *login)
+	}
+	sort.SliceStable(cache.logins, func(i, j int) bool {
+		if cache.logins[i].Count != cache.logins[j].Count {
+			return cache.logins[i].Count > cache.logins[j].Count
+		}
+		return cache.logins[i].Source < cache.logins[j].Source
+	})
+
+	return cache.logins, cache.source
+}
+
+// readJournalSSHLog feeds the sshd messages of the last day to record and
+// returns their number, and false when the journal is not readable
+func readJournalSSHLog(record func(time.Time, string)) (int, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	since := time.Now().Add(-FailedLoginWindow).Format("2006-01-02 15:04:05")
+	output, err := exec.CommandContext(ctx, "journalctl", "--no-pager", "-q", "-o", "json",
+		"--since", since, "_COMM=sshd", "_COMM=sshd-session").Output()
+	if err != nil {
+		slog.Debug("reading sshd journal failed", "err", err)
+		return 0, false
+	}
+
+	entries := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		var entry struct {
+			Message   any    `json:"MESSAGE"`
+			Timestamp string `json:"__REALTIME_TIMESTAMP"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			continue
+		}
+		if message, ok := entry.Message.(string); ok {
+			usec, _ := strconv.ParseInt(entry.Timestamp, 10, 64)
+			record(time.UnixMicro(usec), message)
+			entries++
+		}
+	}
+	return entries, true
+}
+
+// readAuthLogFile parses syslog style auth logs (Debian and RHEL naming)
+func readAuthLogFile(record func(time.Time, string)) string {
+	for _, path := range []string{"/var/log/auth.log", "/var/log/secure"} {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+
+		cutoff := time.Now().Add(-FailedLoginWindow)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			when, message, ok := parseSyslogLine(line)
+			if !ok || when.Before(cutoff) || !strings.Contains(line, "sshd") {
+				continue
+			}
+			record(when, message)
+		}
+		file.Close()
+		return path
+	}
+	return ""
+}
+
+// parseSyslogLine splits "Oct 15 18:09:48 host sshd[123]: message" or the
+// RFC 3339 timestamps of newer rsyslog defaults
+func parseSyslogLine(line string) (time.Time, string, bool) {
+	_, message, found := strings.Cut(line, "]: ")
+	if !found {
+		return time.Time{}, "", false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return time.Time{}, "", false
+	}
+	if when, err := time.Parse(time.RFC3339Nano, fields[0]); err == nil {
+		return when, message, true
+	}
+
+	// Classic syslog timestamps have no year
+	when, err := time.ParseInLocation("Jan _2 15:04:05", strings.Join(fields[:3], " "), time.Local)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	now := time.Now()
+	when = when.AddDate(now.Year(), 0, 0)
+	if when.After(now) {
+		when = when.AddDate(-1, 0, 0)
+	}
+	return when, message, true
+}
+
+// getSudoSessions lists running sudo commands and cached sudo credentials
+func getSudoSessions(processes models.ProcessList) []models.SudoSession {
+	var sessions []models.SudoSession
+	for _, proc := range processes.Processes {
+		if proc.Name != "sudo" {
+			continue
+		}
+		// The real UID stays the invoking user's
+		sessions = append(sessions, models.SudoSession{
+			User:    LookupUsername(proc.User),
+			PID:     proc.PID,
+			Command: proc.Command,
+		})
+	}
+
+	// sudo keeps one timestamp file per user, readable by root only
+	for _, dir := range []string{"/run/sudo/ts", "/var/run/sudo/ts", "/var/db/sudo/ts"} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := os.Stat(filepath.Join(dir, entry.Name()))
+			if err != nil || time.Since(info.ModTime()) > sudoCredentialTimeout {
+				continue
+			}
+			sessions = append(sessions, models.SudoSession{
+				User:     entry.Name(),
+				LastAuth: info.ModTime(),
+			})
+		}
+		break
+	}
+
+	return sessions
+}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/crash"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// ServiceCheckTimeout bounds each connect, a service that takes longer is
+// reported down
+const ServiceCheckTimeout = 2 * time.Second
+
+// CheckServices connects to every host:port address in parallel over TCP
+// and reports whether each accepted and how fast
+func (s *StatsCollector) CheckServices(addresses []string) []models.ServiceCheck {
+	checks := make([]models.ServiceCheck, len(addresses))
+
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			defer crash.Recover()
+			checks[i] = checkService(address)
+		}(i, address)
+	}
+	wg.Wait()
+
+	return checks
+}
+
+func checkService(address string) models.ServiceCheck {
+	check := models.ServiceCheck{Address: address, Time: time.Now()}
+	conn, err := net.DialTimeout("tcp", address, ServiceCheckTimeout)
+	check.Latency = time.Since(check.Time)
+	if err != nil {
+		// "connection refused" rather than "dial tcp 127.0.0.1:80: connect: connection refused"
+		var (
+			dnsErr     *net.DNSError
+			syscallErr *os.SyscallError
+			opErr      *net.OpError
+		)
+		switch {
+		case errors.As(err, &dnsErr):
+			check.Error = dnsErr.Err
+		case errors.As(err, &syscallErr):
+			check.Error = syscallErr.Err.Error()
+		case errors.As(err, &opErr):
+			check.Error = opErr.Err.Error()
+		default:
+			check.Error = err.Error()
+		}
+		return check
+	}
+	conn.Close()
+	check.Up = true
+	return check
+}
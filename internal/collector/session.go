@@ -0,0 +1,239 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// compositorNames are the display servers and compositors, by process name
+var compositorNames = map[string]bool{
+	"Xorg": true, "X": true, "Xwayland": true,
+	"gnome-shell": true, "kwin_wayland": true, "kwin_x11": true,
+	"sway": true, "Hyprland": true, "weston": true, "mutter": true,
+	"picom": true, "compton": true, "xfwm4": true, "marco": true,
+	"muffin": true, "labwc": true, "wayfire": true, "river": true,
+	"niri": true, "cosmic-comp": true, "gamescope": true, "kwin": true,
+}
+
+// gpuClient is a DRM client of a process on a GPU: its drm-pdev and
+// drm-client-id, shared by the file descriptors duplicated from one open
+type gpuClient struct {
+	pid    int
+	device string
+	id     string
+}
+
+// gpuSamples keeps the previous engine busy times of every DRM client, in ns
+type gpuSamples struct {
+	mutex   sync.Mutex
+	busy    map[gpuClient]map[string]uint64
+	sampled time.Time
+}
+
+// GetDesktopStats lists the logind sessions and splits the CPU and GPU
+// usage between the compositors and the applications
+func (s *StatsCollector) GetDesktopStats(processes models.ProcessList) models.DesktopStats {
+	stats := models.DesktopStats{Sessions: listSessions()}
+
+	gpu := s.sampleGPU(processes)
+	stats.GPUKnown = gpu != nil
+	appEngines := make(map[string]float64)
+	for _, proc := range processes.Processes {
+		engines := gpu[proc.PID]
+		if !compositorNames[proc.Name] {
+			stats.AppsCPUPercent += proc.CPUPercent
+			for engine, percent := range engines {
+				appEngines[engine] += percent
+			}
+			continue
+		}
+		usage := models.CompositorUsage{PID: proc.PID, Name: proc.Name, CPUPercent: proc.CPUPercent}
+		for _, percent := range engines {
+			usage.GPUPercent = math.Max(usage.GPUPercent, percent)
+		}
+		stats.Compositors = append(stats.Compositors, usage)
+	}
+	for _, percent := range appEngines {
+		stats.AppsGPUPercent = math.Max(stats.AppsGPUPercent, math.Min(percent, 100))
+	}
+	return stats
+}
+
+// sampleGPU returns how busy each process kept each GPU engine since the
+// last call, in percent, from the DRM fdinfo of its /dev/dri file
+// descriptors (Linux 5.19 or later). Nil when no process' could be read.
+func (s *StatsCollector) sampleGPU(processes models.ProcessList) map[int]map[string]float64 {
+	samples := &s.gpuSamples
+	samples.mutex.Lock()
+	defer samples.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(samples.sampled).Nanoseconds()
+	current := make(map[gpuClient]map[string]uint64)
+	for _, proc := range processes.Processes {
+		for client, engines := range readDRMClients(proc.PID) {
+			current[client] = engines
+		}
+	}
+
+	var usage map[int]map[string]float64
+	if len(current) > 0 {
+		usage = make(map[int]map[string]float64)
+	}
+	for client, engines := range current {
+		prev, ok := samples.busy[client]
+		if usage[client.pid] == nil {
+			usage[client.pid] = make(map[string]float64)
+		}
+		if !ok || samples.sampled.IsZero() || elapsed <= 0 {
+			continue
+		}
+		for engine, busy := range engines {
+			if busy >= prev[engine] {
+				usage[client.pid][engine] += float64(busy-prev[engine]) / float64(elapsed) * 100
+			}
+		}
+	}
+	samples.busy = current
+	samples.sampled = now
+	return usage
+}
+
+// readDRMClients reads the engine busy times of the DRM clients of a
+// process. Empty when it has none or croptop may not read its descriptors.
+func readDRMClients(pid int) map[gpuClient]map[string]uint64 {
+	dir := fmt.Sprintf("/proc/%d/fd", pid)
+	fds, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var clients map[gpuClient]map[string]uint64
+	for _, fd := range fds {
+		target, err := os.Readlink(dir + "/" + fd.Name())
+		if err != nil || !strings.HasPrefix(target, "/dev/dri/") {
+			continue
+		}
+		info, err := os.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%s", pid, fd.Name()))
+		if err != nil {
+			continue
+		}
+
+		client := gpuClient{pid: pid}
+		engines := make(map[string]uint64)
+		for _, line := range strings.Split(string(info), "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			switch {
+			case key == "drm-pdev":
+				client.device = value
+			case key == "drm-client-id":
+				client.id = value
+			case strings.HasPrefix(key, "drm-engine-") && key != "drm-engine-capacity":
+				if ns, err := strconv.ParseUint(strings.TrimSuffix(value, " ns"), 10, 64); err == nil {
+					engines[strings.TrimPrefix(key, "drm-engine-")] = ns
+				}
+			}
+		}
+		// Older kernels and drivers without usage statistics
+		if client.id == "" || len(engines) == 0 {
+			continue
+		}
+		if clients == nil {
+			clients = make(map[gpuClient]map[string]uint64)
+		}
+		clients[client] = engines
+	}
+	return clients
+}
+
+// listSessions reads the logind sessions through loginctl
+func listSessions() []models.Session {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "loginctl", "list-sessions", "--no-legend").Output()
+	if err != nil {
+		slog.Debug("listing logind sessions failed", "err", err)
+		return nil
+	}
+	args := []string{"show-session"}
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			args = append(args, fields[0])
+		}
+	}
+	if len(args) == 1 {
+		return nil
+	}
+	for _, property := range []string{"Id", "Name", "Type", "Class", "Desktop", "Seat", "TTY", "Remote", "State", "IdleHint", "IdleSinceHint"} {
+		args = append(args, "-p", property)
+	}
+	output, err = exec.CommandContext(ctx, "loginctl", args...).Output()
+	if err != nil {
+		slog.Debug("reading logind sessions failed", "err", err)
+		return nil
+	}
+	return parseSessions(string(output), os.Getenv("XDG_SESSION_ID"))
+}
+
+// parseSessions reads the Property=value lines of loginctl show-session,
+// a blank line between sessions
+func parseSessions(output, current string) []models.Session {
+	var sessions []models.Session
+	for _, block := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		var session models.Session
+		var idle bool
+		var idleSince int64
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, "=")
+			switch key {
+			case "Id":
+				session.ID = value
+			case "Name":
+				session.User = value
+			case "Type":
+				session.Type = value
+			case "Class":
+				session.Class = value
+			case "Desktop":
+				session.Desktop = value
+			case "Seat":
+				session.Seat = value
+			case "TTY":
+				session.TTY = value
+			case "Remote":
+				session.Remote = value == "yes"
+			case "State":
+				session.State = value
+			case "IdleHint":
+				idle = value == "yes"
+			case "IdleSinceHint":
+				// Microseconds since the epoch
+				idleSince, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+		if session.ID == "" {
+			continue
+		}
+		if idle && idleSince > 0 {
+			session.IdleSince = time.UnixMicro(idleSince)
+		}
+		session.Current = session.ID == current
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
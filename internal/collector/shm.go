@@ -0,0 +1,230 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const (
+	// MaxShmFiles is the number of tmpfs files listed, biggest first
+	MaxShmFiles = 15
+	// MaxTmpfsWalk caps the files visited on all tmpfs mounts together,
+	// a /tmp full of build trees would take seconds otherwise
+	MaxTmpfsWalk = 50000
+)
+
+// GetSharedMemory lists the biggest tmpfs files and the System V shared
+// memory segments with the processes using them. Owners are found in every
+// process' maps and open files, so this is only collected while shown.
+func (s *StatsCollector) GetSharedMemory(processes models.ProcessList) models.SharedMemory {
+	var shm models.SharedMemory
+	shm.Mounts = tmpfsMounts()
+
+	visited := 0
+	for _, mount := range shm.Mounts {
+		var truncated bool
+		shm.Files, truncated = walkTmpfs(mount.Mountpoint, shm.Files, &visited)
+		shm.Truncated = shm.Truncated || truncated
+	}
+	slices.SortFunc(shm.Files, func(a, b models.ShmFile) int { return compareDesc(a.Size, b.Size) })
+	shm.Files = shm.Files[:min(MaxShmFiles, len(shm.Files))]
+
+	shm.Segments = readSysVShm()
+	slices.SortFunc(shm.Segments, func(a, b models.ShmSegment) int { return compareDesc(a.Size, b.Size) })
+
+	findShmUsers(processes, &shm)
+	return shm
+}
+
+func compareDesc(a, b uint64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
+
+// tmpfsMounts returns the mounted tmpfs filesystems and their usage. A
+// filesystem mounted more than once, e.g. bind mounted into a container,
+// is listed at its first mountpoint.
+func tmpfsMounts() []models.TmpfsMount {
+	content, err := os.ReadFile("/proc/mounts")
+	if err != nil {
+		return nil
+	}
+
+	var mounts []models.TmpfsMount
+	seen := make(map[uint64]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[2] != "tmpfs" {
+			continue
+		}
+		var root syscall.Stat_t
+		if err := syscall.Stat(fields[1], &root); err != nil || seen[uint64(root.Dev)] {
+			continue
+		}
+		seen[uint64(root.Dev)] = true
+		// tmpfs lives in memory, statfs cannot hang on it
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(fields[1], &stat); err != nil {
+			continue
+		}
+		mounts = append(mounts, models.TmpfsMount{
+			Mountpoint: fields[1],
+			Used:       (uint64(stat.Blocks) - uint64(stat.Bfree)) * uint64(stat.Bsize),
+			Size:       uint64(stat.Blocks) * uint64(stat.Bsize),
+		})
+	}
+	return mounts
+}
+
+// walkTmpfs adds the regular files of a tmpfs mount to files, staying on
+// that mount. It reports whether it stopped at MaxTmpfsWalk.
+func walkTmpfs(mountpoint string, files []models.ShmFile, visited *int) ([]models.ShmFile, bool) {
+	var root syscall.Stat_t
+	if err := syscall.Stat(mountpoint, &root); err != nil {
+		return files, false
+	}
+
+	truncated := false
+	filepath.WalkDir(mountpoint, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped, the rest is still listed
+			return nil
+		}
+		if *visited >= MaxTmpfsWalk {
+			truncated = true
+			return filepath.SkipAll
+		}
+		*visited++
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || stat.Dev != root.Dev {
+			// Another filesystem mounted below, listed on its own if tmpfs
+			if entry.IsDir() && path != mountpoint {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() && stat.Blocks > 0 {
+			// Allocated blocks, sparse files take less than their size
+			files = append(files, models.ShmFile{Path: path, Size: uint64(stat.Blocks) * 512})
+		}
+		return nil
+	})
+	return files, truncated
+}
+
+// readSysVShm parses /proc/sysvipc/shm
+func readSysVShm() []models.ShmSegment {
+	content, err := os.ReadFile("/proc/sysvipc/shm")
+	if err != nil {
+		return nil
+	}
+
+	var segments []models.ShmSegment
+	// key shmid perms size cpid lpid nattch uid gid cuid cgid atime dtime ctime rss swap
+	for _, line := range strings.Split(string(content), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 15 {
+			continue
+		}
+		key, _ := strconv.ParseInt(fields[0], 10, 64)
+		id, _ := strconv.Atoi(fields[1])
+		size, _ := strconv.ParseUint(fields[3], 10, 64)
+		creator, _ := strconv.Atoi(fields[4])
+		attached, _ := strconv.Atoi(fields[6])
+		uid, _ := strconv.Atoi(fields[7])
+		rss, _ := strconv.ParseUint(fields[14], 10, 64)
+		segments = append(segments, models.ShmSegment{
+			Key:      fmt.Sprintf("0x%08x", uint32(key)),
+			ID:       id,
+			Size:     size,
+			RSS:      rss,
+			Attached: attached,
+			Creator:  creator,
+			UID:      uid,
+		})
+	}
+	return segments
+}
+
+// findShmUsers fills in the processes mapping each listed file and segment,
+// or holding a file open. System V segments show up in maps as /SYSV<key>
+// with the segment ID as the inode.
+func findShmUsers(processes models.ProcessList, shm *models.SharedMemory) {
+	files := make(map[string]int, len(shm.Files))
+	for i, file := range shm.Files {
+		files[file.Path] = i
+	}
+	segments := make(map[int]int, len(shm.Segments))
+	for i, segment := range shm.Segments {
+		segments[segment.ID] = i
+	}
+	if len(files) == 0 && len(segments) == 0 {
+		return
+	}
+
+	for _, proc := range processes.Processes {
+		seenFiles := make(map[int]bool)
+		seenSegments := make(map[int]bool)
+		addFile := func(path string) {
+			if i, ok := files[path]; ok && !seenFiles[i] {
+				seenFiles[i] = true
+				shm.Files[i].PIDs = append(shm.Files[i].PIDs, proc.PID)
+			}
+		}
+
+		if maps, err := os.Open(fmt.Sprintf("/proc/%d/maps", proc.PID)); err == nil {
+			scanner := bufio.NewScanner(maps)
+			for scanner.Scan() {
+				// address perms offset dev inode path
+				fields := strings.Fields(scanner.Text())
+				if len(fields) < 6 {
+					continue
+				}
+				path := fields[5]
+				if strings.HasPrefix(path, "/SYSV") {
+					id, _ := strconv.Atoi(fields[4])
+					if i, ok := segments[id]; ok && !seenSegments[i] {
+						seenSegments[i] = true
+						shm.Segments[i].PIDs = append(shm.Segments[i].PIDs, proc.PID)
+					}
+					continue
+				}
+				addFile(path)
+			}
+			maps.Close()
+		}
+
+		if len(files) == 0 {
+			continue
+		}
+		fdDir := fmt.Sprintf("/proc/%d/fd", proc.PID)
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			if target, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err == nil {
+				addFile(target)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// MaxSlabCaches is the number of slab caches listed, biggest first
+const MaxSlabCaches = 15
+
+// GetSlabStats returns the slab totals of /proc/meminfo and, when
+// /proc/slabinfo is readable, its biggest caches
+func (s *StatsCollector) GetSlabStats() models.SlabStats {
+	var slab models.SlabStats
+	if content, err := os.ReadFile(ProcMemInfoPath); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			value, _ := strconv.ParseUint(fields[1], 10, 64)
+			switch fields[0] {
+			case "SReclaimable:":
+				slab.Reclaimable = value
+			case "SUnreclaim:":
+				slab.Unreclaimable = value
+			}
+		}
+	}
+
+	slab.Caches = readSlabInfo()
+	slices.SortFunc(slab.Caches, func(a, b models.SlabCache) int { return compareDesc(a.Size, b.Size) })
+	slab.Caches = slab.Caches[:min(MaxSlabCaches, len(slab.Caches))]
+	return slab
+}
+
+// readSlabInfo parses /proc/slabinfo version 2.1
+func readSlabInfo() []models.SlabCache {
+	content, err := os.ReadFile("/proc/slabinfo")
+	if err != nil {
+		return nil
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	var caches []models.SlabCache
+	// name active_objs num_objs objsize objperslab pagesperslab : tunables
+	// limit batchcount sharedfactor : slabdata active_slabs num_slabs sharedavail
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 15 || strings.HasPrefix(fields[0], "#") || fields[6] != ":" {
+			continue
+		}
+		active, _ := strconv.ParseUint(fields[1], 10, 64)
+		objects, _ := strconv.ParseUint(fields[2], 10, 64)
+		objectSize, _ := strconv.ParseUint(fields[3], 10, 64)
+		pagesPerSlab, _ := strconv.ParseUint(fields[5], 10, 64)
+		slabs, _ := strconv.ParseUint(fields[14], 10, 64)
+		caches = append(caches, models.SlabCache{
+			Name:          fields[0],
+			ActiveObjects: active,
+			Objects:       objects,
+			ObjectSize:    objectSize,
+			Size:          slabs * pagesPerSlab * pageSize,
+		})
+	}
+	return caches
+}
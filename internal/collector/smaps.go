@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// AddMemoryDetail fills in the PSS and USS of the processes from
+// /proc/[pid]/smaps_rollup. The kernel walks every mapping of a process to
+// answer, so this is much slower than the rest of the process list.
+// Processes of other users need CAP_SYS_PTRACE and are left at zero.
+func (s *StatsCollector) AddMemoryDetail(processes []models.Process) {
+	for i := range processes {
+		processes[i].PSS, processes[i].USS = readSmapsRollup(processes[i].PID)
+	}
+}
+
+// readSmapsRollup returns the proportional and unique set size of a process
+// in KB; USS is its private pages, clean and dirty
+func readSmapsRollup(pid int) (uint64, uint64) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/smaps_rollup", pid))
+	if err != nil {
+		return 0, 0
+	}
+
+	var pss, uss uint64
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		kb, _ := strconv.ParseUint(fields[0], 10, 64)
+		switch key {
+		case "Pss":
+			pss = kb
+		case "Private_Clean", "Private_Dirty":
+			uss += kb
+		}
+	}
+	return pss, uss
+}
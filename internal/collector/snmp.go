@@ -0,0 +1,131 @@
+package collector
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// snmpSamples keeps the previous protocol counters, keyed "Section:Name"
+type snmpSamples struct {
+	mutex    sync.Mutex
+	counters map[string]uint64
+	sampled  time.Time
+}
+
+func (s *StatsCollector) getProtocolStats() models.ProtocolStats {
+	current := make(map[string]uint64)
+	for _, table := range []struct{ path, section string }{
+		{"/proc/net/snmp", "Ip:"},
+		{"/proc/net/snmp", "Tcp:"},
+		{"/proc/net/snmp", "Udp:"},
+		{"/proc/net/netstat", "TcpExt:"},
+	} {
+		for name, value := range readSNMPTable(table.path, table.section) {
+			current[table.section+name] = value
+		}
+	}
+	for name, value := range readSNMPCounters("/proc/net/snmp6") {
+		if udp, ok := strings.CutPrefix(name, "Udp6"); ok {
+			current["Udp6:"+udp] = value
+		}
+	}
+
+	samples := &s.snmp
+	samples.mutex.Lock()
+	defer samples.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(samples.sampled).Seconds()
+	// counter sums the given keys, e.g. the IPv4 and IPv6 variants
+	counter := func(keys ...string) models.Counter {
+		var c models.Counter
+		for _, key := range keys {
+			c.Total += current[key]
+			if prev, ok := samples.counters[key]; ok {
+				c.Rate += counterRate(prev, current[key], elapsed)
+			}
+		}
+		return c
+	}
+
+	stats := models.ProtocolStats{
+		TCPOutSegs:      counter("Tcp:OutSegs"),
+		TCPRetransSegs:  counter("Tcp:RetransSegs"),
+		TCPInErrs:       counter("Tcp:InErrs"),
+		TCPOutRsts:      counter("Tcp:OutRsts"),
+		ListenOverflows: counter("TcpExt:ListenOverflows"),
+		ListenDrops:     counter("TcpExt:ListenDrops"),
+		IPInDiscards:    counter("Ip:InDiscards"),
+		IPOutDiscards:   counter("Ip:OutDiscards"),
+		IPInHdrErrors:   counter("Ip:InHdrErrors"),
+		UDPRcvbufErrors: counter("Udp:RcvbufErrors", "Udp6:RcvbufErrors"),
+		UDPSndbufErrors: counter("Udp:SndbufErrors", "Udp6:SndbufErrors"),
+		UDPInErrors:     counter("Udp:InErrors", "Udp6:InErrors"),
+		UDPNoPorts:      counter("Udp:NoPorts", "Udp6:NoPorts"),
+	}
+	if stats.TCPOutSegs.Rate > 0 {
+		stats.RetransPercent = stats.TCPRetransSegs.Rate / stats.TCPOutSegs.Rate * 100
+	}
+
+	samples.counters = current
+	samples.sampled = now
+
+	return stats
+}
+
+// getSocketStats reads socket counts and buffer memory from sockstat. The
+// kernel accounts socket memory and its tcp_mem/udp_mem limits in pages.
+func getSocketStats() models.SocketStats {
+	var stats models.SocketStats
+
+	content, err := os.ReadFile("/proc/net/sockstat")
+	if err != nil {
+		return stats
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		// "TCP: inuse 4 orphan 0 tw 0 alloc 4 mem 0"
+		values := make(map[string]uint64)
+		for i := 1; i+1 < len(fields); i += 2 {
+			values[fields[i]], _ = strconv.ParseUint(fields[i+1], 10, 64)
+		}
+
+		switch fields[0] {
+		case "sockets:":
+			stats.Used = int(values["used"])
+		case "TCP:":
+			stats.TCPInUse = int(values["inuse"])
+			stats.TCPOrphan = int(values["orphan"])
+			stats.TCPTimeWait = int(values["tw"])
+			stats.TCPMemory = values["mem"] * pageSize
+		case "UDP:":
+			stats.UDPInUse = int(values["inuse"])
+			stats.UDPMemory = values["mem"] * pageSize
+		}
+	}
+
+	// "<min> <pressure> <max>"
+	if limits := strings.Fields(readCgroupString("/proc/sys/net/ipv4/tcp_mem")); len(limits) == 3 {
+		pressure, _ := strconv.ParseUint(limits[1], 10, 64)
+		maximum, _ := strconv.ParseUint(limits[2], 10, 64)
+		stats.TCPMemoryPressure, stats.TCPMemoryMax = pressure*pageSize, maximum*pageSize
+	}
+	if limits := strings.Fields(readCgroupString("/proc/sys/net/ipv4/udp_mem")); len(limits) == 3 {
+		pressure, _ := strconv.ParseUint(limits[1], 10, 64)
+		maximum, _ := strconv.ParseUint(limits[2], 10, 64)
+		stats.UDPMemoryPressure, stats.UDPMemoryMax = pressure*pageSize, maximum*pageSize
+	}
+
+	return stats
+}
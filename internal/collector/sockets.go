@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Socket states of /proc/net/{tcp,udp}
+const (
+	tcpListen   = "0A"
+	udpUnconned = "07"
+)
+
+// getListeners returns the listening sockets and the processes owning them
+func getListeners(processes models.ProcessList) []models.Listener {
+	var listeners []models.Listener
+	inodes := make(map[string]int) // socket inode -> index in listeners
+
+	for _, table := range []struct{ file, protocol, state string }{
+		{"/proc/net/tcp", "tcp", tcpListen},
+		{"/proc/net/tcp6", "tcp6", tcpListen},
+		{"/proc/net/udp", "udp", udpUnconned},
+		{"/proc/net/udp6", "udp6", udpUnconned},
+	} {
+		content, err := os.ReadFile(table.file)
+		if err != nil {
+			continue
+		}
+
+		lines := strings.Split(string(content), "\n")
+		for _, line := range lines[1:] {
+			fields := strings.Fields(line)
+			if len(fields) < 10 || fields[3] != table.state {
+				continue
+			}
+
+			address, port, ok := parseSocketAddress(fields[1])
+			if !ok {
+				continue
+			}
+			inodes[fields[9]] = len(listeners)
+			listeners = append(listeners, models.Listener{
+				Protocol: table.protocol,
+				Address:  address,
+				Port:     port,
+				User:     LookupUsername(fields[7]),
+			})
+		}
+	}
+
+	// Find the owners through the socket:[inode] links of every open fd
+	names := make(map[int]string, len(processes.Processes))
+	for _, proc := range processes.Processes {
+		names[proc.PID] = proc.Name
+	}
+	for pid, name := range names {
+		fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid))
+		if err != nil {
+			continue // other users' processes need root
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fmt.Sprintf("/proc/%d/fd", pid), fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			if i, ok := inodes[inode]; ok && listeners[i].PID == 0 {
+				listeners[i].PID = pid
+				listeners[i].Process = name
+			}
+		}
+	}
+
+	sort.SliceStable(listeners, func(i, j int) bool {
+		if listeners[i].Port != listeners[j].Port {
+			return listeners[i].Port < listeners[j].Port
+		}
+		return listeners[i].Protocol < listeners[j].Protocol
+	})
+
+	return listeners
+}
+
+// parseSocketAddress decodes "0100007F:0035" style addresses. The address is
+// stored as 32-bit words in host (little endian) byte order.
+func parseSocketAddress(field string) (string, int, bool) {
+	hexAddr, hexPort, found := strings.Cut(field, ":")
+	if !found {
+		return "", 0, false
+	}
+
+	raw, err := hex.DecodeString(hexAddr)
+	if err != nil || len(raw)%4 != 0 {
+		return "", 0, false
+	}
+	for i := 0; i < len(raw); i += 4 {
+		raw[i], raw[i+1], raw[i+2], raw[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return net.IP(raw).String(), int(port), true
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"log/slog"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/crash"
+)
+
+// StatfsTimeout bounds a statfs call. An NFS or CIFS mount whose server is
+// gone blocks in it, for minutes with hard mounts.
+const StatfsTimeout = 500 * time.Millisecond
+
+// statfsCache keeps the last answer of every mountpoint and which calls are
+// still hung, so a stalled mount is not asked again until it answers
+type statfsCache struct {
+	mutex   sync.Mutex
+	last    map[string]syscall.Statfs_t
+	pending map[string]bool
+}
+
+// statfs is syscall.Statfs with StatfsTimeout. A mountpoint that does not
+// answer in time is stalled: it reports its last known sizes, if any.
+func (s *StatsCollector) statfs(mountpoint string) (stat syscall.Statfs_t, stalled bool, err error) {
+	cache := &s.statfsCache
+	cache.mutex.Lock()
+	if cache.pending[mountpoint] {
+		stat = cache.last[mountpoint]
+		cache.mutex.Unlock()
+		return stat, true, nil
+	}
+	if cache.pending == nil {
+		cache.pending = make(map[string]bool)
+		cache.last = make(map[string]syscall.Statfs_t)
+	}
+	cache.pending[mountpoint] = true
+	cache.mutex.Unlock()
+
+	type result struct {
+		stat syscall.Statfs_t
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer crash.Recover()
+		var stat syscall.Statfs_t
+		err := syscall.Statfs(mountpoint, &stat)
+
+		cache.mutex.Lock()
+		defer cache.mutex.Unlock()
+		delete(cache.pending, mountpoint)
+		if err == nil {
+			cache.last[mountpoint] = stat
+		}
+		done <- result{stat, err}
+	}()
+
+	timeout := time.NewTimer(StatfsTimeout)
+	defer timeout.Stop()
+	select {
+	case r := <-done:
+		return r.stat, false, r.err
+	case <-timeout.C:
+		slog.Warn("statfs timed out, mount stalled", "mountpoint", mountpoint, "timeout", StatfsTimeout)
+		cache.mutex.Lock()
+		defer cache.mutex.Unlock()
+		return cache.last[mountpoint], true, nil
+	}
+}
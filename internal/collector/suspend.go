@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SuspendThreshold is how far the boot clock may run ahead of the monotonic
+// clock between two samples before the gap is taken for a suspend
+const SuspendThreshold = 2 * time.Second
+
+// suspendDetector compares /proc/uptime, which is CLOCK_BOOTTIME and keeps
+// counting during suspend, with Go's monotonic clock, which stops
+type suspendDetector struct {
+	mutex   sync.Mutex
+	uptime  float64
+	sampled time.Time
+}
+
+// checkSuspend returns how long the machine slept since the previous call
+func (s *StatsCollector) checkSuspend() time.Duration {
+	uptime, ok := readUptime()
+	if !ok {
+		return 0
+	}
+
+	detector := &s.suspend
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	var slept time.Duration
+	if !detector.sampled.IsZero() {
+		bootElapsed := time.Duration((uptime - detector.uptime) * float64(time.Second))
+		if gap := bootElapsed - time.Since(detector.sampled); gap > SuspendThreshold {
+			slept = gap
+			slog.Info("resumed from suspend, restarting rates", "suspended", gap.Round(time.Second))
+		}
+	}
+	detector.uptime = uptime
+	detector.sampled = time.Now()
+
+	return slept
+}
+
+func readUptime() (float64, bool) {
+	content, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	return uptime, err == nil
+}
+
+// resetRateSamples drops the previous sample of every delta-based rate, so
+// the first readings after a resume don't span the sleep
+func (s *StatsCollector) resetRateSamples() {
+	s.cpuCache.Clear()
+
+	s.procIOMutex.Lock()
+	s.lastProcIO = nil
+	s.procIOMutex.Unlock()
+
+	s.diskSamples.mutex.Lock()
+	s.diskSamples.disks = nil
+	s.diskSamples.mutex.Unlock()
+
+	s.snmp.mutex.Lock()
+	s.snmp.counters = nil
+	s.snmp.mutex.Unlock()
+
+	s.netSamples.mutex.Lock()
+	s.netSamples.bytes = nil
+	s.netSamples.mutex.Unlock()
+
+	if s.traffic != nil {
+		s.traffic.reset()
+	}
+
+	// The session energy total stays, only the counters restart
+	s.rapl.mutex.Lock()
+	s.rapl.energy = nil
+	s.rapl.mutex.Unlock()
+
+	s.kubeSamples.mutex.Lock()
+	s.kubeSamples.usage = nil
+	s.kubeSamples.mutex.Unlock()
+
+	s.vmSamples.mutex.Lock()
+	s.vmSamples.samples = nil
+	s.vmSamples.mutex.Unlock()
+
+	s.procEventCounters.mutex.Lock()
+	s.procEventCounters.sampled = time.Time{}
+	s.procEventCounters.mutex.Unlock()
+
+	if s.cgroup != nil {
+		s.cgroup.mutex.Lock()
+		s.cgroup.lastSampled = time.Time{}
+		s.cgroup.mutex.Unlock()
+	}
+}
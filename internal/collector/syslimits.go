@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// getSystemLimits reads kernel.pid_max, kernel.threads-max and fs.file-max
+// with what is in use of them: the processes in /proc, the threads the
+// scheduler knows of from loadavg and the allocated file handles
+func (s *StatsCollector) getSystemLimits() models.SystemLimits {
+	var limits models.SystemLimits
+	limits.PIDMax, _ = readProcInt("/proc/sys/kernel/pid_max")
+	limits.ThreadsMax, _ = readProcInt("/proc/sys/kernel/threads-max")
+	if entries, err := os.ReadDir("/proc"); err == nil {
+		for _, entry := range entries {
+			if _, err := strconv.Atoi(entry.Name()); err == nil {
+				limits.Processes++
+			}
+		}
+	}
+	// The fourth field is runnable/total scheduling entities, threads
+	if content, err := os.ReadFile("/proc/loadavg"); err == nil {
+		if fields := strings.Fields(string(content)); len(fields) > 3 {
+			if _, total, ok := strings.Cut(fields[3], "/"); ok {
+				limits.Threads, _ = strconv.Atoi(total)
+			}
+		}
+	}
+	// Allocated handles, allocated but unused ones (always 0 since 2.6)
+	// and the maximum
+	if content, err := os.ReadFile("/proc/sys/fs/file-nr"); err == nil {
+		if fields := strings.Fields(string(content)); len(fields) == 3 {
+			allocated, _ := strconv.Atoi(fields[0])
+			unused, _ := strconv.Atoi(fields[1])
+			limits.Files = allocated - unused
+			limits.FilesMax, _ = strconv.Atoi(fields[2])
+		}
+	}
+	return limits
+}
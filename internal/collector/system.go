@@ -46,3 +46,18 @@ func getBootTime() time.Time {
 	}
 	return time.Now()
 }
+
+// getLoadAverage reads the 1, 5 and 15 minute load averages
+func getLoadAverage() [3]float64 {
+	var load [3]float64
+	content, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return load
+	}
+
+	fields := strings.Fields(string(content))
+	for i := 0; i < len(load) && i < len(fields); i++ {
+		load[i], _ = strconv.ParseFloat(fields[i], 64)
+	}
+	return load
+}
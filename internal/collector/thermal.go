@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+const thermalRoot = "/sys/class/thermal"
+
+// cpuThermalTypes are the zone types that measure the CPU, best first
+var cpuThermalTypes = []string{"x86_pkg_temp", "cpu-thermal", "cpu_thermal", "soc_thermal", "TCPU", "acpitz"}
+
+// GetThermalZones lists every thermal zone with its trip points. Zones
+// without a readable temperature, such as disabled ones, are left out.
+func (s *StatsCollector) GetThermalZones() []models.ThermalZone {
+	dirs, _ := filepath.Glob(filepath.Join(thermalRoot, "thermal_zone*"))
+	var zones []models.ThermalZone
+	for _, dir := range dirs {
+		temp, err := readMillidegrees(filepath.Join(dir, "temp"))
+		if err != nil {
+			continue
+		}
+		zone := models.ThermalZone{
+			Name: filepath.Base(dir),
+			Type: readTrimmed(filepath.Join(dir, "type")),
+			Temp: temp,
+		}
+		for i := 0; ; i++ {
+			prefix := filepath.Join(dir, "trip_point_"+strconv.Itoa(i))
+			kind := readTrimmed(prefix + "_type")
+			if kind == "" {
+				break
+			}
+			// Unused trip points read 0 or below
+			if temp, err := readMillidegrees(prefix + "_temp"); err == nil && temp > 0 {
+				zone.Trips = append(zone.Trips, models.TripPoint{Type: kind, Temp: temp})
+			}
+		}
+		zones = append(zones, zone)
+	}
+	// thermal_zone10 after thermal_zone9
+	slices.SortFunc(zones, func(a, b models.ThermalZone) int {
+		return zoneNumber(a.Name) - zoneNumber(b.Name)
+	})
+	return zones
+}
+
+// cpuThermalZone returns the temp file of the zone measuring the CPU, or
+// false when no zone says it does
+func cpuThermalZone() (string, bool) {
+	dirs, _ := filepath.Glob(filepath.Join(thermalRoot, "thermal_zone*"))
+	best, rank := "", len(cpuThermalTypes)
+	for _, dir := range dirs {
+		if i := slices.Index(cpuThermalTypes, readTrimmed(filepath.Join(dir, "type"))); i >= 0 && i < rank {
+			best, rank = filepath.Join(dir, "temp"), i
+		}
+	}
+	return best, best != ""
+}
+
+func zoneNumber(name string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(name, "thermal_zone"))
+	return n
+}
+
+func readMillidegrees(path string) (float64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(value) / 1000, nil
+}
+
+func readTrimmed(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
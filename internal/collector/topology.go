@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// cpuTopology caches where the online CPUs sit. It only changes when CPUs
+// are hotplugged, which changes the online CPUs as well.
+type cpuTopology struct {
+	mutex  sync.Mutex
+	ids    []int
+	places []models.CPUPlace
+}
+
+// getCPUTopology returns the package and core of each CPU in ids, or nil if
+// sysfs does not tell, e.g. in some containers
+func (s *StatsCollector) getCPUTopology(ids []int) []models.CPUPlace {
+	s.topology.mutex.Lock()
+	defer s.topology.mutex.Unlock()
+
+	if slices.Equal(s.topology.ids, ids) {
+		return s.topology.places
+	}
+
+	places := make([]models.CPUPlace, len(ids))
+	for i, id := range ids {
+		dir := fmt.Sprintf("/sys/devices/system/cpu/cpu%d/topology/", id)
+		pkg, err := readSysInt(dir + "physical_package_id")
+		if err != nil {
+			places = nil
+			break
+		}
+		core, err := readSysInt(dir + "core_id")
+		if err != nil {
+			places = nil
+			break
+		}
+		places[i] = models.CPUPlace{Package: pkg, Core: core}
+	}
+
+	s.topology.ids = ids
+	s.topology.places = places
+	return places
+}
+
+func readSysInt(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
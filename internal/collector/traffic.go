@@ -0,0 +1,228 @@
+package collector
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/crash"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Packet socket constants from linux/if_ether.h, linux/if_packet.h and
+// linux/if_arp.h
+const (
+	ethPAll        = 0x0003
+	packetOutgoing = 4
+	arphrdLoopback = 772
+)
+
+const (
+	// enough for the IP and TCP/UDP headers
+	captureSnapLen = 128
+	captureRcvBuf  = 4 << 20
+	// endpoints counted between two samples, bounding the memory a scan
+	// of many hosts takes
+	maxTalkers = 4096
+	topTalkers = 10
+)
+
+// talkerKey is a remote endpoint as the top talkers count it
+type talkerKey struct {
+	host     netip.Addr
+	port     uint16
+	protocol uint8
+}
+
+// talkerBytes is the traffic with one endpoint since the last sample
+type talkerBytes struct {
+	rx, tx uint64
+}
+
+// trafficMonitor counts the bytes of the IP packets on every interface but
+// loopback, from their headers only
+type trafficMonitor struct {
+	fd int
+
+	mutex   sync.Mutex
+	talkers map[talkerKey]*talkerBytes
+	sampled time.Time
+}
+
+// StartTrafficCapture opens a packet socket for the top talkers of the
+// Network tab. It needs CAP_NET_RAW. Traffic forwarded for containers or
+// VMs counts on every interface it crosses, the bridge and the uplink.
+func (s *StatsCollector) StartTrafficCapture() error {
+	if s.traffic != nil {
+		return nil
+	}
+
+	// SOCK_DGRAM strips the link-layer header, whatever the interface type
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM, int(htons(ethPAll)))
+	if err != nil {
+		return err
+	}
+	// Dropped packets only make the sample smaller
+	_ = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF, captureRcvBuf)
+
+	s.traffic = &trafficMonitor{
+		fd:      fd,
+		talkers: make(map[talkerKey]*talkerBytes),
+		sampled: time.Now(),
+	}
+	go s.traffic.listen()
+
+	return nil
+}
+
+// htons converts to network byte order, the protocol of a packet socket
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+// listen counts the captured packets until the socket fails
+func (m *trafficMonitor) listen() {
+	defer crash.Recover()
+	// Only the headers are copied, MSG_TRUNC still returns the full length
+	buf := make([]byte, captureSnapLen)
+	for {
+		n, from, err := syscall.Recvfrom(m.fd, buf, syscall.MSG_TRUNC)
+		if err != nil {
+			if err == syscall.EINTR || err == syscall.ENOBUFS {
+				continue
+			}
+			return
+		}
+		link, ok := from.(*syscall.SockaddrLinklayer)
+		if !ok || link.Hatype == arphrdLoopback {
+			continue
+		}
+		m.count(buf[:min(n, len(buf))], n, link.Pkttype == packetOutgoing)
+	}
+}
+
+// count adds an IP packet of length bytes to its remote endpoint. The
+// remote port is the lower of both ports, so the connections of a client
+// to one service count together whichever side the service is on.
+func (m *trafficMonitor) count(packet []byte, length int, outgoing bool) {
+	if len(packet) < 1 {
+		return
+	}
+	var (
+		src, dst  netip.Addr
+		protocol  uint8
+		transport []byte
+	)
+	switch packet[0] >> 4 {
+	case 4:
+		headerLen := int(packet[0]&0x0f) * 4
+		if len(packet) < 20 || headerLen < 20 {
+			return
+		}
+		protocol = packet[9]
+		src = netip.AddrFrom4([4]byte(packet[12:16]))
+		dst = netip.AddrFrom4([4]byte(packet[16:20]))
+		// Only the first fragment carries the ports
+		if binary.BigEndian.Uint16(packet[6:8])&0x1fff == 0 && len(packet) > headerLen {
+			transport = packet[headerLen:]
+		}
+	case 6:
+		// Extension headers are not followed, their packets count without ports
+		if len(packet) < 40 {
+			return
+		}
+		protocol = packet[6]
+		src = netip.AddrFrom16([16]byte(packet[8:24]))
+		dst = netip.AddrFrom16([16]byte(packet[24:40]))
+		transport = packet[40:]
+	default:
+		return
+	}
+
+	key := talkerKey{host: src, protocol: protocol}
+	if outgoing {
+		key.host = dst
+	}
+	if (protocol == syscall.IPPROTO_TCP || protocol == syscall.IPPROTO_UDP) && len(transport) >= 4 {
+		key.port = binary.BigEndian.Uint16(transport[0:2])
+		if dstPort := binary.BigEndian.Uint16(transport[2:4]); dstPort < key.port {
+			key.port = dstPort
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	talker, ok := m.talkers[key]
+	if !ok {
+		// New endpoints beyond the bound wait for the next sample
+		if len(m.talkers) >= maxTalkers {
+			return
+		}
+		talker = &talkerBytes{}
+		m.talkers[key] = talker
+	}
+	if outgoing {
+		talker.tx += uint64(length)
+	} else {
+		talker.rx += uint64(length)
+	}
+}
+
+// sample returns the count busiest endpoints since the last sample, by the
+// sum of both directions, and starts counting afresh
+func (m *trafficMonitor) sample(count int) []models.Talker {
+	m.mutex.Lock()
+	talkers := m.talkers
+	elapsed := time.Since(m.sampled).Seconds()
+	m.talkers = make(map[talkerKey]*talkerBytes, len(talkers))
+	m.sampled = time.Now()
+	m.mutex.Unlock()
+
+	if elapsed <= 0 {
+		return nil
+	}
+	top := make([]models.Talker, 0, len(talkers))
+	for key, bytes := range talkers {
+		top = append(top, models.Talker{
+			Host:     key.host.Unmap().String(),
+			Port:     int(key.port),
+			Protocol: ipProtocolName(key.protocol),
+			RxRate:   float64(bytes.rx) / elapsed,
+			TxRate:   float64(bytes.tx) / elapsed,
+		})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].RxRate+top[i].TxRate > top[j].RxRate+top[j].TxRate
+	})
+	if len(top) > count {
+		top = top[:count]
+	}
+	return top
+}
+
+// reset drops what was counted, e.g. across a suspend
+func (m *trafficMonitor) reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.talkers = make(map[talkerKey]*talkerBytes)
+	m.sampled = time.Now()
+}
+
+// ipProtocolName names the IP protocols that are told apart
+func ipProtocolName(protocol uint8) string {
+	switch protocol {
+	case syscall.IPPROTO_TCP:
+		return "tcp"
+	case syscall.IPPROTO_UDP:
+		return "udp"
+	case syscall.IPPROTO_ICMP:
+		return "icmp"
+	case syscall.IPPROTO_ICMPV6:
+		return "icmp6"
+	default:
+		return "ip"
+	}
+}
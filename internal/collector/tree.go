@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// procStatEntry holds the /proc/[pid]/stat fields needed to build process trees
+type procStatEntry struct {
+	pid      int
+	ppid     int
+	cpuTicks uint64
+	threads  int
+	rssPages uint64
+}
+
+// GetProcessTree returns aggregated resource usage of rootPID and all of its descendants
+func (s *StatsCollector) GetProcessTree(rootPID int) models.ProcessTreeStats {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return models.ProcessTreeStats{RootPID: rootPID}
+	}
+
+	stats := make(map[int]procStatEntry)
+	children := make(map[int][]int)
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		stat, ok := readProcStatEntry(pid)
+		if !ok {
+			continue
+		}
+		stats[pid] = stat
+		children[stat.ppid] = append(children[stat.ppid], pid)
+	}
+
+	tree := models.ProcessTreeStats{RootPID: rootPID}
+	if _, ok := stats[rootPID]; !ok {
+		return tree
+	}
+
+	pageSizeKB := uint64(os.Getpagesize() / 1024)
+
+	// Walk the tree breadth first starting from the root
+	queue := []int{rootPID}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+
+		stat := stats[pid]
+		readBytes, writeBytes := readProcIO(pid)
+
+		tree.Processes++
+		tree.Threads += stat.threads
+		tree.CPUTicks += stat.cpuTicks
+		tree.MemRSS += stat.rssPages * pageSizeKB
+		tree.ReadBytes += readBytes
+		tree.WriteBytes += writeBytes
+
+		queue = append(queue, children[pid]...)
+	}
+
+	return tree
+}
+
+func readProcStatEntry(pid int) (procStatEntry, bool) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return procStatEntry{}, false
+	}
+
+	// The command name may contain spaces, so split after its closing paren
+	data := string(content)
+	end := strings.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStatEntry{}, false
+	}
+
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return procStatEntry{}, false
+	}
+
+	ppid, _ := strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	threads, _ := strconv.Atoi(fields[17])
+	rss, _ := strconv.ParseUint(fields[21], 10, 64)
+
+	return procStatEntry{
+		pid:      pid,
+		ppid:     ppid,
+		cpuTicks: utime + stime,
+		threads:  threads,
+		rssPages: rss,
+	}, true
+}
+
+// readProcIO returns the storage bytes read and written by a process
+func readProcIO(pid int) (uint64, uint64) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/io", pid))
+	if err != nil {
+		return 0, 0
+	}
+
+	var readBytes, writeBytes uint64
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "read_bytes:":
+			readBytes, _ = strconv.ParseUint(fields[1], 10, 64)
+		case "write_bytes:":
+			writeBytes, _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+
+	return readBytes, writeBytes
+}
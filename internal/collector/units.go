@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// UnitActions are what can be done to a systemd unit, in the order offered
+var UnitActions = []string{"start", "stop", "restart", "enable", "disable"}
+
+// unitActionTimeout leaves time to answer a polkit agent's password prompt
+const unitActionTimeout = 90 * time.Second
+
+// RunUnitAction starts, stops, restarts, enables or disables a systemd unit
+// by asking systemd over D-Bus through busctl. Unless croptop runs as root
+// polkit decides, and may have its agent ask for a password.
+func (s *StatsCollector) RunUnitAction(unit, action string) error {
+	var calls [][]string
+	switch action {
+	case "start", "stop", "restart":
+		method := strings.ToUpper(action[:1]) + action[1:] + "Unit"
+		calls = [][]string{{method, "ss", unit, "replace"}}
+	case "enable":
+		// Not only runtime, and replacing symlinks of other units; systemd
+		// only picks up the changed unit files after a reload
+		calls = [][]string{{"EnableUnitFiles", "asbb", "1", unit, "false", "true"}, {"Reload"}}
+	case "disable":
+		calls = [][]string{{"DisableUnitFiles", "asb", "1", unit, "false"}, {"Reload"}}
+	default:
+		return fmt.Errorf("unknown unit action %q", action)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unitActionTimeout)
+	defer cancel()
+	for _, call := range calls {
+		args := append([]string{"--allow-interactive-authorization=yes",
+			fmt.Sprintf("--timeout=%d", int(unitActionTimeout.Seconds())), "call",
+			"org.freedesktop.systemd1", "/org/freedesktop/systemd1", "org.freedesktop.systemd1.Manager"}, call...)
+		output, err := exec.CommandContext(ctx, "busctl", args...).CombinedOutput()
+		if err != nil {
+			return unitActionError(strings.TrimSpace(string(output)), err)
+		}
+	}
+	return nil
+}
+
+// unitActionError turns what busctl printed into an error saying what to
+// do about it, polkit's refusals in particular
+func unitActionError(output string, err error) error {
+	message := strings.TrimPrefix(output, "Call failed: ")
+	switch {
+	case strings.Contains(message, "Interactive authentication required"):
+		return errors.New("polkit wants a password but no polkit agent is running to ask for it, run croptop with sudo or start an agent such as pkttyagent")
+	case strings.Contains(message, "Access denied"), strings.Contains(message, "Permission denied"):
+		return errors.New("polkit denied it, run croptop with sudo or as a user allowed to manage units")
+	case message != "":
+		return errors.New(message)
+	}
+	return err
+}
@@ -0,0 +1,203 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// UPower refreshes its devices every few seconds, no need to ask more often
+const UPowerCacheDuration = 5 * time.Second
+
+// upowerDevice is one device block of `upower --dump`
+type upowerDevice struct {
+	path       string
+	kind       string
+	properties map[string]string
+}
+
+type upowerCache struct {
+	mutex   sync.Mutex
+	devices []upowerDevice
+	ok      bool
+	updated time.Time
+}
+
+// getUPowerDevices returns the devices known to UPower, or false when the
+// daemon or its command line client is unavailable
+func (s *StatsCollector) getUPowerDevices() ([]upowerDevice, bool) {
+	cache := &s.upower
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if !cache.updated.IsZero() && time.Since(cache.updated) < UPowerCacheDuration {
+		return cache.devices, cache.ok
+	}
+	cache.updated = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "upower", "--dump").Output()
+	if err != nil {
+		slog.Debug("upower failed, using sysfs", "err", err)
+		cache.devices, cache.ok = nil, false
+		return nil, false
+	}
+
+	cache.devices = parseUPowerDump(string(output))
+	cache.ok = len(cache.devices) > 0
+	return cache.devices, cache.ok
+}
+
+func parseUPowerDump(output string) []upowerDevice {
+	var devices []upowerDevice
+	var current *upowerDevice
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if path, ok := strings.CutPrefix(line, "Device: "); ok {
+			devices = append(devices, upowerDevice{
+				path:       strings.TrimSpace(path),
+				properties: make(map[string]string),
+			})
+			current = &devices[len(devices)-1]
+			continue
+		}
+		if current == nil || trimmed == "" {
+			if !strings.HasPrefix(line, " ") {
+				current = nil // Daemon: block or end of device
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			// A bare word opens the device type section, e.g. "battery" or "mouse"
+			if current.kind == "" {
+				current.kind = trimmed
+			}
+			continue
+		}
+		current.properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return devices
+}
+
+// getUPowerBatteryStats builds the battery stats from UPower's calibrated
+// readings, including batteries of wireless peripherals
+func (s *StatsCollector) getUPowerBatteryStats() (models.BatteryStats, bool) {
+	devices, ok := s.getUPowerDevices()
+	if !ok {
+		return models.BatteryStats{}, false
+	}
+
+	var stats models.BatteryStats
+	found := false
+
+	for _, device := range devices {
+		// The display device is a composite of the real batteries
+		if strings.HasSuffix(device.path, "DisplayDevice") {
+			continue
+		}
+
+		props := device.properties
+		level := parseUPowerPercent(props["percentage"])
+
+		if device.kind == "battery" && props["power supply"] == "yes" {
+			if found {
+				continue
+			}
+			found = true
+
+			state := props["state"]
+			stats = models.BatteryStats{
+				Level:      level,
+				Status:     upowerStateLabel(state),
+				TimeLeft:   "N/A",
+				IsCharging: state == "charging",
+				Health:     parseUPowerPercent(props["capacity"]),
+				Source:     "UPower",
+				Watts:      parseUPowerWatts(props["energy-rate"]),
+			}
+			if timeLeft, ok := props["time to empty"]; ok {
+				stats.TimeLeft = timeLeft
+			} else if timeFull, ok := props["time to full"]; ok {
+				stats.TimeLeft = timeFull + " to full"
+			}
+			if stats.Health == 0 {
+				stats.Health = 100
+			}
+			continue
+		}
+
+		// Everything else with a charge level is a peripheral
+		if _, ok := props["percentage"]; !ok || device.kind == "line-power" {
+			continue
+		}
+		name := props["model"]
+		if name == "" {
+			name = props["native-path"]
+		}
+		stats.Peripherals = append(stats.Peripherals, models.PeripheralBattery{
+			Name:   name,
+			Type:   device.kind,
+			Level:  level,
+			Status: upowerStateLabel(props["state"]),
+		})
+	}
+
+	if !found {
+		// UPower runs but the machine has no system battery
+		if len(stats.Peripherals) == 0 {
+			return models.BatteryStats{}, false
+		}
+		stats.Level = 100
+		stats.Status = "Not Available"
+		stats.TimeLeft = "N/A"
+		stats.Health = 100
+		stats.Source = "UPower"
+	}
+
+	return stats, true
+}
+
+// parseUPowerWatts reads a rate such as "7.123 W"
+func parseUPowerWatts(value string) float64 {
+	watts, _ := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "W")), 64)
+	return watts
+}
+
+func parseUPowerPercent(value string) int {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return int(percent + 0.5)
+}
+
+// upowerStateLabel matches the sysfs status spelling, e.g. "fully-charged" -> "Full"
+func upowerStateLabel(state string) string {
+	switch state {
+	case "":
+		return "Unknown"
+	case "fully-charged":
+		return "Full"
+	case "pending-charge", "pending-discharge":
+		return "Not charging"
+	default:
+		return strings.ToUpper(state[:1]) + state[1:]
+	}
+}
@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"os/user"
+	"sort"
+	"sync"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// UserSortBy represents the sorting options of the users view
+type UserSortBy int
+
+const (
+	UserSortByCPU UserSortBy = iota
+	UserSortByMemory
+	UserSortByProcesses
+	UserSortByIO
+	UserSortByName
+)
+
+func (u UserSortBy) String() string {
+	switch u {
+	case UserSortByMemory:
+		return "Memory"
+	case UserSortByProcesses:
+		return "Processes"
+	case UserSortByIO:
+		return "I/O"
+	case UserSortByName:
+		return "Name"
+	default:
+		return "CPU"
+	}
+}
+
+// usernames caches UID to username lookups, /etc/passwd rarely changes
+var usernames sync.Map
+
+// LookupUsername resolves a UID to its user name, falling back to the UID
+func LookupUsername(uid string) string {
+	if name, ok := usernames.Load(uid); ok {
+		return name.(string)
+	}
+
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	usernames.Store(uid, name)
+	return name
+}
+
+// GetUserStats aggregates the process list per owning UID
+func (s *StatsCollector) GetUserStats(processes models.ProcessList, sortBy UserSortBy, descending bool) []models.UserStats {
+	byUID := make(map[string]*models.UserStats)
+
+	for _, proc := range processes.Processes {
+		stats, ok := byUID[proc.User]
+		if !ok {
+			stats = &models.UserStats{
+				UID:  proc.User,
+				Name: LookupUsername(proc.User),
+			}
+			byUID[proc.User] = stats
+		}
+
+		stats.Processes++
+		stats.CPUPercent += proc.CPUPercent
+		stats.MemPercent += proc.MemPercent
+		stats.MemRSS += proc.MemRSS
+		stats.ReadRate += proc.ReadRate
+		stats.WriteRate += proc.WriteRate
+	}
+
+	users := make([]models.UserStats, 0, len(byUID))
+	for _, stats := range byUID {
+		users = append(users, *stats)
+	}
+
+	sortUserStats(users, sortBy, descending)
+	return users
+}
+
+func sortUserStats(users []models.UserStats, sortBy UserSortBy, descending bool) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case UserSortByMemory:
+			return users[i].MemRSS < users[j].MemRSS
+		case UserSortByProcesses:
+			return users[i].Processes < users[j].Processes
+		case UserSortByIO:
+			return users[i].ReadRate+users[i].WriteRate < users[j].ReadRate+users[j].WriteRate
+		case UserSortByName:
+			return users[i].Name < users[j].Name
+		default:
+			return users[i].CPUPercent < users[j].CPUPercent
+		}
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		if descending {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+}
@@ -0,0 +1,271 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// vmSample holds the cumulative counters of a VM process at the last collection
+type vmSample struct {
+	cpuTicks   uint64
+	vcpuTicks  map[int]uint64
+	readBytes  uint64
+	writeBytes uint64
+	rxBytes    uint64
+	txBytes    uint64
+}
+
+type vmSamples struct {
+	mutex   sync.Mutex
+	samples map[int]vmSample
+	sampled time.Time
+}
+
+// HasVirtualization reports whether this machine can run KVM guests
+func (s *StatsCollector) HasVirtualization() bool {
+	_, err := os.Stat("/dev/kvm")
+	return err == nil
+}
+
+// GetVMStats finds qemu/KVM guest processes and derives per-VM usage from them
+func (s *StatsCollector) GetVMStats() []models.VMStats {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	s.vmSamples.mutex.Lock()
+	defer s.vmSamples.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(s.vmSamples.sampled).Seconds()
+	current := make(map[int]vmSample)
+
+	var vms []models.VMStats
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		args := readCmdline(pid)
+		if len(args) == 0 || !isQemuBinary(args[0]) {
+			continue
+		}
+
+		stat, ok := readProcStatEntry(pid)
+		if !ok {
+			continue
+		}
+
+		vm := parseQemuArgs(args)
+		vm.PID = pid
+		vm.MemRSS = stat.rssPages * uint64(os.Getpagesize()/1024)
+		vm.Interfaces = qemuTapInterfaces(pid)
+
+		sample := vmSample{
+			cpuTicks:  stat.cpuTicks,
+			vcpuTicks: qemuVCPUTicks(pid),
+		}
+		sample.readBytes, sample.writeBytes = readProcIO(pid)
+		for _, iface := range vm.Interfaces {
+			// The tap device sees guest traffic reversed: host tx is guest rx
+			sample.rxBytes += readInterfaceCounter(iface, "tx_bytes")
+			sample.txBytes += readInterfaceCounter(iface, "rx_bytes")
+		}
+		current[pid] = sample
+
+		if prev, ok := s.vmSamples.samples[pid]; ok && elapsed > 0 {
+			vm.CPUPercent = tickRate(prev.cpuTicks, sample.cpuTicks, elapsed)
+			vm.ReadRate = counterRate(prev.readBytes, sample.readBytes, elapsed)
+			vm.WriteRate = counterRate(prev.writeBytes, sample.writeBytes, elapsed)
+			vm.RxRate = counterRate(prev.rxBytes, sample.rxBytes, elapsed)
+			vm.TxRate = counterRate(prev.txBytes, sample.txBytes, elapsed)
+
+			// A vCPU hot-added or named since the last sample has no rate yet
+			vm.VCPUPercent = make(map[int]float64, len(sample.vcpuTicks))
+			for index, ticks := range sample.vcpuTicks {
+				if prevTicks, ok := prev.vcpuTicks[index]; ok {
+					vm.VCPUPercent[index] = tickRate(prevTicks, ticks, elapsed)
+				}
+			}
+		}
+		if vm.VCPUs == 0 {
+			vm.VCPUs = len(sample.vcpuTicks)
+		}
+
+		vms = append(vms, vm)
+	}
+
+	s.vmSamples.samples = current
+	s.vmSamples.sampled = now
+
+	sort.Slice(vms, func(i, j int) bool {
+		return vms[i].Name < vms[j].Name
+	})
+	return vms
+}
+
+func isQemuBinary(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, "qemu-system-") || name == "qemu-kvm"
+}
+
+// parseQemuArgs extracts the guest name, vCPU count and memory size
+func parseQemuArgs(args []string) models.VMStats {
+	vm := models.VMStats{Name: "unnamed"}
+
+	for i := 1; i < len(args)-1; i++ {
+		value := args[i+1]
+		switch args[i] {
+		case "-name":
+			// Either "-name foo" or libvirt's "-name guest=foo,debug-threads=on"
+			name := strings.Split(value, ",")[0]
+			vm.Name = strings.TrimPrefix(name, "guest=")
+		case "-smp":
+			for _, opt := range strings.Split(value, ",") {
+				if cpus, ok := strings.CutPrefix(opt, "cpus="); ok {
+					vm.VCPUs, _ = strconv.Atoi(cpus)
+				} else if n, err := strconv.Atoi(opt); err == nil {
+					vm.VCPUs = n
+				}
+			}
+		case "-m":
+			for _, opt := range strings.Split(value, ",") {
+				opt = strings.TrimPrefix(opt, "size=")
+				if size := parseQemuSize(opt); size > 0 {
+					vm.MemoryAlloc = size
+					break
+				}
+			}
+		}
+	}
+
+	return vm
+}
+
+// parseQemuSize converts a qemu memory size (default unit MB) to KB
+func parseQemuSize(value string) uint64 {
+	multiplier := uint64(1024)
+	switch {
+	case strings.HasSuffix(value, "G"), strings.HasSuffix(value, "g"):
+		multiplier = 1024 * 1024
+		value = value[:len(value)-1]
+	case strings.HasSuffix(value, "M"), strings.HasSuffix(value, "m"):
+		value = value[:len(value)-1]
+	case strings.HasSuffix(value, "K"), strings.HasSuffix(value, "k"):
+		multiplier = 1
+		value = value[:len(value)-1]
+	}
+
+	size, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return size * multiplier
+}
+
+// qemuVCPUTicks returns cpu ticks per vCPU thread, which qemu names "CPU <n>/KVM"
+func qemuVCPUTicks(pid int) map[int]uint64 {
+	ticks := make(map[int]uint64)
+
+	tasks, err := os.ReadDir(fmt.Sprintf("/proc/%d/task", pid))
+	if err != nil {
+		return ticks
+	}
+
+	for _, task := range tasks {
+		comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/task/%s/comm", pid, task.Name()))
+		if err != nil {
+			continue
+		}
+
+		var index int
+		if _, err := fmt.Sscanf(string(comm), "CPU %d/KVM", &index); err != nil {
+			continue
+		}
+
+		content, err := os.ReadFile(fmt.Sprintf("/proc/%d/task/%s/stat", pid, task.Name()))
+		if err != nil {
+			continue
+		}
+		data := string(content)
+		fields := strings.Fields(data[strings.LastIndexByte(data, ')')+1:])
+		if len(fields) < 13 {
+			continue
+		}
+		utime, _ := strconv.ParseUint(fields[11], 10, 64)
+		stime, _ := strconv.ParseUint(fields[12], 10, 64)
+		ticks[index] = utime + stime
+	}
+
+	return ticks
+}
+
+// qemuTapInterfaces finds the tap devices a VM owns through the "iff:" line
+// of its /dev/net/tun file descriptors
+func qemuTapInterfaces(pid int) []string {
+	fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid))
+	if err != nil {
+		return nil
+	}
+
+	var interfaces []string
+	for _, fd := range fds {
+		target, err := os.Readlink(fmt.Sprintf("/proc/%d/fd/%s", pid, fd.Name()))
+		if err != nil || target != "/dev/net/tun" {
+			continue
+		}
+
+		info, err := os.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%s", pid, fd.Name()))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(info), "\n") {
+			if iface, ok := strings.CutPrefix(line, "iff:"); ok {
+				interfaces = append(interfaces, strings.TrimSpace(iface))
+			}
+		}
+	}
+
+	return interfaces
+}
+
+func readCmdline(pid int) []string {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil || len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(content), "\x00"), "\x00")
+}
+
+func readInterfaceCounter(iface, counter string) uint64 {
+	content, err := os.ReadFile(fmt.Sprintf("/sys/class/net/%s/statistics/%s", iface, counter))
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	return value
+}
+
+// tickRate converts a clock tick delta into a percentage of one core. At 100
+// ticks per second, ticks/s and percent are the same number.
+func tickRate(prev, current uint64, elapsed float64) float64 {
+	return counterRate(prev, current, elapsed)
+}
+
+// counterRate returns the per-second increase of a monotonic counter
+func counterRate(prev, current uint64, elapsed float64) float64 {
+	if current < prev || elapsed <= 0 {
+		return 0
+	}
+	return float64(current-prev) / elapsed
+}
@@ -0,0 +1,25 @@
+package collector
+
+import (
+	"os"
+	"strings"
+)
+
+// WSLVersion reports whether croptop runs under the Windows Subsystem for
+// Linux: 2 for the Hyper-V VM running Microsoft's kernel, 1 for the older
+// translation layer, 0 anywhere else. Both name Microsoft in /proc/version,
+// WSL 1 as "-Microsoft" and WSL 2 as "-microsoft-standard".
+func WSLVersion() int {
+	content, err := os.ReadFile("/proc/version")
+	if err != nil {
+		return 0
+	}
+	version := string(content)
+	switch {
+	case strings.Contains(version, "microsoft-standard") || strings.Contains(version, "WSL2"):
+		return 2
+	case strings.Contains(strings.ToLower(version), "microsoft"):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,734 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/caps"
+)
+
+// Config is the user configuration read from config.json
+type Config struct {
+	// Path is the file the config was loaded from
+	Path string `json:"-"`
+	// Interval is the refresh interval while the terminal is focused
+	Interval Duration `json:"interval"`
+	// Alerts holds alert rules such as "cpu.usage > 90 for 60s -> notify"
+	Alerts []string `json:"alerts"`
+	// Watches are metric expressions shown in a footer strip, such as
+	// "pg: proc[name=\"postgres\"].rss_sum"; alert rules take them too
+	Watches []string `json:"watches"`
+	// ProcessHighlight colors heavy rows of the process table
+	ProcessHighlight ProcessHighlight `json:"process_highlight"`
+	// DNSCheck periodically measures lookup latency of each resolver
+	DNSCheck DNSCheck `json:"dns_check"`
+	// Services are connected to over TCP every refresh, to show in the
+	// Network tab whether each is up and how fast it accepts
+	Services []Service `json:"services"`
+	// Unfocused sets how croptop refreshes while the terminal is in the background
+	Unfocused Unfocused `json:"unfocused"`
+	// Log configures the debug log file
+	Log Log `json:"log"`
+	// GraphStyle draws history graphs with "braille" dots or "block"
+	// characters; empty picks by terminal
+	GraphStyle string `json:"graph_style"`
+	// Locale selects the language and number format, e.g. "de"; empty means
+	// the one from LANG
+	Locale string `json:"locale"`
+	// Overview lays out the Overview tab as rows of widgets, placed side by
+	// side within a row
+	Overview [][]string `json:"overview"`
+	// Refresh sets how often each expensive data domain is collected, on a
+	// schedule of its own beside Interval
+	Refresh map[string]Duration `json:"refresh"`
+	// ReadOnly disables every action that changes the system, such as
+	// switching the power profile. Only takes effect on restart, so a
+	// config edit cannot lift it.
+	ReadOnly bool `json:"read_only"`
+	// CaptureTraffic reads the headers of the packets on every interface to
+	// show the top talkers in the Network tab. It needs CAP_NET_RAW and only
+	// takes effect on restart.
+	CaptureTraffic bool `json:"capture_traffic"`
+	// ExportDir is where the Processes tab exports to; empty means the
+	// current directory
+	ExportDir string `json:"export_dir"`
+	// CollapseSMT shows one bar per physical core in the CPU tab, averaging
+	// its hyperthreads, instead of one per CPU; t toggles it
+	CollapseSMT bool `json:"collapse_smt"`
+	// SolarisMode shows process CPU% as a share of the whole machine, like
+	// top's Solaris mode, instead of a share of one core; I toggles it
+	SolarisMode bool `json:"solaris_mode"`
+	// AccurateMemory adds PSS and USS from smaps_rollup to the process
+	// table, which makes every refresh noticeably slower; M toggles it
+	AccurateMemory bool `json:"accurate_memory"`
+	// Tags label the processes matched by their patterns in the process
+	// table, in the tag's color; the first matching tag wins
+	Tags []Tag `json:"tags"`
+	// Pinned processes stay at the top of the process table whatever the
+	// sort: a PID such as "1234" or a name pattern such as "postgres*"
+	Pinned []string `json:"pinned"`
+	// ProcessColumns adds optional columns to the process table, before
+	// COMMAND; O toggles them
+	ProcessColumns []string `json:"process_columns"`
+	// Serve configures the HTTP endpoint of `croptop serve`
+	Serve Serve `json:"serve"`
+	// Export configures the backends `croptop serve` pushes metrics to
+	Export Export `json:"export"`
+	// History keeps the metrics `croptop serve` collects for /api/history
+	History History `json:"history"`
+	// Events sends alerts and detected problems, such as OOM kills, to a
+	// webhook or syslog, from the TUI and from `croptop serve`
+	Events Events `json:"events"`
+	// Hosts are the `croptop serve` agents the Hosts tab summarizes
+	Hosts []Host `json:"hosts"`
+	// DiscoverHosts shows the Hosts tab even without hosts, to add the
+	// agents announcing themselves on the LAN
+	DiscoverHosts bool `json:"discover_hosts"`
+}
+
+// Host is a remote agent. URL is its serve address, such as
+// "http://nas.lan:9101"; Name defaults to the host part of it. Token or
+// Username and Password authenticate to agents that ask for it, and
+// Fingerprint, the SHA-256 of its certificate, trusts an agent with a
+// self-signed one.
+type Host struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Token       string `json:"token,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Serve configures agent mode. Listen is the address of the /metrics scrape
+// endpoint; empty disables the endpoint. Announce advertises the agent on
+// the LAN with mDNS, so the Hosts tab can find it. RateLimit is how many
+// REST requests per second each client address may make, 0 for no limit.
+// AllowedOrigins are the web origins, such as "https://grafana.lan", whose
+// pages may open the WebSocket stream besides the agent's own dashboard.
+type Serve struct {
+	Listen         string    `json:"listen"`
+	Announce       bool      `json:"announce"`
+	TLS            ServeTLS  `json:"tls"`
+	Auth           ServeAuth `json:"auth"`
+	RateLimit      float64   `json:"rate_limit"`
+	AllowedOrigins []string  `json:"allowed_origins"`
+}
+
+// ServeTLS serves HTTPS and gRPC over TLS with the PEM files Cert and Key.
+// SelfSigned creates a self-signed certificate when they do not exist yet,
+// by default in the service's state directory or next to the config file,
+// and reuses it after.
+type ServeTLS struct {
+	Cert       string `json:"cert"`
+	Key        string `json:"key"`
+	SelfSigned bool   `json:"self_signed"`
+}
+
+// Enabled reports whether the agent serves over TLS
+func (t ServeTLS) Enabled() bool {
+	return t.Cert != "" || t.SelfSigned
+}
+
+// ServeAuth makes every endpoint ask for one of Tokens as a bearer token, or
+// for Username and Password with basic auth; either is accepted when both
+// are set. `croptop token` manages the tokens.
+type ServeAuth struct {
+	Tokens   []string `json:"tokens,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+}
+
+// Enabled reports whether clients have to authenticate
+func (a ServeAuth) Enabled() bool {
+	return len(a.Tokens) > 0 || a.Username != ""
+}
+
+// History keeps every alert metric, and the expressions in Metrics such as
+// net["eth0"].rx_rate, for Retention as collected and for longer as the
+// averages of Downsample. Backend is where: "memory" keeps it until the
+// agent stops, "file" in an append-only file and "sqlite" in a SQLite
+// database. Path defaults to history.log or history.db in the service's
+// state directory or next to the config file; each downsampling step gets
+// a file of its own beside it.
+type History struct {
+	Enabled    bool         `json:"enabled"`
+	Backend    string       `json:"backend"`
+	Path       string       `json:"path"`
+	Retention  Duration     `json:"retention"`
+	Downsample []Downsample `json:"downsample"`
+	Metrics    []string     `json:"metrics"`
+}
+
+// Downsample keeps the averages over Step for Retention
+type Downsample struct {
+	Step      Duration `json:"step"`
+	Retention Duration `json:"retention"`
+}
+
+// History backends
+const (
+	HistoryMemory = "memory"
+	HistoryFile   = "file"
+	HistorySQLite = "sqlite"
+)
+
+// Export configures pushing metrics. Labels are added to every series, e.g.
+// {"instance": "nas"}.
+type Export struct {
+	Interval    Duration          `json:"interval"`
+	Labels      map[string]string `json:"labels"`
+	RemoteWrite RemoteWrite       `json:"remote_write"`
+	StatsD      StatsD            `json:"statsd"`
+	Graphite    Graphite          `json:"graphite"`
+}
+
+// StatsD configures sending gauges to a StatsD daemon over UDP. Paths look
+// like <prefix>.cpu_usage_percent, with label values appended.
+type StatsD struct {
+	Address string `json:"address"`
+	Prefix  string `json:"prefix"`
+}
+
+// Graphite configures sending to carbon with the plaintext protocol over
+// TCP. Tagged sends labels as Graphite 1.1 tags instead of path components.
+type Graphite struct {
+	Address string `json:"address"`
+	Prefix  string `json:"prefix"`
+	Tagged  bool   `json:"tagged"`
+}
+
+// RemoteWrite configures pushing to a Prometheus remote_write endpoint such
+// as Grafana Cloud or Mimir. BearerToken takes precedence over basic auth.
+type RemoteWrite struct {
+	URL         string            `json:"url"`
+	Username    string            `json:"username"`
+	Password    string            `json:"password"`
+	BearerToken string            `json:"bearer_token"`
+	Headers     map[string]string `json:"headers"`
+	Timeout     Duration          `json:"timeout"`
+}
+
+// Events configures where events go; either, both or neither may be set
+type Events struct {
+	Webhook Webhook `json:"webhook"`
+	Syslog  Syslog  `json:"syslog"`
+}
+
+// Webhook POSTs every event as JSON to URL
+type Webhook struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Timeout Duration          `json:"timeout"`
+}
+
+// Syslog writes events to the local syslog, or to a remote one when Network
+// ("udp" or "tcp") and Address are set. Tag defaults to croptop.
+type Syslog struct {
+	Enabled bool   `json:"enabled"`
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Tag     string `json:"tag"`
+}
+
+// Log configures the log file. Level is one of debug, info, warn or error; an
+// empty File means the default location.
+type Log struct {
+	Level slog.Level `json:"level"`
+	File  string     `json:"file"`
+}
+
+// Refresh modes while the terminal is unfocused
+const (
+	UnfocusedNormal = "normal" // keep refreshing every Config.Interval
+	UnfocusedSlow   = "slow"   // refresh every Interval
+	UnfocusedPause  = "pause"  // stop collecting until focus returns
+)
+
+// Graph styles
+const (
+	GraphBraille = "braille"
+	GraphBlock   = "block"
+)
+
+// Overview widgets
+const (
+	WidgetCPU         = "cpu"
+	WidgetMemory      = "memory"
+	WidgetLoad        = "load"
+	WidgetNetwork     = "network"
+	WidgetDisk        = "disk"
+	WidgetProcesses   = "processes"
+	WidgetTemperature = "temperature"
+	WidgetBattery     = "battery"
+	WidgetSystem      = "system"
+	WidgetLimits      = "limits"
+	WidgetServices    = "services"
+)
+
+// OverviewWidgets lists the widgets the Overview tab can show
+var OverviewWidgets = []string{
+	WidgetCPU, WidgetMemory, WidgetLoad, WidgetNetwork, WidgetDisk,
+	WidgetProcesses, WidgetTemperature, WidgetBattery, WidgetSystem, WidgetLimits,
+	WidgetServices,
+}
+
+// Optional columns of the process table, rates per second
+const (
+	ColumnVoluntarySwitches   = "voluntary_switches_per_sec"
+	ColumnInvoluntarySwitches = "involuntary_switches_per_sec"
+	ColumnMinorFaults         = "minor_faults_per_sec"
+	ColumnMajorFaults         = "major_faults_per_sec"
+	ColumnRunDelay            = "run_delay_percent"
+	ColumnWaitChannel         = "wchan"
+	ColumnMemoryGrowth        = "rss_growth_kb"
+)
+
+// ProcessColumns lists the optional columns of the process table
+var ProcessColumns = []string{
+	ColumnVoluntarySwitches, ColumnInvoluntarySwitches, ColumnMinorFaults, ColumnMajorFaults,
+	ColumnRunDelay, ColumnWaitChannel, ColumnMemoryGrowth,
+}
+
+// Data domains refreshed apart from the main interval
+const (
+	DomainPods       = "pods"
+	DomainVMs        = "vms"
+	DomainPressure   = "memory_pressure"
+	DomainSecurity   = "security"
+	DomainNeighbors  = "neighbors" // neighbor table and DNS resolvers
+	DomainShm        = "shared_memory"
+	DomainSlab       = "slab"
+	DomainThermal    = "thermal"
+	DomainHosts      = "hosts" // snapshots of the remote agents
+	DomainBoot       = "boot"  // systemd-analyze and the usage of the boot units
+	DomainContainers = "containers"
+	DomainSessions   = "sessions" // logind sessions and compositor load
+	DomainAudio      = "audio"
+)
+
+// RefreshDomains lists the data domains Config.Refresh can set
+var RefreshDomains = []string{DomainPods, DomainVMs, DomainPressure, DomainSecurity, DomainNeighbors, DomainShm, DomainSlab, DomainThermal, DomainHosts, DomainBoot, DomainContainers, DomainSessions, DomainAudio}
+
+// Unfocused configures the refresh backoff. It needs a terminal that reports
+// focus changes (most do, tmux needs "focus-events on").
+type Unfocused struct {
+	Mode     string   `json:"mode"`
+	Interval Duration `json:"interval"`
+}
+
+// DNSCheck configures the resolver latency test of the Network tab
+type DNSCheck struct {
+	Enabled  bool     `json:"enabled"`
+	Interval Duration `json:"interval"`
+	Query    string   `json:"query"`
+}
+
+// Service is a TCP service to check, Address being a host:port such as
+// "localhost:8080"; Name defaults to the address
+type Service struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// Duration is a time.Duration written as a string such as "30s" or "7d" in
+// JSON
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	duration, err := ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// ParseDuration is time.ParseDuration that also takes whole days, such as
+// "7d", for retention periods
+func ParseDuration(text string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	return time.ParseDuration(text)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// ProcessHighlight holds the CPU% (of one core) and MEM% at which process
+// rows turn yellow (warning) or red (critical). A zero threshold is disabled.
+type ProcessHighlight struct {
+	CPUWarning  float64 `json:"cpu_warning"`
+	CPUCritical float64 `json:"cpu_critical"`
+	MemWarning  float64 `json:"mem_warning"`
+	MemCritical float64 `json:"mem_critical"`
+}
+
+// Tag labels processes. Name, User, Cgroup and Command are regular
+// expressions on the process name, user name or UID, cgroup path and
+// command line; a process has to match every one that is set. Color is a terminal color
+// such as "39" or "#ff8800".
+type Tag struct {
+	Label   string `json:"label"`
+	Color   string `json:"color"`
+	Name    string `json:"name"`
+	User    string `json:"user"`
+	Cgroup  string `json:"cgroup"`
+	Command string `json:"command"`
+}
+
+// Default returns the configuration used when no config file exists
+func Default() *Config {
+	return &Config{
+		Interval: Duration(time.Second),
+		Alerts: []string{
+			"cpu.usage > 90 for 60s",
+			"memory.usage_percent > 90 for 30s",
+			"swap.usage_percent > 50 for 60s",
+			"battery.level < 10 as critical -> notify",
+		},
+		ProcessHighlight: ProcessHighlight{
+			CPUWarning:  50,
+			CPUCritical: 90,
+			MemWarning:  10,
+			MemCritical: 25,
+		},
+		Unfocused: Unfocused{
+			Mode:     UnfocusedSlow,
+			Interval: Duration(5 * time.Second),
+		},
+		Log: Log{
+			Level: slog.LevelInfo,
+		},
+		DNSCheck: DNSCheck{
+			Interval: Duration(30 * time.Second),
+			Query:    "example.com",
+		},
+		Refresh: map[string]Duration{
+			DomainPods:       Duration(5 * time.Second),
+			DomainVMs:        Duration(5 * time.Second),
+			DomainPressure:   Duration(2 * time.Second),
+			DomainSecurity:   Duration(10 * time.Second),
+			DomainNeighbors:  Duration(5 * time.Second),
+			DomainShm:        Duration(10 * time.Second),
+			DomainSlab:       Duration(5 * time.Second),
+			DomainThermal:    Duration(5 * time.Second),
+			DomainHosts:      Duration(5 * time.Second),
+			DomainBoot:       Duration(5 * time.Second),
+			DomainContainers: Duration(2 * time.Second),
+			DomainSessions:   Duration(2 * time.Second),
+			DomainAudio:      Duration(2 * time.Second),
+		},
+		Overview: [][]string{
+			{WidgetCPU},
+			{WidgetMemory},
+			{WidgetLoad, WidgetTemperature, WidgetBattery},
+			{WidgetNetwork, WidgetDisk},
+			{WidgetProcesses, WidgetSystem, WidgetLimits},
+		},
+		Serve: Serve{
+			Listen:    "127.0.0.1:9101",
+			RateLimit: 5,
+		},
+		History: History{
+			Backend:   HistoryMemory,
+			Retention: Duration(time.Hour),
+			Downsample: []Downsample{
+				{Step: Duration(time.Minute), Retention: Duration(7 * 24 * time.Hour)},
+			},
+		},
+		Export: Export{
+			Interval: Duration(15 * time.Second),
+			RemoteWrite: RemoteWrite{
+				Timeout: Duration(10 * time.Second),
+			},
+			StatsD: StatsD{
+				Prefix: "croptop",
+			},
+			Graphite: Graphite{
+				Prefix: "croptop",
+			},
+		},
+		Events: Events{
+			Webhook: Webhook{
+				Timeout: Duration(10 * time.Second),
+			},
+			Syslog: Syslog{
+				Tag: "croptop",
+			},
+		},
+	}
+}
+
+// MinInterval is the fastest refresh interval accepted
+const MinInterval = 100 * time.Millisecond
+
+// DefaultPath returns $XDG_CONFIG_HOME/croptop/config.json
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "config.json"
+	}
+	return filepath.Join(dir, "croptop", "config.json")
+}
+
+// Load reads the config file at path. A missing file yields the defaults;
+// settings absent from the file keep their default values.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	cfg.Path = path
+
+	data, err := caps.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if time.Duration(cfg.Interval) < MinInterval {
+		return nil, fmt.Errorf("%s: interval %s is below the minimum of %s", path, time.Duration(cfg.Interval), MinInterval)
+	}
+
+	for domain, interval := range cfg.Refresh {
+		if !slices.Contains(RefreshDomains, domain) {
+			return nil, fmt.Errorf("%s: unknown refresh domain %q (want one of %s)",
+				path, domain, strings.Join(RefreshDomains, ", "))
+		}
+		if time.Duration(interval) < MinInterval {
+			return nil, fmt.Errorf("%s: %s refresh %s is below the minimum of %s", path, domain, time.Duration(interval), MinInterval)
+		}
+	}
+
+	switch cfg.GraphStyle {
+	case "", GraphBraille, GraphBlock:
+	default:
+		return nil, fmt.Errorf("%s: unknown graph_style %q (want %s or %s)", path, cfg.GraphStyle, GraphBraille, GraphBlock)
+	}
+
+	for _, row := range cfg.Overview {
+		for _, widget := range row {
+			if !slices.Contains(OverviewWidgets, widget) {
+				return nil, fmt.Errorf("%s: unknown overview widget %q (want one of %s)",
+					path, widget, strings.Join(OverviewWidgets, ", "))
+			}
+		}
+	}
+
+	for _, column := range cfg.ProcessColumns {
+		if !slices.Contains(ProcessColumns, column) {
+			return nil, fmt.Errorf("%s: unknown process column %q (want one of %s)",
+				path, column, strings.Join(ProcessColumns, ", "))
+		}
+	}
+
+	for i, host := range cfg.Hosts {
+		u, err := url.Parse(host.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%s: host URL %q is not an http:// or https:// address", path, host.URL)
+		}
+		if host.Name == "" {
+			cfg.Hosts[i].Name = u.Hostname()
+		}
+	}
+
+	for i, service := range cfg.Services {
+		_, port, err := net.SplitHostPort(service.Address)
+		if number, _ := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+			return nil, fmt.Errorf("%s: service address %q is not a host:port", path, service.Address)
+		}
+		if service.Name == "" {
+			cfg.Services[i].Name = service.Address
+		}
+	}
+
+	if (cfg.Serve.TLS.Cert == "") != (cfg.Serve.TLS.Key == "") {
+		return nil, fmt.Errorf("%s: serve tls needs both cert and key", path)
+	}
+	if (cfg.Serve.Auth.Username == "") != (cfg.Serve.Auth.Password == "") {
+		return nil, fmt.Errorf("%s: serve auth needs both username and password", path)
+	}
+	if cfg.Serve.RateLimit < 0 {
+		return nil, fmt.Errorf("%s: serve rate_limit %g is negative", path, cfg.Serve.RateLimit)
+	}
+
+	switch cfg.History.Backend {
+	case HistoryMemory, HistoryFile, HistorySQLite:
+	default:
+		return nil, fmt.Errorf("%s: unknown history backend %q (want %s, %s or %s)",
+			path, cfg.History.Backend, HistoryMemory, HistoryFile, HistorySQLite)
+	}
+	if cfg.History.Enabled && time.Duration(cfg.History.Retention) < time.Minute {
+		return nil, fmt.Errorf("%s: history retention %s is below the minimum of 1m", path, time.Duration(cfg.History.Retention))
+	}
+	// Each step coarser and kept longer than the one before
+	previous := Downsample{Step: cfg.Interval, Retention: cfg.History.Retention}
+	for _, tier := range cfg.History.Downsample {
+		step, retention := time.Duration(tier.Step), time.Duration(tier.Retention)
+		if step <= time.Duration(previous.Step) || retention <= time.Duration(previous.Retention) {
+			return nil, fmt.Errorf("%s: history downsample step %s for %s must be coarser and kept longer than %s for %s",
+				path, step, retention, time.Duration(previous.Step), time.Duration(previous.Retention))
+		}
+		previous = tier
+	}
+
+	if time.Duration(cfg.Export.Interval) < time.Second {
+		return nil, fmt.Errorf("%s: export interval %s is below the minimum of 1s", path, time.Duration(cfg.Export.Interval))
+	}
+
+	switch cfg.Events.Syslog.Network {
+	case "", "udp", "tcp":
+	default:
+		return nil, fmt.Errorf("%s: unknown syslog network %q (want udp or tcp)", path, cfg.Events.Syslog.Network)
+	}
+
+	switch cfg.Unfocused.Mode {
+	case UnfocusedNormal, UnfocusedSlow, UnfocusedPause:
+	default:
+		return nil, fmt.Errorf("%s: unknown unfocused mode %q (want %s, %s or %s)",
+			path, cfg.Unfocused.Mode, UnfocusedNormal, UnfocusedSlow, UnfocusedPause)
+	}
+	return cfg, nil
+}
+
+// SavePinned writes the pinned processes to the config file at path, keeping
+// every other setting in it as it is, and creates the file if missing. It
+// returns what it wrote, so a watcher of the file can tell the write apart
+// from an edit.
+func SavePinned(path string, pinned []string) ([]byte, error) {
+	if pinned == nil {
+		pinned = []string{}
+	}
+	return updateSettings(path, func(settings map[string]json.RawMessage) (err error) {
+		settings["pinned"], err = json.Marshal(pinned)
+		return err
+	})
+}
+
+// SaveHosts writes the hosts of the Hosts tab to the config file at path,
+// like SavePinned
+func SaveHosts(path string, hosts []Host) ([]byte, error) {
+	if hosts == nil {
+		hosts = []Host{}
+	}
+	return updateSettings(path, func(settings map[string]json.RawMessage) (err error) {
+		settings["hosts"], err = json.Marshal(hosts)
+		return err
+	})
+}
+
+// SaveServeAuth writes the credentials of `croptop serve` to the config
+// file at path, keeping the other serve settings, like SavePinned
+func SaveServeAuth(path string, auth ServeAuth) ([]byte, error) {
+	return updateSettings(path, func(settings map[string]json.RawMessage) error {
+		serve := map[string]json.RawMessage{}
+		if data, ok := settings["serve"]; ok {
+			if err := json.Unmarshal(data, &serve); err != nil {
+				return fmt.Errorf("%s: serve: %w", path, err)
+			}
+		}
+		var err error
+		if serve["auth"], err = json.Marshal(auth); err != nil {
+			return err
+		}
+		settings["serve"], err = json.Marshal(serve)
+		return err
+	})
+}
+
+// holdsCredentials reports whether settings hold tokens or a password of
+// `croptop serve`
+func holdsCredentials(settings map[string]json.RawMessage) bool {
+	var serve struct {
+		Auth ServeAuth `json:"auth"`
+	}
+	if data, ok := settings["serve"]; ok {
+		json.Unmarshal(data, &serve)
+	}
+	return len(serve.Auth.Tokens) > 0 || serve.Auth.Password != ""
+}
+
+// updateSettings lets update change the top-level settings of the config
+// file at path and writes the file back
+func updateSettings(path string, update func(settings map[string]json.RawMessage) error) ([]byte, error) {
+	settings := map[string]json.RawMessage{}
+	data, err := caps.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	if err := update(settings); err != nil {
+		return nil, err
+	}
+	// Unescaped, alert rules keep their > as typed
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(settings); err != nil {
+		return nil, err
+	}
+	data = buf.Bytes()
+
+	// Written next to the file and renamed over it, so a reader never sees
+	// half of it
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	// A file holding the serve credentials stays 0600, as created; others
+	// keep their permissions
+	if info, err := os.Stat(path); err == nil {
+		if holdsCredentials(settings) {
+			if info.Mode().Perm()&0o077 != 0 {
+				slog.Warn("the config file holds serve credentials and was readable by other users, it is now 0600",
+					"path", path, "was", info.Mode().Perm())
+			}
+		} else {
+			os.Chmod(tmp.Name(), info.Mode().Perm())
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
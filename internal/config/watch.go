@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// settleDelay lets the burst of events of a single save pass before reloading
+const settleDelay = 100 * time.Millisecond
+
+// Watcher reports changes to the config file. Editors often save by writing a
+// new file and renaming it over the old one, so the whole directory is watched.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	path    string
+}
+
+// NewWatcher watches the config file at path. The directory has to exist.
+func NewWatcher(path string) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return &Watcher{watcher: watcher, path: filepath.Clean(path)}, nil
+}
+
+// Wait blocks until the config file was written or replaced
+func (w *Watcher) Wait() error {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return fsnotify.ErrClosed
+			}
+			if filepath.Clean(event.Name) != w.path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			w.drain()
+			return nil
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return fsnotify.ErrClosed
+			}
+			return err
+		}
+	}
+}
+
+// drain discards the events that follow within settleDelay
+func (w *Watcher) drain() {
+	timer := time.NewTimer(settleDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.watcher.Events:
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
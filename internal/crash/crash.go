@@ -0,0 +1,118 @@
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	mu      sync.Mutex
+	handler func(value any, stack []byte)
+)
+
+// SetHandler installs the function that reports a recovered panic. It is
+// expected not to return, e.g. by exiting the process.
+func SetHandler(h func(value any, stack []byte)) {
+	mu.Lock()
+	defer mu.Unlock()
+	handler = h
+}
+
+// Recover hands a panic to the installed handler. It has to be deferred at the
+// top of every goroutine, as a panic cannot be recovered from another one.
+// Without a handler the panic continues as usual.
+func Recover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	mu.Lock()
+	h := handler
+	mu.Unlock()
+	if h == nil {
+		panic(r)
+	}
+	h(r, debug.Stack())
+}
+
+// Model wraps a Bubble Tea model so that its commands, which Bubble Tea runs
+// in goroutines of its own, are covered by Recover
+type Model struct {
+	tea.Model
+}
+
+func (m Model) Init() tea.Cmd {
+	return guard(m.Model.Init())
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.Model.Update(msg)
+	return Model{model}, guard(cmd)
+}
+
+func guard(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		defer Recover()
+		msg := cmd()
+		// Batched commands are run separately once this one returns
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i := range batch {
+				batch[i] = guard(batch[i])
+			}
+		}
+		return msg
+	}
+}
+
+// DefaultDir returns the directory crash reports are written to
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(dir, "croptop")
+}
+
+// WriteReport writes the panic, its stack trace and the last collected
+// snapshot to a new file in dir and returns its path
+func WriteReport(dir string, value any, stack []byte, snapshot any) (string, error) {
+	now := time.Now()
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "croptop crash report\n\n")
+	fmt.Fprintf(&report, "time:    %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&report, "go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&report, "command: %s\n\n", strings.Join(os.Args, " "))
+	fmt.Fprintf(&report, "panic: %v\n\n%s\n", value, stack)
+
+	if snapshot != nil {
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			fmt.Fprintf(&report, "snapshot unavailable: %v\n", err)
+		} else {
+			fmt.Fprintf(&report, "last snapshot:\n%s\n", data)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "crash-"+now.Format("20060102-150405")+".log")
+	if err := os.WriteFile(path, []byte(report.String()), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
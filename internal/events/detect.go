@@ -0,0 +1,119 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/alert"
+	"github.com/prabalesh/croptop/internal/models"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// FromAlert describes an alert firing or clearing
+func FromAlert(event alert.Event) schema.Event {
+	rule := event.Alert.Rule
+	details := map[string]any{
+		"rule":      rule.Source,
+		"metric":    rule.Metric,
+		"value":     event.Alert.Value,
+		"threshold": rule.Threshold,
+	}
+	if event.Type == alert.EventCleared {
+		details["peak"] = event.Alert.Peak
+		details["duration_seconds"] = event.Time.Sub(event.Alert.Since).Seconds()
+		return schema.Event{
+			Time:     event.Time,
+			Type:     schema.EventAlertCleared,
+			Severity: schema.SeverityInfo,
+			Message:  fmt.Sprintf("%s cleared (now %.1f, peak %.1f)", rule.Source, event.Alert.Value, event.Alert.Peak),
+			Details:  details,
+		}
+	}
+	return schema.Event{
+		Time:     event.Time,
+		Type:     schema.EventAlertFired,
+		Severity: rule.Severity.String(),
+		Message:  fmt.Sprintf("%s (now %.1f)", rule.Source, event.Alert.Value),
+		Details:  details,
+	}
+}
+
+// Detector turns changes between samples into events: OOM kills,
+// filesystems that stall or recover and commands starting to crash loop.
+// The first sample only sets the baseline, so problems from before croptop
+// started are not reported.
+type Detector struct {
+	started    bool
+	oomKills   uint64
+	stalled    map[string]bool
+	crashLoops map[string]bool
+}
+
+// Check compares a sample with the previous one. oomKills is the kernel's
+// OOM kill counter since boot.
+func (d *Detector) Check(now time.Time, stats models.SystemStats, processes models.ProcessList, oomKills uint64) []schema.Event {
+	stalled := make(map[string]bool)
+	for _, disk := range stats.Disk {
+		if disk.Stalled {
+			stalled[disk.Mountpoint] = true
+		}
+	}
+	crashLoops := make(map[string]bool)
+	for _, loop := range processes.CrashLoops {
+		crashLoops[loop.Name+"\x00"+loop.Command] = true
+	}
+
+	var events []schema.Event
+	if d.started {
+		if oomKills > d.oomKills {
+			events = append(events, schema.Event{
+				Time:     now,
+				Type:     schema.EventOOMKill,
+				Severity: schema.SeverityCritical,
+				Message:  fmt.Sprintf("the OOM killer killed %d process(es)", oomKills-d.oomKills),
+				Details:  map[string]any{"kills": oomKills - d.oomKills, "kills_since_boot": oomKills},
+			})
+		}
+		for _, disk := range stats.Disk {
+			switch {
+			case disk.Stalled && !d.stalled[disk.Mountpoint]:
+				events = append(events, schema.Event{
+					Time:     now,
+					Type:     schema.EventDiskStalled,
+					Severity: schema.SeverityCritical,
+					Message:  fmt.Sprintf("%s stopped responding", disk.Mountpoint),
+					Details:  map[string]any{"mountpoint": disk.Mountpoint, "device": disk.Device, "filesystem": disk.Filesystem},
+				})
+			case !disk.Stalled && d.stalled[disk.Mountpoint]:
+				events = append(events, schema.Event{
+					Time:     now,
+					Type:     schema.EventDiskRecovered,
+					Severity: schema.SeverityInfo,
+					Message:  fmt.Sprintf("%s responds again", disk.Mountpoint),
+					Details:  map[string]any{"mountpoint": disk.Mountpoint, "device": disk.Device, "filesystem": disk.Filesystem},
+				})
+			}
+		}
+		for _, loop := range processes.CrashLoops {
+			if d.crashLoops[loop.Name+"\x00"+loop.Command] {
+				continue
+			}
+			events = append(events, schema.Event{
+				Time:     now,
+				Type:     schema.EventCrashLoop,
+				Severity: schema.SeverityWarning,
+				Message:  fmt.Sprintf("%s keeps failing, restarted %d times", loop.Name, loop.Restarts),
+				Details: map[string]any{
+					"name": loop.Name, "command": loop.Command, "restarts": loop.Restarts,
+					"exit_code": loop.ExitCode, "signal": loop.Signal,
+				},
+			})
+		}
+	}
+
+	d.started = true
+	d.oomKills = oomKills
+	d.stalled = stalled
+	d.crashLoops = crashLoops
+	return events
+}
@@ -0,0 +1,97 @@
+// Package events sends what croptop notices, alerts firing and clearing and
+// problems such as OOM kills or crash loops, to a webhook or syslog, so they
+// reach a central place even when nobody watches the TUI.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// Sink delivers events to a backend
+type Sink interface {
+	// Name identifies the backend in logs
+	Name() string
+	Send(ctx context.Context, event schema.Event) error
+}
+
+// queueSize is how many events wait for slow sinks before new ones are
+// dropped
+const queueSize = 64
+
+// sendTimeout bounds the delivery of one event to one sink
+const sendTimeout = 15 * time.Second
+
+// Emitter sends events to the configured sinks in the background, so a slow
+// webhook does not hold up collection
+type Emitter struct {
+	sinks []Sink
+	host  string
+	queue chan schema.Event
+	done  chan struct{}
+}
+
+// New returns an emitter for the sinks enabled in the config. Without any,
+// Emit does nothing.
+func New(cfg config.Events) *Emitter {
+	var sinks []Sink
+	if cfg.Webhook.URL != "" {
+		sinks = append(sinks, NewWebhook(cfg.Webhook))
+	}
+	if cfg.Syslog.Enabled {
+		sinks = append(sinks, NewSyslog(cfg.Syslog))
+	}
+
+	host, _ := os.Hostname()
+	e := &Emitter{sinks: sinks, host: host, queue: make(chan schema.Event, queueSize), done: make(chan struct{})}
+	go e.run()
+	return e
+}
+
+// Enabled reports whether any sink is configured
+func (e *Emitter) Enabled() bool {
+	return len(e.sinks) > 0
+}
+
+// Emit queues an event for the sinks
+func (e *Emitter) Emit(event schema.Event) {
+	if !e.Enabled() {
+		return
+	}
+	event.SchemaVersion = schema.Version
+	event.Host = e.host
+	select {
+	case e.queue <- event:
+	default:
+		slog.Warn("event queue full, dropping event", "type", event.Type, "message", event.Message)
+	}
+}
+
+// Close delivers the queued events, waiting at most timeout, and stops the
+// emitter
+func (e *Emitter) Close(timeout time.Duration) {
+	close(e.queue)
+	select {
+	case <-e.done:
+	case <-time.After(timeout):
+		slog.Warn("events not delivered before exit", "queued", len(e.queue))
+	}
+}
+
+func (e *Emitter) run() {
+	defer close(e.done)
+	for event := range e.queue {
+		for _, sink := range e.sinks {
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			if err := sink.Send(ctx, event); err != nil {
+				slog.Warn("sending event failed", "sink", sink.Name(), "type", event.Type, "err", err)
+			}
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// Syslog writes each event as one line, "<type>: <message> key=value...",
+// at the priority of its severity
+type Syslog struct {
+	cfg config.Syslog
+
+	mutex  sync.Mutex
+	writer *syslog.Writer
+}
+
+func NewSyslog(cfg config.Syslog) *Syslog {
+	return &Syslog{cfg: cfg}
+}
+
+func (s *Syslog) Name() string {
+	return "syslog"
+}
+
+func (s *Syslog) Send(ctx context.Context, event schema.Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// Connected on first use, syslog may start after croptop serve; the
+	// writer reconnects by itself after that
+	if s.writer == nil {
+		writer, err := syslog.Dial(s.cfg.Network, s.cfg.Address, syslog.LOG_DAEMON|syslog.LOG_INFO, s.cfg.Tag)
+		if err != nil {
+			return err
+		}
+		s.writer = writer
+	}
+
+	line := syslogLine(event)
+	switch event.Severity {
+	case schema.SeverityCritical:
+		return s.writer.Crit(line)
+	case schema.SeverityWarning:
+		return s.writer.Warning(line)
+	}
+	return s.writer.Info(line)
+}
+
+func syslogLine(event schema.Event) string {
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s: %s", event.Type, event.Message)
+	keys := make([]string, 0, len(event.Details))
+	for key := range event.Details {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := fmt.Sprint(event.Details[key])
+		if value == "" || strings.ContainsAny(value, " \"=") {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&line, " %s=%s", key, value)
+	}
+	return line.String()
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// Webhook POSTs each event as a schema.Event JSON document
+type Webhook struct {
+	cfg    config.Webhook
+	client *http.Client
+}
+
+func NewWebhook(cfg config.Webhook) *Webhook {
+	return &Webhook{cfg: cfg, client: &http.Client{Timeout: time.Duration(cfg.Timeout)}}
+}
+
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+func (w *Webhook) Send(ctx context.Context, event schema.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "croptop")
+	for name, value := range w.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s: %s", w.cfg.URL, resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// Exporter sends samples to a monitoring backend
+type Exporter interface {
+	// Name identifies the backend in logs
+	Name() string
+	// Export sends the samples taken at t
+	Export(ctx context.Context, t time.Time, samples []Sample) error
+}
+
+// New returns the exporters enabled in the config
+func New(cfg config.Export) []Exporter {
+	var exporters []Exporter
+	if cfg.RemoteWrite.URL != "" {
+		exporters = append(exporters, NewRemoteWrite(cfg.RemoteWrite))
+	}
+	if cfg.StatsD.Address != "" {
+		exporters = append(exporters, NewStatsD(cfg.StatsD))
+	}
+	if cfg.Graphite.Address != "" {
+		exporters = append(exporters, NewGraphite(cfg.Graphite))
+	}
+	return exporters
+}
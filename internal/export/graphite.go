@@ -0,0 +1,83 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// Graphite sends samples to carbon with the plaintext protocol, one
+// "path value timestamp" line per series over TCP
+type Graphite struct {
+	cfg config.Graphite
+}
+
+func NewGraphite(cfg config.Graphite) *Graphite {
+	return &Graphite{cfg: cfg}
+}
+
+func (g *Graphite) Name() string {
+	return "graphite"
+}
+
+func (g *Graphite) Export(ctx context.Context, t time.Time, samples []Sample) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", g.cfg.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	out := bufio.NewWriter(conn)
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	for _, sample := range samples {
+		path := metricPath(g.cfg.Prefix, sample, !g.cfg.Tagged)
+		if g.cfg.Tagged {
+			// Graphite 1.1 tags: name;tag=value
+			for _, label := range sample.Labels {
+				path += ";" + label.Name + "=" + sanitizePathPart(label.Value)
+			}
+		}
+		out.WriteString(path + " " + strconv.FormatFloat(sample.Value, 'f', -1, 64) + " " + timestamp + "\n")
+	}
+	return out.Flush()
+}
+
+// metricPath builds a dotted path such as "croptop.network_receive_bytes_total.eth0"
+// from the prefix, the metric name without its croptop_ prefix and, unless
+// tags carry them, the label values
+func metricPath(prefix string, sample Sample, withLabels bool) string {
+	parts := []string{strings.TrimPrefix(sample.Name, "croptop_")}
+	if prefix != "" {
+		parts = append([]string{prefix}, parts...)
+	}
+	if withLabels {
+		for _, label := range sample.Labels {
+			parts = append(parts, sanitizePathPart(label.Value))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// sanitizePathPart makes a label value safe as one component of a dotted
+// path: "/home" becomes "_home"
+func sanitizePathPart(value string) string {
+	if value == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		}
+		return '_'
+	}, value)
+}
@@ -0,0 +1,126 @@
+// Package export turns collected stats into metric samples and sends them
+// to monitoring backends.
+package export
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Metric types, as in the Prometheus exposition format
+const (
+	Gauge   = "gauge"
+	Counter = "counter"
+)
+
+// Label is a name/value pair identifying a series of a metric
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is the current value of one series
+type Sample struct {
+	Name   string
+	Help   string
+	Type   string
+	Labels []Label
+	Value  float64
+}
+
+// kbToBytes converts the kilobyte figures of MemoryStats
+const kbToBytes = 1024
+
+// Samples converts a stats sample into metric series. Names follow the
+// Prometheus conventions: base units, _total for counters.
+func Samples(stats models.SystemStats, processes models.ProcessList) []Sample {
+	var samples []Sample
+	add := func(name, kind, help string, value float64, labels ...Label) {
+		samples = append(samples, Sample{Name: name, Help: help, Type: kind, Labels: labels, Value: value})
+	}
+
+	add("croptop_cpu_usage_percent", Gauge, "CPU usage of all cores", stats.CPU.Usage)
+	for i, usage := range stats.CPU.Cores {
+		add("croptop_cpu_core_usage_percent", Gauge, "CPU usage per core", usage, Label{"core", strconv.Itoa(stats.CPU.CoreID(i))})
+	}
+	add("croptop_cpu_frequency_mhz", Gauge, "Average CPU frequency", stats.CPU.Frequency)
+	if stats.CPU.Temp > 0 {
+		add("croptop_cpu_temperature_celsius", Gauge, "CPU temperature", float64(stats.CPU.Temp))
+	}
+	if len(stats.CPU.Power.Domains) > 0 {
+		add("croptop_cpu_package_power_watts", Gauge, "CPU package power draw (RAPL)", stats.CPU.Power.PackageWatts)
+	}
+	for i, period := range []string{"1", "5", "15"} {
+		add("croptop_load"+period, Gauge, period+" minute load average", stats.CPU.Load[i])
+	}
+
+	memory := stats.Memory
+	add("croptop_memory_total_bytes", Gauge, "Physical memory", memory.Total*kbToBytes)
+	add("croptop_memory_used_bytes", Gauge, "Memory in use", memory.Used*kbToBytes)
+	add("croptop_memory_available_bytes", Gauge, "Memory available without swapping", memory.Available*kbToBytes)
+	add("croptop_swap_total_bytes", Gauge, "Swap space", memory.SwapTotal*kbToBytes)
+	add("croptop_swap_used_bytes", Gauge, "Swap space in use", memory.SwapUsed*kbToBytes)
+
+	for _, iface := range stats.Network.Interfaces {
+		name := Label{"interface", iface.Name}
+		add("croptop_network_receive_bytes_total", Counter, "Bytes received per interface", float64(iface.RxBytes), name)
+		add("croptop_network_transmit_bytes_total", Counter, "Bytes sent per interface", float64(iface.TxBytes), name)
+		add("croptop_network_receive_packets_total", Counter, "Packets received per interface", float64(iface.RxPackets), name)
+		add("croptop_network_transmit_packets_total", Counter, "Packets sent per interface", float64(iface.TxPackets), name)
+	}
+	protocol := stats.Network.Protocol
+	add("croptop_tcp_retransmitted_segments_total", Counter, "TCP segments retransmitted", float64(protocol.TCPRetransSegs.Total))
+	add("croptop_tcp_listen_overflows_total", Counter, "Connections dropped by a full listen queue", float64(protocol.ListenOverflows.Total))
+	add("croptop_udp_receive_buffer_errors_total", Counter, "UDP datagrams dropped by a full receive buffer", float64(protocol.UDPRcvbufErrors.Total))
+
+	for _, disk := range stats.Disk {
+		labels := []Label{{"device", disk.Device}, {"fstype", disk.Filesystem}, {"mountpoint", disk.Mountpoint}}
+		add("croptop_filesystem_size_bytes", Gauge, "Filesystem size", float64(disk.Total), labels...)
+		add("croptop_filesystem_used_bytes", Gauge, "Filesystem space in use", float64(disk.Used), labels...)
+		add("croptop_filesystem_free_bytes", Gauge, "Filesystem space free", float64(disk.Free), labels...)
+	}
+
+	if stats.Battery.Status != "Not Available" {
+		add("croptop_battery_level_percent", Gauge, "Battery charge", float64(stats.Battery.Level))
+		charging := 0.0
+		if stats.Battery.IsCharging {
+			charging = 1
+		}
+		add("croptop_battery_charging", Gauge, "1 while the battery charges", charging)
+	}
+
+	if cgroup := stats.Cgroup; cgroup.CPULimit > 0 {
+		add("croptop_cgroup_cpu_usage_percent", Gauge, "CPU usage relative to the cgroup limit", cgroup.CPUUsage)
+	}
+	if cgroup := stats.Cgroup; cgroup.MemoryLimit > 0 {
+		add("croptop_cgroup_memory_usage_percent", Gauge, "Memory usage relative to the cgroup limit", cgroup.MemoryPercent)
+	}
+
+	for _, state := range []struct {
+		name  string
+		count int
+	}{{"running", processes.Running}, {"sleeping", processes.Sleeping}, {"zombie", processes.Zombie}} {
+		add("croptop_processes", Gauge, "Processes by state", float64(state.count), Label{"state", state.name})
+	}
+	add("croptop_uptime_seconds", Gauge, "Time since boot", stats.Uptime.Seconds())
+
+	return samples
+}
+
+// WithLabels returns the samples with extra labels, such as the instance
+// name, added to every series. Labels of a series are kept sorted by name.
+func WithLabels(samples []Sample, extra []Label) []Sample {
+	if len(extra) == 0 {
+		return samples
+	}
+	labeled := make([]Sample, len(samples))
+	for i, sample := range samples {
+		labels := append(append([]Label(nil), sample.Labels...), extra...)
+		sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+		sample.Labels = labels
+		labeled[i] = sample
+	}
+	return labeled
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// labelEscaper escapes label values for the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// WriteText writes the samples in the Prometheus text exposition format.
+// Series of a metric must be adjacent, as Samples returns them.
+func WriteText(w io.Writer, samples []Sample) error {
+	out := bufio.NewWriter(w)
+	previous := ""
+	for _, sample := range samples {
+		if sample.Name != previous {
+			out.WriteString("# HELP " + sample.Name + " " + sample.Help + "\n")
+			out.WriteString("# TYPE " + sample.Name + " " + sample.Type + "\n")
+			previous = sample.Name
+		}
+
+		out.WriteString(sample.Name)
+		if len(sample.Labels) > 0 {
+			out.WriteByte('{')
+			for i, label := range sample.Labels {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				out.WriteString(label.Name + `="` + labelEscaper.Replace(label.Value) + `"`)
+			}
+			out.WriteByte('}')
+		}
+		out.WriteString(" " + strconv.FormatFloat(sample.Value, 'g', -1, 64) + "\n")
+	}
+	return out.Flush()
+}
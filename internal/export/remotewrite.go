@@ -0,0 +1,108 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/pb"
+)
+
+// RemoteWrite pushes samples with the Prometheus remote_write protocol
+// (version 1: a snappy compressed protobuf WriteRequest), for machines
+// behind NAT that cannot be scraped
+type RemoteWrite struct {
+	cfg    config.RemoteWrite
+	client *http.Client
+}
+
+func NewRemoteWrite(cfg config.RemoteWrite) *RemoteWrite {
+	return &RemoteWrite{cfg: cfg, client: &http.Client{Timeout: time.Duration(cfg.Timeout)}}
+}
+
+func (r *RemoteWrite) Name() string {
+	return "remote_write"
+}
+
+func (r *RemoteWrite) Export(ctx context.Context, t time.Time, samples []Sample) error {
+	body := snappyEncode(encodeWriteRequest(t, samples))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "croptop")
+	switch {
+	case r.cfg.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+r.cfg.BearerToken)
+	case r.cfg.Username != "":
+		req.SetBasicAuth(r.cfg.Username, r.cfg.Password)
+	}
+	for name, value := range r.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s: %s", r.cfg.URL, resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// encodeWriteRequest encodes the samples as a prometheus.WriteRequest:
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label        { string name = 1; string value = 2; }
+//	message Sample       { double value = 1; int64 timestamp = 2; }
+//
+// The metric name becomes the __name__ label, which sorts before the others.
+func encodeWriteRequest(t time.Time, samples []Sample) []byte {
+	var request pb.Buffer
+	for _, sample := range samples {
+		request.Message(1, func(series *pb.Buffer) {
+			labels := append([]Label{{"__name__", sample.Name}}, sample.Labels...)
+			for _, label := range labels {
+				series.Message(1, func(m *pb.Buffer) {
+					m.String(1, label.Name)
+					m.String(2, label.Value)
+				})
+			}
+			series.Message(2, func(m *pb.Buffer) {
+				m.Double(1, sample.Value)
+				m.Int64(2, t.UnixMilli())
+			})
+		})
+	}
+	return request.Bytes()
+}
+
+// snappyChunk is the longest literal written by snappyEncode
+const snappyChunk = 1 << 16
+
+// snappyEncode frames data in the snappy block format without compressing
+// it, as a sequence of literals. Remote write requires snappy, and a few
+// kilobytes per push do not justify a compression library.
+func snappyEncode(data []byte) []byte {
+	out := binary.AppendUvarint(make([]byte, 0, len(data)+len(data)/snappyChunk*3+8), uint64(len(data)))
+	for len(data) > 0 {
+		n := min(len(data), snappyChunk)
+		// Tag 61<<2: literal whose length-1 follows in two bytes
+		out = append(out, 61<<2, byte(n-1), byte((n-1)>>8))
+		out = append(out, data[:n]...)
+		data = data[n:]
+	}
+	return out
+}
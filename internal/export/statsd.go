@@ -0,0 +1,61 @@
+package export
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// statsdPacketSize keeps datagrams below the usual path MTU
+const statsdPacketSize = 1432
+
+// StatsD sends every sample as a gauge ("path:value|g") over UDP. Counters
+// are sent as gauges too, so the daemon does not sum them up again.
+type StatsD struct {
+	cfg config.StatsD
+}
+
+func NewStatsD(cfg config.StatsD) *StatsD {
+	return &StatsD{cfg: cfg}
+}
+
+func (s *StatsD) Name() string {
+	return "statsd"
+}
+
+func (s *StatsD) Export(ctx context.Context, _ time.Time, samples []Sample) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", s.cfg.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var packet strings.Builder
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := conn.Write([]byte(packet.String()))
+		packet.Reset()
+		return err
+	}
+
+	for _, sample := range samples {
+		line := metricPath(s.cfg.Prefix, sample, true) + ":" + strconv.FormatFloat(sample.Value, 'f', -1, 64) + "|g"
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsdPacketSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	return flush()
+}
@@ -0,0 +1,179 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/caps"
+)
+
+// Event is something that happened at a time, such as an alert firing or
+// clearing, as the JSON it was recorded as
+type Event struct {
+	Time time.Time
+	Data json.RawMessage
+}
+
+// events keeps the events for the retention period, appending each to a
+// text file beside the series when they are kept on disk: the time in Unix
+// nanoseconds, a tab and the JSON. Events are few, so each is written as it
+// comes and the file is rewritten once more of it has expired than is left.
+type events struct {
+	mutex  sync.Mutex
+	events []Event
+	// Empty when the events are kept in memory only
+	path    string
+	out     *os.File
+	expired int
+}
+
+// eventsPath is the file of the events of a store at path, e.g.
+// history-events.log for history.log or history.db
+func eventsPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "-events.log"
+}
+
+func openEvents(path string) (*events, error) {
+	e := &events{path: path}
+	if path == "" {
+		return e, nil
+	}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	e.out = out
+	return e, nil
+}
+
+// load reads the events of a previous run. A line cut short by a crash is
+// skipped.
+func (e *events) load() error {
+	in, err := caps.Open(e.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		unixNano, data, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok || !json.Valid([]byte(data)) {
+			continue
+		}
+		t, err := strconv.ParseInt(unixNano, 10, 64)
+		if err != nil {
+			continue
+		}
+		e.events = append(e.events, Event{Time: time.Unix(0, t), Data: json.RawMessage(data)})
+	}
+	return scanner.Err()
+}
+
+// ReadEvents returns the events a store at path keeps on disk, oldest
+// first. The file is only read, so this works while an agent records into
+// it.
+func ReadEvents(path string) ([]Event, error) {
+	e := &events{path: eventsPath(path)}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	return e.events, nil
+}
+
+func (e *events) append(event Event) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.events = append(e.events, event)
+	if e.out == nil {
+		return nil
+	}
+	_, err := e.out.WriteString(formatEvent(event))
+	return err
+}
+
+func formatEvent(event Event) string {
+	return strconv.FormatInt(event.Time.UnixNano(), 10) + "\t" + string(event.Data) + "\n"
+}
+
+// query returns the events from from to to, oldest first
+func (e *events) query(from, to time.Time) []Event {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	start := sort.Search(len(e.events), func(i int) bool { return !e.events[i].Time.Before(from) })
+	end := sort.Search(len(e.events), func(i int) bool { return e.events[i].Time.After(to) })
+	return slices.Clone(e.events[start:end])
+}
+
+// trim drops the events before a time
+func (e *events) trim(before time.Time) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	n := sort.Search(len(e.events), func(i int) bool { return !e.events[i].Time.Before(before) })
+	e.events = append(e.events[:0:0], e.events[n:]...)
+	e.expired += n
+	if e.out == nil || e.expired <= len(e.events) {
+		return nil
+	}
+	return e.compact()
+}
+
+// compact rewrites the file with the events kept. The caller holds mutex.
+func (e *events) compact() error {
+	temp, err := os.CreateTemp(filepath.Dir(e.path), ".history-events-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	writer := bufio.NewWriter(temp)
+	for _, event := range e.events {
+		writer.WriteString(formatEvent(event))
+	}
+	if err := writer.Flush(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), e.path); err != nil {
+		return fmt.Errorf("replacing %s: %w", e.path, err)
+	}
+
+	out, err := os.OpenFile(e.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	e.out.Close()
+	e.out, e.expired = out, 0
+	return nil
+}
+
+func (e *events) close() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.out == nil {
+		return nil
+	}
+	return e.out.Close()
+}
@@ -0,0 +1,179 @@
+package history
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/caps"
+)
+
+// flushInterval is how long appended values are buffered before they are
+// written, so flash storage sees one write a minute rather than one per
+// collection. Up to that much is lost if the agent is killed.
+const flushInterval = time.Minute
+
+// file keeps the series in memory and appends every collection to a text
+// file, one line of tab separated name=value pairs after the time in Unix
+// nanoseconds, read back on start. The file is only rewritten once more
+// of it has expired than is left.
+type file struct {
+	*memory
+	path string
+
+	// Held while writing to the file, so a compaction in the background
+	// sees every collection in memory either written out or not at all
+	write     sync.Mutex
+	out       *os.File
+	writer    *bufio.Writer
+	lastFlush time.Time
+	// Samples in the file, and how many of them were trimmed since
+	onDisk  int
+	expired int
+}
+
+func openFile(path string) (*file, error) {
+	f := &file{memory: newMemory(), path: path}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	f.out, f.writer, f.lastFlush = out, bufio.NewWriter(out), time.Now()
+	return f, nil
+}
+
+// load reads the collections of a previous run. A line cut short by a
+// crash is skipped.
+func (f *file) load() error {
+	in, err := caps.Open(f.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		unixNano, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		values := make(map[string]float64, len(fields)-1)
+		for _, field := range fields[1:] {
+			// Expressions such as proc[name="x"] have an = of their own
+			i := strings.LastIndexByte(field, '=')
+			if i < 0 {
+				continue
+			}
+			if value, err := strconv.ParseFloat(field[i+1:], 64); err == nil {
+				values[field[:i]] = value
+			}
+		}
+		f.memory.Append(time.Unix(0, unixNano), values)
+		f.onDisk += len(values)
+	}
+	return scanner.Err()
+}
+
+func (f *file) Append(t time.Time, values map[string]float64) error {
+	f.write.Lock()
+	defer f.write.Unlock()
+	f.memory.Append(t, values)
+	f.onDisk += len(values)
+	writeCollection(f.writer, t.UnixNano(), values)
+	if time.Since(f.lastFlush) < flushInterval {
+		return nil
+	}
+	f.lastFlush = time.Now()
+	return f.writer.Flush()
+}
+
+func writeCollection(w *bufio.Writer, unixNano int64, values map[string]float64) {
+	w.WriteString(strconv.FormatInt(unixNano, 10))
+	for name, value := range values {
+		w.WriteByte('\t')
+		w.WriteString(name)
+		w.WriteByte('=')
+		w.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	w.WriteByte('\n')
+}
+
+func (f *file) Trim(before time.Time) error {
+	f.write.Lock()
+	defer f.write.Unlock()
+	f.mutex.Lock()
+	f.expired += f.memory.trim(before)
+	f.mutex.Unlock()
+	if f.expired <= f.onDisk-f.expired {
+		return nil
+	}
+	return f.compact()
+}
+
+// compact rewrites the file with the values kept in memory. The caller
+// holds write.
+func (f *file) compact() error {
+	if err := f.writer.Flush(); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(f.path), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	f.mutex.RLock()
+	times, collections := f.memory.collections()
+	f.mutex.RUnlock()
+	writer := bufio.NewWriter(temp)
+	var kept int
+	for _, unixNano := range times {
+		writeCollection(writer, unixNano, collections[unixNano])
+		kept += len(collections[unixNano])
+	}
+	if err := writer.Flush(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), f.path); err != nil {
+		return fmt.Errorf("replacing %s: %w", f.path, err)
+	}
+
+	out, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	f.out.Close()
+	f.out, f.writer = out, bufio.NewWriter(out)
+	f.onDisk, f.expired = kept, 0
+	return nil
+}
+
+func (f *file) Close() error {
+	f.write.Lock()
+	defer f.write.Unlock()
+	if err := f.writer.Flush(); err != nil {
+		f.out.Close()
+		return err
+	}
+	return f.out.Close()
+}
@@ -0,0 +1,276 @@
+// Package history keeps the metrics `croptop serve` collects over time and
+// downsamples them for the /api/history endpoint.
+package history
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/config"
+)
+
+// trimInterval is how often values past their retention period are dropped
+const trimInterval = time.Minute
+
+// Point is a value at a time, or the average of a step starting at it
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is what a query returns: the points from From on, each the
+// average of Step
+type Series struct {
+	From   time.Time
+	Step   time.Duration
+	Points []Point
+}
+
+// Backend keeps the values of named series. Remote databases can be added
+// by implementing it.
+type Backend interface {
+	// Append adds the values of a collection at t
+	Append(t time.Time, values map[string]float64) error
+	// Query returns the series name from from to to averaged over steps of
+	// step, one point per step with values, or every value for a step of 0
+	Query(name string, from, to time.Time, step time.Duration) ([]Point, error)
+	// Trim drops the values before a time
+	Trim(before time.Time) error
+	Close() error
+}
+
+// tier is the values as collected, with a step of 0, or their averages
+// over step, kept in a backend of its own for retention
+type tier struct {
+	step      time.Duration
+	retention time.Duration
+	backend   Backend
+	// The step being averaged
+	start time.Time
+	sums  map[string]*average
+}
+
+type average struct {
+	sum   float64
+	count int
+}
+
+// add counts values in the averages of the step t falls in, writing out
+// the averages of the previous step first
+func (t *tier) add(at time.Time, values map[string]float64) error {
+	var err error
+	if start := at.Truncate(t.step); !start.Equal(t.start) {
+		err = t.flush()
+		t.start = start
+	}
+	for name, value := range values {
+		a, ok := t.sums[name]
+		if !ok {
+			a = new(average)
+			t.sums[name] = a
+		}
+		a.sum += value
+		a.count++
+	}
+	return err
+}
+
+// flush writes out the averages of the current step
+func (t *tier) flush() error {
+	if len(t.sums) == 0 {
+		return nil
+	}
+	averages := make(map[string]float64, len(t.sums))
+	for name, a := range t.sums {
+		averages[name] = a.sum / float64(a.count)
+	}
+	clear(t.sums)
+	return t.backend.Append(t.start, averages)
+}
+
+// Store keeps series as collected for the retention period and their
+// averages over coarser steps for longer, dropping what expired in the
+// background
+type Store struct {
+	// Finest first, the values as collected
+	tiers  []*tier
+	events *events
+
+	lastTrim time.Time
+	trimming atomic.Bool
+	trims    sync.WaitGroup
+}
+
+// Open returns a store keeping its series in the configured backend, at
+// path for the ones keeping them on disk
+func Open(cfg config.History, path string) (*Store, error) {
+	s := &Store{}
+	eventsFile := ""
+	if OnDisk(cfg) {
+		eventsFile = eventsPath(path)
+	}
+	var err error
+	if s.events, err = openEvents(eventsFile); err != nil {
+		return nil, err
+	}
+	steps := append([]config.Downsample{{Retention: cfg.Retention}}, cfg.Downsample...)
+	for _, downsample := range steps {
+		backend, err := openBackend(cfg.Backend, tierPath(path, time.Duration(downsample.Step)))
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.tiers = append(s.tiers, &tier{
+			step:      time.Duration(downsample.Step),
+			retention: time.Duration(downsample.Retention),
+			backend:   backend,
+			sums:      make(map[string]*average),
+		})
+	}
+	return s, nil
+}
+
+// OnDisk reports whether the backend of cfg keeps the history on disk
+func OnDisk(cfg config.History) bool {
+	return cfg.Backend == config.HistoryFile || cfg.Backend == config.HistorySQLite
+}
+
+// Path is the file of a store of cfg: history.path, or history.log or
+// history.db in dir
+func Path(cfg config.History, dir string) string {
+	switch {
+	case cfg.Path != "":
+		return cfg.Path
+	case cfg.Backend == config.HistorySQLite:
+		return filepath.Join(dir, "history.db")
+	default:
+		return filepath.Join(dir, "history.log")
+	}
+}
+
+func openBackend(kind, path string) (Backend, error) {
+	switch kind {
+	case config.HistoryFile:
+		return openFile(path)
+	case config.HistorySQLite:
+		return openSQLite(path)
+	default:
+		return newMemory(), nil
+	}
+}
+
+// tierPath is the file of the averages over step, e.g. history-1m.log for
+// history.log
+func tierPath(path string, step time.Duration) string {
+	if step == 0 {
+		return path
+	}
+	// 1m0s as 1m and 1h0m0s as 1h
+	name := step.String()
+	if strings.HasSuffix(name, "m0s") {
+		name = strings.TrimSuffix(name, "0s")
+	}
+	if strings.HasSuffix(name, "h0m") {
+		name = strings.TrimSuffix(name, "0m")
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + name + ext
+}
+
+// Retention is how far back the store goes
+func (s *Store) Retention() time.Duration {
+	return s.tiers[len(s.tiers)-1].retention
+}
+
+// Record adds the values of a collection at t, leaving out the ones that
+// are not numbers. Only called from the collection loop.
+func (s *Store) Record(t time.Time, values map[string]float64) error {
+	for name, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			delete(values, name)
+		}
+	}
+	errs := []error{s.tiers[0].backend.Append(t, values)}
+	for _, tier := range s.tiers[1:] {
+		errs = append(errs, tier.add(t, values))
+	}
+
+	// Trimming may rewrite a file, so it runs beside the collection loop;
+	// a trim still running skips the next one
+	if t.Sub(s.lastTrim) >= trimInterval && s.trimming.CompareAndSwap(false, true) {
+		s.lastTrim = t
+		s.trims.Add(1)
+		go func() {
+			defer s.trims.Done()
+			defer s.trimming.Store(false)
+			for _, tier := range s.tiers {
+				if err := tier.backend.Trim(t.Add(-tier.retention)); err != nil {
+					slog.Warn("trimming history failed", "step", tier.step, "err", err)
+				}
+			}
+			if err := s.events.trim(t.Add(-s.Retention())); err != nil {
+				slog.Warn("trimming history events failed", "err", err)
+			}
+		}()
+	}
+	return errors.Join(errs...)
+}
+
+// Query returns the series name from from to to averaged over steps of at
+// least step. It reads the coarsest tier whose steps fit in step and that
+// goes back far enough, or the finest that goes back far enough; from is
+// aligned to the step, so polling again averages the same values.
+func (s *Store) Query(name string, from, to time.Time, step time.Duration) (Series, error) {
+	span := to.Sub(from)
+	var chosen *tier
+	for _, tier := range s.tiers {
+		if tier.retention < span && tier != s.tiers[len(s.tiers)-1] {
+			continue
+		}
+		if chosen == nil || tier.step <= step {
+			chosen = tier
+		}
+	}
+
+	series := Series{Step: max(step, chosen.step)}
+	if series.Step > 0 {
+		series.From = from.Truncate(series.Step)
+	} else {
+		series.From = from
+	}
+	points, err := chosen.backend.Query(name, series.From, to, series.Step)
+	series.Points = points
+	return series, err
+}
+
+// RecordEvent adds an event, such as an alert firing or clearing, kept as
+// long as the coarsest series
+func (s *Store) RecordEvent(event Event) error {
+	return s.events.append(event)
+}
+
+// Events returns the events from from to to, oldest first
+func (s *Store) Events(from, to time.Time) []Event {
+	return s.events.query(from, to)
+}
+
+// Close writes out the averages of the current steps and what the backends
+// buffer
+func (s *Store) Close() error {
+	s.trims.Wait()
+	var errs []error
+	for i, tier := range s.tiers {
+		if i > 0 {
+			errs = append(errs, tier.flush())
+		}
+		errs = append(errs, tier.backend.Close())
+	}
+	errs = append(errs, s.events.close())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,111 @@
+package history
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sample is a Point as kept, a third of the size
+type sample struct {
+	unixNano int64
+	value    float64
+}
+
+// memory keeps the series in memory only, they are lost on restart
+type memory struct {
+	mutex  sync.RWMutex
+	series map[string][]sample
+}
+
+func newMemory() *memory {
+	return &memory{series: make(map[string][]sample)}
+}
+
+func (m *memory) Append(t time.Time, values map[string]float64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for name, value := range values {
+		m.series[name] = append(m.series[name], sample{unixNano: t.UnixNano(), value: value})
+	}
+	return nil
+}
+
+func (m *memory) Query(name string, from, to time.Time, step time.Duration) ([]Point, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	samples := m.series[name]
+	start := sort.Search(len(samples), func(i int) bool { return samples[i].unixNano >= from.UnixNano() })
+	end := sort.Search(len(samples), func(i int) bool { return samples[i].unixNano > to.UnixNano() })
+
+	points := []Point{}
+	var bucket int64 = -1
+	var sum float64
+	var count int
+	flush := func() {
+		if count > 0 {
+			points = append(points, Point{Time: time.Unix(0, from.UnixNano()+bucket*int64(step)), Value: sum / float64(count)})
+		}
+	}
+	for _, sample := range samples[start:end] {
+		if step <= 0 {
+			points = append(points, Point{Time: time.Unix(0, sample.unixNano), Value: sample.value})
+			continue
+		}
+		if b := (sample.unixNano - from.UnixNano()) / int64(step); b != bucket {
+			flush()
+			bucket, sum, count = b, 0, 0
+		}
+		sum += sample.value
+		count++
+	}
+	flush()
+	return points, nil
+}
+
+func (m *memory) Trim(before time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.trim(before)
+	return nil
+}
+
+// trim drops the samples before a time and returns how many it dropped
+func (m *memory) trim(before time.Time) int {
+	var dropped int
+	for name, samples := range m.series {
+		old := sort.Search(len(samples), func(i int) bool { return samples[i].unixNano >= before.UnixNano() })
+		switch {
+		case old == len(samples):
+			// A series that stopped, such as an unmounted disk's
+			delete(m.series, name)
+		case old > 0:
+			m.series[name] = slices.Clone(samples[old:])
+		}
+		dropped += old
+	}
+	return dropped
+}
+
+// collections regroups the samples by the collection they came from
+func (m *memory) collections() (times []int64, values map[int64]map[string]float64) {
+	values = make(map[int64]map[string]float64)
+	for name, samples := range m.series {
+		for _, sample := range samples {
+			collection, ok := values[sample.unixNano]
+			if !ok {
+				collection = make(map[string]float64)
+				values[sample.unixNano] = collection
+				times = append(times, sample.unixNano)
+			}
+			collection[name] = sample.value
+		}
+	}
+	slices.Sort(times)
+	return times, values
+}
+
+func (m *memory) Close() error {
+	return nil
+}
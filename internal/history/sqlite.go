@@ -0,0 +1,186 @@
+package history
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sqliteEnd is printed after every batch of statements, so the output of
+// one can be told from the next
+const sqliteEnd = "croptop-end"
+
+// sqliteSchema creates the table of the samples. WAL lets other programs
+// read the database while the agent writes to it.
+const sqliteSchema = `PRAGMA journal_mode=WAL;
+CREATE TABLE IF NOT EXISTS samples (name TEXT NOT NULL, time INTEGER NOT NULL, value REAL NOT NULL);
+CREATE INDEX IF NOT EXISTS samples_name_time ON samples (name, time);
+`
+
+// sqlite keeps the series in a SQLite database through the sqlite3
+// command-line shell, which saves croptop a cgo driver. The shell runs for
+// as long as the agent and takes the statements on its standard input;
+// inserts are buffered for flushInterval and written in one transaction.
+type sqlite struct {
+	mutex     sync.Mutex
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    *bufio.Reader
+	pending   strings.Builder
+	lastFlush time.Time
+}
+
+func openSQLite(path string) (*sqlite, error) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return nil, errors.New("the sqlite history backend needs the sqlite3 command")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("sqlite3", "-batch", path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			slog.Warn("sqlite3 failed", "err", scanner.Text())
+		}
+	}()
+
+	s := &sqlite{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout), lastFlush: time.Now()}
+	if _, err := s.exec(sqliteSchema); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// exec runs statements and returns the lines they print. The caller holds
+// the mutex, except while opening.
+func (s *sqlite) exec(statements string) ([]string, error) {
+	if _, err := io.WriteString(s.stdin, statements+"SELECT '"+sqliteEnd+"';\n"); err != nil {
+		return nil, err
+	}
+	var lines []string
+	for {
+		line, err := s.stdout.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("sqlite3 stopped: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == sqliteEnd {
+			return lines, nil
+		}
+		lines = append(lines, line)
+	}
+}
+
+// flush writes the buffered inserts. The caller holds the mutex.
+func (s *sqlite) flush() error {
+	if s.pending.Len() == 0 {
+		return nil
+	}
+	_, err := s.exec("BEGIN;\n" + s.pending.String() + "COMMIT;\n")
+	s.pending.Reset()
+	s.lastFlush = time.Now()
+	return err
+}
+
+func (s *sqlite) Append(t time.Time, values map[string]float64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for name, value := range values {
+		fmt.Fprintf(&s.pending, "INSERT INTO samples VALUES (%s, %d, %s);\n",
+			quote(name), t.UnixNano(), strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	if time.Since(s.lastFlush) < flushInterval {
+		return nil
+	}
+	return s.flush()
+}
+
+func (s *sqlite) Query(name string, from, to time.Time, step time.Duration) ([]Point, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// The buffered values are part of the answer
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
+
+	var query string
+	if step <= 0 {
+		query = fmt.Sprintf("SELECT time, value FROM samples WHERE name = %s AND time BETWEEN %d AND %d ORDER BY time;\n",
+			quote(name), from.UnixNano(), to.UnixNano())
+	} else {
+		query = fmt.Sprintf("SELECT %[2]d + (time - %[2]d) / %[4]d * %[4]d, avg(value) FROM samples WHERE name = %[1]s AND time BETWEEN %[2]d AND %[3]d GROUP BY 1 ORDER BY 1;\n",
+			quote(name), from.UnixNano(), to.UnixNano(), int64(step))
+	}
+	lines, err := s.exec(query)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]Point, 0, len(lines))
+	for _, line := range lines {
+		unixNano, value, ok := strings.Cut(line, "|")
+		if !ok {
+			continue
+		}
+		t, err := strconv.ParseInt(unixNano, 10, 64)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		points = append(points, Point{Time: time.Unix(0, t), Value: v})
+	}
+	return points, nil
+}
+
+func (s *sqlite) Trim(before time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fmt.Fprintf(&s.pending, "DELETE FROM samples WHERE time < %d;\n", before.UnixNano())
+	return nil
+}
+
+func (s *sqlite) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	err := s.flush()
+	s.stdin.Close()
+	if waitErr := s.cmd.Wait(); err == nil {
+		err = waitErr
+	}
+	return err
+}
+
+// quote makes a SQL string literal
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,519 @@
+package i18n
+
+import "golang.org/x/text/language"
+
+func init() {
+	register(language.German, map[string]string{
+		// Tabs
+		"Overview":   "Übersicht",
+		"Memory":     "Speicher",
+		"Processes":  "Prozesse",
+		"Users":      "Benutzer",
+		"Network":    "Netzwerk",
+		"Disk":       "Datenträger",
+		"Battery":    "Akku",
+		"Security":   "Sicherheit",
+		"Alerts":     "Alarme",
+		"Boot":       "Systemstart",
+		"Containers": "Container",
+
+		// Title, help and alert bar
+		"(paused while unfocused)":                                       "(pausiert ohne Fokus)",
+		"(refreshing every %s while unfocused)":                          "(aktualisiert alle %s ohne Fokus)",
+		"⚠ %d alert(s): %s":                                              "⚠ %d Alarm(e): %s",
+		"✓ Reloaded %s":                                                  "✓ %s neu geladen",
+		"✗ Config not reloaded: %v":                                      "✗ Konfiguration nicht neu geladen: %v",
+		"✗ Stopped watching the config file: %v":                         "✗ Konfigurationsdatei wird nicht mehr überwacht: %v",
+		"🔒 %s needs more permissions: %s":                                "🔒 %s benötigt mehr Rechte: %s",
+		"CPU power from RAPL energy counters":                            "CPU-Leistung aus den RAPL-Energiezählern",
+		"Disk I/O of other users' processes":                             "Datenträger-I/O der Prozesse anderer Benutzer",
+		"Kernel log from /dev/kmsg":                                      "Kernel-Log aus /dev/kmsg",
+		"run with sudo or grant CAP_SYS_PTRACE":                          "mit sudo starten oder CAP_SYS_PTRACE gewähren",
+		"run with sudo, grant CAP_SYSLOG or set kernel.dmesg_restrict=0": "mit sudo starten, CAP_SYSLOG gewähren oder kernel.dmesg_restrict=0 setzen",
+		"run with sudo or grant CAP_DAC_READ_SEARCH (energy counters are root-only since the PLATYPUS fix)": "mit sudo starten oder CAP_DAC_READ_SEARCH gewähren (Energiezähler sind seit dem PLATYPUS-Fix nur für root lesbar)",
+		"(stale: %s)":                                                "(veraltet: %s)",
+		"[read-only]":                                                "[schreibgeschützt]",
+		"Switching the power profile":                                "Das Wechseln des Energieprofils",
+		"🔒 %s is disabled in read-only mode":                         "🔒 %s ist im schreibgeschützten Modus deaktiviert",
+		"✗ Export failed: %v":                                        "✗ Export fehlgeschlagen: %v",
+		"✓ Exported %d processes to %s":                              "✓ %d Prozesse nach %s exportiert",
+		"✗ Copy failed: %v":                                          "✗ Kopieren fehlgeschlagen: %v",
+		"✓ Copied %s to the clipboard":                               "✓ %s in die Zwischenablage kopiert",
+		"PID %s":                                                     "PID %s",
+		"the command line of PID %s":                                 "Befehlszeile von PID %s",
+		"%d neighbor IP addresses":                                   "%d IP-Adressen der Nachbarn",
+		"%d mountpoints":                                             "%d Einhängepunkte",
+		"✗ Could not switch to %s: %v":                               "✗ Wechsel zu %s fehlgeschlagen: %v",
+		"✓ Switched power profile to %s":                             "✓ Energieprofil zu %s gewechselt",
+		"power-profiles-daemon is not available":                     "power-profiles-daemon ist nicht verfügbar",
+		"DNS test already running":                                   "DNS-Test läuft bereits",
+		"Nothing to copy, the neighbor table is empty":               "Nichts zu kopieren, die Nachbartabelle ist leer",
+		"Nothing to copy, no disks found":                            "Nichts zu kopieren, keine Datenträger gefunden",
+		"Nothing to copy, no process selected":                       "Nichts zu kopieren, kein Prozess ausgewählt",
+		"Reading PSS and USS of every process, refreshes get slower": "PSS und USS aller Prozesse werden gelesen, Aktualisierungen werden langsamer",
+		"PSS and USS off":                                            "PSS und USS aus",
+		"checking...":                                                "prüfe...",
+		"Up:":                                                        "Erreichbar:",
+		"%d of %d, checked at %s":                                    "%d von %d, geprüft um %s",
+		"down: %s":                                                   "nicht erreichbar: %s",
+		"Services":                                                   "Dienste",
+		"Capture unavailable: %v":                                    "Mitschnitt nicht verfügbar: %v",
+		"Run with sudo or grant CAP_NET_RAW (croptop grant-caps -capture)": "Mit sudo starten oder CAP_NET_RAW gewähren (croptop grant-caps -capture)",
+		"No traffic captured": "Kein Verkehr mitgeschnitten",
+		"No other network namespaces found; other users' processes need root": "Keine anderen Netzwerk-Namespaces gefunden; Prozesse anderer Benutzer erfordern root",
+		"Host (croptop's own namespace)":                                      "Host (croptops eigener Namespace)",
+		"net:[%s] · PID %s %s · %d processes":                                 "net:[%s] · PID %s %s · %d Prozesse",
+		"Show the network namespace of":                                       "Netzwerk-Namespace anzeigen von",
+		"Network Namespace net:[%s]":                                          "Netzwerk-Namespace net:[%s]",
+		"Seen through:":                                                       "Gesehen über:",
+		"PID %s %s, %d processes in the namespace":                            "PID %s %s, %d Prozesse im Namespace",
+		"n: pick another namespace or the host's":                             "n: anderen Namespace oder den des Hosts wählen",
+		"Reading the namespace…":                                              "Lese den Namespace…",
+		"Addresses:":                                                          "Adressen:",
+		"Interfaces":                                                          "Schnittstellen",
+		"Connections (%d)":                                                    "Verbindungen (%d)",
+		"No sockets":                                                          "Keine Sockets",
+		"n: show another network namespace, such as a container's":            "n: anderen Netzwerk-Namespace anzeigen, etwa den eines Containers",
+		"System Limits":                                                       "Systemgrenzen",
+		"Threads":                                                             "Threads",
+		"Open files":                                                          "Offene Dateien",
+		"none":                                                                "keine",
+		"all (%s)":                                                            "alle (%s)",
+		"all but %s":                                                          "alle außer %s",
+		"disabled":                                                            "aus",
+		"strict":                                                              "strikt",
+		"filter":                                                              "Filter",
+		"filter, %s installed":                                                "Filter, %s installiert",
+		"dropped capabilities":                                                "abgegebene Capabilities",
+		"own %s namespaces":                                                   "eigene %s-Namespaces",
+		"none, privileged and unconfined":                                     "keine, privilegiert und uneingeschränkt",
+		"none, but unprivileged":                                              "keine, aber unprivilegiert",
+		"no":                                                                  "nein",
+		"yes":                                                                 "ja",
+		"Sandboxing:":                                                         "Sandboxing:",
+		"Capabilities:":                                                       "Capabilities:",
+		"Seccomp:":                                                            "Seccomp:",
+		"No new privileges:":                                                  "Keine neuen Privilegien:",
+		"Namespaces:":                                                         "Namespaces:",
+		"%s, own: %s":                                                         "%s, eigene: %s",
+		"Host":                                                                "Host",
+		"Container":                                                           "Container",
+		"Sandbox":                                                             "Sandbox",
+		"Processes by Namespace":                                              "Prozesse nach Namespace",
+		"A container has its own PID and mount namespace, a sandbox only some namespaces of its own • N/Esc: back to the table": "Ein Container hat einen eigenen PID- und Mount-Namespace, eine Sandbox nur einige eigene Namespaces • N/Esc: zurück zur Tabelle",
+		"Reading namespaces…":                                         "Lese Namespaces…",
+		"%d namespace sets, %d of them containers":                    "%d Namespace-Gruppen, davon %d Container",
+		"%s · %d processes · CPU %s · MEM %s":                         "%s · %d Prozesse · CPU %s · MEM %s",
+		"Not readable · %d processes":                                 "Nicht lesbar · %d Prozesse",
+		"Reading the namespaces of other users' processes needs root": "Die Namespaces der Prozesse anderer Benutzer zu lesen erfordert root",
+		"own: %s":            "eigene: %s",
+		"Limits":             "Limits",
+		"Open files:":        "Offene Dateien:",
+		"Processes:":         "Prozesse:",
+		"Locked memory:":     "Gesperrter Speicher:",
+		"unlimited":          "unbegrenzt",
+		"%s of %s (hard %s)": "%s von %s (hart %s)",
+		"Soft limits apply, a process may raise them up to the hard limit; processes counts the threads of all processes of the user": "Es gelten die weichen Limits, ein Prozess darf sie bis zum harten Limit anheben; Prozesse zählt die Threads aller Prozesse des Benutzers",
+		"Audio (%s)": "Audio (%s)",
+		"Server:":    "Server:",
+		" • quantum %d frames (%.1fms per cycle)": " • Quantum %d Frames (%.1fms pro Zyklus)",
+		"Rate:":                           "Rate:",
+		"No streams playing or recording": "Keine Wiedergabe oder Aufnahme",
+		"Sessions":                        "Sitzungen",
+		"This session:":                   "Diese Sitzung:",
+		"Compositor Load":                 "Last des Compositors",
+		"No display server or compositor running": "Kein Displayserver oder Compositor aktiv",
+		"Applications": "Anwendungen",
+		"A busy compositor beside idle applications is compositing overhead (effects, animations, many or large windows), not application load": "Ein ausgelasteter Compositor neben untätigen Anwendungen ist Compositing-Aufwand (Effekte, Animationen, viele oder große Fenster), keine Anwendungslast",
+		"GPU usage needs DRM usage statistics (Linux 5.19+) and permission to read the processes' file descriptors":                             "die GPU-Nutzung braucht DRM-Nutzungsstatistiken (Linux 5.19+) und das Recht, die Dateideskriptoren der Prozesse zu lesen",
+		"Stopping and restarting containers": "Das Stoppen und Neustarten von Containern",
+		"Stop container %s?":                 "Container %s stoppen?",
+		"Restart container %s?":              "Container %s neu starten?",
+		"Stopping":                           "Stoppen",
+		"Restarting":                         "Neustart",
+		"API:":                               "API:",
+		"No containers":                      "Keine Container",
+		"↑↓ j/k: select • Enter: logs • s: stop • r: restart": "↑↓ j/k: wählen • Enter: Logs • s: stoppen • r: neu starten",
+		"paused":                         "pausiert",
+		"following":                      "folgt",
+		"Logs of %s (last %d lines, %s)": "Logs von %s (letzte %d Zeilen, %s)",
+		"Esc/Enter: back • ↑↓ j/k: scroll • f: follow • s: stop • r: restart": "Esc/Enter: zurück • ↑↓ j/k: blättern • f: folgen • s: stoppen • r: neu starten",
+		"Managing systemd units": "Das Verwalten von systemd-Units",
+		"Action on %s":           "Aktion für %s",
+		"%s %s?":                 "%s: %s?",
+		"Start":                  "Starten",
+		"Stop":                   "Stoppen",
+		"Restart":                "Neu starten",
+		"Enable at boot":         "Beim Systemstart aktivieren",
+		"Disable at boot":        "Beim Systemstart deaktivieren",
+		"Started %s":             "%s gestartet",
+		"Stopped %s":             "%s gestoppt",
+		"Restarted %s":           "%s neu gestartet",
+		"Enabled %s at boot":     "%s beim Systemstart aktiviert",
+		"Disabled %s at boot":    "%s beim Systemstart deaktiviert",
+		"✗ %s %s failed: %v":     "✗ %s %s fehlgeschlagen: %v",
+		"↑↓ j/k: select unit • Enter: start, stop, restart, enable or disable it": "↑↓ j/k: Unit wählen • Enter: starten, stoppen, neu starten, aktivieren oder deaktivieren",
+		"Nothing to copy, the executable could not be read":                       "Nichts zu kopieren, die ausführbare Datei ist nicht lesbar",
+		"the SHA256 of PID %s":                                     "SHA256 von PID %s",
+		"not installed by a package":                               "nicht über ein Paket installiert",
+		"Last Boot":                                                "Letzter Systemstart",
+		"Slowest Units":                                            "Langsamste Units",
+		"… and %d more":                                            "… und %d weitere",
+		"Critical Chain":                                           "Kritische Kette",
+		"⚡ unusually high, usually %s":                             "⚡ ungewöhnlich hoch, sonst %s",
+		"⚡ unusually low, usually %s":                              "⚡ ungewöhnlich niedrig, sonst %s",
+		"Already looking for agents":                               "Suche nach Agenten läuft bereits",
+		"Looking for agents on the LAN…":                           "Suche Agenten im LAN…",
+		"✗ Looking for agents failed: %v":                          "✗ Suche nach Agenten fehlgeschlagen: %v",
+		"No new agents, the %d found are hosts already":            "Keine neuen Agenten, die %d gefundenen sind schon Hosts",
+		"No agents found; start them with croptop serve -announce": "Keine Agenten gefunden; starte sie mit croptop serve -announce",
+		"Add a host:":                                              "Host hinzufügen:",
+		"Hosts not saved: %v":                                      "Hosts nicht gespeichert: %v",
+		"✓ Added %s":                                               "✓ %s hinzugefügt",
+		"No hosts yet. Press D to look for agents on the LAN, or list them under \"hosts\" in the config file.": "Noch keine Hosts. D sucht Agenten im LAN, oder trage sie unter \"hosts\" in der Konfigurationsdatei ein.",
+		"Polled every %s • ↑↓ j/k: select • Enter: details • D: discover agents":                                "Abfrage alle %s • ↑↓ j/k: auswählen • Enter: Details • D: Agenten suchen",
+		"Hosts":                  "Hosts",
+		"%d firing":              "%d aktiv",
+		"%d firing, %d critical": "%d aktiv, %d kritisch",
+		"Host %s · %s":           "Host %s · %s",
+		"unreachable: %v":        "nicht erreichbar: %v",
+		"No snapshot received from this agent yet": "Von diesem Agenten ist noch kein Snapshot eingegangen",
+		"%.1f%% of %d CPUs, %.0f MHz":              "%.1f%% von %d CPUs, %.0f MHz",
+		"Hostname:":                                "Hostname:",
+		"Collected:":                               "Erfasst:",
+		"Uptime:":                                  "Laufzeit:",
+		"Load average:":                            "Lastdurchschnitt:",
+		"%.1f%%, %s of %s used, %s available":      "%.1f%%, %s von %s belegt, %s verfügbar",
+		"Swap:":                                    "Swap:",
+		"%s of %s used":                            "%s von %s belegt",
+		"Network:":                                 "Netzwerk:",
+		"↓ %s/s ↑ %s/s":                            "↓ %s/s ↑ %s/s",
+		"Battery:":                                 "Akku:",
+		"not responding":                           "antwortet nicht",
+		"None firing":                              "Keine aktiv",
+		"%s, now %.1f, for %s":                     "%s, jetzt %.1f, seit %s",
+		"Top Processes (%d in total)":              "Top-Prozesse (%d insgesamt)",
+		"Esc/Enter: back • ↑↓ j/k: scroll":         "Esc/Enter: zurück • ↑↓ j/k: scrollen",
+		"WSL 1: the totals are those of Windows, shared with every Windows program":                                                       "WSL 1: die Summen sind die von Windows, geteilt mit jedem Windows-Programm",
+		"WSL 2: the totals are those of the WSL VM, capped by memory= in %%UserProfile%%\\.wslconfig (half of the host's RAM by default)": "WSL 2: die Summen sind die der WSL-VM, begrenzt durch memory= in %%UserProfile%%\\.wslconfig (standardmäßig die Hälfte des RAMs des Hosts)",
+		"Thermal Zones":         "Thermische Zonen",
+		"%.0f°C below critical": "%.0f°C unter kritisch",
+		"throttling":            "drosselt",
+		"⚠ %d zone(s) throttling or near their critical temperature, where the machine shuts down": "⚠ %d Zone(n) drosseln oder nahe ihrer kritischen Temperatur, bei der sich die Maschine abschaltet",
+		"the NFS server":                                 "den NFS-Server",
+		"an RPC server, usually NFS":                     "einen RPC-Server, meist NFS",
+		"the SMB server":                                 "den SMB-Server",
+		"a FUSE filesystem":                              "ein FUSE-Dateisystem",
+		"a futex, a lock held by another thread":         "einen Futex, eine Sperre eines anderen Threads",
+		"the filesystem journal":                         "das Dateisystem-Journal",
+		"disk I/O":                                       "Festplatten-E/A",
+		"events, an idle event loop":                     "Ereignisse, eine untätige Ereignisschleife",
+		"file descriptors, select":                       "Dateideskriptoren, select",
+		"file descriptors, poll":                         "Dateideskriptoren, poll",
+		"a child process to exit":                        "das Ende eines Kindprozesses",
+		"a pipe":                                         "eine Pipe",
+		"the network":                                    "das Netzwerk",
+		"a Unix socket":                                  "einen Unix-Socket",
+		"a timer, sleeping":                              "einen Timer, schlafend",
+		"%s, probably waiting for %s":                    "%s, wartet vermutlich auf %s",
+		"(uninterruptible)":                              "(nicht unterbrechbar)",
+		"Waiting in:":                                    "Wartet in:",
+		"kernel function a blocked (D) process waits in": "Kernelfunktion, in der ein blockierter (D) Prozess wartet",
+		"RSS growth over the last 10 minutes, to spot leaks":       "Wachstum des RSS in den letzten 10 Minuten, um Lecks zu finden",
+		"Show or hide a column:":                                   "Spalte ein- oder ausblenden:",
+		"voluntary context switches, waiting for I/O or locks":     "freiwillige Kontextwechsel, Warten auf E/A oder Sperren",
+		"involuntary context switches, preempted by the scheduler": "unfreiwillige Kontextwechsel, vom Scheduler verdrängt",
+		"minor page faults, served from memory":                    "leichte Seitenfehler, aus dem Speicher bedient",
+		"major page faults, read from disk":                        "schwere Seitenfehler, von der Festplatte gelesen",
+		"Process %s · %s":                                          "Prozess %s · %s",
+		"exited, showing its last sample":                          "beendet, letzte Messung",
+		"Command:":                                                 "Befehl:",
+		"Executable:":                                              "Programmdatei:",
+		"Working directory:":                                       "Arbeitsverzeichnis:",
+		"User:":                                                    "Benutzer:",
+		"Parent:":                                                  "Elternprozess:",
+		"State:":                                                   "Zustand:",
+		"Threads:":                                                 "Threads:",
+		"Started:":                                                 "Gestartet:",
+		"%s, %s ago":                                               "%s, vor %s",
+		"Cgroup:":                                                  "Cgroup:",
+		"Resources":                                                "Ressourcen",
+		"CPU:":                                                     "CPU:",
+		"Memory:":                                                  "Speicher:",
+		"%.1f%%, RSS %s, swap %s":                                  "%.1f%%, RSS %s, Swap %s",
+		"Disk I/O:":                                                "Festplatten-E/A:",
+		"read %s/s, write %s/s":                                    "lesen %s/s, schreiben %s/s",
+		"Scheduling":                                               "Scheduling",
+		"Context switches:":                                        "Kontextwechsel:",
+		"%d voluntary (%s/s), %d involuntary (%s/s)": "%d freiwillig (%s/s), %d unfreiwillig (%s/s)",
+		"Page faults:":                     "Seitenfehler:",
+		"%d minor (%s/s), %d major (%s/s)": "%d leicht (%s/s), %d schwer (%s/s)",
+		"Voluntary switches wait for I/O or locks, involuntary ones lost the CPU to other work; major faults read from disk; run queue wait is time ready to run without a free CPU": "Freiwillige Wechsel warten auf E/A oder Sperren, unfreiwillige verloren die CPU an andere Arbeit; schwere Seitenfehler lesen von der Festplatte; Wartezeit in der Run-Queue ist Zeit, lauffähig ohne freie CPU",
+		"Run queue wait:": "Wartezeit Run-Queue:",
+		"%.1f%% of the time, %s in total (main thread)":                                                     "%.1f%% der Zeit, %s insgesamt (Haupt-Thread)",
+		"time waiting for a CPU, of the main thread":                                                        "Wartezeit auf eine CPU, des Haupt-Threads",
+		"Esc/Enter: back • ↑↓ j/k: scroll • x/X: signal • n: nice • y/Y: copy PID/command • s: copy SHA256": "Esc/Enter: zurück • ↑↓ j/k: blättern • x/X: Signal • n: Nice • y/Y: PID/Befehl kopieren • s: SHA256 kopieren",
+		"Filter processes, e.g. user:www-data cpu>5 name~nginx.* (empty shows all):":                        "Prozesse filtern, z. B. user:www-data cpu>5 name~nginx.* (leer zeigt alle):",
+		"Pin %s (PID %s) to the top":                                                                        "%s (PID %s) oben anheften",
+		"PID %s only":                                                                                       "Nur PID %s",
+		"Every process named %s":                                                                            "Jeden Prozess namens %s",
+		"Pinned %s":                                                                                         "%s angeheftet",
+		"Unpinned %s":                                                                                       "%s nicht mehr angeheftet",
+		"Pins not saved: %v":                                                                                "Angeheftete Prozesse nicht gespeichert: %v",
+		"Yes":                                                                                               "Ja",
+		"No":                                                                                                "Nein",
+		"y/n • ←/→: choose • Enter: confirm • Esc: cancel":           "y/n • ←/→: wählen • Enter: bestätigen • Esc: abbrechen",
+		"Enter: confirm • Ctrl+U: clear • Esc: cancel":               "Enter: bestätigen • Strg+U: leeren • Esc: abbrechen",
+		"↑/↓: choose • 1-9/Enter: pick • Esc: cancel":                "↑/↓: wählen • 1-9/Enter: übernehmen • Esc: abbrechen",
+		"Switch the power profile to":                                "Energieprofil wechseln zu",
+		"Send %s to %s (PID %s)?":                                    "%s an %s (PID %s) senden?",
+		"Signal to send to %s (PID %s)":                              "Signal an %s (PID %s)",
+		"Sending %s to %s":                                           "Senden von %s an %s",
+		"%s sent to %s":                                              "%s an %s gesendet",
+		"Nice value for %s (PID %s), -20 (highest priority) to 19:":  "Nice-Wert für %s (PID %s), -20 (höchste Priorität) bis 19:",
+		"enter a whole number from -20 to 19":                        "eine ganze Zahl von -20 bis 19 eingeben",
+		"Renicing %s":                                                "Ändern des Nice-Werts von %s",
+		"Nice value of %s set to %d":                                 "Nice-Wert von %s auf %d gesetzt",
+		"✗ %s failed: %v":                                            "✗ %s fehlgeschlagen: %v",
+		"Renicing processes":                                         "Das Ändern von Nice-Werten",
+		"Signalling processes":                                       "Das Senden von Signalen",
+		"Edit the alert rule":                                        "Alarmregel bearbeiten",
+		"New rule…":                                                  "Neue Regel…",
+		"Alert rule, e.g. cpu.usage > 90 for 60s; empty removes it:": "Alarmregel, z. B. cpu.usage > 90 for 60s; leer entfernt sie:",
+		"✓ Alert rules updated until the config file is reloaded":    "✓ Alarmregeln bis zum Neuladen der Konfigurationsdatei geändert",
+		"⏾ Resumed from suspend at %s after %s, rates restarted":     "⏾ Um %s nach %s aus dem Ruhezustand aufgewacht, Raten neu gestartet",
+		"←/→ h/l: tabs • Shift+←/→ H/L: scroll tabs • ↑/↓ k/j: scroll • s/r: sort/reverse • PgUp/PgDn: page scroll • Home/End: top/bottom • q: quit": "←/→ h/l: Tabs • Shift+←/→ H/L: Tabs blättern • ↑/↓ k/j: scrollen • s/r: sortieren/umkehren • Bild↑/Bild↓: seitenweise • Pos1/Ende: Anfang/Ende • q: beenden",
+
+		// Overview
+		"System Overview":                     "Systemübersicht",
+		"Quick Stats":                         "Kurzübersicht",
+		"CPU: %.1f%%":                         "CPU: %.1f %%",
+		"Memory: %.1f%%":                      "Speicher: %.1f %%",
+		"Processes: %d":                       "Prozesse: %d",
+		"Uptime: %s":                          "Laufzeit: %s",
+		" (host) • %.1f%% of %.1f-core limit": " (Host) • %.1f %% von %.1f Kernen Limit",
+		" (host) • %.1f%% of %.1f GB limit":   " (Host) • %.1f %% von %.1f GB Limit",
+		"CPU Cores: %d":                       "CPU-Kerne: %d",
+		"Memory Total: %.1f GB":               "Speicher gesamt: %.1f GB",
+		"Network Interfaces: %d":              "Netzwerkschnittstellen: %d",
+		"Load Average":                        "Lastdurchschnitt",
+		"Temperature":                         "Temperatur",
+		"%.1f°C":                              "%.1f °C",
+		"Battery: %d%% (%s)":                  "Akku: %d %% (%s)",
+		"↓ %s/s  ↑ %s/s":                      "↓ %s/s  ↑ %s/s",
+		"Disks":                               "Datenträger",
+		"… %d more in the Disk tab":           "… %d weitere im Tab Datenträger",
+		"Top Processes":                       "Aktivste Prozesse",
+
+		// CPU
+		"CPU Information":                 "CPU-Informationen",
+		"Model:":                          "Modell:",
+		"Frequency:":                      "Frequenz:",
+		"Temperature:":                    "Temperatur:",
+		"Overall Usage:":                  "Gesamtauslastung:",
+		"Power (RAPL)":                    "Leistung (RAPL)",
+		"Package:":                        "Package:",
+		"Session Energy:":                 "Energie der Sitzung:",
+		"Cgroup Limit":                    "Cgroup-Limit",
+		"Slab Caches":                     "Slab-Caches",
+		"Reclaimable:":                    "Rückforderbar:",
+		"Unreclaimable:":                  "Nicht rückforderbar:",
+		"Slab caches from /proc/slabinfo": "Slab-Caches aus /proc/slabinfo",
+		"run with sudo or grant CAP_DAC_READ_SEARCH":          "mit sudo ausführen oder CAP_DAC_READ_SEARCH gewähren",
+		"Shared Memory & tmpfs":                               "Gemeinsamer Speicher & tmpfs",
+		"%s %s of %s":                                         "%s %s von %s",
+		"No tmpfs mounted":                                    "Kein tmpfs eingehängt",
+		"Biggest tmpfs Files":                                 "Größte tmpfs-Dateien",
+		"No files on tmpfs":                                   "Keine Dateien auf tmpfs",
+		"Stopped after %d files, bigger files may be missing": "Nach %d Dateien abgebrochen, größere Dateien können fehlen",
+		"System V Segments":                                   "System-V-Segmente",
+		"No System V shared memory segments":                  "Keine System-V-Shared-Memory-Segmente",
+		"created by PID %s":                                   "erstellt von PID %s",
+		"CPU Quota:":                                          "CPU-Kontingent:",
+		"Usage of Limit:":                                     "Anteil am Limit:",
+		"%s %.2f cores":                                       "%s %.2f Kerne",
+		"Per-Core Usage":                                      "Auslastung pro Kern",
+		"Core %d: %.1f%%":                                     "Kern %d: %.1f %%",
+		"Core %d (CPUs %s): %.1f%%":                           "Kern %d (CPUs %s): %.1f %%",
+		"CPU %d (core %d): %.1f%%":                            "CPU %d (Kern %d): %.1f %%",
+		"Package %d":                                          "Sockel %d",
+		"arrows: select a core • Esc: done":                   "Pfeiltasten: Kern wählen • Esc: fertig",
+		"g: bars • Enter: select a core":                      "g: Balken • Enter: Kern wählen",
+		"g: grid":                                             "g: Raster",
+		"CPU %d":                                              "CPU %d",
+		"Core %d (CPUs %s)":                                   "Kern %d (CPUs %s)",
+		"Selected:":                                           "Ausgewählt:",
+		"Location:":                                           "Lage:",
+		"package %d, core %d":                                 "Sockel %d, Kern %d",
+		"SMT Siblings:":                                       "SMT-Geschwister:",
+		"t: one bar per physical core":                        "t: ein Balken je physischem Kern",
+		"t: one bar per hyperthread":                          "t: ein Balken je Hyperthread",
+
+		// Memory
+		"Memory Information": "Speicherinformationen",
+		"Total:":             "Gesamt:",
+		"Used:":              "Belegt:",
+		"Free:":              "Frei:",
+		"Available:":         "Verfügbar:",
+		"Usage:":             "Auslastung:",
+		"Limit:":             "Limit:",
+		"used":               "belegt",
+		"reclaimable cache":  "freigebbarer Cache",
+		"free":               "frei",
+
+		// Users, Swap and I/O
+		"%d users • sorted by %s (%s)": "%d Benutzer • sortiert nach %s (%s)",
+		"descending":                   "absteigend",
+		"ascending":                    "aufsteigend",
+		"CPU%% of all %d CPUs (I)":     "CPU-%% aller %d CPUs (I)",
+		"CPU%% of one core (I)":        "CPU-%% eines Kerns (I)",
+		"Swap & OOM":                   "Swap & OOM",
+		"Swap: %.2f GB / %.2f GB • OOM kills since boot: %d": "Swap: %.2f GB / %.2f GB • OOM-Kills seit dem Start: %d",
+		"Processes by Swap Usage":                            "Prozesse nach Swap-Nutzung",
+		"No process has swapped out memory":                  "Kein Prozess hat Speicher ausgelagert",
+		"Highest OOM Scores":                                 "Höchste OOM-Werte",
+		"Recent OOM Kills":                                   "Letzte OOM-Kills",
+		"No OOM kills in the kernel log":                     "Keine OOM-Kills im Kernel-Log",
+		"system":                                             "System",
+		"cgroup":                                             "Cgroup",
+		"Disk I/O":                                           "Datenträger-I/O",
+		"Read: %s/s • Write: %s/s":                           "Lesen: %s/s • Schreiben: %s/s",
+		"Read:":                                              "Lesen:",
+		"Write:":                                             "Schreiben:",
+		"%d processes doing I/O • sorted by %s (%s)": "%d Prozesse mit I/O • sortiert nach %s (%s)",
+
+		// Processes
+		"Process List": "Prozessliste",
+		"Total: %d | Running: %d | Sleeping: %d | Zombie: %d | %s | Sort: %s %s": "Gesamt: %d | Laufend: %d | Schlafend: %d | Zombie: %d | %s | Sortierung: %s %s",
+		"PSS/USS: slower refresh (M)":                                            "PSS/USS: langsamere Aktualisierung (M)",
+		"Exec activity: %.1f forks/s":                                            "Exec-Aktivität: %.1f Forks/s",
+		" | %.1f execs/s | %d short-lived recently":                              " | %.1f Execs/s | %d kurzlebige zuletzt",
+		"🔒 execs: %s":        "🔒 Execs: %s",
+		"↻ %d crash looping": "↻ %d in Absturzschleife",
+		"🔒 Listing only the %d processes you may see. %s; %s": "🔒 Nur die %d Prozesse, die Sie sehen dürfen. %s; %s",
+		"Filter: %s • %d of %d match • /: edit • Esc: clear":  "Filter: %s • %d von %d passen • /: bearbeiten • Esc: löschen",
+		"Crash Loops":                  "Absturzschleifen",
+		"exit %d":                      "Exit %d",
+		"signal %d":                    "Signal %d",
+		"Recent Short-Lived Processes": "Kürzlich beendete kurzlebige Prozesse",
+		"▲ More content above":         "▲ Weiterer Inhalt oben",
+		"▼ More content below":         "▼ Weiterer Inhalt unten",
+
+		// Pods and VMs
+		"Kubernetes Pods":              "Kubernetes-Pods",
+		"Pods:":                        "Pods:",
+		"QoS:":                         "QoS:",
+		"%s %s (request %s, limit %s)": "%s %s (Anforderung %s, Limit %s)",
+		"%s %s (limit %s)":             "%s %s (Limit %s)",
+		"Virtual Machines":             "Virtuelle Maschinen",
+		"Running:":                     "Laufend:",
+		"vCPUs:":                       "vCPUs:",
+		"%s resident of %s allocated":  "%s resident von %s zugewiesen",
+		"%s %s/s read, %s/s write":     "%s %s/s gelesen, %s/s geschrieben",
+		"%s %s/s rx, %s/s tx (%s)":     "%s %s/s empfangen, %s/s gesendet (%s)",
+
+		// Network
+		"Network Interfaces": "Netzwerkschnittstellen",
+		"Total RX:":          "Empfangen gesamt:",
+		"Total TX:":          "Gesendet gesamt:",
+		"IP Traffic (all interfaces, including loopback)": "IP-Verkehr (alle Schnittstellen, einschließlich Loopback)",
+		"%s RX %.1f MB / TX %.1f MB (%d / %d packets)":    "%s RX %.1f MB / TX %.1f MB (%d / %d Pakete)",
+		"%s %.1f%% of IP traffic":                         "%s %.1f %% des IP-Verkehrs",
+		"IPv6 share:":                                     "IPv6-Anteil:",
+		"Interface: %s":                                   "Schnittstelle: %s",
+		"Status:":                                         "Status:",
+		"Speed:":                                          "Geschwindigkeit:",
+		"RX Packets:":                                     "Empfangene Pakete:",
+		"TX Packets:":                                     "Gesendete Pakete:",
+		"IPv4 & other:":                                   "IPv4 & andere:",
+		"Global IPv6:":                                    "Globale IPv6:",
+		"Protocol Health":                                 "Protokollzustand",
+		"%s %s (%d total)":                                "%s %s (%d gesamt)",
+		"%s %s (%.1f/s of %.1f segments/s, %d total)": "%s %s (%.1f/s von %.1f Segmenten/s, %d gesamt)",
+		"%s %.1f/s (%d total)":                        "%s %.1f/s (%d gesamt)",
+		"TCP retransmits:":                            "TCP-Neuübertragungen:",
+		"TCP receive errors:":                         "TCP-Empfangsfehler:",
+		"TCP resets sent:":                            "Gesendete TCP-Resets:",
+		"Listen overflows:":                           "Listen-Überläufe:",
+		"Listen drops:":                               "Listen-Verwerfungen:",
+		"IP input discards:":                          "Verworfene IP-Eingänge:",
+		"IP output discards:":                         "Verworfene IP-Ausgänge:",
+		"IP header errors:":                           "IP-Headerfehler:",
+		"Sockets & Buffers":                           "Sockets & Puffer",
+		"%s %d (TCP %d in use, %d orphaned, %d time-wait • UDP %d in use)": "%s %d (TCP %d belegt, %d verwaist, %d Time-Wait • UDP %d belegt)",
+		"Sockets:":                       "Sockets:",
+		"%s %s (pressure at %s, max %s)": "%s %s (Druck ab %s, max. %s)",
+		"TCP buffer memory:":             "TCP-Pufferspeicher:",
+		"UDP buffer memory:":             "UDP-Pufferspeicher:",
+		"UDP receive buffer errors:":     "UDP-Empfangspufferfehler:",
+		"UDP send buffer errors:":        "UDP-Sendepufferfehler:",
+		"UDP input errors:":              "UDP-Eingangsfehler:",
+		"UDP to closed ports:":           "UDP an geschlossene Ports:",
+		"⚠ UDP receive buffers are overflowing: datagrams are dropped, raise net.core.rmem_max or the socket's SO_RCVBUF": "⚠ UDP-Empfangspuffer laufen über: Datagramme werden verworfen, net.core.rmem_max oder SO_RCVBUF des Sockets erhöhen",
+		"⚠ UDP send buffers are full: raise net.core.wmem_max or the socket's SO_SNDBUF":                                  "⚠ UDP-Sendepuffer sind voll: net.core.wmem_max oder SO_SNDBUF des Sockets erhöhen",
+		"⚠ TCP memory is under pressure, the kernel is shrinking socket buffers":                                          "⚠ TCP-Speicher unter Druck, der Kernel verkleinert Socket-Puffer",
+		"⚠ UDP memory is under pressure, the kernel is shrinking socket buffers":                                          "⚠ UDP-Speicher unter Druck, der Kernel verkleinert Socket-Puffer",
+		"Neighbors (ARP/NDP)": "Nachbarn (ARP/NDP)",
+		"No neighbor entries": "Keine Nachbareinträge",
+		"(router)":            "(Router)",
+		"DNS Resolvers":       "DNS-Resolver",
+		"No nameserver configured in /etc/resolv.conf": "Kein Nameserver in /etc/resolv.conf konfiguriert",
+		"Source:":               "Quelle:",
+		"Search:":               "Suche:",
+		"not tested":            "nicht getestet",
+		"%s for %s (%s)":        "%s für %s (%s)",
+		"d: test resolvers now": "d: Resolver jetzt testen",
+		"Top Talkers":           "Größte Gegenstellen",
+
+		// Disk
+		"Disk Usage": "Datenträgerbelegung",
+		"WSL 2: / is the distribution's virtual disk (ext4.vhdx), sized to its maximum rather than": "WSL 2: / ist die virtuelle Festplatte der Distribution (ext4.vhdx), mit ihrer Maximalgröße statt",
+		"the Windows drive holding it; Windows drives are mounted under /mnt through 9p":            "der des Windows-Laufwerks, auf dem sie liegt; Windows-Laufwerke sind über 9p unter /mnt eingehängt",
+		"⚠ stalled, not responding (last known sizes)":                                              "⚠ hängt, antwortet nicht (zuletzt bekannte Größen)",
+		"Filesystem:": "Dateisystem:",
+
+		// Battery
+		"Battery Information":          "Akkuinformationen",
+		"Level:":                       "Ladestand:",
+		"Time Left:":                   "Restzeit:",
+		"Health:":                      "Zustand:",
+		"Charging:":                    "Lädt:",
+		"Power Draw":                   "Leistungsaufnahme",
+		"Power":                        "Energie",
+		"Backlight:":                   "Hintergrundbeleuchtung:",
+		"N/A":                          "n. v.",
+		"Power Profile:":               "Energieprofil:",
+		"%s %s (of %s) • p: switch":    "%s %s (von %s) • p: wechseln",
+		"Sleep Inhibitors":             "Ruhezustand-Blockaden",
+		"None (or logind unavailable)": "Keine (oder logind nicht verfügbar)",
+		"%d lock(s) currently block sleep or idle": "%d Sperre(n) blockieren gerade Ruhezustand oder Leerlauf",
+		"Peripherals": "Peripheriegeräte",
+		"The battery does not report its charge or discharge rate": "Der Akku meldet seine Lade- oder Entladerate nicht",
+		"discharging": "entlädt",
+		"charging":    "lädt",
+		"Power:":      "Leistung:",
+		"%s %s %s (min %.1f W, max %.1f W) • %s %.1f%% (right axis)": "%s %s %s (min. %.1f W, max. %.1f W) • %s %.1f %% (rechte Achse)",
+
+		// Kernel log, Security and Alerts
+		"Kernel Log": "Kernel-Log",
+		"Showing %s and above • %s • v: severity • f: follow":     "Zeigt %s und höher • %s • v: Schweregrad • f: folgen",
+		"No kernel messages at this severity":                     "Keine Kernel-Meldungen mit diesem Schweregrad",
+		"Failed SSH Logins (last 24h)":                            "Fehlgeschlagene SSH-Anmeldungen (letzte 24 h)",
+		"Neither the journal nor /var/log/auth.log is readable":   "Weder das Journal noch /var/log/auth.log ist lesbar",
+		"None found in %s":                                        "Keine in %s gefunden",
+		"%d attempts from %d sources (%s)":                        "%d Versuche von %d Quellen (%s)",
+		"Listening Services":                                      "Lauschende Dienste",
+		"Sudo Sessions":                                           "Sudo-Sitzungen",
+		"No active sudo sessions":                                 "Keine aktiven Sudo-Sitzungen",
+		"running":                                                 "läuft",
+		"cached":                                                  "gemerkt",
+		"authenticated at %s":                                     "authentifiziert um %s",
+		"Alert History":                                           "Alarmverlauf",
+		"%d rule(s) • t: edit thresholds":                         "%d Regel(n) • t: Schwellen bearbeiten",
+		"No alerts fired this session":                            "In dieser Sitzung keine Alarme ausgelöst",
+		"%d event(s) this session • %d firing":                    "%d Ereignis(se) in dieser Sitzung • %d aktiv",
+		"⚠ Alert history not read: %v":                            "⚠ Alarmverlauf nicht gelesen: %v",
+		"No alerts fired this session or recorded in the history": "Keine Alarme in dieser Sitzung ausgelöst oder im Verlauf gespeichert",
+		"%d event(s) this session • %d recorded in the history • %d firing": "%d Ereignis(se) in dieser Sitzung • %d im Verlauf gespeichert • %d aktiv",
+	})
+}
@@ -0,0 +1,88 @@
+// Package i18n translates user-facing strings and formats numbers for the
+// selected locale.
+//
+// Messages are keyed by their English format string. A translation is a map
+// from those keys to translated format strings, registered from a file per
+// locale (see de.go); messages missing from it fall back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// printer formats for the selected locale. SetLocale swaps it on a config
+// reload while commands of the UI format on other goroutines.
+var printer atomic.Pointer[message.Printer]
+
+func init() {
+	printer.Store(message.NewPrinter(language.English))
+}
+
+// register adds the translations of a locale to the catalog
+func register(tag language.Tag, messages map[string]string) {
+	for key, translation := range messages {
+		message.SetString(tag, key, translation)
+	}
+}
+
+// SetLocale selects the locale, e.g. "de" or "de_DE.UTF-8". An empty locale is
+// taken from LC_ALL, LC_MESSAGES or LANG, where the C locale or one that cannot
+// be parsed means English.
+func SetLocale(locale string) error {
+	fromEnv := locale == ""
+	if fromEnv {
+		locale = localeFromEnv()
+	}
+
+	tag := language.English
+	if name := normalize(locale); name != "" {
+		parsed, err := language.Parse(name)
+		if err != nil && !fromEnv {
+			return fmt.Errorf("unknown locale %q", locale)
+		}
+		if err == nil {
+			tag = parsed
+		}
+	}
+
+	printer.Store(message.NewPrinter(tag))
+	return nil
+}
+
+func localeFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalize turns a POSIX locale such as "de_DE.UTF-8@euro" into a BCP 47
+// tag ("de-DE"), and the C and POSIX locales into ""
+func normalize(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	if locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	return strings.ReplaceAll(locale, "_", "-")
+}
+
+// T translates a message without arguments
+func T(key string) string {
+	return printer.Load().Sprintf(key)
+}
+
+// Sprintf translates a format string and formats its arguments for the
+// locale, including digit grouping and decimal separators. Identifiers such
+// as PIDs should be formatted with fmt instead, as they must not be grouped.
+func Sprintf(key string, args ...any) string {
+	return printer.Load().Sprintf(key, args...)
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// MaxSize is the size at which the log is rotated on startup, keeping one
+// previous file with a ".1" suffix
+const MaxSize = 5 << 20
+
+// DefaultPath returns $XDG_STATE_HOME/croptop/croptop.log
+func DefaultPath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(os.TempDir(), "croptop.log")
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "croptop", "croptop.log")
+}
+
+// Setup makes the log file at path the destination of the default slog
+// logger. Stdout and stderr belong to the TUI, so the logger never writes
+// there; if the file cannot be opened logging is discarded.
+func Setup(path string, level slog.Level) (io.Closer, error) {
+	file, err := open(path)
+	if err != nil {
+		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		return nil, err
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: level})))
+	return file, nil
+}
+
+func open(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > MaxSize {
+		os.Rename(path, path+".1")
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+}
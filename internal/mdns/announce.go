@@ -0,0 +1,161 @@
+package mdns
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+)
+
+// legacyTTL caps the TTL of answers to plain DNS resolvers, which query
+// from a port other than 5353 and cannot be told when records change
+const legacyTTL = 10
+
+// Responder answers the queries for one agent
+type Responder struct {
+	instance string // e.g. "nas._croptop._tcp.local."
+	host     string // e.g. "nas.local."
+	port     uint16
+	tls      bool
+	// Announced addresses; every non-loopback IPv4 address when empty
+	ips []net.IP
+}
+
+// NewResponder returns the responder of an agent named name, usually the
+// hostname, serving on port, over TLS if tls is set
+func NewResponder(name string, port int, tls bool, ips []net.IP) *Responder {
+	// The short hostname, dots would split the name into labels
+	label, _, _ := strings.Cut(name, ".")
+	if label == "" {
+		label = "croptop"
+	}
+	return &Responder{
+		instance: label + "." + ServiceType,
+		host:     label + ".local.",
+		port:     uint16(port),
+		tls:      tls,
+		ips:      ips,
+	}
+}
+
+// Run announces the agent, answers queries until ctx is done and then says
+// goodbye, so browsers forget it right away
+func (r *Responder) Run(ctx context.Context) error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, groupAddr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		// TTL 0 withdraws the records
+		conn.WriteToUDP(message{response: true, records: r.records(0)}.encode(), groupAddr)
+		conn.Close()
+	}()
+
+	// Announced twice, a second apart, as RFC 6762 asks
+	go func() {
+		for range 2 {
+			if _, err := conn.WriteToUDP(message{response: true, records: r.records(recordTTL)}.encode(), groupAddr); err != nil {
+				slog.Debug("mDNS announcement failed", "err", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+
+	buf := make([]byte, 9000)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		query, err := decode(buf[:n])
+		if err != nil || query.response {
+			continue
+		}
+		answers := r.answer(query.questions)
+		if len(answers) == 0 {
+			continue
+		}
+
+		reply := message{response: true, records: answers}
+		dst := groupAddr
+		if src.Port != groupAddr.Port {
+			// A plain resolver gets its question back, unicast
+			reply.id = query.id
+			reply.questions = query.questions
+			for i := range reply.records {
+				reply.records[i].ttl = min(reply.records[i].ttl, legacyTTL)
+			}
+			dst = src
+		}
+		if _, err := conn.WriteToUDP(reply.encode(), dst); err != nil {
+			slog.Debug("mDNS reply failed", "to", dst, "err", err)
+		}
+	}
+}
+
+// answer returns the records asked for by questions, if any are ours
+func (r *Responder) answer(questions []question) []record {
+	all := r.records(recordTTL)
+	var answers []record
+	for _, q := range questions {
+		switch {
+		case sameName(q.name, ServiceType) && (q.qtype == typePTR || q.qtype == typeANY),
+			sameName(q.name, r.instance) && (q.qtype == typeSRV || q.qtype == typeTXT || q.qtype == typeANY):
+			return all
+		case sameName(q.name, r.host) && (q.qtype == typeA || q.qtype == typeANY):
+			for _, record := range all {
+				if record.rtype == typeA {
+					answers = append(answers, record)
+				}
+			}
+		}
+	}
+	return answers
+}
+
+// records are the PTR, SRV, TXT and A records of the agent
+func (r *Responder) records(ttl uint32) []record {
+	// The path of the snapshot the Hosts tab polls, and how to reach it
+	txt := []string{"path=/api/v1/snapshot"}
+	if r.tls {
+		txt = append(txt, "tls=1")
+	}
+	records := []record{
+		{name: ServiceType, rtype: typePTR, ttl: ttl, target: r.instance},
+		{name: r.instance, rtype: typeSRV, ttl: ttl, target: r.host, port: r.port},
+		{name: r.instance, rtype: typeTXT, ttl: ttl, txt: txt},
+	}
+	for _, ip := range r.addresses() {
+		records = append(records, record{name: r.host, rtype: typeA, ttl: ttl, ip: ip})
+	}
+	return records
+}
+
+// addresses returns the IPv4 addresses to announce, read afresh each time
+// as DHCP may change them
+func (r *Responder) addresses() []net.IP {
+	if len(r.ips) > 0 {
+		return r.ips
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			ips = append(ips, ipnet.IP.To4())
+		}
+	}
+	return ips
+}
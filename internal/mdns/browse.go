@@ -0,0 +1,116 @@
+package mdns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service is an agent that answered a browse
+type Service struct {
+	// Name is the instance name, usually the agent's hostname
+	Name string
+	IP   net.IP
+	Port int
+	// TLS is set for agents serving HTTPS
+	TLS bool
+}
+
+// URL is the agent's serve address, as the Hosts tab takes it
+func (s Service) URL() string {
+	scheme := "http://"
+	if s.TLS {
+		scheme = "https://"
+	}
+	return scheme + net.JoinHostPort(s.IP.String(), strconv.Itoa(s.Port))
+}
+
+// Browse asks the LAN for agents and returns the ones that answer within
+// timeout, by name
+func Browse(ctx context.Context, timeout time.Duration) ([]Service, error) {
+	// From a port other than 5353 the answers come back unicast
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	query := message{questions: []question{{name: ServiceType, qtype: typePTR}}}.encode()
+	// Asked twice in case the first query is lost
+	go func() {
+		for range 2 {
+			conn.WriteToUDP(query, groupAddr)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(timeout / 3):
+			}
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	var (
+		instances []string
+		srv       = make(map[string]record)
+		txt       = make(map[string][]string)
+		addresses = make(map[string]net.IP)
+		// Where each instance answered from, for agents without A records
+		sources = make(map[string]net.IP)
+	)
+	buf := make([]byte, 9000)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break
+		}
+		msg, err := decode(buf[:n])
+		if err != nil || !msg.response {
+			continue
+		}
+		for _, r := range msg.records {
+			switch {
+			case r.rtype == typePTR && sameName(r.name, ServiceType) && r.ttl > 0:
+				if !slices.ContainsFunc(instances, func(name string) bool { return sameName(name, r.target) }) {
+					instances = append(instances, r.target)
+				}
+				sources[strings.ToLower(r.target)] = src.IP
+			case r.rtype == typeSRV:
+				srv[strings.ToLower(r.name)] = r
+			case r.rtype == typeTXT:
+				txt[strings.ToLower(r.name)] = r.txt
+			case r.rtype == typeA:
+				addresses[strings.ToLower(r.name)] = r.ip
+			}
+		}
+	}
+	// Running into the timeout is how a browse ends
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil, ctx.Err()
+	}
+
+	var services []Service
+	for _, instance := range instances {
+		key := strings.ToLower(instance)
+		service, ok := srv[key]
+		if !ok {
+			continue
+		}
+		ip := addresses[strings.ToLower(service.target)]
+		if ip == nil {
+			ip = sources[key]
+		}
+		name, _, _ := strings.Cut(instance, ".")
+		services = append(services, Service{Name: name, IP: ip, Port: int(service.port), TLS: slices.Contains(txt[key], "tls=1")})
+	}
+	slices.SortFunc(services, func(a, b Service) int { return strings.Compare(a.Name, b.Name) })
+	return services, nil
+}
@@ -0,0 +1,236 @@
+// Package mdns lets croptop agents announce themselves on the LAN with
+// multicast DNS service discovery (RFC 6762 and 6763) and lets the TUI find
+// them, so adding a host to the Hosts tab needs no typing. It speaks just
+// enough of DNS for one service type: PTR, SRV, TXT and A records.
+package mdns
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+)
+
+// ServiceType is what agents register as, e.g. "nas._croptop._tcp.local."
+const ServiceType = "_croptop._tcp.local."
+
+// Port and group of multicast DNS
+var groupAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// Record types and classes
+const (
+	typeA   = 1
+	typePTR = 12
+	typeTXT = 16
+	typeSRV = 33
+	typeANY = 255
+
+	classIN = 1
+	// The top bit of a question's class asks for a unicast response, of a
+	// record's class that it replaces the cached ones
+	classUnicast = 0x8000
+)
+
+// recordTTL is how long records stay in caches, the RFC's 120 seconds for
+// records naming a host
+const recordTTL = 120
+
+type question struct {
+	name  string
+	qtype uint16
+}
+
+// record is a resource record. Only the fields of its type are set.
+type record struct {
+	name   string
+	rtype  uint16
+	ttl    uint32
+	target string // PTR and SRV
+	port   uint16 // SRV
+	txt    []string
+	ip     net.IP // A
+}
+
+type message struct {
+	id        uint16
+	response  bool
+	questions []question
+	records   []record
+}
+
+var errMalformed = errors.New("malformed DNS message")
+
+// encode builds the wire form of msg, without name compression; all
+// records go in the answer section
+func (msg message) encode() []byte {
+	buf := make([]byte, 12, 512)
+	binary.BigEndian.PutUint16(buf[0:], msg.id)
+	if msg.response {
+		// Authoritative answer
+		binary.BigEndian.PutUint16(buf[2:], 0x8400)
+	}
+	binary.BigEndian.PutUint16(buf[4:], uint16(len(msg.questions)))
+	binary.BigEndian.PutUint16(buf[6:], uint16(len(msg.records)))
+
+	for _, q := range msg.questions {
+		buf = appendName(buf, q.name)
+		buf = binary.BigEndian.AppendUint16(buf, q.qtype)
+		buf = binary.BigEndian.AppendUint16(buf, classIN|classUnicast)
+	}
+	for _, r := range msg.records {
+		buf = appendName(buf, r.name)
+		buf = binary.BigEndian.AppendUint16(buf, r.rtype)
+		buf = binary.BigEndian.AppendUint16(buf, classIN)
+		buf = binary.BigEndian.AppendUint32(buf, r.ttl)
+
+		var data []byte
+		switch r.rtype {
+		case typePTR:
+			data = appendName(nil, r.target)
+		case typeSRV:
+			// Priority and weight, unused with one instance per host
+			data = binary.BigEndian.AppendUint32(nil, 0)
+			data = binary.BigEndian.AppendUint16(data, r.port)
+			data = appendName(data, r.target)
+		case typeTXT:
+			for _, entry := range r.txt {
+				data = append(data, byte(len(entry)))
+				data = append(data, entry...)
+			}
+		case typeA:
+			data = r.ip.To4()
+		}
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf
+}
+
+// appendName appends a dotted name such as "nas.local." as labels
+func appendName(buf []byte, name string) []byte {
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" {
+			continue
+		}
+		label = label[:min(len(label), 63)]
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, label...)
+	}
+	return append(buf, 0)
+}
+
+// decode parses a DNS message, reading the answer, authority and additional
+// sections alike. Records of other types are skipped.
+func decode(buf []byte) (message, error) {
+	var msg message
+	if len(buf) < 12 {
+		return msg, errMalformed
+	}
+	msg.id = binary.BigEndian.Uint16(buf[0:])
+	msg.response = buf[2]&0x80 != 0
+	questions := int(binary.BigEndian.Uint16(buf[4:]))
+	records := int(binary.BigEndian.Uint16(buf[6:])) + int(binary.BigEndian.Uint16(buf[8:])) + int(binary.BigEndian.Uint16(buf[10:]))
+
+	offset := 12
+	for range questions {
+		name, next, err := readName(buf, offset)
+		if err != nil || next+4 > len(buf) {
+			return msg, errMalformed
+		}
+		msg.questions = append(msg.questions, question{name: name, qtype: binary.BigEndian.Uint16(buf[next:])})
+		offset = next + 4
+	}
+
+	for range records {
+		name, next, err := readName(buf, offset)
+		if err != nil || next+10 > len(buf) {
+			return msg, errMalformed
+		}
+		r := record{
+			name:  name,
+			rtype: binary.BigEndian.Uint16(buf[next:]),
+			ttl:   binary.BigEndian.Uint32(buf[next+4:]),
+		}
+		start := next + 10
+		end := start + int(binary.BigEndian.Uint16(buf[next+8:]))
+		if end > len(buf) {
+			return msg, errMalformed
+		}
+		data := buf[start:end]
+		offset = end
+
+		switch r.rtype {
+		case typePTR:
+			r.target, _, err = readName(buf, start)
+		case typeSRV:
+			if len(data) < 7 {
+				return msg, errMalformed
+			}
+			r.port = binary.BigEndian.Uint16(data[4:])
+			r.target, _, err = readName(buf, start+6)
+		case typeTXT:
+			for len(data) > 0 {
+				size := int(data[0])
+				if 1+size > len(data) {
+					return msg, errMalformed
+				}
+				r.txt = append(r.txt, string(data[1:1+size]))
+				data = data[1+size:]
+			}
+		case typeA:
+			if len(data) != 4 {
+				return msg, errMalformed
+			}
+			r.ip = net.IP(append([]byte(nil), data...))
+		default:
+			continue
+		}
+		if err != nil {
+			return msg, err
+		}
+		msg.records = append(msg.records, r)
+	}
+	return msg, nil
+}
+
+// readName reads the name at offset, following compression pointers, and
+// returns it with the offset after it
+func readName(buf []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	// More jumps than that is a pointer loop
+	for jumps := 0; jumps < 32; {
+		if offset >= len(buf) {
+			return "", 0, errMalformed
+		}
+		size := int(buf[offset])
+		switch {
+		case size == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, ".") + ".", next, nil
+		case size&0xC0 == 0xC0:
+			if offset+1 >= len(buf) {
+				return "", 0, errMalformed
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(buf[offset:]) & 0x3FFF)
+			jumps++
+		default:
+			if offset+1+size > len(buf) {
+				return "", 0, errMalformed
+			}
+			labels = append(labels, string(buf[offset+1:offset+1+size]))
+			offset += 1 + size
+		}
+	}
+	return "", 0, errMalformed
+}
+
+// sameName compares DNS names, which are case-insensitive
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
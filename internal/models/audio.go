@@ -0,0 +1,38 @@
+package models
+
+// AudioStats describes the sound server: its processes' load, the graph
+// timing and the streams playing or recording
+type AudioStats struct {
+	// PipeWire or PulseAudio, empty when neither runs
+	Server string `json:"server"`
+	// Processes of the sound server, e.g. pipewire, pipewire-pulse and
+	// wireplumber
+	Processes []AudioProcess `json:"processes"`
+	// Rate the server runs at in Hz, and the quantum, the frames processed
+	// per cycle (PipeWire only). A quantum shorter than the load allows is
+	// what makes audio crackle.
+	SampleRate int           `json:"sample_rate"`
+	Quantum    int           `json:"quantum"`
+	Streams    []AudioStream `json:"streams"`
+}
+
+type AudioProcess struct {
+	PID        int     `json:"pid"`
+	Name       string  `json:"name"`
+	CPUPercent float64 `json:"cpu_percent"`
+}
+
+// AudioStream is an application playing or recording
+type AudioStream struct {
+	Application string `json:"application"`
+	Media       string `json:"media"`
+	PID         int    `json:"pid"`
+	Process     string `json:"process"`
+	Capture     bool   `json:"capture"`
+	// running, idle or suspended on PipeWire, corked or running on PulseAudio
+	State      string `json:"state"`
+	SampleRate int    `json:"sample_rate"`
+	Channels   int    `json:"channels"`
+	// Latency the stream asked for, e.g. "256/48000" on PipeWire or "40ms"
+	Latency string `json:"latency"`
+}
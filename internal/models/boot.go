@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// BootStats is how long the last boot took according to systemd-analyze
+type BootStats struct {
+	// Error explains why the boot could not be analyzed, e.g. while it is
+	// still in progress
+	Error string `json:"error,omitempty"`
+	// Phases of the boot in order (firmware, loader, kernel, initrd,
+	// userspace); firmware and loader are only known on EFI machines
+	Phases []BootPhase    `json:"phases"`
+	Total  time.Duration  `json:"total"`
+	Units  []BootUnit     `json:"units"`
+	Chain  []CriticalUnit `json:"chain"`
+}
+
+// BootPhase is a stage of the boot and how long it took
+type BootPhase struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// BootUnit is a unit started during boot, slowest first, with what its
+// cgroup uses now
+type BootUnit struct {
+	Name      string        `json:"name"`
+	StartTime time.Duration `json:"start_time"`
+	// Running is false for units without a cgroup, such as mounts and
+	// services that exited
+	Running    bool    `json:"running"`
+	CPUPercent float64 `json:"cpu_percent"`
+	MemoryUsed float64 `json:"memory_used"` // KB
+	// Nil without cgroup v2 pressure information
+	Pressure *CgroupPressure `json:"pressure,omitempty"`
+}
+
+// CriticalUnit is a unit on the critical chain, the units the boot waited
+// for in turn
+type CriticalUnit struct {
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+	// Active is when the unit became active after userspace started, Took
+	// how long it took to start (0 when it started at once)
+	Active time.Duration `json:"active"`
+	Took   time.Duration `json:"took"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// Container is a Docker or Podman container as listed by its runtime's API
+type Container struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// State is running, exited, paused, restarting..., Status says since
+	// when, e.g. "Up 3 hours"
+	State  string `json:"state"`
+	Status string `json:"status"`
+	// Nil for containers that are not running and without cgroup v2
+	// pressure information
+	Pressure *CgroupPressure `json:"pressure,omitempty"`
+}
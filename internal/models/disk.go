@@ -12,4 +12,7 @@ type DiskStats struct {
 	WriteBytes   uint64  `json:"write_bytes"`
 	ReadOps      uint64  `json:"read_ops"`
 	WriteOps     uint64  `json:"write_ops"`
+	// Stalled is set when statfs did not answer in time, typically a network
+	// mount whose server is gone; the sizes are the last known ones
+	Stalled bool `json:"stalled"`
 }
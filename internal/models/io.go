@@ -0,0 +1,19 @@
+package models
+
+// IOStats holds system-wide block device throughput
+type IOStats struct {
+	ReadRate  float64    `json:"read_rate"`
+	WriteRate float64    `json:"write_rate"`
+	Devices   []DeviceIO `json:"devices"`
+}
+
+// DeviceIO is the activity of one whole block device over the last interval
+type DeviceIO struct {
+	Name        string  `json:"name"`
+	ReadRate    float64 `json:"read_rate"`
+	WriteRate   float64 `json:"write_rate"`
+	ReadIOPS    float64 `json:"read_iops"`
+	WriteIOPS   float64 `json:"write_iops"`
+	Utilization float64 `json:"utilization"` // percent of time the device was busy
+	InFlight    uint64  `json:"in_flight"`
+}
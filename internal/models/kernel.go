@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// KernelMessage is one record of the kernel ring buffer
+type KernelMessage struct {
+	Time     time.Time `json:"time"`
+	Priority int       `json:"priority"` // syslog level, 0 (emerg) to 7 (debug)
+	Message  string    `json:"message"`
+}
+
+// KernelLevelNames are the syslog level names indexed by priority
+var KernelLevelNames = []string{"emerg", "alert", "crit", "err", "warn", "notice", "info", "debug"}
@@ -0,0 +1,25 @@
+package models
+
+// PodStats holds the resource usage of a Kubernetes pod read from its cgroup.
+// CPU figures are in cores and memory figures in KB; zero means unset.
+type PodStats struct {
+	Namespace   string           `json:"namespace"`
+	Name        string           `json:"name"`
+	UID         string           `json:"uid"`
+	QoSClass    string           `json:"qos_class"`
+	CPUUsage    float64          `json:"cpu_usage"`
+	CPURequest  float64          `json:"cpu_request"`
+	CPULimit    float64          `json:"cpu_limit"`
+	MemoryUsed  float64          `json:"memory_used"`
+	MemoryLimit float64          `json:"memory_limit"`
+	Containers  []ContainerStats `json:"containers"`
+}
+
+type ContainerStats struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	CPUUsage    float64 `json:"cpu_usage"`
+	CPULimit    float64 `json:"cpu_limit"`
+	MemoryUsed  float64 `json:"memory_used"`
+	MemoryLimit float64 `json:"memory_limit"`
+}
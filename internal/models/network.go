@@ -1,17 +1,158 @@
 package models
 
+import "time"
+
 type NetworkStats struct {
 	Interfaces []NetworkInterface `json:"interfaces"`
 	TotalRx    uint64             `json:"total_rx"`
 	TotalTx    uint64             `json:"total_tx"`
+	RxRate     float64            `json:"rx_rate"`
+	TxRate     float64            `json:"tx_rate"`
+	IPv4       IPTraffic          `json:"ipv4"`
+	IPv6       IPTraffic          `json:"ipv6"`
+	Protocol   ProtocolStats      `json:"protocol"`
+	Sockets    SocketStats        `json:"sockets"`
+	// Talkers are the busiest remote endpoints while traffic is captured
+	Talkers []Talker `json:"talkers,omitempty"`
 }
 
-type NetworkInterface struct {
-	Name      string `json:"name"`
+// Talker is the traffic exchanged with a remote host on one service port,
+// the lower of both ports, in bytes per second of IP packets. Port is 0
+// for protocols without ports.
+type Talker struct {
+	Host     string  `json:"host"`
+	Port     int     `json:"port"`
+	Protocol string  `json:"protocol"`
+	RxRate   float64 `json:"rx_rate"`
+	TxRate   float64 `json:"tx_rate"`
+}
+
+// Counter is a cumulative kernel counter and its per-second rate
+type Counter struct {
+	Total uint64  `json:"total"`
+	Rate  float64 `json:"rate"`
+}
+
+// ProtocolStats holds the TCP/IP error counters of /proc/net/snmp and
+// /proc/net/netstat, the signals interface byte counters don't show
+type ProtocolStats struct {
+	TCPOutSegs      Counter `json:"tcp_out_segs"`
+	TCPRetransSegs  Counter `json:"tcp_retrans_segs"`
+	TCPInErrs       Counter `json:"tcp_in_errs"`
+	TCPOutRsts      Counter `json:"tcp_out_rsts"`
+	ListenOverflows Counter `json:"listen_overflows"`
+	ListenDrops     Counter `json:"listen_drops"`
+	IPInDiscards    Counter `json:"ip_in_discards"`
+	IPOutDiscards   Counter `json:"ip_out_discards"`
+	IPInHdrErrors   Counter `json:"ip_in_hdr_errors"`
+	// UDP counters cover both IPv4 and IPv6
+	UDPRcvbufErrors Counter `json:"udp_rcvbuf_errors"`
+	UDPSndbufErrors Counter `json:"udp_sndbuf_errors"`
+	UDPInErrors     Counter `json:"udp_in_errors"`
+	UDPNoPorts      Counter `json:"udp_no_ports"`
+	// Share of segments sent in the last interval that were retransmissions
+	RetransPercent float64 `json:"retrans_percent"`
+}
+
+// IPTraffic counts the IP level bytes and packets of one address family
+type IPTraffic struct {
 	RxBytes   uint64 `json:"rx_bytes"`
 	TxBytes   uint64 `json:"tx_bytes"`
 	RxPackets uint64 `json:"rx_packets"`
 	TxPackets uint64 `json:"tx_packets"`
-	Status    string `json:"status"`
-	Speed     string `json:"speed"`
+}
+
+type NetworkInterface struct {
+	Name      string  `json:"name"`
+	RxBytes   uint64  `json:"rx_bytes"`
+	TxBytes   uint64  `json:"tx_bytes"`
+	RxPackets uint64  `json:"rx_packets"`
+	TxPackets uint64  `json:"tx_packets"`
+	RxRate    float64 `json:"rx_rate"`
+	TxRate    float64 `json:"tx_rate"`
+	Status    string  `json:"status"`
+	Speed     string  `json:"speed"`
+	// The kernel only counts IPv6 per interface; the rest of the traffic is
+	// IPv4 plus link-layer overhead and non-IP protocols
+	IPv6       IPTraffic `json:"ipv6"`
+	GlobalIPv6 []string  `json:"global_ipv6"`
+}
+
+// SocketStats holds socket counts and kernel socket buffer memory from
+// /proc/net/sockstat. Memory values are in bytes; Pressure and Max are the
+// tcp_mem/udp_mem thresholds at which the kernel starts trimming buffers
+// and refuses new allocations.
+type SocketStats struct {
+	Used              int    `json:"used"`
+	TCPInUse          int    `json:"tcp_in_use"`
+	TCPOrphan         int    `json:"tcp_orphan"`
+	TCPTimeWait       int    `json:"tcp_time_wait"`
+	TCPMemory         uint64 `json:"tcp_memory"`
+	TCPMemoryPressure uint64 `json:"tcp_memory_pressure"`
+	TCPMemoryMax      uint64 `json:"tcp_memory_max"`
+	UDPInUse          int    `json:"udp_in_use"`
+	UDPMemory         uint64 `json:"udp_memory"`
+	UDPMemoryPressure uint64 `json:"udp_memory_pressure"`
+	UDPMemoryMax      uint64 `json:"udp_memory_max"`
+}
+
+// Neighbor is an entry of the ARP (IPv4) or NDP (IPv6) neighbor table
+type Neighbor struct {
+	IP        string `json:"ip"`
+	MAC       string `json:"mac"`
+	Interface string `json:"interface"`
+	State     string `json:"state"` // e.g. REACHABLE, STALE, FAILED
+	Router    bool   `json:"router"`
+}
+
+// DNSStats lists the resolvers the system is configured to use
+type DNSStats struct {
+	Resolvers     []string `json:"resolvers"`
+	Source        string   `json:"source"` // resolv.conf or systemd-resolved
+	SearchDomains []string `json:"search_domains"`
+}
+
+// DNSCheck is the result of a test lookup against one resolver
+type DNSCheck struct {
+	Resolver string        `json:"resolver"`
+	Latency  time.Duration `json:"latency"`
+	Error    string        `json:"error"`
+	Time     time.Time     `json:"time"`
+}
+
+// ServiceCheck is the result of a TCP connect to a service, Latency being
+// how long the handshake took
+type ServiceCheck struct {
+	Name    string        `json:"name"`
+	Address string        `json:"address"`
+	Up      bool          `json:"up"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error"`
+	Time    time.Time     `json:"time"`
+}
+
+// NetNamespace is a network namespace other than croptop's, such as a
+// container's, found through the processes in it
+type NetNamespace struct {
+	ID uint64 `json:"id"`
+	// Lowest PID in the namespace, whose /proc/[pid]/net shows it
+	PID       int    `json:"pid"`
+	Process   string `json:"process"`
+	Processes int    `json:"processes"`
+}
+
+// NamespaceNetwork is what a network namespace has of its own: its
+// interfaces, local addresses and sockets
+type NamespaceNetwork struct {
+	Interfaces  []NetworkInterface `json:"interfaces"`
+	Addresses   []string           `json:"addresses"`
+	Connections []Connection       `json:"connections"`
+}
+
+// Connection is a TCP or UDP socket of /proc/net/{tcp,udp}{,6}
+type Connection struct {
+	Protocol string `json:"protocol"`
+	Local    string `json:"local"`
+	Remote   string `json:"remote"`
+	State    string `json:"state"`
 }
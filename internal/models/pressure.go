@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// MemoryPressure gathers swap users, likely OOM victims and past OOM kills
+type MemoryPressure struct {
+	SwapProcesses  []Process  `json:"swap_processes"`
+	OOMCandidates  []OOMScore `json:"oom_candidates"`
+	OOMKills       []OOMKill  `json:"oom_kills"`
+	OOMKillCount   uint64     `json:"oom_kill_count"`   // since boot, from /proc/vmstat
+	KernelLogError string     `json:"kernel_log_error"` // why OOM kills could not be listed
+}
+
+// CgroupPressure is the pressure stall information of a cgroup: the share
+// of the last 10 seconds, in percent, some of its tasks waited for a
+// resource, which says which unit suffers under contention
+type CgroupPressure struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+	IO     float64 `json:"io"`
+}
+
+type OOMScore struct {
+	PID      int    `json:"pid"`
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	ScoreAdj int    `json:"score_adj"`
+	MemRSS   uint64 `json:"mem_rss"`
+}
+
+// OOMKill is a process killed by the kernel OOM killer
+type OOMKill struct {
+	Time    time.Time `json:"time"`
+	PID     int       `json:"pid"`
+	Name    string    `json:"name"`
+	AnonRSS uint64    `json:"anon_rss"` // KB
+	FileRSS uint64    `json:"file_rss"` // KB
+	Cgroup  bool      `json:"cgroup"`   // killed by a memory cgroup limit
+}
@@ -0,0 +1,20 @@
+package models
+
+// Features that need more permissions than an ordinary user has
+const (
+	FeatureProcessIO    = "process_io"
+	FeatureKernelLog    = "kernel_log"
+	FeatureRAPL         = "rapl"
+	FeatureExecEvents   = "exec_events"
+	FeatureSlabinfo     = "slabinfo"
+	FeatureAllProcesses = "all_processes"
+)
+
+// Privilege tells whether croptop may use a feature, and how to unlock it
+// when it may not
+type Privilege struct {
+	Feature     string `json:"feature"`
+	Description string `json:"description"`
+	Available   bool   `json:"available"`
+	Hint        string `json:"hint,omitempty"`
+}
@@ -1,16 +1,119 @@
 package models
 
+import "time"
+
 type Process struct {
 	PID        int     `json:"pid"`
+	PPID       int     `json:"ppid"`
 	Name       string  `json:"name"`
 	Command    string  `json:"command"`
-	CPUPercent float64 `json:"cpu_percent"`
+	CPUPercent float64 `json:"cpu_percent"` // of one core, may exceed 100
 	MemPercent float64 `json:"mem_percent"`
 	MemRSS     uint64  `json:"mem_rss"`
-	Status     string  `json:"status"`
-	User       string  `json:"user"`
-	Runtime    string  `json:"runtime"`
-	Priority   int     `json:"priority"`
+	Swap       uint64  `json:"swap"` // KB swapped out
+	// RSS change in KB over the last collector.GrowthWindow, or since
+	// croptop first saw the process
+	RSSGrowth int64 `json:"rss_growth"`
+	// Proportional and unique set size in KB, only in accurate memory mode
+	PSS        uint64        `json:"pss,omitempty"`
+	USS        uint64        `json:"uss,omitempty"`
+	Status     string        `json:"status"`
+	User       string        `json:"user"`
+	Runtime    time.Duration `json:"runtime"` // since the process started
+	Priority   int           `json:"priority"`
+	ReadBytes  uint64        `json:"read_bytes"`
+	WriteBytes uint64        `json:"write_bytes"`
+	ReadRate   float64       `json:"read_rate"`
+	WriteRate  float64       `json:"write_rate"`
+	// Context switches from status and page faults from stat, cumulative
+	// and per second over the last interval
+	VoluntaryCtxSwitches    uint64  `json:"voluntary_ctxt_switches"`
+	NonvoluntaryCtxSwitches uint64  `json:"nonvoluntary_ctxt_switches"`
+	MinorFaults             uint64  `json:"minor_faults"`
+	MajorFaults             uint64  `json:"major_faults"`
+	VoluntaryCtxRate        float64 `json:"voluntary_ctxt_rate"`
+	NonvoluntaryCtxRate     float64 `json:"nonvoluntary_ctxt_rate"`
+	MinorFaultRate          float64 `json:"minor_fault_rate"`
+	MajorFaultRate          float64 `json:"major_fault_rate"`
+	// Time the main thread waited on a runqueue for a CPU, from schedstat,
+	// and the share of the last interval it waited
+	RunDelay        time.Duration `json:"run_delay"`
+	RunDelayPercent float64       `json:"run_delay_percent"`
+	// Kernel function a process in uninterruptible sleep (D) is blocked in
+	WaitChannel string `json:"wchan,omitempty"`
+	// Cgroup path, only read when a tag matches on it
+	Cgroup string `json:"cgroup,omitempty"`
+	// Namespaces, only read for the namespace view
+	Namespaces Namespaces `json:"namespaces,omitzero"`
+	// Label of the first configured tag matching the process
+	Tag string `json:"tag,omitempty"`
+	// Restarts of its command while crash looping, see CrashLoop
+	Restarts int `json:"restarts,omitempty"`
+}
+
+// ProcessDetail is what the process detail view adds to the table's
+// Process, read for the one process it shows
+type ProcessDetail struct {
+	PID int `json:"pid"`
+	// Untruncated command line, one argument each
+	Args []string `json:"args"`
+	// Executable and working directory, empty when not allowed to read them
+	Executable string `json:"executable"`
+	Cwd        string `json:"cwd"`
+	// Package that installed the executable, empty when none did or the
+	// package manager could not tell (PackageKnown false)
+	Package      string `json:"package,omitempty"`
+	PackageKnown bool   `json:"package_known"`
+	// SHA256 of the executable, to look it up in threat intelligence
+	SHA256  string    `json:"sha256,omitempty"`
+	Threads int       `json:"threads"`
+	Started time.Time `json:"started"`
+	Cgroup  string    `json:"cgroup"`
+	// Kernel function the process sleeps in, whatever its state
+	WaitChannel string     `json:"wchan"`
+	Namespaces  Namespaces `json:"namespaces"`
+	// Open files, processes and locked memory limits, empty when the
+	// limits could not be read
+	Limits   []ResourceLimit `json:"limits,omitempty"`
+	Security ProcessSecurity `json:"security"`
+}
+
+// ProcessSecurity is how a process is confined, from its status
+type ProcessSecurity struct {
+	// Whether the status was readable, the rest is empty otherwise
+	Known bool `json:"known"`
+	// Effective capabilities, and those of the kernel's it lacks
+	Capabilities        []string `json:"capabilities"`
+	MissingCapabilities []string `json:"missing_capabilities"`
+	// Seccomp mode, "disabled", "strict" or "filter", and the number of
+	// filters installed
+	Seccomp        string `json:"seccomp"`
+	SeccompFilters int    `json:"seccomp_filters"`
+	// Set when the process and its children can't gain privileges through
+	// setuid executables or file capabilities
+	NoNewPrivs bool `json:"no_new_privs"`
+}
+
+// Namespaces identifies the PID, network, mount and user namespaces of a
+// process by their inode numbers, 0 where they could not be read. Processes
+// sharing all four see the same system.
+type Namespaces struct {
+	PID  uint64 `json:"pid"`
+	Net  uint64 `json:"net"`
+	Mnt  uint64 `json:"mnt"`
+	User uint64 `json:"user"`
+}
+
+// ResourceLimit is one rlimit of a process with what it uses of it.
+// Unlimited limits are -1, as is a use that could not be measured.
+type ResourceLimit struct {
+	// Name as in ulimit: nofile, nproc or memlock
+	Name string `json:"name"`
+	Soft int64  `json:"soft"`
+	Hard int64  `json:"hard"`
+	Used int64  `json:"used"`
+	// Unit of the values, "bytes" or a count such as "files"
+	Unit string `json:"unit"`
 }
 
 type ProcessList struct {
@@ -19,4 +122,19 @@ type ProcessList struct {
 	Running   int       `json:"running"`
 	Sleeping  int       `json:"sleeping"`
 	Zombie    int       `json:"zombie"`
+	// Commands failing and being started again, seen through the proc
+	// connector
+	CrashLoops []CrashLoop `json:"crash_loops,omitempty"`
+}
+
+// UserStats aggregates the resource usage of every process owned by one UID
+type UserStats struct {
+	UID        string  `json:"uid"`
+	Name       string  `json:"name"`
+	Processes  int     `json:"processes"`
+	CPUPercent float64 `json:"cpu_percent"`
+	MemPercent float64 `json:"mem_percent"`
+	MemRSS     uint64  `json:"mem_rss"`
+	ReadRate   float64 `json:"read_rate"`
+	WriteRate  float64 `json:"write_rate"`
 }
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ExecActivity summarizes process creation between collections, including
+// processes too short-lived to ever show up in the process list
+type ExecActivity struct {
+	Source     string              `json:"source"`
+	ForkRate   float64             `json:"fork_rate"`
+	ExecRate   float64             `json:"exec_rate"`
+	ShortLived []ShortLivedProcess `json:"short_lived"`
+}
+
+// CrashLoop is a command, by name and command line, that keeps exiting
+// with an error and being started again. Restarts counts the restarts in
+// the detection window; ExitCode and Signal are of the last failed exit.
+type CrashLoop struct {
+	Name     string    `json:"name"`
+	Command  string    `json:"command"`
+	Restarts int       `json:"restarts"`
+	LastExit time.Time `json:"last_exit"`
+	ExitCode int       `json:"exit_code"`
+	Signal   int       `json:"signal,omitempty"`
+}
+
+type ShortLivedProcess struct {
+	PID      int           `json:"pid"`
+	PPID     int           `json:"ppid"`
+	Name     string        `json:"name"`
+	Command  string        `json:"command"`
+	Lifetime time.Duration `json:"lifetime"`
+	ExitedAt time.Time     `json:"exited_at"`
+	ExitCode int           `json:"exit_code"`
+}
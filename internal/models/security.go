@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// SecurityStats is a quick security posture summary of the machine
+type SecurityStats struct {
+	FailedLogins       []FailedLogin `json:"failed_logins"`
+	FailedLoginsSource string        `json:"failed_logins_source"` // journald, a log file, or empty when unreadable
+	Listeners          []Listener    `json:"listeners"`
+	SudoSessions       []SudoSession `json:"sudo_sessions"`
+}
+
+// FailedLogin aggregates the failed SSH logins of one source address
+type FailedLogin struct {
+	Source string    `json:"source"`
+	Count  int       `json:"count"`
+	Users  []string  `json:"users"`
+	Last   time.Time `json:"last"`
+}
+
+// Listener is a listening TCP socket or bound UDP socket
+type Listener struct {
+	Protocol string `json:"protocol"`
+	Address  string `json:"address"`
+	Port     int    `json:"port"`
+	PID      int    `json:"pid"` // 0 when the owner is not visible
+	Process  string `json:"process"`
+	User     string `json:"user"`
+}
+
+// SudoSession is a running sudo command, or cached sudo credentials when PID is 0
+type SudoSession struct {
+	User     string    `json:"user"`
+	PID      int       `json:"pid"`
+	Command  string    `json:"command"`
+	LastAuth time.Time `json:"last_auth"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// DesktopStats are the login sessions and how much of the CPU and GPU the
+// compositor or X server takes compared to the applications
+type DesktopStats struct {
+	Sessions []Session `json:"sessions"`
+	// Display servers and compositors running, such as Xorg or gnome-shell
+	Compositors []CompositorUsage `json:"compositors"`
+	// Everything else, for comparison
+	AppsCPUPercent float64 `json:"apps_cpu_percent"`
+	AppsGPUPercent float64 `json:"apps_gpu_percent"`
+	// Whether any process' GPU usage could be read from its DRM file
+	// descriptors, which needs a recent kernel and, for other users'
+	// processes, root
+	GPUKnown bool `json:"gpu_known"`
+}
+
+// Session is a logind session
+type Session struct {
+	ID      string `json:"id"`
+	User    string `json:"user"`
+	Type    string `json:"type"`  // x11, wayland, tty, mir or unspecified
+	Class   string `json:"class"` // user, greeter, lock-screen...
+	Desktop string `json:"desktop"`
+	Seat    string `json:"seat"`
+	TTY     string `json:"tty"`
+	Remote  bool   `json:"remote"`
+	State   string `json:"state"` // active, online or closing
+	// Set by desktops that report idleness to logind, zero otherwise
+	IdleSince time.Time `json:"idle_since"`
+	// The session croptop runs in
+	Current bool `json:"current"`
+}
+
+// CompositorUsage is the load of a display server or compositor process.
+// GPUPercent is the busiest GPU engine's busy time.
+type CompositorUsage struct {
+	PID        int     `json:"pid"`
+	Name       string  `json:"name"`
+	CPUPercent float64 `json:"cpu_percent"`
+	GPUPercent float64 `json:"gpu_percent"`
+}
@@ -0,0 +1,38 @@
+package models
+
+// SharedMemory lists memory held by tmpfs files and System V shared memory,
+// which is used but shows up in no process' RSS until mapped
+type SharedMemory struct {
+	Mounts   []TmpfsMount `json:"mounts"`
+	Files    []ShmFile    `json:"files"`    // biggest first
+	Segments []ShmSegment `json:"segments"` // biggest first
+	// The walk of the tmpfs mounts stopped at its file limit, so bigger
+	// files may be missing
+	Truncated bool `json:"truncated"`
+}
+
+// TmpfsMount is a mounted tmpfs and the memory its files take
+type TmpfsMount struct {
+	Mountpoint string `json:"mountpoint"`
+	Used       uint64 `json:"used"` // bytes
+	Size       uint64 `json:"size"` // bytes, the mount's limit
+}
+
+// ShmFile is a file on a tmpfs and the processes that map or hold it open
+type ShmFile struct {
+	Path string `json:"path"`
+	Size uint64 `json:"size"` // bytes allocated
+	PIDs []int  `json:"pids"`
+}
+
+// ShmSegment is a System V shared memory segment from /proc/sysvipc/shm
+type ShmSegment struct {
+	Key      string `json:"key"` // hex, 0x00000000 for IPC_PRIVATE
+	ID       int    `json:"id"`
+	Size     uint64 `json:"size"` // bytes
+	RSS      uint64 `json:"rss"`  // bytes resident
+	Attached int    `json:"attached"`
+	Creator  int    `json:"creator"` // PID that created the segment
+	UID      int    `json:"uid"`
+	PIDs     []int  `json:"pids"` // processes mapping the segment
+}
@@ -0,0 +1,19 @@
+package models
+
+// SlabStats is the kernel's slab allocator memory. Caches needs
+// /proc/slabinfo, which only root may read; the totals come from
+// /proc/meminfo either way.
+type SlabStats struct {
+	Reclaimable   uint64      `json:"reclaimable"`   // KB, e.g. dentry and inode caches
+	Unreclaimable uint64      `json:"unreclaimable"` // KB
+	Caches        []SlabCache `json:"caches"`        // biggest first
+}
+
+// SlabCache is a cache of /proc/slabinfo
+type SlabCache struct {
+	Name          string `json:"name"`
+	ActiveObjects uint64 `json:"active_objects"`
+	Objects       uint64 `json:"objects"`
+	ObjectSize    uint64 `json:"object_size"` // bytes
+	Size          uint64 `json:"size"`        // bytes of the cache's slabs
+}
@@ -8,15 +8,83 @@ type SystemStats struct {
 	Network NetworkStats  `json:"network"`
 	Disk    []DiskStats   `json:"disk"`
 	Battery BatteryStats  `json:"battery"`
+	Cgroup  CgroupStats   `json:"cgroup"`
+	Limits  SystemLimits  `json:"limits"`
 	Uptime  time.Duration `json:"uptime"`
+	// SuspendedFor is how long the machine was suspended right before this
+	// sample. History and graphs should treat such samples as a gap.
+	SuspendedFor time.Duration `json:"suspended_for"`
+	// Stale lists the domains (cpu, memory, network, disk, battery) whose
+	// collection timed out; they hold the last good values
+	Stale []string `json:"stale,omitempty"`
+}
+
+// SystemLimits are the system-wide ceilings on processes, threads and open
+// files against their use; a limit is 0 when it could not be read. Once hit,
+// fork, clone or open fail in every process alike.
+type SystemLimits struct {
+	Processes  int `json:"processes"`
+	PIDMax     int `json:"pid_max"`
+	Threads    int `json:"threads"`
+	ThreadsMax int `json:"threads_max"`
+	// File handles the kernel allocated, against fs.file-max
+	Files    int `json:"files"`
+	FilesMax int `json:"files_max"`
+}
+
+// DomainHealth holds the failure counters of a supervised collector domain
+type DomainHealth struct {
+	Name        string    `json:"name"`
+	LastSuccess time.Time `json:"last_success"`
+	// Failures counts timeouts and calls that found the domain still hung,
+	// Consecutive those since the last success
+	Failures    int `json:"failures"`
+	Consecutive int `json:"consecutive"`
+	// StalledFor is how long the running collection has been hung
+	StalledFor time.Duration `json:"stalled_for"`
 }
 
 type CPUStats struct {
-	Usage     float64   `json:"usage"`
-	Cores     []float64 `json:"cores"`
-	Frequency float64   `json:"frequency"`
-	Temp      float32   `json:"temperature"`
-	Model     string    `json:"model"`
+	Usage float64   `json:"usage"`
+	Cores []float64 `json:"cores"`
+	// CoreIDs are the CPU numbers of Cores. CPUs taken offline are left
+	// out, so there may be gaps.
+	CoreIDs []int `json:"core_ids"`
+	// Topology places each of Cores, nil when sysfs does not tell
+	Topology  []CPUPlace `json:"topology"`
+	Frequency float64    `json:"frequency"`
+	Temp      float32    `json:"temperature"`
+	Model     string     `json:"model"`
+	Power     RAPLStats  `json:"power"`
+	// Load is the 1, 5 and 15 minute load average
+	Load [3]float64 `json:"load"`
+}
+
+// CPUPlace locates a logical CPU in the physical package (socket) and the
+// core within it. SMT siblings, hyperthreads, share both.
+type CPUPlace struct {
+	Package int `json:"package"`
+	Core    int `json:"core"`
+}
+
+// CoreID returns the CPU number of Cores[i]
+func (c CPUStats) CoreID(i int) int {
+	if i < len(c.CoreIDs) {
+		return c.CoreIDs[i]
+	}
+	return i
+}
+
+// RAPLStats holds power draw measured by the RAPL energy counters
+type RAPLStats struct {
+	Domains       []PowerDomain `json:"domains"`
+	PackageWatts  float64       `json:"package_watts"`
+	SessionEnergy float64       `json:"session_energy"` // joules since croptop started
+}
+
+type PowerDomain struct {
+	Name  string  `json:"name"`
+	Watts float64 `json:"watts"`
 }
 
 type MemoryStats struct {
@@ -35,4 +103,50 @@ type BatteryStats struct {
 	TimeLeft   string `json:"time_left"`
 	IsCharging bool   `json:"is_charging"`
 	Health     int    `json:"health"`
+	Source     string `json:"source"`
+	// Rate the battery charges or discharges at, in W; 0 when it does not
+	// report one
+	Watts       float64             `json:"watts"`
+	Peripherals []PeripheralBattery `json:"peripherals"`
+	Inhibitors  []Inhibitor         `json:"inhibitors"`
+	Power       PowerSettings       `json:"power"`
+}
+
+// PowerSettings holds the screen backlight and power-profiles-daemon state.
+// Backlight is -1 without a backlight device, PowerProfile is empty without the daemon.
+type PowerSettings struct {
+	BacklightDevice string   `json:"backlight_device"`
+	Backlight       int      `json:"backlight"`
+	PowerProfile    string   `json:"power_profile"`
+	PowerProfiles   []string `json:"power_profiles"`
+}
+
+// Inhibitor is a logind lock delaying or blocking sleep, idle or shutdown
+type Inhibitor struct {
+	What string `json:"what"`
+	Who  string `json:"who"`
+	Why  string `json:"why"`
+	Mode string `json:"mode"`
+	UID  int    `json:"uid"`
+	PID  int    `json:"pid"`
+}
+
+// PeripheralBattery is the battery of a wireless device such as a mouse or headset
+type PeripheralBattery struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Level  int    `json:"level"`
+	Status string `json:"status"`
+}
+
+// CgroupStats describes the resource limits of the cgroup croptop runs in.
+// Limits are zero when the cgroup is unconstrained.
+type CgroupStats struct {
+	Path          string  `json:"path"`
+	Version       int     `json:"version"`
+	CPULimit      float64 `json:"cpu_limit"`
+	CPUUsage      float64 `json:"cpu_usage"`
+	MemoryLimit   float64 `json:"memory_limit"`
+	MemoryUsed    float64 `json:"memory_used"`
+	MemoryPercent float64 `json:"memory_percent"`
 }
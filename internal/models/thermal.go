@@ -0,0 +1,30 @@
+package models
+
+// ThermalZone is a thermal zone of /sys/class/thermal, ACPI or platform
+// sensors each with the trip points the kernel acts at
+type ThermalZone struct {
+	Name  string      `json:"name"` // thermal_zone3
+	Type  string      `json:"type"` // x86_pkg_temp, acpitz, ...
+	Temp  float64     `json:"temperature"`
+	Trips []TripPoint `json:"trips"`
+}
+
+// TripPoint is a temperature at which the kernel cools the zone down:
+// passive trips throttle, active ones start fans, hot ones notify and
+// critical ones shut the machine down
+type TripPoint struct {
+	Type string  `json:"type"`
+	Temp float64 `json:"temperature"`
+}
+
+// Trip returns the lowest trip point of a type
+func (z ThermalZone) Trip(kind string) (float64, bool) {
+	var temp float64
+	found := false
+	for _, trip := range z.Trips {
+		if trip.Type == kind && (!found || trip.Temp < temp) {
+			temp, found = trip.Temp, true
+		}
+	}
+	return temp, found
+}
@@ -0,0 +1,18 @@
+package models
+
+// VMStats describes a running qemu/KVM guest as seen from its host process
+type VMStats struct {
+	PID        int     `json:"pid"`
+	Name       string  `json:"name"`
+	VCPUs      int     `json:"vcpus"`
+	CPUPercent float64 `json:"cpu_percent"`
+	// By vCPU index, which need not be contiguous
+	VCPUPercent map[int]float64 `json:"vcpu_percent"`
+	MemoryAlloc uint64          `json:"memory_alloc"`
+	MemRSS      uint64          `json:"mem_rss"`
+	ReadRate    float64         `json:"read_rate"`
+	WriteRate   float64         `json:"write_rate"`
+	Interfaces  []string        `json:"interfaces"`
+	RxRate      float64         `json:"rx_rate"`
+	TxRate      float64         `json:"tx_rate"`
+}
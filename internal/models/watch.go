@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// ProcessTreeStats aggregates resource usage of a process and all of its descendants
+type ProcessTreeStats struct {
+	RootPID    int    `json:"root_pid"`
+	Processes  int    `json:"processes"`
+	Threads    int    `json:"threads"`
+	CPUTicks   uint64 `json:"cpu_ticks"`
+	MemRSS     uint64 `json:"mem_rss"`
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+}
+
+type WatchSample struct {
+	Elapsed      time.Duration `json:"elapsed"`
+	CPUPercent   float64       `json:"cpu_percent"`
+	MemRSS       uint64        `json:"mem_rss"`
+	ReadBytes    uint64        `json:"read_bytes"`
+	WriteBytes   uint64        `json:"write_bytes"`
+	Processes    int           `json:"processes"`
+	SystemCPU    float64       `json:"system_cpu"`
+	SystemMemory float64       `json:"system_memory"`
+	// Suspended is how long the machine slept before this sample; plots
+	// should show a gap here rather than connect the points
+	Suspended time.Duration `json:"suspended,omitempty"`
+}
+
+// WatchProfile is the resource profile of a command run under `croptop watch`
+type WatchProfile struct {
+	Command        []string      `json:"command"`
+	ExitCode       int           `json:"exit_code"`
+	StartedAt      time.Time     `json:"started_at"`
+	Duration       time.Duration `json:"duration"`
+	UserTime       time.Duration `json:"user_time"`
+	SystemTime     time.Duration `json:"system_time"`
+	PeakCPUPercent float64       `json:"peak_cpu_percent"`
+	AvgCPUPercent  float64       `json:"avg_cpu_percent"`
+	PeakRSS        uint64        `json:"peak_rss"`
+	PeakProcesses  int           `json:"peak_processes"`
+	ReadBytes      uint64        `json:"read_bytes"`
+	WriteBytes     uint64        `json:"write_bytes"`
+	Samples        []WatchSample `json:"samples"`
+}
@@ -0,0 +1,162 @@
+// Package pb encodes and decodes the protobuf wire format for the few
+// messages croptop exchanges (remote_write requests, the gRPC agent API),
+// without generated code.
+package pb
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Wire types
+const (
+	WireVarint  = 0
+	WireFixed64 = 1
+	WireBytes   = 2
+	WireFixed32 = 5
+)
+
+// Buffer accumulates an encoded message. Zero values are skipped, as proto3
+// does for scalar fields.
+type Buffer struct {
+	b []byte
+}
+
+// Bytes returns the encoded message
+func (m *Buffer) Bytes() []byte {
+	return m.b
+}
+
+// Reset empties the buffer, keeping its memory
+func (m *Buffer) Reset() {
+	m.b = m.b[:0]
+}
+
+func (m *Buffer) tag(field, wireType int) {
+	m.b = binary.AppendUvarint(m.b, uint64(field<<3|wireType))
+}
+
+func (m *Buffer) Uint64(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	m.tag(field, WireVarint)
+	m.b = binary.AppendUvarint(m.b, v)
+}
+
+func (m *Buffer) Int64(field int, v int64) {
+	m.Uint64(field, uint64(v))
+}
+
+func (m *Buffer) Bool(field int, v bool) {
+	if v {
+		m.Uint64(field, 1)
+	}
+}
+
+func (m *Buffer) Double(field int, v float64) {
+	if v == 0 {
+		return
+	}
+	m.tag(field, WireFixed64)
+	m.b = binary.LittleEndian.AppendUint64(m.b, math.Float64bits(v))
+}
+
+func (m *Buffer) String(field int, s string) {
+	if s == "" {
+		return
+	}
+	m.tag(field, WireBytes)
+	m.b = binary.AppendUvarint(m.b, uint64(len(s)))
+	m.b = append(m.b, s...)
+}
+
+// Message encodes a nested message written by fill. Unlike scalars, an empty
+// message is still written, so repeated messages keep their count.
+func (m *Buffer) Message(field int, fill func(*Buffer)) {
+	var nested Buffer
+	fill(&nested)
+	m.tag(field, WireBytes)
+	m.b = binary.AppendUvarint(m.b, uint64(len(nested.b)))
+	m.b = append(m.b, nested.b...)
+}
+
+// PackedDoubles encodes a repeated double field
+func (m *Buffer) PackedDoubles(field int, values []float64) {
+	if len(values) == 0 {
+		return
+	}
+	m.tag(field, WireBytes)
+	m.b = binary.AppendUvarint(m.b, uint64(len(values)*8))
+	for _, v := range values {
+		m.b = binary.LittleEndian.AppendUint64(m.b, math.Float64bits(v))
+	}
+}
+
+// PackedUint32s encodes a repeated uint32 field
+func (m *Buffer) PackedUint32s(field int, values []int) {
+	if len(values) == 0 {
+		return
+	}
+	var packed []byte
+	for _, v := range values {
+		packed = binary.AppendUvarint(packed, uint64(uint32(v)))
+	}
+	m.tag(field, WireBytes)
+	m.b = binary.AppendUvarint(m.b, uint64(len(packed)))
+	m.b = append(m.b, packed...)
+}
+
+var errTruncated = errors.New("pb: truncated message")
+
+// Field is a decoded field. Varint and fixed values are in Value, length
+// delimited ones in Data.
+type Field struct {
+	Number   int
+	WireType int
+	Value    uint64
+	Data     []byte
+}
+
+// Decode splits a message into its fields
+func Decode(b []byte) ([]Field, error) {
+	var fields []Field
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		b = b[n:]
+
+		field := Field{Number: int(tag >> 3), WireType: int(tag & 7)}
+		switch field.WireType {
+		case WireVarint:
+			field.Value, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errTruncated
+			}
+			b = b[n:]
+		case WireFixed64:
+			if len(b) < 8 {
+				return nil, errTruncated
+			}
+			field.Value, b = binary.LittleEndian.Uint64(b), b[8:]
+		case WireFixed32:
+			if len(b) < 4 {
+				return nil, errTruncated
+			}
+			field.Value, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case WireBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return nil, errTruncated
+			}
+			field.Data, b = b[n:n+int(length)], b[n+int(length):]
+		default:
+			return nil, errors.New("pb: unsupported wire type")
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
@@ -0,0 +1,466 @@
+// Package schema defines the JSON documents croptop writes for other
+// programs: the REST API, the WebSocket stream, events and
+// `croptop watch -o` profiles.
+//
+// Unlike the models, whose JSON follows the collector's internal units,
+// these documents are a public contract. Every document carries
+// schema_version; field names end in their unit (_bytes, _seconds,
+// _percent, _per_second) and timestamps are RFC 3339. Within a version
+// fields are only added, never renamed, removed or changed in unit.
+// Anything else bumps Version.
+package schema
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/alert"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/history"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Version is the schema_version of every document
+const Version = 1
+
+// kbToBytes converts the kilobyte figures of the models
+const kbToBytes = 1024
+
+// Snapshot is one collection of system stats and processes
+type Snapshot struct {
+	SchemaVersion int        `json:"schema_version"`
+	Time          time.Time  `json:"time"`
+	Host          string     `json:"host,omitempty"`
+	System        System     `json:"system"`
+	Processes     *Processes `json:"processes,omitempty"`
+	// Alerts are the rules firing on the agent, critical ones first
+	Alerts []Alert `json:"alerts,omitempty"`
+}
+
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Metric    string    `json:"metric"`
+	Severity  string    `json:"severity"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Since     time.Time `json:"since"`
+}
+
+type System struct {
+	UptimeSeconds float64  `json:"uptime_seconds"`
+	CPU           CPU      `json:"cpu"`
+	Memory        Memory   `json:"memory"`
+	Network       Network  `json:"network"`
+	Disks         []Disk   `json:"disks"`
+	Battery       *Battery `json:"battery,omitempty"`
+	Cgroup        *Cgroup  `json:"cgroup,omitempty"`
+	Suspended     *Suspend `json:"suspended,omitempty"`
+}
+
+type CPU struct {
+	Model            string    `json:"model"`
+	UsagePercent     float64   `json:"usage_percent"`
+	CoreUsagePercent []float64 `json:"core_usage_percent"`
+	// CPU numbers of core_usage_percent, offline CPUs are left out
+	CoreIDs            []int     `json:"core_ids"`
+	FrequencyMHz       float64   `json:"frequency_mhz"`
+	TemperatureCelsius *float64  `json:"temperature_celsius,omitempty"`
+	PackageWatts       *float64  `json:"package_watts,omitempty"`
+	LoadAverage        []float64 `json:"load_average"`
+}
+
+type Memory struct {
+	TotalBytes     uint64  `json:"total_bytes"`
+	UsedBytes      uint64  `json:"used_bytes"`
+	FreeBytes      uint64  `json:"free_bytes"`
+	AvailableBytes uint64  `json:"available_bytes"`
+	UsagePercent   float64 `json:"usage_percent"`
+	SwapTotalBytes uint64  `json:"swap_total_bytes"`
+	SwapUsedBytes  uint64  `json:"swap_used_bytes"`
+}
+
+type Network struct {
+	ReceiveBytesPerSecond  float64     `json:"receive_bytes_per_second"`
+	TransmitBytesPerSecond float64     `json:"transmit_bytes_per_second"`
+	Interfaces             []Interface `json:"interfaces"`
+}
+
+type Interface struct {
+	Name                   string  `json:"name"`
+	Status                 string  `json:"status"`
+	ReceiveBytes           uint64  `json:"receive_bytes"`
+	TransmitBytes          uint64  `json:"transmit_bytes"`
+	ReceivePackets         uint64  `json:"receive_packets"`
+	TransmitPackets        uint64  `json:"transmit_packets"`
+	ReceiveBytesPerSecond  float64 `json:"receive_bytes_per_second"`
+	TransmitBytesPerSecond float64 `json:"transmit_bytes_per_second"`
+}
+
+type Disk struct {
+	Device       string  `json:"device"`
+	Mountpoint   string  `json:"mountpoint"`
+	Filesystem   string  `json:"filesystem"`
+	TotalBytes   uint64  `json:"total_bytes"`
+	UsedBytes    uint64  `json:"used_bytes"`
+	FreeBytes    uint64  `json:"free_bytes"`
+	UsagePercent float64 `json:"usage_percent"`
+	// Stalled mounts did not answer; the sizes are the last known ones
+	Stalled bool `json:"stalled"`
+}
+
+// Battery is omitted on machines without one
+type Battery struct {
+	LevelPercent int    `json:"level_percent"`
+	Status       string `json:"status"`
+	Charging     bool   `json:"charging"`
+}
+
+// Cgroup is present when croptop runs in a cgroup with CPU or memory limits
+type Cgroup struct {
+	Path               string   `json:"path"`
+	CPULimitCores      *float64 `json:"cpu_limit_cores,omitempty"`
+	CPUUsagePercent    *float64 `json:"cpu_usage_percent,omitempty"`
+	MemoryLimitBytes   *uint64  `json:"memory_limit_bytes,omitempty"`
+	MemoryUsagePercent *float64 `json:"memory_usage_percent,omitempty"`
+}
+
+// Suspend marks the first sample after a resume, whose rates start afresh
+type Suspend struct {
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+type Processes struct {
+	Total    int       `json:"total"`
+	Running  int       `json:"running"`
+	Sleeping int       `json:"sleeping"`
+	Zombie   int       `json:"zombie"`
+	List     []Process `json:"list"`
+}
+
+type Process struct {
+	PID                 int     `json:"pid"`
+	Name                string  `json:"name"`
+	Command             string  `json:"command"`
+	User                string  `json:"user"`
+	UID                 string  `json:"uid"`
+	Status              string  `json:"status"`
+	CPUPercent          float64 `json:"cpu_percent"`
+	MemoryPercent       float64 `json:"memory_percent"`
+	RSSBytes            uint64  `json:"rss_bytes"`
+	SwapBytes           uint64  `json:"swap_bytes"`
+	ReadBytes           uint64  `json:"read_bytes"`
+	WriteBytes          uint64  `json:"write_bytes"`
+	ReadBytesPerSecond  float64 `json:"read_bytes_per_second"`
+	WriteBytesPerSecond float64 `json:"write_bytes_per_second"`
+	RuntimeSeconds      float64 `json:"runtime_seconds"`
+}
+
+// NewSnapshot converts a collection. processes may be nil to leave the
+// process list out.
+func NewSnapshot(t time.Time, stats models.SystemStats, processes *models.ProcessList) Snapshot {
+	snapshot := Snapshot{
+		SchemaVersion: Version,
+		Time:          t,
+		System:        NewSystem(stats),
+	}
+	if processes != nil {
+		list := NewProcesses(*processes)
+		snapshot.Processes = &list
+	}
+	return snapshot
+}
+
+func NewSystem(stats models.SystemStats) System {
+	system := System{
+		UptimeSeconds: stats.Uptime.Seconds(),
+		CPU: CPU{
+			Model:            stats.CPU.Model,
+			UsagePercent:     stats.CPU.Usage,
+			CoreUsagePercent: stats.CPU.Cores,
+			CoreIDs:          stats.CPU.CoreIDs,
+			FrequencyMHz:     stats.CPU.Frequency,
+			LoadAverage:      stats.CPU.Load[:],
+		},
+		Memory: Memory{
+			TotalBytes:     uint64(stats.Memory.Total * kbToBytes),
+			UsedBytes:      uint64(stats.Memory.Used * kbToBytes),
+			FreeBytes:      uint64(stats.Memory.Free * kbToBytes),
+			AvailableBytes: uint64(stats.Memory.Available * kbToBytes),
+			UsagePercent:   stats.Memory.UsagePercent,
+			SwapTotalBytes: uint64(stats.Memory.SwapTotal * kbToBytes),
+			SwapUsedBytes:  uint64(stats.Memory.SwapUsed * kbToBytes),
+		},
+		Network: Network{
+			ReceiveBytesPerSecond:  stats.Network.RxRate,
+			TransmitBytesPerSecond: stats.Network.TxRate,
+			Interfaces:             []Interface{},
+		},
+		Disks: []Disk{},
+	}
+	if system.CPU.CoreUsagePercent == nil {
+		system.CPU.CoreUsagePercent = []float64{}
+	}
+	if stats.CPU.Temp > 0 {
+		temp := float64(stats.CPU.Temp)
+		system.CPU.TemperatureCelsius = &temp
+	}
+	if len(stats.CPU.Power.Domains) > 0 {
+		watts := stats.CPU.Power.PackageWatts
+		system.CPU.PackageWatts = &watts
+	}
+
+	for _, iface := range stats.Network.Interfaces {
+		system.Network.Interfaces = append(system.Network.Interfaces, Interface{
+			Name:                   iface.Name,
+			Status:                 iface.Status,
+			ReceiveBytes:           iface.RxBytes,
+			TransmitBytes:          iface.TxBytes,
+			ReceivePackets:         iface.RxPackets,
+			TransmitPackets:        iface.TxPackets,
+			ReceiveBytesPerSecond:  iface.RxRate,
+			TransmitBytesPerSecond: iface.TxRate,
+		})
+	}
+	for _, disk := range stats.Disk {
+		system.Disks = append(system.Disks, Disk{
+			Device:       disk.Device,
+			Mountpoint:   disk.Mountpoint,
+			Filesystem:   disk.Filesystem,
+			TotalBytes:   disk.Total,
+			UsedBytes:    disk.Used,
+			FreeBytes:    disk.Free,
+			UsagePercent: disk.UsagePercent,
+			Stalled:      disk.Stalled,
+		})
+	}
+
+	if battery := stats.Battery; battery.Status != "Not Available" {
+		system.Battery = &Battery{LevelPercent: battery.Level, Status: battery.Status, Charging: battery.IsCharging}
+	}
+
+	if cgroup := stats.Cgroup; cgroup.CPULimit > 0 || cgroup.MemoryLimit > 0 {
+		system.Cgroup = &Cgroup{Path: cgroup.Path}
+		if cgroup.CPULimit > 0 {
+			system.Cgroup.CPULimitCores = &cgroup.CPULimit
+			system.Cgroup.CPUUsagePercent = &cgroup.CPUUsage
+		}
+		if cgroup.MemoryLimit > 0 {
+			limit := uint64(cgroup.MemoryLimit * kbToBytes)
+			system.Cgroup.MemoryLimitBytes = &limit
+			system.Cgroup.MemoryUsagePercent = &cgroup.MemoryPercent
+		}
+	}
+
+	if stats.SuspendedFor > 0 {
+		system.Suspended = &Suspend{DurationSeconds: stats.SuspendedFor.Seconds()}
+	}
+	return system
+}
+
+func NewProcesses(list models.ProcessList) Processes {
+	processes := Processes{
+		Total:    list.Total,
+		Running:  list.Running,
+		Sleeping: list.Sleeping,
+		Zombie:   list.Zombie,
+		List:     make([]Process, 0, len(list.Processes)),
+	}
+	for _, proc := range list.Processes {
+		processes.List = append(processes.List, NewProcess(proc))
+	}
+	return processes
+}
+
+func NewProcess(proc models.Process) Process {
+	return Process{
+		PID:                 proc.PID,
+		Name:                proc.Name,
+		Command:             proc.Command,
+		User:                collector.LookupUsername(proc.User),
+		UID:                 proc.User,
+		Status:              proc.Status,
+		CPUPercent:          proc.CPUPercent,
+		MemoryPercent:       proc.MemPercent,
+		RSSBytes:            proc.MemRSS * kbToBytes,
+		SwapBytes:           proc.Swap * kbToBytes,
+		ReadBytes:           proc.ReadBytes,
+		WriteBytes:          proc.WriteBytes,
+		ReadBytesPerSecond:  proc.ReadRate,
+		WriteBytesPerSecond: proc.WriteRate,
+		RuntimeSeconds:      proc.Runtime.Seconds(),
+	}
+}
+
+func NewAlerts(alerts []alert.Alert) []Alert {
+	converted := make([]Alert, 0, len(alerts))
+	for _, a := range alerts {
+		converted = append(converted, Alert{
+			Rule:      a.Rule.Source,
+			Metric:    a.Rule.Metric,
+			Severity:  a.Rule.Severity.String(),
+			Value:     a.Value,
+			Threshold: a.Rule.Threshold,
+			Since:     a.Since,
+		})
+	}
+	return converted
+}
+
+// History is a metric over time, as returned by /api/history. Each point
+// is the average of the step starting at its time; steps without values
+// are left out.
+type History struct {
+	SchemaVersion int    `json:"schema_version"`
+	Host          string `json:"host,omitempty"`
+	Metric        string `json:"metric"`
+	// Unit of the values, as in alert messages: "%", "B/s", "°C", ...
+	Unit        string         `json:"unit"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	StepSeconds float64        `json:"step_seconds"`
+	Points      []HistoryPoint `json:"points"`
+}
+
+type HistoryPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+func NewHistory(metric, unit string, from, to time.Time, step time.Duration, points []history.Point) History {
+	converted := History{
+		SchemaVersion: Version,
+		Metric:        metric,
+		Unit:          unit,
+		From:          from,
+		To:            to,
+		StepSeconds:   step.Seconds(),
+		Points:        make([]HistoryPoint, 0, len(points)),
+	}
+	for _, point := range points {
+		converted.Points = append(converted.Points, HistoryPoint{Time: point.Time, Value: point.Value})
+	}
+	return converted
+}
+
+// HistoryEvents is the alerts that fired and cleared over a range, as
+// returned by /api/history/alerts, oldest first
+type HistoryEvents struct {
+	SchemaVersion int       `json:"schema_version"`
+	Host          string    `json:"host,omitempty"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Events        []Event   `json:"events"`
+}
+
+// NewHistoryEvents converts recorded events, skipping any that are not an
+// Event
+func NewHistoryEvents(from, to time.Time, events []history.Event) HistoryEvents {
+	converted := HistoryEvents{
+		SchemaVersion: Version,
+		From:          from,
+		To:            to,
+		Events:        make([]Event, 0, len(events)),
+	}
+	for _, recorded := range events {
+		var event Event
+		if json.Unmarshal(recorded.Data, &event) == nil {
+			converted.Events = append(converted.Events, event)
+		}
+	}
+	return converted
+}
+
+// WatchProfile is the resource profile of a command run under
+// `croptop watch`
+type WatchProfile struct {
+	SchemaVersion     int           `json:"schema_version"`
+	Command           []string      `json:"command"`
+	ExitCode          int           `json:"exit_code"`
+	StartedAt         time.Time     `json:"started_at"`
+	DurationSeconds   float64       `json:"duration_seconds"`
+	UserTimeSeconds   float64       `json:"user_time_seconds"`
+	SystemTimeSeconds float64       `json:"system_time_seconds"`
+	PeakCPUPercent    float64       `json:"peak_cpu_percent"`
+	AverageCPUPercent float64       `json:"average_cpu_percent"`
+	PeakRSSBytes      uint64        `json:"peak_rss_bytes"`
+	PeakProcesses     int           `json:"peak_processes"`
+	ReadBytes         uint64        `json:"read_bytes"`
+	WriteBytes        uint64        `json:"write_bytes"`
+	Samples           []WatchSample `json:"samples"`
+}
+
+type WatchSample struct {
+	ElapsedSeconds      float64 `json:"elapsed_seconds"`
+	CPUPercent          float64 `json:"cpu_percent"`
+	RSSBytes            uint64  `json:"rss_bytes"`
+	ReadBytes           uint64  `json:"read_bytes"`
+	WriteBytes          uint64  `json:"write_bytes"`
+	Processes           int     `json:"processes"`
+	SystemCPUPercent    float64 `json:"system_cpu_percent"`
+	SystemMemoryPercent float64 `json:"system_memory_percent"`
+	// SuspendedSeconds is how long the machine slept before this sample;
+	// plots should show a gap here rather than connect the points
+	SuspendedSeconds float64 `json:"suspended_seconds,omitempty"`
+}
+
+func NewWatchProfile(profile models.WatchProfile) WatchProfile {
+	converted := WatchProfile{
+		SchemaVersion:     Version,
+		Command:           profile.Command,
+		ExitCode:          profile.ExitCode,
+		StartedAt:         profile.StartedAt,
+		DurationSeconds:   profile.Duration.Seconds(),
+		UserTimeSeconds:   profile.UserTime.Seconds(),
+		SystemTimeSeconds: profile.SystemTime.Seconds(),
+		PeakCPUPercent:    profile.PeakCPUPercent,
+		AverageCPUPercent: profile.AvgCPUPercent,
+		PeakRSSBytes:      profile.PeakRSS * kbToBytes,
+		PeakProcesses:     profile.PeakProcesses,
+		ReadBytes:         profile.ReadBytes,
+		WriteBytes:        profile.WriteBytes,
+		Samples:           make([]WatchSample, 0, len(profile.Samples)),
+	}
+	for _, sample := range profile.Samples {
+		converted.Samples = append(converted.Samples, WatchSample{
+			ElapsedSeconds:      sample.Elapsed.Seconds(),
+			CPUPercent:          sample.CPUPercent,
+			RSSBytes:            sample.MemRSS * kbToBytes,
+			ReadBytes:           sample.ReadBytes,
+			WriteBytes:          sample.WriteBytes,
+			Processes:           sample.Processes,
+			SystemCPUPercent:    sample.SystemCPU,
+			SystemMemoryPercent: sample.SystemMemory,
+			SuspendedSeconds:    sample.Suspended.Seconds(),
+		})
+	}
+	return converted
+}
+
+// Event types
+const (
+	EventAlertFired    = "alert_fired"
+	EventAlertCleared  = "alert_cleared"
+	EventOOMKill       = "oom_kill"
+	EventDiskStalled   = "disk_stalled"
+	EventDiskRecovered = "disk_recovered"
+	EventCrashLoop     = "crash_loop"
+)
+
+// Event severities; alerts carry the severity of their rule
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Event is something croptop noticed, as sent to the events webhook. Details
+// depend on the type, e.g. the rule and value of an alert.
+type Event struct {
+	SchemaVersion int            `json:"schema_version"`
+	Time          time.Time      `json:"time"`
+	Type          string         `json:"type"`
+	Severity      string         `json:"severity"`
+	Host          string         `json:"host"`
+	Message       string         `json:"message"`
+	Details       map[string]any `json:"details,omitempty"`
+}
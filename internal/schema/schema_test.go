@@ -0,0 +1,194 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/history"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// Field names, unit suffixes and schema_version are what other tools read,
+// so the documents are compared with the files in testdata. A change to
+// them should come with a new Version; -update rewrites the files.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var testTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// checkGolden compares the JSON of document with testdata/name, and checks
+// that the file decodes back into the same document
+func checkGolden[T any](t *testing.T, name string, document T) {
+	t.Helper()
+	got, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s changed, got:\n%s", path, got)
+	}
+
+	var decoded T
+	if err := json.Unmarshal(want, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, document) {
+		t.Errorf("%s does not decode back:\n got %+v\nwant %+v", path, decoded, document)
+	}
+}
+
+func testStats() models.SystemStats {
+	return models.SystemStats{
+		CPU: models.CPUStats{
+			Usage:     42.5,
+			Cores:     []float64{40, 45},
+			CoreIDs:   []int{0, 2},
+			Frequency: 2400,
+			Temp:      55,
+			Model:     "Test CPU",
+			Power:     models.RAPLStats{Domains: []models.PowerDomain{{Name: "package-0", Watts: 12.5}}, PackageWatts: 12.5},
+			Load:      [3]float64{0.5, 0.75, 1},
+		},
+		Memory: models.MemoryStats{
+			Total:        8 * 1024 * 1024,
+			Used:         2 * 1024 * 1024,
+			Free:         4 * 1024 * 1024,
+			Available:    6 * 1024 * 1024,
+			UsagePercent: 25,
+			SwapTotal:    1024 * 1024,
+			SwapUsed:     512,
+		},
+		Network: models.NetworkStats{
+			RxRate: 1000,
+			TxRate: 500,
+			Interfaces: []models.NetworkInterface{{
+				Name: "eth0", Status: "up",
+				RxBytes: 10000, TxBytes: 5000, RxPackets: 100, TxPackets: 50,
+				RxRate: 1000, TxRate: 500,
+			}},
+		},
+		Disk: []models.DiskStats{{
+			Device: "/dev/sda1", Mountpoint: "/", Filesystem: "ext4",
+			Total: 100 << 30, Used: 40 << 30, Free: 60 << 30, UsagePercent: 40,
+		}},
+		Battery: models.BatteryStats{Level: 80, Status: "Discharging"},
+		Cgroup: models.CgroupStats{
+			Path:          "/system.slice/croptop.service",
+			CPULimit:      2,
+			CPUUsage:      10,
+			MemoryLimit:   1024 * 1024,
+			MemoryPercent: 5,
+		},
+		Uptime:       time.Hour,
+		SuspendedFor: 90 * time.Second,
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	processes := &models.ProcessList{
+		Processes: []models.Process{{
+			PID: 1, Name: "init", Command: "/sbin/init", User: "0", Status: "S",
+			CPUPercent: 1.5, MemPercent: 0.5, MemRSS: 10240, Swap: 0,
+			ReadBytes: 4096, WriteBytes: 8192, ReadRate: 10, WriteRate: 20,
+			Runtime: time.Hour,
+		}},
+		Total:    1,
+		Sleeping: 1,
+	}
+	snapshot := NewSnapshot(testTime, testStats(), processes)
+	snapshot.Host = "test"
+	snapshot.Alerts = []Alert{{
+		Rule: "cpu > 40", Metric: "cpu", Severity: "warning",
+		Value: 42.5, Threshold: 40, Since: testTime.Add(-time.Minute),
+	}}
+	checkGolden(t, "snapshot.json", snapshot)
+}
+
+func TestSnapshotWithout(t *testing.T) {
+	// Without a battery, cgroup limits or processes those are left out
+	checkGolden(t, "snapshot_minimal.json", NewSnapshot(testTime, models.SystemStats{
+		Battery: models.BatteryStats{Status: "Not Available"},
+	}, nil))
+}
+
+func TestEvent(t *testing.T) {
+	// As the events webhook and the NDJSON stream send it; numbers in
+	// details decode as float64
+	checkGolden(t, "event.json", Event{
+		SchemaVersion: Version,
+		Time:          testTime,
+		Type:          EventAlertCleared,
+		Severity:      SeverityInfo,
+		Host:          "test",
+		Message:       "cpu > 40 cleared (now 20.0, peak 95.0)",
+		Details: map[string]any{
+			"rule":             "cpu > 40",
+			"metric":           "cpu",
+			"value":            20.0,
+			"threshold":        40.0,
+			"peak":             95.0,
+			"duration_seconds": 60.0,
+		},
+	})
+}
+
+func TestHistory(t *testing.T) {
+	document := NewHistory("cpu.usage", "%", testTime.Add(-time.Hour), testTime, time.Minute, []history.Point{
+		{Time: testTime.Add(-2 * time.Minute), Value: 10},
+		{Time: testTime.Add(-time.Minute), Value: 20},
+	})
+	document.Host = "test"
+	checkGolden(t, "history.json", document)
+}
+
+func TestHistoryEvents(t *testing.T) {
+	fired, err := json.Marshal(Event{
+		SchemaVersion: Version,
+		Time:          testTime.Add(-time.Minute),
+		Type:          EventAlertFired,
+		Severity:      SeverityWarning,
+		Host:          "test",
+		Message:       "cpu > 40 (now 95.0)",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := NewHistoryEvents(testTime.Add(-time.Hour), testTime, []history.Event{
+		{Time: testTime.Add(-time.Minute), Data: fired},
+		// Not an event, left out
+		{Time: testTime, Data: json.RawMessage(`[]`)},
+	})
+	document.Host = "test"
+	checkGolden(t, "history_events.json", document)
+}
+
+func TestWatchProfile(t *testing.T) {
+	checkGolden(t, "watch_profile.json", NewWatchProfile(models.WatchProfile{
+		Command:   []string{"make", "-j4"},
+		ExitCode:  0,
+		StartedAt: testTime,
+		Duration:  10 * time.Second,
+		PeakRSS:   2048,
+		Samples: []models.WatchSample{{
+			Elapsed: time.Second, CPUPercent: 150, MemRSS: 2048, Processes: 4,
+			SystemCPU: 40, SystemMemory: 30,
+		}},
+	}))
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/alert"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/history"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// alertLogInterval is how often the Alerts tab rereads the alert history
+const alertLogInterval = 5 * time.Second
+
+// alertLogMsg delivers the alert events read from the history
+type alertLogMsg struct {
+	events []schema.Event
+	err    error
+}
+
+// alertLogPath is the history an agent with the same config records alerts
+// in, empty when it keeps none on disk
+func alertLogPath(cfg *config.Config) string {
+	if !cfg.History.Enabled || !history.OnDisk(cfg.History) {
+		return ""
+	}
+	return history.Path(cfg.History, filepath.Dir(cfg.Path))
+}
+
+// alertLogDue reports whether the Alerts tab should reread the history
+func (a *App) alertLogDue() bool {
+	return a.alertLog != "" && !a.alertLogReading && time.Since(a.lastAlertLog) >= alertLogInterval
+}
+
+// readAlertLog reads the alert events of the history off the UI goroutine
+func (a *App) readAlertLog() tea.Cmd {
+	a.alertLogReading = true
+	a.lastAlertLog = time.Now()
+
+	path := a.alertLog
+	return func() tea.Msg {
+		recorded, err := history.ReadEvents(path)
+		if err != nil {
+			return alertLogMsg{err: err}
+		}
+		var msg alertLogMsg
+		for _, record := range recorded {
+			var event schema.Event
+			if json.Unmarshal(record.Data, &event) != nil {
+				continue
+			}
+			if event.Type == schema.EventAlertFired || event.Type == schema.EventAlertCleared {
+				msg.events = append(msg.events, event)
+			}
+		}
+		return msg
+	}
+}
+
+// applyAlertLog stores a read of the history, keeping the events read last
+// if it failed
+func (a *App) applyAlertLog(msg alertLogMsg) {
+	a.alertLogReading = false
+	a.alertLogErr = msg.err
+	if msg.err == nil {
+		a.alertRecorded = msg.events
+	}
+}
+
+// detailFloat returns a number of an event's details, which decode as
+// float64
+func detailFloat(event schema.Event, key string) float64 {
+	value, _ := event.Details[key].(float64)
+	return value
+}
+
+// detailString returns a string of an event's details
+func detailString(event schema.Event, key string) string {
+	value, _ := event.Details[key].(string)
+	return value
+}
+
+// alertRow is a line of the Alerts tab, from this session or the history
+type alertRow struct {
+	time     time.Time
+	kind     string
+	severity string
+	value    float64
+	peak     float64
+	lasted   string
+	rule     string
+	style    lipgloss.Style
+}
+
+func sessionAlertRow(event alert.Event) alertRow {
+	row := alertRow{
+		time:     event.Time,
+		kind:     "FIRED",
+		severity: event.Alert.Rule.Severity.String(),
+		value:    event.Alert.Value,
+		peak:     event.Alert.Peak,
+		rule:     event.Alert.Rule.Source,
+		style:    ErrorStyle,
+	}
+	if event.Alert.Rule.Severity == alert.SeverityWarning {
+		row.style = WarningStyle
+	}
+	if event.Type == alert.EventCleared {
+		row.kind, row.style = "CLEARED", SuccessStyle
+		row.lasted = formatDuration(event.Time.Sub(event.Alert.Since))
+	}
+	return row
+}
+
+// recordedAlertRow is an event of events.FromAlert read from the history.
+// A clear is recorded with the info severity, not the rule's.
+func recordedAlertRow(event schema.Event) alertRow {
+	row := alertRow{
+		time:     event.Time,
+		kind:     "FIRED",
+		severity: event.Severity,
+		value:    detailFloat(event, "value"),
+		peak:     detailFloat(event, "peak"),
+		rule:     detailString(event, "rule"),
+		style:    ErrorStyle,
+	}
+	if event.Severity == schema.SeverityWarning {
+		row.style = WarningStyle
+	}
+	if event.Type == schema.EventAlertCleared {
+		row.kind, row.style = "CLEARED", SuccessStyle
+		row.lasted = formatDuration(time.Duration(detailFloat(event, "duration_seconds") * float64(time.Second)))
+	}
+	return row
+}
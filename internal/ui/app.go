@@ -1,116 +1,619 @@
 package ui
 
 import (
+	"bytes"
 	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/prabalesh/croptop/internal/alert"
+	"github.com/prabalesh/croptop/internal/caps"
 	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/events"
+	"github.com/prabalesh/croptop/internal/i18n"
 	"github.com/prabalesh/croptop/internal/models"
+	"github.com/prabalesh/croptop/internal/schema"
 
-	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 var KBToGB float64 = 1048576
 
 type tickMsg time.Time
 
+// snapshotMsg carries the system stats of a round of the collector pipeline
+type snapshotMsg models.SystemStats
+
+// dnsCheckMsg carries the results of a resolver latency test
+type dnsCheckMsg []models.DNSCheck
+
+// configChangedMsg reports that the config file changed on disk
+type configChangedMsg struct {
+	err error
+}
+
+// powerProfileMsg reports the result of switching the power profile
+type powerProfileMsg struct {
+	profile string
+	err     error
+}
+
 type App struct {
-	collector   *collector.StatsCollector
-	stats       models.SystemStats
-	processes   models.ProcessList
-	activeTab   int
-	tabs        []string
-	width       int
-	height      int
-	selectedRow int
+	collector *collector.StatsCollector
+	// Collects the system stats on tickers of its own
+	pipeline *collector.Pipeline
+	alerts   *alert.Engine
+	// Expressions of the footer strip
+	watches []alert.Watch
+	// Tags of the process table, compiled
+	tags []processTag
+	// Where alerts and detected problems are sent
+	events     *events.Emitter
+	detector   events.Detector
+	highlight  config.ProcessHighlight
+	interval   time.Duration
+	graphStyle GraphStyle
+	// Rows of widgets of the Overview tab
+	overview [][]string
+	// Recent samples for the history graphs
+	cpuHistory     *History
+	netRxHistory   *History
+	netTxHistory   *History
+	ioReadHistory  *History
+	ioWriteHistory *History
+	// Battery charge or discharge rate in W, sampled with cpuHistory
+	batteryHistory *History
+	stats          models.SystemStats
+	processes      models.ProcessList
+	users          []models.UserStats
+	pods           []models.PodStats
+	vms            []models.VMStats
+	execs          models.ExecActivity
+	io             models.IOStats
+	ioProcesses    []models.Process
+	pressure       models.MemoryPressure
+	shm            models.SharedMemory
+	slab           models.SlabStats
+	thermal        []models.ThermalZone
+	boot           models.BootStats
+	desktop        models.DesktopStats
+	audio          models.AudioStats
+	kernelLog      []models.KernelMessage
+	kernelErr      string
+	security       models.SecurityStats
+	neighbors      []models.Neighbor
+	dns            models.DNSStats
+	activeTab      int
+	tabs           []string
+	views          map[string]tabModel
+	layout         Layout
+	// Processes tab sorting
+	processSortBy   collector.SortBy
+	processSortDesc bool
+	// Users tab sorting
+	userSortBy   collector.UserSortBy
+	userSortDesc bool
+	// I/O tab sorting
+	ioSortBy   collector.IOSortBy
+	ioSortDesc bool
+	// Whether new kernel log messages scroll in
+	kernelFollow bool
+	// Network namespace the Network tab shows instead of croptop's, ID 0
+	// for croptop's own, and what was read of it, nil until read
+	netns        models.NetNamespace
+	netnsNetwork *models.NamespaceNetwork
+	netnsErr     error
+	netnsBusy    bool
+	// Resolver latency test of the network tab
+	dnsCheck     config.DNSCheck
+	dnsChecks    []models.DNSCheck
+	dnsChecking  bool
+	lastDNSCheck time.Time
+	// TCP checks of the configured services, run every refresh
+	services        []config.Service
+	serviceChecks   []models.ServiceCheck
+	serviceChecking bool
+	// Remote agents of the Hosts tab and their last poll, by URL
+	hosts        []config.Host
+	hostStatus   map[string]hostStatus
+	hostsPolling bool
+	lastHostPoll time.Time
+	discovering  bool
+	// Alert events an agent with the same config recorded in the history,
+	// read while the Alerts tab is shown; alertLog is empty without one
+	alertLog        string
+	alertRecorded   []schema.Event
+	alertLogErr     error
+	alertLogReading bool
+	lastAlertLog    time.Time
+	// Docker or Podman containers, and the output of the one whose logs
+	// are open
+	containers          []models.Container
+	containersErr       error
+	containerLogsID     string
+	containerLogs       []string
+	containerLogsErr    error
+	containerLogsBusy   bool
+	containerLogsFollow bool
+	// Refresh backoff while the terminal is unfocused
+	unfocused   config.Unfocused
+	focused     bool
+	lastRefresh time.Time
+	// Config file, reloaded when it changes on disk
+	configPath    string
+	configWatcher *config.Watcher
+	// What croptop last wrote to the config file itself, not reloaded
+	configWritten []byte
+	// Pins of the processes kept at the top of the process table
+	pinned []string
+	// Optional columns of the process table turned on, by key
+	processColumns []string
+	// Process of the detail view while open, and its detail
+	detailPID int
+	detail    models.ProcessDetail
+	// Transient notice shown in the alert bar (config reloads, resume from suspend)
+	notice      string
+	noticeStyle lipgloss.Style
+	noticeUntil time.Time
+	// Directory the process list is exported to
+	exportDir string
+	// One bar per physical core in the CPU tab instead of per hyperthread
+	collapseSMT bool
+	// Process CPU% of the whole machine instead of one core
+	solarisMode bool
+	// Collect PSS and USS of every process, slowing refreshes down
+	accurateMemory bool
+	// Processes tab grouped by namespaces instead of the table, which reads
+	// the namespaces of every process
+	namespaceView bool
+	// Per-core grid of the CPU tab, the selected cell while selecting and
+	// the columns of the last render, to move up and down by
+	coreGrid      bool
+	coreSelecting bool
+	coreSelected  int
+	coreColumns   int
+	// Intervals of the data domains, when each was last started and
+	// whether it is still being collected
+	refresh       map[string]config.Duration
+	domainUpdated map[string]time.Time
+	domainBusy    map[string]bool
+	// WSL version when running under the Windows Subsystem for Linux, 0
+	// otherwise
+	wsl int
+	// Actions that change the system are refused
+	readOnly bool
+	// Packet headers are captured for the top talkers, unless starting the
+	// capture failed
+	captureTraffic bool
+	captureErr     error
+	// Features the permissions do not allow, by models.Feature*
+	locked map[string]models.Privilege
+	// Results of the user's actions, newest last
+	toasts []toast
+	// Modal prompt that gets the keys while open
+	dialog dialog
 	// Tab scrolling state
 	tabScrollOffset int
-	// Vertical scrolling state
-	verticalScrollOffset int
-	contentHeight        int // Track content height for scrolling
-	// Progress bars for different components
-	cpuProgress     progress.Model
-	memoryProgress  progress.Model
-	diskProgress    progress.Model
-	batteryProgress progress.Model
-	coreProgresses  []progress.Model // For CPU cores
-}
-
-func NewApp() *App {
-	// Initialize progress bars with consistent styling
-	cpuProg := progress.New(progress.WithDefaultGradient())
-	memoryProg := progress.New(progress.WithDefaultGradient())
-	diskProg := progress.New(progress.WithDefaultGradient())
-	batteryProg := progress.New(progress.WithDefaultGradient())
-
-	return &App{
-		collector:            collector.NewStatsCollector(),
-		tabs:                 []string{"Overview", "CPU", "Memory", "Processes", "Network", "Disk", "Battery"},
-		activeTab:            0,
-		tabScrollOffset:      0,
-		verticalScrollOffset: 0,
-		cpuProgress:          cpuProg,
-		memoryProgress:       memoryProg,
-		diskProgress:         diskProg,
-		batteryProgress:      batteryProg,
-		coreProgresses:       make([]progress.Model, 0), // Will be initialized based on CPU cores
+	// Gauges for different components
+	cpuGauge     Gauge
+	memoryGauge  Gauge
+	diskGauge    Gauge
+	batteryGauge Gauge
+	coreGauges   []Gauge // For CPU cores
+}
+
+func NewApp(cfg *config.Config) (*App, error) {
+	rules, err := alert.ParseRules(cfg.Alerts)
+	if err != nil {
+		return nil, err
+	}
+	watches, err := alert.ParseWatches(cfg.Watches)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := compileTags(cfg.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	statsCollector := collector.NewStatsCollector()
+	// Needs CAP_NET_ADMIN, exec activity falls back to fork counters otherwise
+	if err := statsCollector.StartProcEvents(); err != nil {
+		slog.Info("proc connector unavailable, showing fork counters only", "err", err)
+	}
+	// Opt-in, reading everyone's packet headers is not for every session
+	var captureErr error
+	if cfg.CaptureTraffic {
+		if captureErr = statsCollector.StartTrafficCapture(); captureErr != nil {
+			slog.Warn("traffic capture unavailable", "err", captureErr)
+		}
+	}
+	locked := make(map[string]models.Privilege)
+	for _, privilege := range statsCollector.DetectPrivileges() {
+		if !privilege.Available {
+			slog.Info("feature locked", "feature", privilege.Feature, "hint", privilege.Hint)
+			locked[privilege.Feature] = privilege
+		}
 	}
+
+	tabs := []string{"Overview", "CPU", "Memory", "Swap", "Processes", "Users"}
+	// The Pods tab only makes sense on Kubernetes nodes
+	if statsCollector.HasKubernetes() {
+		tabs = append(tabs, "Pods")
+	}
+	if statsCollector.HasVirtualization() {
+		tabs = append(tabs, "VMs")
+	}
+	if statsCollector.HasContainers() {
+		tabs = append(tabs, "Containers")
+	}
+	tabs = append(tabs, "Network", "Disk", "I/O")
+	// WSL has no battery of its own to show
+	wsl := collector.WSLVersion()
+	if wsl == 0 {
+		tabs = append(tabs, "Battery")
+	}
+	tabs = append(tabs, "Kernel")
+	// The Boot tab reads systemd-analyze
+	if statsCollector.HasSystemd() {
+		tabs = append(tabs, "Boot")
+	}
+	tabs = append(tabs, "Security", "Alerts")
+	if len(cfg.Hosts) > 0 || cfg.DiscoverHosts {
+		tabs = append(tabs, "Hosts")
+	}
+
+	// Without a config directory there is nothing to reload
+	configWatcher, err := config.NewWatcher(cfg.Path)
+	if err != nil {
+		slog.Debug("not watching the config file", "path", cfg.Path, "err", err)
+	}
+
+	app := &App{
+		collector:       statsCollector,
+		pipeline:        statsCollector.StartPipeline(time.Duration(cfg.Interval)),
+		alerts:          alert.NewEngine(rules),
+		watches:         watches,
+		events:          events.New(cfg.Events),
+		tags:            tags,
+		highlight:       cfg.ProcessHighlight,
+		interval:        time.Duration(cfg.Interval),
+		graphStyle:      graphStyle(cfg.GraphStyle),
+		overview:        cfg.Overview,
+		exportDir:       cfg.ExportDir,
+		collapseSMT:     cfg.CollapseSMT,
+		solarisMode:     cfg.SolarisMode,
+		accurateMemory:  cfg.AccurateMemory,
+		pinned:          cfg.Pinned,
+		processColumns:  cfg.ProcessColumns,
+		locked:          locked,
+		readOnly:        cfg.ReadOnly,
+		captureTraffic:  cfg.CaptureTraffic,
+		captureErr:      captureErr,
+		wsl:             wsl,
+		refresh:         cfg.Refresh,
+		domainUpdated:   make(map[string]time.Time),
+		domainBusy:      make(map[string]bool),
+		cpuHistory:      NewHistory(historySize).WithBaseline(anomalyFloorCPU),
+		netRxHistory:    NewHistory(historySize).WithBaseline(anomalyFloorNetwork),
+		netTxHistory:    NewHistory(historySize).WithBaseline(anomalyFloorNetwork),
+		ioReadHistory:   NewHistory(historySize).WithBaseline(anomalyFloorIO),
+		ioWriteHistory:  NewHistory(historySize).WithBaseline(anomalyFloorIO),
+		batteryHistory:  NewHistory(historySize).WithBaseline(anomalyFloorWatts),
+		configPath:      cfg.Path,
+		configWatcher:   configWatcher,
+		dnsCheck:        cfg.DNSCheck,
+		services:        cfg.Services,
+		hosts:           cfg.Hosts,
+		hostStatus:      make(map[string]hostStatus),
+		alertLog:        alertLogPath(cfg),
+		unfocused:       cfg.Unfocused,
+		focused:         true,
+		tabs:            tabs,
+		activeTab:       0,
+		processSortBy:   collector.SortByCPU,
+		processSortDesc: true,
+		userSortDesc:    true,
+		ioSortDesc:      true,
+		kernelFollow:    true,
+		tabScrollOffset: 0,
+		cpuGauge:        NewGauge(50, 12),
+		memoryGauge:     NewGauge(50, 12),
+		diskGauge:       NewGauge(40, 17),
+		batteryGauge:    NewGauge(40, 17),
+	}
+	app.views = make(map[string]tabModel, len(tabs))
+	for _, name := range tabs {
+		app.views[name] = app.newTabView(name)
+	}
+	return app, nil
+}
+
+// Snapshot returns the last collected data, for crash reports
+func (a *App) Snapshot() any {
+	return struct {
+		Tab       string
+		Stats     models.SystemStats
+		Processes models.ProcessList
+	}{a.currentTab(), a.stats, a.processes}
 }
 
 func (a *App) Init() tea.Cmd {
 	return tea.Batch(
-		a.updateStats(),
+		a.waitSnapshot(),
 		a.tick(),
+		a.watchConfig(),
 	)
 }
 
 func (a *App) tick() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(a.interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
-func (a *App) updateStats() tea.Cmd {
+// waitSnapshot waits for the next system stats of the pipeline
+func (a *App) waitSnapshot() tea.Cmd {
+	snapshots := a.pipeline.Snapshots()
+	return func() tea.Msg {
+		return snapshotMsg(<-snapshots)
+	}
+}
+
+// pacePipeline collects the system stats every interval, or as the
+// unfocused backoff asks while the terminal is unfocused
+func (a *App) pacePipeline() {
+	switch {
+	case a.focused:
+		a.pipeline.SetInterval(a.interval)
+	case a.unfocused.Mode == config.UnfocusedPause:
+		a.pipeline.Pause()
+	case a.unfocused.Mode == config.UnfocusedSlow && time.Duration(a.unfocused.Interval) > a.interval:
+		a.pipeline.SetInterval(time.Duration(a.unfocused.Interval))
+	default:
+		a.pipeline.SetInterval(a.interval)
+	}
+}
+
+// watchConfig waits for the next change of the config file
+func (a *App) watchConfig() tea.Cmd {
+	if a.configWatcher == nil {
+		return nil
+	}
+	watcher := a.configWatcher
 	return func() tea.Msg {
-		stats := a.collector.GetSystemStats()
-		processes := a.collector.GetProcessList()
+		return configChangedMsg{err: watcher.Wait()}
+	}
+}
+
+// eventsCloseTimeout is how long queued events may hold up an exit or a
+// config reload
+const eventsCloseTimeout = 5 * time.Second
+
+// Close sends the events still queued, once the program has ended
+func (a *App) Close() {
+	a.pipeline.Stop()
+	a.events.Close(eventsCloseTimeout)
+}
+
+// configNoticeDuration is how long a config reload is reported
+const configNoticeDuration = 10 * time.Second
+
+// reloadConfig applies the config file without a restart. An invalid file is
+// reported and the running settings are kept.
+func (a *App) reloadConfig() {
+	// Saving pins writes the file too; reloading that would reset what was
+	// toggled since the start
+	if data, err := caps.ReadFile(a.configPath); err == nil && a.configWritten != nil && bytes.Equal(data, a.configWritten) {
+		return
+	}
+
+	cfg, err := config.Load(a.configPath)
+	var rules []alert.Rule
+	var watches []alert.Watch
+	var tags []processTag
+	if err == nil {
+		rules, err = alert.ParseRules(cfg.Alerts)
+	}
+	if err == nil {
+		watches, err = alert.ParseWatches(cfg.Watches)
+	}
+	if err == nil {
+		tags, err = compileTags(cfg.Tags)
+	}
+	if err == nil {
+		err = i18n.SetLocale(cfg.Locale)
+	}
+	if err != nil {
+		slog.Warn("config not reloaded", "path", a.configPath, "err", err)
+		a.setNotice(i18n.Sprintf("✗ Config not reloaded: %v", err), ErrorStyle, configNoticeDuration)
+		return
+	}
+
+	a.alerts.SetRules(rules)
+	a.watches = watches
+	// The old emitter delivers what it has queued in the background
+	go a.events.Close(eventsCloseTimeout)
+	a.events = events.New(cfg.Events)
+	a.tags = tags
+	a.highlight = cfg.ProcessHighlight
+	a.interval = time.Duration(cfg.Interval)
+	a.graphStyle = graphStyle(cfg.GraphStyle)
+	a.overview = cfg.Overview
+	a.exportDir = cfg.ExportDir
+	a.collapseSMT = cfg.CollapseSMT
+	a.solarisMode = cfg.SolarisMode
+	a.accurateMemory = cfg.AccurateMemory
+	a.pinned = cfg.Pinned
+	a.processColumns = cfg.ProcessColumns
+	a.refresh = cfg.Refresh
+	a.dnsCheck = cfg.DNSCheck
+	// The next round checks the new list
+	a.services = cfg.Services
+	// The Hosts tab only exists if there were hosts at the start
+	a.hosts = cfg.Hosts
+	a.unfocused = cfg.Unfocused
+	if path := alertLogPath(cfg); path != a.alertLog {
+		a.alertLog, a.alertRecorded, a.alertLogErr, a.lastAlertLog = path, nil, nil, time.Time{}
+	}
+	a.pacePipeline()
+	slog.Info("config reloaded", "path", a.configPath)
+	a.setNotice(i18n.Sprintf("✓ Reloaded %s", a.configPath), SuccessStyle, configNoticeDuration)
+}
+
+// setNotice shows a message in the alert bar for the given duration
+func (a *App) setNotice(text string, style lipgloss.Style, duration time.Duration) {
+	a.notice = text
+	a.noticeStyle = style
+	a.noticeUntil = time.Now().Add(duration)
+}
+
+// allowAction reports whether an action that changes the system may run,
+// telling the user why not in read-only mode
+func (a *App) allowAction(action string) bool {
+	if !a.readOnly {
+		return true
+	}
+	slog.Info("action refused in read-only mode", "action", action)
+	a.toast(toastWarning, i18n.Sprintf("🔒 %s is disabled in read-only mode", action))
+	return false
+}
+
+// shouldRefresh applies the unfocused refresh backoff to a tick
+func (a *App) shouldRefresh() bool {
+	if a.focused {
+		return true
+	}
+	switch a.unfocused.Mode {
+	case config.UnfocusedPause:
+		return false
+	case config.UnfocusedSlow:
+		return time.Since(a.lastRefresh) >= time.Duration(a.unfocused.Interval)
+	}
+	return true
+}
+
+func (a *App) updateStats() tea.Cmd {
+	processSortBy, processSortDesc := a.processSortBy, a.processSortDesc
+	userSortBy, userSortDesc := a.userSortBy, a.userSortDesc
+	ioSortBy, ioSortDesc := a.ioSortBy, a.ioSortDesc
+	accurateMemory := a.accurateMemory
+	namespaceView := a.namespaceView
+	tags := a.tags
+	reportEvents := a.events.Enabled()
+	// A paused kernel log keeps the messages it shows
+	showKernelLog := a.currentTab() == "Kernel" && a.kernelFollow
+	update := func() tea.Msg {
+		processes := a.collector.GetProcessListSorted(processSortBy, processSortDesc)
+		if accurateMemory {
+			a.collector.AddMemoryDetail(processes.Processes)
+		}
+		if tagsNeedCgroup(tags) {
+			a.collector.AddCgroups(processes.Processes)
+		}
+		if namespaceView {
+			a.collector.AddNamespaces(processes.Processes)
+		}
+		tagProcesses(tags, processes.Processes)
+		users := a.collector.GetUserStats(processes, userSortBy, userSortDesc)
+		execs := a.collector.GetExecActivity()
+		io := a.collector.GetIOStats()
+		ioProcesses := a.collector.TopIOProcesses(processes, ioSortBy, ioSortDesc)
+
+		// Only needed to report OOM kills as events
+		var oomKills uint64
+		if reportEvents {
+			oomKills = a.collector.OOMKillCount()
+		}
+
+		var kernelLog []models.KernelMessage
+		var kernelErr string
+		if showKernelLog {
+			var err error
+			if kernelLog, err = a.collector.GetKernelLog(); err != nil {
+				kernelErr = err.Error()
+			}
+		}
+
 		return struct {
-			stats     models.SystemStats
-			processes models.ProcessList
-		}{stats, processes}
+			processes   models.ProcessList
+			users       []models.UserStats
+			execs       models.ExecActivity
+			io          models.IOStats
+			ioProcesses []models.Process
+			kernelLog   []models.KernelMessage
+			kernelErr   string
+			oomKills    uint64
+		}{processes, users, execs, io, ioProcesses, kernelLog, kernelErr, oomKills}
+	}
+	cmds := append(a.updateDomains(), update)
+	if a.currentTab() == "Processes" {
+		cmds = append(cmds, a.loadProcessDetail())
+	}
+	return tea.Batch(cmds...)
+}
+
+// currentTab returns the name of the active tab
+func (a *App) currentTab() string {
+	return a.tabs[a.activeTab]
+}
+
+func (a *App) hasTab(name string) bool {
+	for _, tab := range a.tabs {
+		if tab == name {
+			return true
+		}
 	}
+	return false
 }
 
-// Initialize core progress bars based on the number of CPU cores
-func (a *App) initializeCoreProgresses(coreCount int) {
-	if len(a.coreProgresses) != coreCount {
-		a.coreProgresses = make([]progress.Model, coreCount)
-		for i := range a.coreProgresses {
-			a.coreProgresses[i] = progress.New(progress.WithDefaultGradient())
-			a.coreProgresses[i].Width = 30
+// Initialize core gauges based on the number of CPU cores
+func (a *App) initializeCoreGauges(coreCount int) {
+	if len(a.coreGauges) != coreCount {
+		// Only the first time, so hotplug keeps what the user chose
+		if a.coreGauges == nil {
+			a.coreGrid = coreCount > coreGridThreshold
 		}
+		a.coreGauges = make([]Gauge, coreCount)
+		for i := range a.coreGauges {
+			a.coreGauges[i] = NewGauge(30, 0)
+			a.coreGauges[i].Resize(a.layout.Content)
+		}
+	}
+}
+
+// gauges lists every gauge so they can be resized together
+func (a *App) gauges() []*Gauge {
+	gauges := []*Gauge{&a.cpuGauge, &a.memoryGauge, &a.diskGauge, &a.batteryGauge}
+	for i := range a.coreGauges {
+		gauges = append(gauges, &a.coreGauges[i])
 	}
+	return gauges
 }
 
 // Calculate visible tabs based on screen width and scroll offset
 func (a *App) getVisibleTabs() ([]string, []int, bool, bool) {
-	if a.width <= 0 {
+	if a.layout.Width <= 0 {
 		return a.tabs, []int{}, false, false
 	}
 
 	// Estimate tab width (tab name + padding + borders)
 	// This is an approximation - you might need to adjust based on your styling
 	estimatedTabWidth := func(tabName string) int {
-		return len(tabName) + 6 // 6 for padding and borders
+		return lipgloss.Width(i18n.T(tabName)) + 6 // 6 for padding and borders
 	}
 
 	visibleTabs := []string{}
 	visibleIndices := []int{}
 	currentWidth := 0
-	availableWidth := a.width - 10 // Leave some margin
+	availableWidth := a.layout.Width - 10 // Leave some margin
 
 	// Ensure active tab is visible by adjusting scroll offset
 	a.ensureActiveTabVisible()
@@ -154,18 +657,18 @@ func (a *App) ensureActiveTabVisible() {
 
 // Raw calculation without ensuring active tab visibility (to avoid infinite recursion)
 func (a *App) getVisibleTabsRaw() ([]string, []int, bool, bool) {
-	if a.width <= 0 {
+	if a.layout.Width <= 0 {
 		return a.tabs, []int{}, false, false
 	}
 
 	estimatedTabWidth := func(tabName string) int {
-		return len(tabName) + 6
+		return lipgloss.Width(i18n.T(tabName)) + 6
 	}
 
 	visibleTabs := []string{}
 	visibleIndices := []int{}
 	currentWidth := 0
-	availableWidth := a.width - 10
+	availableWidth := a.layout.Width - 10
 
 	for i := a.tabScrollOffset; i < len(a.tabs); i++ {
 		tabWidth := estimatedTabWidth(a.tabs[i])
@@ -184,98 +687,49 @@ func (a *App) getVisibleTabsRaw() ([]string, []int, bool, bool) {
 }
 
 // Get the height available for content (excluding sticky header elements)
-func (a *App) getContentAreaHeight() int {
-	// Reserve space for: title (2 lines), tabs (2 lines), help (2 lines), margins (2 lines)
-	reservedHeight := 8
-	return max(1, a.height-reservedHeight)
-}
-
-// Get the maximum scroll offset based on content height
-func (a *App) getMaxScrollOffset() int {
-	availableHeight := a.getContentAreaHeight()
-	if a.contentHeight <= availableHeight {
-		return 0
-	}
-	return a.contentHeight - availableHeight
-}
-
-// Clamp vertical scroll offset to valid range
-func (a *App) clampVerticalScroll() {
-	maxOffset := a.getMaxScrollOffset()
-	a.verticalScrollOffset = max(0, min(a.verticalScrollOffset, maxOffset))
-}
-
-// Apply vertical scrolling to content by truncating lines
-func (a *App) applyVerticalScroll(content string) string {
-	lines := strings.Split(content, "\n")
-	a.contentHeight = len(lines)
-
-	a.clampVerticalScroll()
-
-	availableHeight := a.getContentAreaHeight()
-
-	// If content fits entirely, return as-is
-	if len(lines) <= availableHeight {
-		return content
-	}
-
-	// Calculate visible lines
-	startLine := a.verticalScrollOffset
-	endLine := min(startLine+availableHeight, len(lines))
-
-	visibleLines := lines[startLine:endLine]
-
-	// Add scroll indicators if needed
-	result := strings.Join(visibleLines, "\n")
-
-	// Add scroll indicators in the content area
-	if a.verticalScrollOffset > 0 {
-		scrollUpIndicator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("12")).
-			Bold(true).
-			Render("▲ More content above")
-		result = scrollUpIndicator + "\n" + result
-	}
-
-	if a.verticalScrollOffset < a.getMaxScrollOffset() {
-		scrollDownIndicator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("12")).
-			Bold(true).
-			Render("▼ More content below")
-		result = result + "\n" + scrollDownIndicator
-	}
-
-	return result
-}
-
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		a.width = msg.Width
-		a.height = msg.Height
-
-		// Update progress bar widths based on window size
-		progressWidth := min(50, a.width-20)
-		a.cpuProgress.Width = progressWidth
-		a.memoryProgress.Width = progressWidth
-		a.diskProgress.Width = min(40, a.width-25)
-		a.batteryProgress.Width = min(40, a.width-25)
+		a.layout = newLayout(msg.Width, msg.Height)
+		for _, gauge := range a.gauges() {
+			gauge.Resize(a.layout.Content)
+		}
 
 		return a, nil
 
 	case tea.KeyMsg:
+		if d := a.dialog; d != nil {
+			if msg.String() == "ctrl+c" {
+				return a, tea.Quit
+			}
+			cmd, done := d.Update(msg)
+			// Unless confirming opened the next dialog
+			if done && a.dialog == d {
+				a.dialog = nil
+			}
+			return a, cmd
+		}
+
+		view := a.views[a.currentTab()]
+		if scroller, ok := view.(horizontalScroller); ok && scroller.ScrollsHorizontally() {
+			switch msg.String() {
+			case "left", "h", "right", "l":
+				return a, view.Update(msg)
+			}
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return a, tea.Quit
 		case "left", "h":
 			if a.activeTab > 0 {
 				a.activeTab--
-				a.verticalScrollOffset = 0 // Reset scroll when changing tabs
+				return a, a.views[a.currentTab()].Init()
 			}
 		case "right", "l":
 			if a.activeTab < len(a.tabs)-1 {
 				a.activeTab++
-				a.verticalScrollOffset = 0 // Reset scroll when changing tabs
+				return a, a.views[a.currentTab()].Init()
 			}
 		case "shift+left", "H":
 			// Scroll tabs left
@@ -288,110 +742,299 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if canScrollRight {
 				a.tabScrollOffset++
 			}
-		case "up", "k":
-			// Handle different behaviors based on current tab
-			if a.activeTab == 3 { // Processes tab
-				if a.selectedRow > 0 {
-					a.selectedRow--
-				}
-			} else {
-				// Vertical scroll up for other tabs
-				if a.verticalScrollOffset > 0 {
-					a.verticalScrollOffset--
-				}
-			}
-		case "down", "j":
-			// Handle different behaviors based on current tab
-			if a.activeTab == 3 { // Processes tab
-				if a.selectedRow < len(a.processes.Processes)-1 {
-					a.selectedRow++
-				}
-			} else {
-				// Vertical scroll down for other tabs
-				a.verticalScrollOffset++
-				a.clampVerticalScroll() // Ensure we don't scroll past content
-			}
-		case "pgup", "ctrl+u":
-			// Page up - scroll up by half the available height
-			scrollAmount := max(1, a.getContentAreaHeight()/2)
-			a.verticalScrollOffset = max(0, a.verticalScrollOffset-scrollAmount)
-		case "pgdown", "ctrl+d":
-			// Page down - scroll down by half the available height
-			scrollAmount := max(1, a.getContentAreaHeight()/2)
-			a.verticalScrollOffset += scrollAmount
-			a.clampVerticalScroll()
-		case "home", "ctrl+home":
-			// Go to top
-			a.verticalScrollOffset = 0
-		case "end", "ctrl+end":
-			// Go to bottom
-			a.verticalScrollOffset = a.getMaxScrollOffset()
+		default:
+			// Everything else belongs to the active tab
+			return a, a.views[a.currentTab()].Update(msg)
+		}
+
+	case tea.FocusMsg:
+		a.focused = true
+		// Catch up right away instead of waiting for the next slow refresh
+		a.pacePipeline()
+		return a, a.updateStats()
+
+	case tea.BlurMsg:
+		a.focused = false
+		a.pacePipeline()
+		return a, nil
+
+	case snapshotMsg:
+		a.stats = models.SystemStats(msg)
+		a.cpuHistory.Add(a.stats.CPU.Usage)
+		a.netRxHistory.Add(a.stats.Network.RxRate)
+		a.netTxHistory.Add(a.stats.Network.TxRate)
+		a.batteryHistory.Add(a.stats.Battery.Watts)
+		if a.stats.SuspendedFor > 0 {
+			a.setNotice(i18n.Sprintf("⏾ Resumed from suspend at %s after %s, rates restarted",
+				time.Now().Format("15:04:05"), formatDuration(a.stats.SuspendedFor)),
+				lipgloss.NewStyle().Foreground(lipgloss.Color("12")), resumeNoticeDuration)
 		}
+		// The processes and the alerts follow the pipeline's pace
+		return a, tea.Batch(a.updateStats(), a.waitSnapshot())
 
 	case tickMsg:
-		return a, tea.Batch(a.updateStats(), a.tick())
+		if !a.shouldRefresh() {
+			return a, a.tick()
+		}
+		a.lastRefresh = time.Now()
+		cmds := []tea.Cmd{a.tick()}
+		if a.dnsCheck.Enabled && !a.dnsChecking && time.Since(a.lastDNSCheck) >= time.Duration(a.dnsCheck.Interval) {
+			cmds = append(cmds, a.checkDNS())
+		}
+		cmds = append(cmds, a.checkServices())
+		if a.currentTab() == "Hosts" && a.hostsDue() {
+			cmds = append(cmds, a.pollHosts())
+		}
+		if a.currentTab() == "Containers" {
+			cmds = append(cmds, a.fetchContainerLogs())
+		}
+		if a.currentTab() == "Network" {
+			cmds = append(cmds, a.fetchNetNamespace())
+		}
+		if a.currentTab() == "Alerts" && a.alertLogDue() {
+			cmds = append(cmds, a.readAlertLog())
+		}
+		return a, tea.Batch(cmds...)
+
+	case configChangedMsg:
+		if msg.err != nil {
+			slog.Warn("stopped watching the config file", "err", msg.err)
+			a.setNotice(i18n.Sprintf("✗ Stopped watching the config file: %v", msg.err), ErrorStyle, configNoticeDuration)
+			return a, nil
+		}
+		a.reloadConfig()
+		return a, a.watchConfig()
+
+	case dnsCheckMsg:
+		a.dnsChecks = msg
+		a.dnsChecking = false
+		return a, nil
+
+	case serviceCheckMsg:
+		a.serviceChecks = msg
+		a.serviceChecking = false
+		return a, nil
+
+	case hostsMsg:
+		a.applyHosts(msg)
+		return a, nil
+
+	case alertLogMsg:
+		a.applyAlertLog(msg)
+		return a, nil
+
+	case netnsMsg:
+		a.netnsBusy = false
+		// Back to the host or another namespace picked meanwhile
+		if msg.id == a.netns.ID {
+			a.netnsNetwork, a.netnsErr = &msg.network, msg.err
+		}
+		return a, nil
+
+	case containerLogsMsg:
+		a.containerLogsBusy = false
+		// Logs closed or another container's opened meanwhile
+		if msg.id == a.containerLogsID {
+			a.containerLogs, a.containerLogsErr = msg.lines, msg.err
+		}
+		return a, nil
+
+	case containerActionMsg:
+		if msg.err != nil {
+			slog.Warn("container action failed", "container", msg.name, "action", msg.action, "err", msg.err)
+			a.toast(toastError, i18n.Sprintf("✗ %s %s failed: %v", msg.action, msg.name, msg.err))
+			return a, nil
+		}
+		slog.Info("container action", "container", msg.name, "action", msg.action)
+		a.toast(toastSuccess, "✓ "+i18n.Sprintf(msg.done, msg.name))
+		a.domainUpdated[config.DomainContainers] = time.Time{}
+		return a, a.updateStats()
+
+	case hostsDiscoveredMsg:
+		a.pickDiscoveredHost(msg)
+		return a, nil
+
+	case processExportMsg:
+		if msg.err != nil {
+			slog.Warn("process export failed", "path", msg.path, "err", msg.err)
+			a.toast(toastError, i18n.Sprintf("✗ Export failed: %v", msg.err))
+			return a, nil
+		}
+		a.toast(toastSuccess, i18n.Sprintf("✓ Exported %d processes to %s", msg.rows, msg.path))
+		return a, nil
+
+	case clipboardMsg:
+		if msg.err != nil {
+			slog.Warn("copying to the clipboard failed", "what", msg.what, "err", msg.err)
+			a.toast(toastError, i18n.Sprintf("✗ Copy failed: %v", msg.err))
+			return a, nil
+		}
+		a.toast(toastSuccess, i18n.Sprintf("✓ Copied %s to the clipboard", msg.what))
+		return a, nil
+
+	case processActionMsg:
+		if msg.err != nil {
+			slog.Warn("process action failed", "action", msg.action, "err", msg.err)
+			a.toast(toastError, i18n.Sprintf("✗ %s failed: %v", msg.action, msg.err))
+			return a, nil
+		}
+		slog.Info("process action", "action", msg.done)
+		a.toast(toastSuccess, "✓ "+msg.done)
+		return a, a.updateStats()
+
+	case unitActionMsg:
+		if msg.err != nil {
+			slog.Warn("unit action failed", "unit", msg.unit, "action", msg.action, "err", msg.err)
+			a.toast(toastError, i18n.Sprintf("✗ %s %s failed: %v", i18n.T(unitActionTexts[msg.action].name), msg.unit, msg.err))
+			return a, nil
+		}
+		slog.Info("unit action", "unit", msg.unit, "action", msg.action)
+		a.toast(toastSuccess, "✓ "+i18n.Sprintf(unitActionTexts[msg.action].done, msg.unit))
+		// Show what the unit uses now rather than at the next interval
+		a.domainUpdated[config.DomainBoot] = time.Time{}
+		return a, a.updateStats()
+
+	case powerProfileMsg:
+		if msg.err != nil {
+			slog.Warn("switching power profile failed", "profile", msg.profile, "err", msg.err)
+			a.toast(toastError, i18n.Sprintf("✗ Could not switch to %s: %v", msg.profile, msg.err))
+			return a, nil
+		}
+		a.toast(toastSuccess, i18n.Sprintf("✓ Switched power profile to %s", msg.profile))
+		return a, a.updateStats()
+
+	case processDetailMsg:
+		// A process that exited keeps the detail last read
+		if msg.err == nil && msg.detail.PID == a.detailPID {
+			a.detail = msg.detail
+		}
+		return a, nil
+
+	case domainMsg:
+		a.domainBusy[msg.name] = false
+		msg.apply(a)
+		return a, nil
 
 	case struct {
-		stats     models.SystemStats
-		processes models.ProcessList
+		processes   models.ProcessList
+		users       []models.UserStats
+		execs       models.ExecActivity
+		io          models.IOStats
+		ioProcesses []models.Process
+		kernelLog   []models.KernelMessage
+		kernelErr   string
+		oomKills    uint64
 	}:
-		a.stats = msg.stats
+		a.ioReadHistory.Add(msg.io.ReadRate)
+		a.ioWriteHistory.Add(msg.io.WriteRate)
 		a.processes = msg.processes
+		a.users = msg.users
+		a.execs = msg.execs
+		a.io = msg.io
+		a.ioProcesses = msg.ioProcesses
+		if msg.kernelLog != nil || msg.kernelErr != "" {
+			a.kernelLog = msg.kernelLog
+			a.kernelErr = msg.kernelErr
+		}
+
+		var cmds []tea.Cmd
+		for _, event := range a.alerts.Evaluate(time.Now(), a.stats, a.processes) {
+			if event.Type == alert.EventFired {
+				slog.Info("alert fired", "rule", event.Alert.Rule.Source, "value", event.Alert.Value)
+			} else {
+				slog.Info("alert cleared", "rule", event.Alert.Rule.Source, "value", event.Alert.Value, "peak", event.Alert.Peak)
+			}
+			if event.Type == alert.EventFired && event.Alert.Rule.Action == alert.ActionNotify {
+				cmds = append(cmds, notifyAlert(event.Alert))
+			}
+			a.events.Emit(events.FromAlert(event))
+		}
+		if a.events.Enabled() {
+			for _, event := range a.detector.Check(time.Now(), a.stats, a.processes, msg.oomKills) {
+				a.events.Emit(event)
+			}
+		}
 
 		// Initialize core progresses if needed
-		a.initializeCoreProgresses(len(a.stats.CPU.Cores))
+		a.initializeCoreGauges(len(a.stats.CPU.Cores))
+		return a, tea.Batch(cmds...)
 	}
 
 	return a, nil
 }
 
 func (a *App) View() string {
-	if a.width == 0 {
+	if a.layout.Width == 0 {
 		return "Loading..."
 	}
 
 	// Title (sticky)
-	title := TitleStyle.Width(a.width).Render("CropTop")
+	titleText := "CropTop"
+	if !a.focused {
+		switch a.unfocused.Mode {
+		case config.UnfocusedPause:
+			titleText += " " + i18n.T("(paused while unfocused)")
+		case config.UnfocusedSlow:
+			titleText += " " + i18n.Sprintf("(refreshing every %s while unfocused)", time.Duration(a.unfocused.Interval))
+		}
+	}
+	if a.readOnly {
+		titleText += " " + i18n.T("[read-only]")
+	}
+	if len(a.stats.Stale) > 0 {
+		// A hung collector, e.g. on a dead network mount, serves old values
+		titleText += " " + i18n.Sprintf("(stale: %s)", strings.Join(a.stats.Stale, ", "))
+	}
+	title := TitleStyle.Width(a.layout.Width).Render(titleText)
 
 	// Tabs (sticky)
 	tabs := a.renderTabs()
 
 	// Content (scrollable)
-	var content string
-	switch a.activeTab {
-	case 0:
-		content = a.renderOverview()
-	case 1:
-		content = a.renderCPU()
-	case 2:
-		content = a.renderMemory()
-	case 3:
-		content = a.renderProcesses()
-	case 4:
-		content = a.renderNetwork()
-	case 5:
-		content = a.renderDisk()
-	case 6:
-		content = a.renderBattery()
-	}
-
-	// Apply vertical scrolling to content
-	scrollableContent := a.applyVerticalScroll(content)
+	content := a.layout.Box(a.views[a.currentTab()].View(a.layout.Content, a.layout.ContentHeight))
 
 	// Help text (sticky)
 	help := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
-		Render("←/→ h/l: tabs • Shift+←/→ H/L: scroll tabs • ↑/↓ k/j: scroll • PgUp/PgDn: page scroll • Home/End: top/bottom • q: quit")
+		Render(ansi.Truncate(i18n.T("←/→ h/l: tabs • Shift+←/→ H/L: scroll tabs • ↑/↓ k/j: scroll • s/r: sort/reverse • PgUp/PgDn: page scroll • Home/End: top/bottom • q: quit"), a.layout.Width, "…"))
 
-	return lipgloss.JoinVertical(lipgloss.Left,
+	return a.renderToasts(a.renderDialog(lipgloss.JoinVertical(lipgloss.Left,
 		title,
 		"",
 		tabs,
-		"",
-		scrollableContent,
-		"",
+		a.renderAlertBar(),
+		content,
+		a.renderWatches(),
 		help,
-	)
+	)))
+}
+
+// resumeNoticeDuration is how long the resume notice stays up
+const resumeNoticeDuration = 30 * time.Second
+
+// renderAlertBar shows the firing alerts on the line below the tabs. While
+// none is firing, a pending notice such as a config reload or a resume has
+// the line until it expires.
+func (a *App) renderAlertBar() string {
+	active := a.alerts.Active()
+	if len(active) == 0 {
+		if a.notice != "" && time.Now().Before(a.noticeUntil) {
+			return a.noticeStyle.Render(truncateString(a.notice, max(10, a.layout.Width-2)))
+		}
+		return ""
+	}
+
+	parts := make([]string, 0, len(active))
+	for _, firing := range active {
+		parts = append(parts, i18n.Sprintf("%s (%.1f)", firing.Rule.Source, firing.Value))
+	}
+	line := truncateString(i18n.Sprintf("⚠ %d alert(s): %s", len(active), strings.Join(parts, " • ")), max(10, a.layout.Width-2))
+
+	// Critical alerts are sorted first
+	if active[0].Rule.Severity == alert.SeverityCritical {
+		return ErrorStyle.Render(line)
+	}
+	return WarningStyle.Render(line)
 }
 
 func (a *App) renderTabs() string {
@@ -411,10 +1054,11 @@ func (a *App) renderTabs() string {
 	// Render visible tabs
 	for i, tab := range visibleTabs {
 		realIndex := visibleIndices[i]
+		// Tabs are dispatched by their English name, only the label is translated
 		if realIndex == a.activeTab {
-			tabElements = append(tabElements, ActiveTabStyle.Render(tab))
+			tabElements = append(tabElements, ActiveTabStyle.Render(i18n.T(tab)))
 		} else {
-			tabElements = append(tabElements, InactiveTabStyle.Render(tab))
+			tabElements = append(tabElements, InactiveTabStyle.Render(i18n.T(tab)))
 		}
 	}
 
@@ -430,187 +1074,542 @@ func (a *App) renderTabs() string {
 	return lipgloss.JoinHorizontal(lipgloss.Left, tabElements...)
 }
 
-func (a *App) renderOverview() string {
-	cpu := fmt.Sprintf("CPU: %.1f%%", a.stats.CPU.Usage)
-	memory := fmt.Sprintf("Memory: %.1f%%", a.stats.Memory.UsagePercent)
-	processes := fmt.Sprintf("Processes: %d", a.processes.Total)
-	uptime := fmt.Sprintf("Uptime: %v", a.stats.Uptime.Truncate(time.Second))
-
-	// Create progress bars for overview
-	cpuBar := a.cpuProgress.ViewAs(a.stats.CPU.Usage / 100.0)
-	memBar := a.memoryProgress.ViewAs(a.stats.Memory.UsagePercent / 100.0)
-
-	return BaseStyle.Width(a.width-4).Render(
-		lipgloss.JoinVertical(lipgloss.Left,
-			HeaderStyle.Render("System Overview"),
-			"",
-			LabelStyle.Render(cpu),
-			cpuBar,
-			"",
-			LabelStyle.Render(memory),
-			memBar,
-			"",
-			LabelStyle.Render(processes),
-			LabelStyle.Render(uptime),
-			"",
-			"",
-			HeaderStyle.Render("Quick Stats"),
-			fmt.Sprintf("CPU Temperature: %.1f°C", a.stats.CPU.Temp),
-			fmt.Sprintf("CPU Cores: %d", len(a.stats.CPU.Cores)),
-			fmt.Sprintf("Memory Total: %.1f GB", float64(a.stats.Memory.Total)/float64(KBToGB))),
-		"Disk Usage: Multiple drives",
-		fmt.Sprintf("Network Interfaces: %d", len(a.stats.Network.Interfaces)),
-	)
-}
-
 func (a *App) renderCPU() string {
 	content := []string{
-		HeaderStyle.Render("CPU Information"),
+		sectionHeader(i18n.T("CPU Information")),
 		"",
-		fmt.Sprintf("%s %s", LabelStyle.Render("Model:"), ValueStyle.Render(a.stats.CPU.Model)),
-		fmt.Sprintf("%s %.1f MHz", LabelStyle.Render("Frequency:"), a.stats.CPU.Frequency),
-		fmt.Sprintf("%s %.1f°C", LabelStyle.Render("Temperature:"), a.stats.CPU.Temp),
+		i18n.Sprintf("%s %s", LabelStyle.Render(i18n.T("Model:")), ValueStyle.Render(a.stats.CPU.Model)),
+		i18n.Sprintf("%s %.1f MHz", LabelStyle.Render(i18n.T("Frequency:")), a.stats.CPU.Frequency),
+		i18n.Sprintf("%s %.1f°C", LabelStyle.Render(i18n.T("Temperature:")), a.stats.CPU.Temp),
 		"",
-		fmt.Sprintf("%s %.1f%%", LabelStyle.Render("Overall Usage:"), a.stats.CPU.Usage),
-		a.cpuProgress.ViewAs(a.stats.CPU.Usage / 100.0),
+		i18n.Sprintf("%s %.1f%%", LabelStyle.Render(i18n.T("Overall Usage:")), a.stats.CPU.Usage),
+		a.cpuGauge.View(a.stats.CPU.Usage),
 		"",
-		HeaderStyle.Render("Per-Core Usage"),
 	}
 
-	for i, usage := range a.stats.CPU.Cores {
-		if i < len(a.coreProgresses) {
-			content = append(content,
-				fmt.Sprintf("Core %d: %.1f%%", i, usage),
-				a.coreProgresses[i].ViewAs(usage/100.0),
-				"",
-			)
+	content = append(content, a.renderGraph(i18n.T("Usage History:"), a.cpuHistory, 100, lipgloss.Color("36"), func(usage float64) string {
+		return i18n.Sprintf("%.1f%%", usage)
+	})...)
+	content = append(content, "")
+
+	if power := a.stats.CPU.Power; len(power.Domains) > 0 {
+		content = append(content,
+			sectionHeader(i18n.T("Power (RAPL)")),
+			i18n.Sprintf("%s %.1f W", LabelStyle.Render(i18n.T("Package:")), power.PackageWatts),
+		)
+		for _, domain := range power.Domains {
+			content = append(content, i18n.Sprintf("  %-12s %6.2f W", domain.Name, domain.Watts))
 		}
+		content = append(content,
+			i18n.Sprintf("%s %.2f Wh", LabelStyle.Render(i18n.T("Session Energy:")), power.SessionEnergy/3600),
+			"",
+		)
 	}
 
-	return BaseStyle.Width(a.width - 4).Render(
-		lipgloss.JoinVertical(lipgloss.Left, content...),
-	)
+	if notice := a.lockedNotice(models.FeatureRAPL); notice != "" {
+		content = append(content, sectionHeader(i18n.T("Power (RAPL)")), notice, "")
+	}
+
+	if len(a.thermal) > 0 {
+		content = append(content, a.renderThermalZones()...)
+		content = append(content, "")
+	}
+
+	if a.audio.Server != "" {
+		content = append(content, a.renderAudio()...)
+		content = append(content, "")
+	}
+
+	if cgroup := a.stats.Cgroup; cgroup.CPULimit > 0 {
+		content = append(content,
+			sectionHeader(i18n.T("Cgroup Limit")),
+			i18n.Sprintf("%s %.2f cores", LabelStyle.Render(i18n.T("CPU Quota:")), cgroup.CPULimit),
+			i18n.Sprintf("%s %.1f%%", LabelStyle.Render(i18n.T("Usage of Limit:")), cgroup.CPUUsage),
+			a.cpuGauge.View(cgroup.CPUUsage),
+			"",
+		)
+	}
+
+	content = append(content, sectionHeader(i18n.T("Per-Core Usage")))
+	if len(a.coreGauges) == len(a.stats.CPU.Cores) {
+		content = append(content, a.renderCores()...)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
 }
 
 func (a *App) renderMemory() string {
 	mem := a.stats.Memory
 
 	content := []string{
-		HeaderStyle.Render("Memory Information"),
+		sectionHeader(i18n.T("Memory Information")),
+		"",
+		i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Total:")), mem.Total/KBToGB),
+		i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Used:")), mem.Used/KBToGB),
+		i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Free:")), mem.Free/KBToGB),
+		i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Available:")), mem.Available/KBToGB),
 		"",
-		fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Total:"), mem.Total/KBToGB),
-		fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Used:"), mem.Used/KBToGB),
-		fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Free:"), mem.Free/KBToGB),
-		fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Available:"), mem.Available/KBToGB),
+		i18n.Sprintf("%s %.1f%% (%.1f GB/%.1f GB)", LabelStyle.Render(i18n.T("Usage:")), mem.UsagePercent, a.stats.Memory.Used/KBToGB, a.stats.Memory.Total/KBToGB),
+		a.memoryGauge.View(mem.UsagePercent),
 		"",
-		fmt.Sprintf("%s %.1f%% (%.1f GB/%.1f GB)", LabelStyle.Render("Usage:"), mem.UsagePercent, a.stats.Memory.Used/KBToGB, a.stats.Memory.Total/KBToGB),
-		a.memoryProgress.ViewAs(mem.UsagePercent / 100.0),
+		SegmentedBar([]Segment{
+			{Label: i18n.T("used"), Value: mem.Used, Color: lipgloss.Color("205")},
+			{Label: i18n.T("reclaimable cache"), Value: mem.Available - mem.Free, Color: lipgloss.Color("39")},
+			{Label: i18n.T("free"), Value: mem.Free, Color: lipgloss.Color("36")},
+		}, mem.Total, min(50, a.layout.Content-12)),
 		"",
-		HeaderStyle.Render("Swap"),
-		fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Total:"), mem.SwapTotal/KBToGB),
-		fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Used:"), mem.SwapUsed/KBToGB),
+		sectionHeader(i18n.T("Swap")),
+		i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Total:")), mem.SwapTotal/KBToGB),
+		i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Used:")), mem.SwapUsed/KBToGB),
 	}
 
-	return BaseStyle.Width(a.width - 4).Render(
-		lipgloss.JoinVertical(lipgloss.Left, content...),
-	)
-}
-
-func (a *App) renderProcesses() string {
-	// Calculate visible rows (leave space for header and stats)
-	visibleRows := a.height - 8
-	if visibleRows < 1 {
-		visibleRows = 1
+	switch a.wsl {
+	case 1:
+		content = append(content, "", WarningStyle.Render(i18n.T("WSL 1: the totals are those of Windows, shared with every Windows program")))
+	case 2:
+		content = append(content, "", WarningStyle.Render(i18n.T("WSL 2: the totals are those of the WSL VM, capped by memory= in %%UserProfile%%\\.wslconfig (half of the host's RAM by default)")))
 	}
 
-	// Calculate scroll window
-	startIdx := 0
-	if a.selectedRow >= visibleRows {
-		startIdx = a.selectedRow - visibleRows + 1
+	if cgroup := a.stats.Cgroup; cgroup.MemoryLimit > 0 {
+		content = append(content,
+			"",
+			sectionHeader(i18n.T("Cgroup Limit")),
+			i18n.Sprintf("%s %s", LabelStyle.Render(i18n.T("Cgroup:")), ValueStyle.Render(cgroup.Path)),
+			i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Limit:")), cgroup.MemoryLimit/KBToGB),
+			i18n.Sprintf("%s %.1f%% (%.1f GB/%.1f GB)", LabelStyle.Render(i18n.T("Usage of Limit:")), cgroup.MemoryPercent, cgroup.MemoryUsed/KBToGB, cgroup.MemoryLimit/KBToGB),
+			a.memoryGauge.View(cgroup.MemoryPercent),
+		)
 	}
-	endIdx := startIdx + visibleRows
-	if endIdx > len(a.processes.Processes) {
-		endIdx = len(a.processes.Processes)
+
+	content = append(content, "")
+	content = append(content, a.renderSlab()...)
+	content = append(content, "")
+	content = append(content, a.renderSharedMemory()...)
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// maxShortLivedRows limits the short-lived section of the processes tab
+const maxShortLivedRows = 5
+
+// maxCrashLoopRows limits the crash loop section of the processes tab
+const maxCrashLoopRows = 5
+
+// userKeys cycles (s) and reverses (r) the sort column of the users tab
+func (a *App) userKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "s":
+		a.userSortBy = (a.userSortBy + 1) % (collector.UserSortByName + 1)
+	case "r":
+		a.userSortDesc = !a.userSortDesc
+	default:
+		return nil, false
 	}
+	a.users = a.collector.GetUserStats(a.processes, a.userSortBy, a.userSortDesc)
+	return nil, true
+}
 
+func (a *App) renderUsers() string {
 	var content strings.Builder
 
-	// Header section
-	content.WriteString(HeaderStyle.Render("Process List"))
+	content.WriteString(sectionHeader(i18n.T("Users")))
 	content.WriteString("\n\n")
 
-	// Stats
-	stats := fmt.Sprintf("Total: %d | Running: %d | Sleeping: %d | Zombie: %d",
-		a.processes.Total, a.processes.Running, a.processes.Sleeping, a.processes.Zombie)
-	content.WriteString(stats)
+	order := i18n.T("descending")
+	if !a.userSortDesc {
+		order = i18n.T("ascending")
+	}
+	content.WriteString(i18n.Sprintf("%d users • sorted by %s (%s)", len(a.users), a.userSortBy, order))
 	content.WriteString("\n\n")
 
-	// Table header with proper styling
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205")). // Bright blue
+		Foreground(lipgloss.Color("205")).
 		PaddingLeft(1).
 		PaddingRight(1)
 
-	header := fmt.Sprintf("%-8s %-20s %8s %8s %-12s %-s",
-		"PID", "NAME", "CPU%", "MEM%", "STATUS", "COMMAND")
-	content.WriteString(headerStyle.Render(header))
+	header := fmt.Sprintf("%-16s %-8s %6s %8s %8s %10s %10s %10s",
+		"USER", "UID", "PROCS", "CPU%", "MEM%", "RSS", "READ/s", "WRITE/s")
+	content.WriteString(columnHeader(headerStyle.Render(header)))
 	content.WriteString("\n")
 
-	// Process rows with proper alignment
-	for i := startIdx; i < endIdx; i++ {
-		proc := a.processes.Processes[i]
-
-		// Truncate strings to fit columns
-		name := truncateString(proc.Name, 20)
-		status := truncateString(proc.Status, 12)
-
-		// Calculate remaining width for command
-		usedWidth := 8 + 1 + 20 + 1 + 8 + 1 + 8 + 1 + 12 + 1 // PID + spaces + NAME + spaces + CPU% + spaces + MEM% + spaces + STATUS + spaces
-		remainingWidth := a.width - usedWidth - 4            // -4 for padding
-		if remainingWidth < 10 {
-			remainingWidth = 10
-		}
-		command := truncateString(proc.Command, remainingWidth)
-
-		row := fmt.Sprintf("%-8d %-20s %7.1f%% %7.1f%% %-12s %s",
-			proc.PID, name, proc.CPUPercent, proc.MemPercent, status, command)
+	for i, u := range a.users {
+		row := fmt.Sprintf("%-16s %-8s %6d %7.1f%% %7.1f%% %10s %10s %10s",
+			truncateString(u.Name, 16), truncateString(u.UID, 8), u.Processes,
+			a.shownCPU(u.CPUPercent), u.MemPercent, formatBytes(float64(u.MemRSS)*1024),
+			formatBytes(u.ReadRate), formatBytes(u.WriteRate))
 
-		// Style the row
 		rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
-
-		// Highlight selected row
-		if i == a.selectedRow {
-			rowStyle = rowStyle.
-				Background(lipgloss.Color("240")). // Light gray background
-				Foreground(lipgloss.Color("15")).  // White text
-				Bold(true)
+		if i%2 == 0 {
+			rowStyle = rowStyle.Foreground(lipgloss.Color("252"))
 		} else {
-			// Alternate row colors for better readability
-			if (i-startIdx)%2 == 0 {
-				rowStyle = rowStyle.Foreground(lipgloss.Color("252")) // Light gray text
-			} else {
-				rowStyle = rowStyle.Foreground(lipgloss.Color("245")) // Slightly darker gray text
-			}
+			rowStyle = rowStyle.Foreground(lipgloss.Color("245"))
 		}
 
 		content.WriteString(rowStyle.Render(row))
 		content.WriteString("\n")
 	}
 
-	// Add some spacing and scroll indicator
-	if len(a.processes.Processes) > visibleRows {
-		content.WriteString("\n")
-		scrollInfo := fmt.Sprintf("Showing %d-%d of %d processes • Use ↑↓ arrows or j/k to navigate",
-			startIdx+1, endIdx, len(a.processes.Processes))
-		scrollStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			Italic(true).
-			PaddingLeft(1)
-		content.WriteString(scrollStyle.Render(scrollInfo))
-	}
-
-	return BaseStyle.Render(content.String())
+	content.WriteString("\n")
+	content.WriteString(a.renderSessions())
+	return content.String()
+}
+
+// shownCPU converts a process CPU% of one core to the displayed mode: as is
+// (Irix) or a share of all CPUs (Solaris)
+func (a *App) shownCPU(percent float64) float64 {
+	if cpus := len(a.stats.CPU.Cores); a.solarisMode && cpus > 0 {
+		return percent / float64(cpus)
+	}
+	return percent
+}
+
+// cpuModeInfo names what process CPU% is relative to
+func (a *App) cpuModeInfo() string {
+	if a.solarisMode {
+		return i18n.Sprintf("CPU%% of all %d CPUs (I)", len(a.stats.CPU.Cores))
+	}
+	return i18n.T("CPU%% of one core (I)")
+}
+
+// processHighlight returns the row color of a process above the configured
+// CPU% or MEM% thresholds
+func (a *App) processHighlight(proc models.Process) (lipgloss.TerminalColor, bool) {
+	exceeds := func(value, threshold float64) bool {
+		return threshold > 0 && value >= threshold
+	}
+
+	h := a.highlight
+	if exceeds(proc.CPUPercent, h.CPUCritical) || exceeds(proc.MemPercent, h.MemCritical) {
+		return ErrorStyle.GetForeground(), true
+	}
+	if exceeds(proc.CPUPercent, h.CPUWarning) || exceeds(proc.MemPercent, h.MemWarning) {
+		return WarningStyle.GetForeground(), true
+	}
+	return nil, false
+}
+
+// maxSwapRows limits the swap and OOM kill lists of the Swap tab
+const maxSwapRows = 15
+
+func (a *App) renderSwap() string {
+	pressure := a.pressure
+	mem := a.stats.Memory
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		PaddingLeft(1).
+		PaddingRight(1)
+	rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+
+	var content strings.Builder
+	content.WriteString(sectionHeader(i18n.T("Swap & OOM")))
+	content.WriteString("\n\n")
+	content.WriteString(i18n.Sprintf("Swap: %.2f GB / %.2f GB • OOM kills since boot: %d",
+		mem.SwapUsed/KBToGB, mem.SwapTotal/KBToGB, pressure.OOMKillCount))
+	content.WriteString("\n\n")
+
+	content.WriteString(sectionHeader(i18n.T("Processes by Swap Usage")))
+	content.WriteString("\n")
+	if len(pressure.SwapProcesses) == 0 {
+		content.WriteString(" " + i18n.T("No process has swapped out memory") + "\n")
+	} else {
+		content.WriteString(columnHeader(headerStyle.Render(fmt.Sprintf("%-8s %-20s %10s %10s", "PID", "NAME", "SWAP", "RSS"))))
+		content.WriteString("\n")
+		for _, proc := range pressure.SwapProcesses[:min(maxSwapRows, len(pressure.SwapProcesses))] {
+			content.WriteString(rowStyle.Render(fmt.Sprintf("%-8d %-20s %10s %10s",
+				proc.PID, truncateString(proc.Name, 20),
+				formatBytes(float64(proc.Swap)*1024), formatBytes(float64(proc.MemRSS)*1024))))
+			content.WriteString("\n")
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(sectionHeader(i18n.T("Highest OOM Scores")))
+	content.WriteString("\n")
+	content.WriteString(columnHeader(headerStyle.Render(fmt.Sprintf("%-8s %-20s %6s %6s %10s", "PID", "NAME", "SCORE", "ADJ", "RSS"))))
+	content.WriteString("\n")
+	for _, candidate := range pressure.OOMCandidates {
+		content.WriteString(rowStyle.Render(fmt.Sprintf("%-8d %-20s %6d %6d %10s",
+			candidate.PID, truncateString(candidate.Name, 20), candidate.Score,
+			candidate.ScoreAdj, formatBytes(float64(candidate.MemRSS)*1024))))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(sectionHeader(i18n.T("Recent OOM Kills")))
+	content.WriteString("\n")
+	switch {
+	case pressure.KernelLogError != "":
+		content.WriteString(WarningStyle.Render(" " + pressure.KernelLogError))
+		content.WriteString("\n")
+	case len(pressure.OOMKills) == 0:
+		content.WriteString(SuccessStyle.Render(" " + i18n.T("No OOM kills in the kernel log")))
+		content.WriteString("\n")
+	default:
+		content.WriteString(columnHeader(headerStyle.Render(fmt.Sprintf("%-19s %-8s %-20s %10s %10s %-7s",
+			"TIME", "PID", "NAME", "ANON RSS", "FILE RSS", "SCOPE"))))
+		content.WriteString("\n")
+		// Newest first
+		for i := len(pressure.OOMKills) - 1; i >= max(0, len(pressure.OOMKills)-maxSwapRows); i-- {
+			kill := pressure.OOMKills[i]
+			scope := i18n.T("system")
+			if kill.Cgroup {
+				scope = i18n.T("cgroup")
+			}
+			content.WriteString(ErrorStyle.Render(rowStyle.Render(fmt.Sprintf("%-19s %-8d %-20s %10s %10s %-7s",
+				kill.Time.Format("2006-01-02 15:04:05"), kill.PID, truncateString(kill.Name, 20),
+				formatBytes(float64(kill.AnonRSS)*1024), formatBytes(float64(kill.FileRSS)*1024), scope))))
+			content.WriteString("\n")
+		}
+	}
+
+	return content.String()
+}
+
+// ioKeys cycles (s) and reverses (r) the sort column of the I/O tab
+func (a *App) ioKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "s":
+		a.ioSortBy = (a.ioSortBy + 1) % (collector.IOSortByName + 1)
+	case "r":
+		a.ioSortDesc = !a.ioSortDesc
+	default:
+		return nil, false
+	}
+	a.ioProcesses = a.collector.TopIOProcesses(a.processes, a.ioSortBy, a.ioSortDesc)
+	return nil, true
+}
+
+func (a *App) renderIO() string {
+	var content strings.Builder
+
+	content.WriteString(sectionHeader(i18n.T("Disk I/O")))
+	content.WriteString("\n\n")
+	content.WriteString(i18n.Sprintf("Read: %s/s • Write: %s/s",
+		formatBytes(a.io.ReadRate), formatBytes(a.io.WriteRate)))
+	content.WriteString("\n\n")
+
+	peak := math.Max(a.ioReadHistory.Max(historySize), a.ioWriteHistory.Max(historySize))
+	rate := func(rate float64) string { return formatBytes(rate) + "/s" }
+	graphs := a.renderSeriesGraph([]graphSeries{
+		{name: i18n.T("Read:"), history: a.ioReadHistory, color: lipgloss.Color("39")},
+		{name: i18n.T("Write:"), history: a.ioWriteHistory, color: lipgloss.Color("205")},
+	}, peak, rate)
+	content.WriteString(strings.Join(graphs, "\n"))
+	content.WriteString("\n\n")
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		PaddingLeft(1).
+		PaddingRight(1)
+	rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+
+	content.WriteString(columnHeader(headerStyle.Render(fmt.Sprintf("%-12s %10s %10s %8s %8s %6s %8s",
+		"DEVICE", "READ/s", "WRITE/s", "R IOPS", "W IOPS", "UTIL", "QUEUED"))))
+	content.WriteString("\n")
+	for _, device := range a.io.Devices {
+		row := fmt.Sprintf("%-12s %10s %10s %8.0f %8.0f %5.1f%% %8d",
+			truncateString(device.Name, 12), formatBytes(device.ReadRate), formatBytes(device.WriteRate),
+			device.ReadIOPS, device.WriteIOPS, device.Utilization, device.InFlight)
+		content.WriteString(rowStyle.Render(row))
+		content.WriteString("\n")
+	}
+
+	order := i18n.T("descending")
+	if !a.ioSortDesc {
+		order = i18n.T("ascending")
+	}
+	content.WriteString("\n")
+	content.WriteString(sectionHeader(i18n.T("Processes")))
+	content.WriteString("\n\n")
+	content.WriteString(i18n.Sprintf("%d processes doing I/O • sorted by %s (%s)", len(a.ioProcesses), a.ioSortBy, order))
+	content.WriteString("\n\n")
+	if notice := a.lockedNotice(models.FeatureProcessIO); notice != "" {
+		content.WriteString(notice)
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString(columnHeader(headerStyle.Render(fmt.Sprintf("%-8s %-20s %-12s %10s %10s %10s %10s",
+		"PID", "NAME", "USER", "READ/s", "WRITE/s", "READ", "WRITTEN"))))
+	content.WriteString("\n")
+	for i, proc := range a.ioProcesses {
+		row := fmt.Sprintf("%-8d %-20s %-12s %10s %10s %10s %10s",
+			proc.PID, truncateString(proc.Name, 20), truncateString(collector.LookupUsername(proc.User), 12),
+			formatBytes(proc.ReadRate), formatBytes(proc.WriteRate),
+			formatBytes(float64(proc.ReadBytes)), formatBytes(float64(proc.WriteBytes)))
+
+		style := rowStyle.Foreground(lipgloss.Color("252"))
+		if i%2 == 1 {
+			style = rowStyle.Foreground(lipgloss.Color("245"))
+		}
+		content.WriteString(style.Render(row))
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+// graphHeight is the height of history graphs in rows
+const graphHeight = 4
+
+// historySize is the number of samples kept, enough for wide terminals
+const historySize = 600
+
+// Smallest deviations from the norm flagged in the graphs, in CPU
+// percentage points, bytes per second and watts
+const (
+	anomalyFloorCPU     = 20
+	anomalyFloorNetwork = 256 * 1024
+	anomalyFloorIO      = 1024 * 1024
+	anomalyFloorWatts   = 5
+)
+
+// graphStyle resolves the configured graph style
+func graphStyle(style string) GraphStyle {
+	switch style {
+	case config.GraphBraille:
+		return GraphBraille
+	case config.GraphBlock:
+		return GraphBlock
+	}
+	return DetectGraphStyle()
+}
+
+// renderGraph draws a history graph below a line with its title, the latest
+// value and the lowest and highest one shown, with a scale to its left and
+// the time it spans below. A maxValue of 0 scales to the peak.
+func (a *App) renderGraph(title string, history *History, maxValue float64, color lipgloss.Color, format func(float64) string) []string {
+	series := graphSeries{name: title, history: history, color: color}
+	return a.renderSeriesGraph([]graphSeries{series}, maxValue, format)
+}
+
+// graphSeries is one line of a graph and its entry in the legend
+type graphSeries struct {
+	name    string
+	history *History
+	color   lipgloss.Color
+}
+
+// renderSeriesGraph draws one or two series on a shared scale, each with a
+// legend line of its latest, lowest and highest value. Where the lines of
+// two cross, the first is drawn.
+func (a *App) renderSeriesGraph(series []graphSeries, maxValue float64, format func(float64) string) []string {
+	if maxValue <= 0 {
+		for _, s := range series {
+			maxValue = math.Max(maxValue, s.history.Max(historySize))
+		}
+	}
+	if maxValue <= 0 {
+		// LineGraph draws an empty history against 1
+		maxValue = 1
+	}
+	labels, axisWidth := axisLabels(maxValue, graphHeight, format)
+	width := max(1, a.layout.Content-axisWidth-2)
+	samples := width
+	if a.graphStyle == GraphBraille {
+		samples *= 2
+	}
+
+	var lines []string
+	for _, s := range series {
+		values := s.history.Values()
+		var latest float64
+		if len(values) > 0 {
+			latest = values[len(values)-1]
+		}
+		name := LabelStyle.Render(s.name)
+		// Several series need telling apart by their color
+		if len(series) > 1 {
+			name = lipgloss.NewStyle().Foreground(s.color).Render("━━") + " " + name
+		}
+		line := i18n.Sprintf("%s %s (min %s, max %s)", name, format(latest),
+			format(s.history.Min(samples)), format(s.history.Max(samples)))
+		if anomaly, ok := s.history.Anomaly(); ok {
+			line += " " + anomalyNote(anomaly, format)
+		}
+		lines = append(lines, line)
+	}
+
+	var rows []string
+	if len(series) == 1 {
+		style := lipgloss.NewStyle().Foreground(series[0].color)
+		for _, row := range LineGraph(series[0].history.Values(), maxValue, width, graphHeight, a.graphStyle) {
+			rows = append(rows, style.Render(row))
+		}
+	} else {
+		front, back := series[0], series[1]
+		rows = OverlayGraph(front.history.Values(), back.history.Values(), maxValue, maxValue,
+			width, graphHeight, a.graphStyle, front.color, back.color)
+	}
+	lines = append(lines, withYAxis(rows, labels, axisWidth)...)
+	return append(lines, timeAxis(axisWidth+2, width, samples, a.interval))
+}
+
+// anomalyNote flags a value far from its norm, such as a transfer many times
+// the usual rate
+func anomalyNote(anomaly Anomaly, format func(float64) string) string {
+	if anomaly.High() {
+		return WarningStyle.Render(i18n.Sprintf("⚡ unusually high, usually %s", format(anomaly.Usual)))
+	}
+	return WarningStyle.Render(i18n.Sprintf("⚡ unusually low, usually %s", format(anomaly.Usual)))
+}
+
+// pressureWarning is the share of time stalled on a resource, in percent,
+// from which a cgroup is highlighted
+const pressureWarning = 10
+
+// formatPressure renders the CPU, memory and I/O pressure of a cgroup as
+// "cpu/mem/io" percentages, and whether one of them calls for attention
+func formatPressure(pressure *models.CgroupPressure) (string, bool) {
+	if pressure == nil {
+		return "-", false
+	}
+	stalled := math.Max(pressure.CPU, math.Max(pressure.Memory, pressure.IO)) >= pressureWarning
+	return fmt.Sprintf("%.0f/%.0f/%.0f", pressure.CPU, pressure.Memory, pressure.IO), stalled
+}
+
+// formatBytes renders a byte count with a binary unit suffix
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", bytes, units[unit])
+}
+
+// formatDuration renders a duration compactly with the precision that
+// matters at its scale: "350ms", "4.2s", "42s", "12m 34s", "04:12:33",
+// "3d 04:12", "2w 1d". Every view shows durations through it.
+func formatDuration(d time.Duration) string {
+	const (
+		day  = 24 * time.Hour
+		week = 7 * day
+	)
+	switch {
+	case d < 0:
+		return "-" + formatDuration(-d)
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < 10*time.Second:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm %02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < day:
+		return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	case d < week:
+		return fmt.Sprintf("%dd %02d:%02d", int(d/day), int(d%day/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return fmt.Sprintf("%dw %dd", int(d/week), int(d%week/day))
 }
 
 func truncateString(s string, maxLen int) string {
@@ -623,57 +1622,463 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+func (a *App) renderPods() string {
+	content := []string{
+		sectionHeader(i18n.T("Kubernetes Pods")),
+		"",
+		i18n.Sprintf("%s %d", LabelStyle.Render(i18n.T("Pods:")), len(a.pods)),
+		"",
+	}
+
+	// formatCores renders a cpu figure in millicores, "-" when unset
+	formatCores := func(cores float64) string {
+		if cores <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0fm", cores*1000)
+	}
+	formatMemory := func(kb float64) string {
+		if kb <= 0 {
+			return "-"
+		}
+		return formatBytes(kb * 1024)
+	}
+
+	for _, pod := range a.pods {
+		name := pod.Name
+		if pod.Namespace != "" {
+			name = pod.Namespace + "/" + pod.Name
+		}
+
+		content = append(content,
+			sectionHeader(name),
+			fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("QoS:")), ValueStyle.Render(pod.QoSClass)),
+			i18n.Sprintf("%s %s (request %s, limit %s)", LabelStyle.Render(i18n.T("CPU:")),
+				formatCores(pod.CPUUsage), formatCores(pod.CPURequest), formatCores(pod.CPULimit)),
+			i18n.Sprintf("%s %s (limit %s)", LabelStyle.Render(i18n.T("Memory:")),
+				formatMemory(pod.MemoryUsed), formatMemory(pod.MemoryLimit)),
+		)
+		if pod.MemoryLimit > 0 {
+			content = append(content, a.memoryGauge.View(pod.MemoryUsed/pod.MemoryLimit*100))
+		}
+
+		for _, container := range pod.Containers {
+			content = append(content, fmt.Sprintf("  %-24s cpu %-6s / %-6s mem %-9s / %s",
+				truncateString(container.Name, 24),
+				formatCores(container.CPUUsage), formatCores(container.CPULimit),
+				formatMemory(container.MemoryUsed), formatMemory(container.MemoryLimit)))
+		}
+		content = append(content, "")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+func (a *App) renderVMs() string {
+	content := []string{
+		sectionHeader(i18n.T("Virtual Machines")),
+		"",
+		i18n.Sprintf("%s %d", LabelStyle.Render(i18n.T("Running:")), len(a.vms)),
+		"",
+	}
+
+	for _, vm := range a.vms {
+		content = append(content,
+			sectionHeader(fmt.Sprintf("%s (PID %d)", vm.Name, vm.PID)),
+			i18n.Sprintf("%s %d", LabelStyle.Render(i18n.T("vCPUs:")), vm.VCPUs),
+			i18n.Sprintf("%s %.1f%%", LabelStyle.Render(i18n.T("CPU:")), vm.CPUPercent),
+		)
+
+		for _, index := range slices.Sorted(maps.Keys(vm.VCPUPercent)) {
+			usage := vm.VCPUPercent[index]
+			content = append(content,
+				i18n.Sprintf("  vCPU %-3d %5.1f%% %s", index, usage, LEDMeter(usage, 20)))
+		}
+
+		memory := formatBytes(float64(vm.MemRSS) * 1024)
+		if vm.MemoryAlloc > 0 {
+			memory = i18n.Sprintf("%s resident of %s allocated", memory, formatBytes(float64(vm.MemoryAlloc)*1024))
+		}
+		content = append(content,
+			fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Memory:")), ValueStyle.Render(memory)),
+			i18n.Sprintf("%s %s/s read, %s/s write", LabelStyle.Render(i18n.T("Disk I/O:")), formatBytes(vm.ReadRate), formatBytes(vm.WriteRate)),
+		)
+
+		if len(vm.Interfaces) > 0 {
+			content = append(content,
+				i18n.Sprintf("%s %s/s rx, %s/s tx (%s)", LabelStyle.Render(i18n.T("Network:")),
+					formatBytes(vm.RxRate), formatBytes(vm.TxRate), strings.Join(vm.Interfaces, ", ")),
+			)
+		}
+		content = append(content, "")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// networkKeys runs the resolver latency test now (d) and picks the network
+// namespace shown (n)
+func (a *App) networkKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "n":
+		return a.pickNetNamespace(), true
+	case "d":
+		if a.dnsChecking {
+			a.toast(toastInfo, i18n.T("DNS test already running"))
+			return nil, true
+		}
+		return a.checkDNS(), true
+	case "y":
+		// The addresses of the neighbor table, one per line
+		ips := make([]string, len(a.neighbors))
+		for i, neighbor := range a.neighbors {
+			ips[i] = neighbor.IP
+		}
+		if len(ips) == 0 {
+			a.toast(toastWarning, i18n.T("Nothing to copy, the neighbor table is empty"))
+			return nil, true
+		}
+		return copyToClipboard(i18n.Sprintf("%d neighbor IP addresses", len(ips)), strings.Join(ips, "\n")), true
+	}
+	return nil, false
+}
+
+func (a *App) diskKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if msg.String() != "y" {
+		return nil, false
+	}
+	mountpoints := make([]string, len(a.stats.Disk))
+	for i, disk := range a.stats.Disk {
+		mountpoints[i] = disk.Mountpoint
+	}
+	if len(mountpoints) == 0 {
+		a.toast(toastWarning, i18n.T("Nothing to copy, no disks found"))
+		return nil, true
+	}
+	return copyToClipboard(i18n.Sprintf("%d mountpoints", len(mountpoints)), strings.Join(mountpoints, "\n")), true
+}
+
 func (a *App) renderNetwork() string {
+	if a.netns.ID != 0 {
+		return a.renderNetNamespace()
+	}
 	content := []string{
-		HeaderStyle.Render("Network Interfaces"),
+		sectionHeader(i18n.T("Network Interfaces")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(i18n.T("n: show another network namespace, such as a container's")),
 		"",
-		fmt.Sprintf("%s %.1f MB", LabelStyle.Render("Total RX:"), float64(a.stats.Network.TotalRx)/(1024*1024)),
-		fmt.Sprintf("%s %.1f MB", LabelStyle.Render("Total TX:"), float64(a.stats.Network.TotalTx)/(1024*1024)),
+		i18n.Sprintf("%s %.1f MB", LabelStyle.Render(i18n.T("Total RX:")), float64(a.stats.Network.TotalRx)/(1024*1024)),
+		i18n.Sprintf("%s %.1f MB", LabelStyle.Render(i18n.T("Total TX:")), float64(a.stats.Network.TotalTx)/(1024*1024)),
 		"",
 	}
 
+	// Both directions share the scale so they can be compared
+	peak := math.Max(a.netRxHistory.Max(historySize), a.netTxHistory.Max(historySize))
+	rate := func(rate float64) string { return formatBytes(rate) + "/s" }
+	content = append(content, a.renderSeriesGraph([]graphSeries{
+		{name: "RX:", history: a.netRxHistory, color: lipgloss.Color("39")},
+		{name: "TX:", history: a.netTxHistory, color: lipgloss.Color("205")},
+	}, peak, rate)...)
+	content = append(content, "")
+
+	// Share of IP traffic carried over IPv6
+	ipv4, ipv6 := a.stats.Network.IPv4, a.stats.Network.IPv6
+	ipv6Share := 0.0
+	if total := ipv4.RxBytes + ipv4.TxBytes + ipv6.RxBytes + ipv6.TxBytes; total > 0 {
+		ipv6Share = float64(ipv6.RxBytes+ipv6.TxBytes) / float64(total) * 100
+	}
+	content = append(content,
+		sectionHeader(i18n.T("IP Traffic (all interfaces, including loopback)")),
+		i18n.Sprintf("%s RX %.1f MB / TX %.1f MB (%d / %d packets)", LabelStyle.Render("IPv4:"),
+			float64(ipv4.RxBytes)/(1024*1024), float64(ipv4.TxBytes)/(1024*1024), ipv4.RxPackets, ipv4.TxPackets),
+		i18n.Sprintf("%s RX %.1f MB / TX %.1f MB (%d / %d packets)", LabelStyle.Render("IPv6:"),
+			float64(ipv6.RxBytes)/(1024*1024), float64(ipv6.TxBytes)/(1024*1024), ipv6.RxPackets, ipv6.TxPackets),
+		i18n.Sprintf("%s %.1f%% of IP traffic", LabelStyle.Render(i18n.T("IPv6 share:")), ipv6Share),
+		"",
+	)
+
+	content = append(content, a.renderServices()...)
+	content = append(content, a.renderTalkers()...)
+	content = append(content, a.renderProtocolHealth()...)
+	content = append(content, a.renderSocketBuffers()...)
+	content = append(content, a.renderNeighbors()...)
+	content = append(content, a.renderDNS()...)
+
 	for _, iface := range a.stats.Network.Interfaces {
+		globalIPv6 := i18n.T("none")
+		if len(iface.GlobalIPv6) > 0 {
+			globalIPv6 = strings.Join(iface.GlobalIPv6, ", ")
+		}
+
 		content = append(content,
-			HeaderStyle.Render("Interface: "+iface.Name),
-			fmt.Sprintf("%s %s", LabelStyle.Render("Status:"), ValueStyle.Render(iface.Status)),
-			fmt.Sprintf("%s %s", LabelStyle.Render("Speed:"), ValueStyle.Render(iface.Speed)),
-			fmt.Sprintf("%s %.1f MB", LabelStyle.Render("RX:"), float64(iface.RxBytes)/(1024*1024)),
-			fmt.Sprintf("%s %.1f MB", LabelStyle.Render("TX:"), float64(iface.TxBytes)/(1024*1024)),
-			fmt.Sprintf("%s %d", LabelStyle.Render("RX Packets:"), iface.RxPackets),
-			fmt.Sprintf("%s %d", LabelStyle.Render("TX Packets:"), iface.TxPackets),
+			sectionHeader(i18n.Sprintf("Interface: %s", iface.Name)),
+			fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Status:")), ValueStyle.Render(iface.Status)),
+			fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Speed:")), ValueStyle.Render(iface.Speed)),
+			i18n.Sprintf("%s %.1f MB", LabelStyle.Render("RX:"), float64(iface.RxBytes)/(1024*1024)),
+			i18n.Sprintf("%s %.1f MB", LabelStyle.Render("TX:"), float64(iface.TxBytes)/(1024*1024)),
+			i18n.Sprintf("%s %d", LabelStyle.Render(i18n.T("RX Packets:")), iface.RxPackets),
+			i18n.Sprintf("%s %d", LabelStyle.Render(i18n.T("TX Packets:")), iface.TxPackets),
+			i18n.Sprintf("%s RX %.1f MB / TX %.1f MB (%d / %d packets)", LabelStyle.Render("IPv6:"),
+				float64(iface.IPv6.RxBytes)/(1024*1024), float64(iface.IPv6.TxBytes)/(1024*1024),
+				iface.IPv6.RxPackets, iface.IPv6.TxPackets),
+			i18n.Sprintf("%s RX %.1f MB / TX %.1f MB", LabelStyle.Render(i18n.T("IPv4 & other:")),
+				float64(iface.RxBytes-min64(iface.RxBytes, iface.IPv6.RxBytes))/(1024*1024),
+				float64(iface.TxBytes-min64(iface.TxBytes, iface.IPv6.TxBytes))/(1024*1024)),
+			fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Global IPv6:")), ValueStyle.Render(globalIPv6)),
 		)
 	}
 
-	return BaseStyle.Width(a.width - 4).Render(
-		lipgloss.JoinVertical(lipgloss.Left, content...),
-	)
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderProtocolHealth shows TCP/IP error counters with their rates, the
+// usual suspects when "the network feels slow"
+func (a *App) renderProtocolHealth() []string {
+	proto := a.stats.Network.Protocol
+
+	// Any non-zero rate of a drop counter deserves attention
+	counterLine := func(label string, c models.Counter) string {
+		style := ValueStyle
+		if c.Rate > 0 {
+			style = WarningStyle
+		}
+		return i18n.Sprintf("%s %s (%d total)", LabelStyle.Render(i18n.T(label)), style.Render(i18n.Sprintf("%.1f/s", c.Rate)), c.Total)
+	}
+
+	retransStyle := SuccessStyle
+	if proto.RetransPercent >= 5 {
+		retransStyle = ErrorStyle
+	} else if proto.RetransPercent >= 1 {
+		retransStyle = WarningStyle
+	}
+
+	return []string{
+		sectionHeader(i18n.T("Protocol Health")),
+		i18n.Sprintf("%s %s (%.1f/s of %.1f segments/s, %d total)", LabelStyle.Render(i18n.T("TCP retransmits:")),
+			retransStyle.Render(i18n.Sprintf("%.2f%%", proto.RetransPercent)),
+			proto.TCPRetransSegs.Rate, proto.TCPOutSegs.Rate, proto.TCPRetransSegs.Total),
+		counterLine("TCP receive errors:", proto.TCPInErrs),
+		i18n.Sprintf("%s %.1f/s (%d total)", LabelStyle.Render(i18n.T("TCP resets sent:")), proto.TCPOutRsts.Rate, proto.TCPOutRsts.Total),
+		counterLine("Listen overflows:", proto.ListenOverflows),
+		counterLine("Listen drops:", proto.ListenDrops),
+		counterLine("IP input discards:", proto.IPInDiscards),
+		counterLine("IP output discards:", proto.IPOutDiscards),
+		counterLine("IP header errors:", proto.IPInHdrErrors),
+		"",
+	}
+}
+
+// renderSocketBuffers shows socket counts, buffer memory against the kernel
+// limits and UDP buffer overflows
+func (a *App) renderSocketBuffers() []string {
+	proto := a.stats.Network.Protocol
+	sockets := a.stats.Network.Sockets
+
+	memoryLine := func(label string, used, pressure, maximum uint64) string {
+		style := ValueStyle
+		if pressure > 0 && used >= pressure {
+			style = ErrorStyle
+		}
+		return i18n.Sprintf("%s %s (pressure at %s, max %s)", LabelStyle.Render(i18n.T(label)),
+			style.Render(formatBytes(float64(used))), formatBytes(float64(pressure)), formatBytes(float64(maximum)))
+	}
+
+	content := []string{
+		sectionHeader(i18n.T("Sockets & Buffers")),
+		i18n.Sprintf("%s %d (TCP %d in use, %d orphaned, %d time-wait • UDP %d in use)", LabelStyle.Render(i18n.T("Sockets:")),
+			sockets.Used, sockets.TCPInUse, sockets.TCPOrphan, sockets.TCPTimeWait, sockets.UDPInUse),
+		memoryLine("TCP buffer memory:", sockets.TCPMemory, sockets.TCPMemoryPressure, sockets.TCPMemoryMax),
+		memoryLine("UDP buffer memory:", sockets.UDPMemory, sockets.UDPMemoryPressure, sockets.UDPMemoryMax),
+		i18n.Sprintf("%s %.1f/s (%d total)", LabelStyle.Render(i18n.T("UDP receive buffer errors:")), proto.UDPRcvbufErrors.Rate, proto.UDPRcvbufErrors.Total),
+		i18n.Sprintf("%s %.1f/s (%d total)", LabelStyle.Render(i18n.T("UDP send buffer errors:")), proto.UDPSndbufErrors.Rate, proto.UDPSndbufErrors.Total),
+		i18n.Sprintf("%s %.1f/s (%d total)", LabelStyle.Render(i18n.T("UDP input errors:")), proto.UDPInErrors.Rate, proto.UDPInErrors.Total),
+		i18n.Sprintf("%s %.1f/s (%d total)", LabelStyle.Render(i18n.T("UDP to closed ports:")), proto.UDPNoPorts.Rate, proto.UDPNoPorts.Total),
+	}
+
+	if proto.UDPRcvbufErrors.Rate > 0 {
+		content = append(content, ErrorStyle.Render(
+			i18n.T("⚠ UDP receive buffers are overflowing: datagrams are dropped, raise net.core.rmem_max or the socket's SO_RCVBUF")))
+	}
+	if proto.UDPSndbufErrors.Rate > 0 {
+		content = append(content, ErrorStyle.Render(
+			i18n.T("⚠ UDP send buffers are full: raise net.core.wmem_max or the socket's SO_SNDBUF")))
+	}
+	if sockets.TCPMemoryPressure > 0 && sockets.TCPMemory >= sockets.TCPMemoryPressure {
+		content = append(content, ErrorStyle.Render(i18n.T("⚠ TCP memory is under pressure, the kernel is shrinking socket buffers")))
+	}
+	if sockets.UDPMemoryPressure > 0 && sockets.UDPMemory >= sockets.UDPMemoryPressure {
+		content = append(content, ErrorStyle.Render(i18n.T("⚠ UDP memory is under pressure, the kernel is shrinking socket buffers")))
+	}
+
+	return append(content, "")
+}
+
+// renderNeighbors lists the ARP/NDP neighbor table, highlighting entries
+// that went stale or could not be resolved
+func (a *App) renderNeighbors() []string {
+	content := []string{sectionHeader(i18n.T("Neighbors (ARP/NDP)"))}
+	if len(a.neighbors) == 0 {
+		return append(content, i18n.T("No neighbor entries"), "")
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%-39s %-17s %-12s %s", "IP", "MAC", "INTERFACE", "STATE"))))
+
+	for _, neighbor := range a.neighbors {
+		mac := neighbor.MAC
+		if mac == "" {
+			mac = "-"
+		}
+		state := neighbor.State
+		if neighbor.Router {
+			state += " " + i18n.T("(router)")
+		}
+		row := fmt.Sprintf("%-39s %-17s %-12s %s", neighbor.IP, mac, truncateString(neighbor.Interface, 12), state)
+
+		style := lipgloss.NewStyle()
+		switch {
+		case strings.Contains(neighbor.State, "FAILED") || strings.Contains(neighbor.State, "INCOMPLETE"):
+			style = ErrorStyle
+		case strings.Contains(neighbor.State, "STALE") || strings.Contains(neighbor.State, "PROBE") ||
+			strings.Contains(neighbor.State, "DELAY"):
+			style = WarningStyle
+		}
+		content = append(content, style.Render(row))
+	}
+
+	return append(content, "")
+}
+
+// renderDNS lists the resolvers with the result of the last latency test
+func (a *App) renderDNS() []string {
+	content := []string{sectionHeader(i18n.T("DNS Resolvers"))}
+	if len(a.dns.Resolvers) == 0 {
+		return append(content, WarningStyle.Render(i18n.T("No nameserver configured in /etc/resolv.conf")), "")
+	}
+
+	content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Source:")), ValueStyle.Render(a.dns.Source)))
+	if len(a.dns.SearchDomains) > 0 {
+		content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Search:")), strings.Join(a.dns.SearchDomains, " ")))
+	}
+
+	results := make(map[string]models.DNSCheck, len(a.dnsChecks))
+	for _, check := range a.dnsChecks {
+		results[check.Resolver] = check
+	}
+
+	for _, resolver := range a.dns.Resolvers {
+		check, ok := results[resolver]
+		switch {
+		case a.dnsChecking:
+			content = append(content, fmt.Sprintf("  %-39s %s", resolver, i18n.T("checking...")))
+		case !ok:
+			content = append(content, fmt.Sprintf("  %-39s %s", resolver, i18n.T("not tested")))
+		case check.Error != "":
+			content = append(content, ErrorStyle.Render(fmt.Sprintf("  %-39s %s (%s)", resolver, check.Error, check.Time.Format("15:04:05"))))
+		default:
+			style := SuccessStyle
+			if check.Latency > 200*time.Millisecond {
+				style = WarningStyle
+			}
+			content = append(content, style.Render(fmt.Sprintf("  %-39s %s", resolver, i18n.Sprintf("%s for %s (%s)",
+				check.Latency.Round(time.Millisecond), a.dnsCheck.Query, check.Time.Format("15:04:05")))))
+		}
+	}
+
+	return append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(i18n.T("d: test resolvers now")), "")
 }
 
 func (a *App) renderDisk() string {
 	content := []string{
-		HeaderStyle.Render("Disk Usage"),
+		sectionHeader(i18n.T("Disk Usage")),
 		"",
 	}
+	if a.wsl == 2 {
+		// The virtual disk grows on demand, up to its maximum size shown
+		// here, whatever the space left on the Windows drive holding it
+		content = append(content,
+			WarningStyle.Render(i18n.T("WSL 2: / is the distribution's virtual disk (ext4.vhdx), sized to its maximum rather than")),
+			WarningStyle.Render(i18n.T("the Windows drive holding it; Windows drives are mounted under /mnt through 9p")),
+			"")
+	}
 
 	for _, disk := range a.stats.Disk {
 		// Create a temporary progress bar for this disk
-		diskBar := a.diskProgress.ViewAs(disk.UsagePercent / 100.0)
+		diskBar := a.diskGauge.View(disk.UsagePercent)
 
+		title := sectionHeader(disk.Device + " (" + disk.Mountpoint + ")")
+		if disk.Stalled {
+			title += " " + WarningStyle.Render(i18n.T("⚠ stalled, not responding (last known sizes)"))
+		}
 		content = append(content,
-			HeaderStyle.Render(disk.Device+" ("+disk.Mountpoint+")"),
-			fmt.Sprintf("%s %s", LabelStyle.Render("Filesystem:"), ValueStyle.Render(disk.Filesystem)),
-			fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Total:"), float64(disk.Total)/(1024*1024*1024)),
-			fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Used:"), float64(disk.Used)/(1024*1024*1024)),
-			fmt.Sprintf("%s %.1f GB", LabelStyle.Render("Free:"), float64(disk.Free)/(1024*1024*1024)),
-			fmt.Sprintf("%s %.1f%%", LabelStyle.Render("Usage:"), disk.UsagePercent),
+			title,
+			fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Filesystem:")), ValueStyle.Render(disk.Filesystem)),
+			i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Total:")), float64(disk.Total)/(1024*1024*1024)),
+			i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Used:")), float64(disk.Used)/(1024*1024*1024)),
+			i18n.Sprintf("%s %.1f GB", LabelStyle.Render(i18n.T("Free:")), float64(disk.Free)/(1024*1024*1024)),
+			i18n.Sprintf("%s %.1f%%", LabelStyle.Render(i18n.T("Usage:")), disk.UsagePercent),
 			diskBar,
 			"",
 		)
 	}
 
-	return BaseStyle.Width(a.width - 4).Render(
-		lipgloss.JoinVertical(lipgloss.Left, content...),
-	)
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// checkDNS measures the lookup latency of every configured resolver
+func (a *App) checkDNS() tea.Cmd {
+	a.dnsChecking = true
+	a.lastDNSCheck = time.Now()
+
+	query := a.dnsCheck.Query
+	return func() tea.Msg {
+		resolvers := a.collector.GetDNSStats().Resolvers
+		return dnsCheckMsg(a.collector.CheckDNS(resolvers, query))
+	}
+}
+
+// pickPowerProfile asks which power profile to switch to
+func (a *App) pickPowerProfile() {
+	power := a.stats.Battery.Power
+	if len(power.PowerProfiles) == 0 {
+		a.toast(toastWarning, i18n.T("power-profiles-daemon is not available"))
+		return
+	}
+
+	profiles := power.PowerProfiles
+	current := 0
+	for i, profile := range profiles {
+		if profile == power.PowerProfile {
+			current = i
+		}
+	}
+	a.openDialog(newPickerDialog(i18n.T("Switch the power profile to"), profiles, current, func(i int) tea.Cmd {
+		return func() tea.Msg {
+			return powerProfileMsg{profile: profiles[i], err: a.collector.SetPowerProfile(profiles[i])}
+		}
+	}))
+}
+
+// notifyAlert sends the desktop notification of an alert with the notify action
+func notifyAlert(firing alert.Alert) tea.Cmd {
+	return func() tea.Msg {
+		// Best effort, notify-send is missing on headless machines
+		if err := alert.Notify(firing); err != nil {
+			slog.Debug("desktop notification failed", "err", err)
+		}
+		return nil
+	}
+}
+
+// batteryKeys switches the power profile (p)
+func (a *App) batteryKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if msg.String() != "p" {
+		return nil, false
+	}
+	if !a.allowAction(i18n.T("Switching the power profile")) {
+		return nil, true
+	}
+	a.pickPowerProfile()
+	return nil, true
 }
 
 func (a *App) renderBattery() string {
@@ -687,23 +2092,384 @@ func (a *App) renderBattery() string {
 	}
 
 	// Create battery progress bar
-	batteryBar := a.batteryProgress.ViewAs(float64(battery.Level) / 100.0)
+	batteryBar := a.batteryGauge.View(float64(battery.Level))
 
 	content := []string{
-		HeaderStyle.Render("Battery Information"),
+		sectionHeader(i18n.T("Battery Information")),
 		"",
-		fmt.Sprintf("%s %s", LabelStyle.Render("Status:"), statusStyle.Render(battery.Status)),
-		fmt.Sprintf("%s %d%%", LabelStyle.Render("Level:"), battery.Level),
+		fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Status:")), statusStyle.Render(battery.Status)),
+		i18n.Sprintf("%s %d%%", LabelStyle.Render(i18n.T("Level:")), battery.Level),
 		batteryBar,
 		"",
-		fmt.Sprintf("%s %s", LabelStyle.Render("Time Left:"), ValueStyle.Render(battery.TimeLeft)),
-		fmt.Sprintf("%s %d%%", LabelStyle.Render("Health:"), battery.Health),
-		fmt.Sprintf("%s %v", LabelStyle.Render("Charging:"), battery.IsCharging),
+		fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Time Left:")), ValueStyle.Render(battery.TimeLeft)),
+		i18n.Sprintf("%s %d%%", LabelStyle.Render(i18n.T("Health:")), battery.Health),
+		fmt.Sprintf("%s %v", LabelStyle.Render(i18n.T("Charging:")), battery.IsCharging),
+		fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Source:")), ValueStyle.Render(battery.Source)),
 	}
 
-	return BaseStyle.Width(a.width - 4).Render(
-		lipgloss.JoinVertical(lipgloss.Left, content...),
-	)
+	// Power draw against CPU usage shows which activity drained the battery
+	content = append(content, "", sectionHeader(i18n.T("Power Draw")))
+	content = append(content, a.renderPowerGraph()...)
+
+	// Laptop power panel: backlight and power profile
+	content = append(content, "", sectionHeader(i18n.T("Power")))
+	if power := battery.Power; power.Backlight >= 0 {
+		content = append(content,
+			i18n.Sprintf("%s %d%% (%s)", LabelStyle.Render(i18n.T("Backlight:")), power.Backlight, power.BacklightDevice),
+			RenderProgressBar(float64(power.Backlight), 20),
+		)
+	} else {
+		content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Backlight:")), ValueStyle.Render(i18n.T("N/A"))))
+	}
+	if power := battery.Power; power.PowerProfile != "" {
+		content = append(content, i18n.Sprintf("%s %s (of %s) • p: switch",
+			LabelStyle.Render(i18n.T("Power Profile:")), ValueStyle.Render(power.PowerProfile),
+			strings.Join(power.PowerProfiles, ", ")))
+	} else {
+		content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Power Profile:")), ValueStyle.Render(i18n.T("N/A"))))
+	}
+
+	// Sleep/idle inhibitors answer "why won't my laptop suspend"
+	content = append(content, "", sectionHeader(i18n.T("Sleep Inhibitors")))
+	blockers := 0
+	for _, inhibitor := range battery.Inhibitors {
+		style := ValueStyle
+		if collector.IsSleepBlocker(inhibitor) {
+			style = WarningStyle
+			blockers++
+		}
+		content = append(content, style.Render(fmt.Sprintf("%-20s %-6d %-8s %-24s %s",
+			truncateString(inhibitor.Who, 20), inhibitor.PID, inhibitor.Mode,
+			truncateString(inhibitor.What, 24), inhibitor.Why)))
+	}
+	switch {
+	case len(battery.Inhibitors) == 0:
+		content = append(content, i18n.T("None (or logind unavailable)"))
+	case blockers > 0:
+		content = append(content, WarningStyle.Render(i18n.Sprintf("%d lock(s) currently block sleep or idle", blockers)))
+	}
+
+	if len(battery.Peripherals) > 0 {
+		content = append(content, "", sectionHeader(i18n.T("Peripherals")))
+		for _, device := range battery.Peripherals {
+			content = append(content, fmt.Sprintf("%-28s %-10s %3d%% %s %s",
+				truncateString(device.Name, 28), device.Type, device.Level,
+				RenderProgressBar(float64(device.Level), 20), device.Status))
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderPowerGraph plots the battery's charge or discharge rate with the CPU
+// usage over it, on an axis of its own to the right
+func (a *App) renderPowerGraph() []string {
+	const axisWidth = 7
+	width := a.layout.Content - 2*axisWidth
+	samples := width
+	if a.graphStyle == GraphBraille {
+		samples *= 2
+	}
+	peak := a.batteryHistory.Max(samples)
+	if peak == 0 {
+		return []string{i18n.T("The battery does not report its charge or discharge rate")}
+	}
+
+	battery := a.stats.Battery
+	direction := i18n.T("discharging")
+	if battery.IsCharging {
+		direction = i18n.T("charging")
+	}
+	powerColor, cpuColor := lipgloss.Color("214"), lipgloss.Color("39")
+	lines := []string{i18n.Sprintf("%s %s %s (min %.1f W, max %.1f W) • %s %.1f%% (right axis)",
+		LabelStyle.Render(i18n.T("Power:")), lipgloss.NewStyle().Foreground(powerColor).Render(i18n.Sprintf("%.1f W", battery.Watts)),
+		direction, a.batteryHistory.Min(samples), peak, lipgloss.NewStyle().Foreground(cpuColor).Render("CPU"), a.stats.CPU.Usage)}
+
+	rows := OverlayGraph(a.batteryHistory.Values(), a.cpuHistory.Values(), peak, 100,
+		width, graphHeight, a.graphStyle, powerColor, cpuColor)
+	for i, row := range rows {
+		left, right := "", ""
+		switch i {
+		case 0:
+			left, right = fmt.Sprintf("%.1f W", peak), "100%"
+		case len(rows) - 1:
+			left, right = "0 W", "0%"
+		}
+		lines = append(lines, fmt.Sprintf("%*s %s %-*s", axisWidth-1, left, row, axisWidth-1, right))
+	}
+	return append(lines, timeAxis(axisWidth, width, samples, a.interval))
+}
+
+// maxKernelLogRows is the number of most recent kernel messages rendered
+const maxKernelLogRows = 500
+
+func (a *App) renderKernelLog(level int) string {
+	var content strings.Builder
+
+	content.WriteString(sectionHeader(i18n.T("Kernel Log")))
+	content.WriteString("\n\n")
+
+	mode := i18n.T("following")
+	if !a.kernelFollow {
+		mode = i18n.T("paused")
+	}
+	content.WriteString(i18n.Sprintf("Showing %s and above • %s • v: severity • f: follow",
+		models.KernelLevelNames[level], mode))
+	content.WriteString("\n\n")
+	if notice := a.lockedNotice(models.FeatureKernelLog); notice != "" {
+		content.WriteString(notice)
+		content.WriteString("\n\n")
+	}
+
+	if a.kernelErr != "" {
+		content.WriteString(WarningStyle.Render(a.kernelErr))
+		return content.String()
+	}
+
+	var shown []models.KernelMessage
+	for _, record := range a.kernelLog {
+		if record.Priority <= level {
+			shown = append(shown, record)
+		}
+	}
+	if len(shown) > maxKernelLogRows {
+		shown = shown[len(shown)-maxKernelLogRows:]
+	}
+	if len(shown) == 0 {
+		content.WriteString(i18n.T("No kernel messages at this severity"))
+		return content.String()
+	}
+
+	for _, record := range shown {
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+		switch {
+		case record.Priority <= 3:
+			style = ErrorStyle
+		case record.Priority == 4:
+			style = WarningStyle
+		case record.Priority == 7:
+			style = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		}
+
+		line := fmt.Sprintf("%s %-6s %s", record.Time.Format("Jan 02 15:04:05"),
+			models.KernelLevelNames[record.Priority], record.Message)
+		content.WriteString(style.Render(truncateString(line, a.layout.Content)))
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+func (a *App) renderSecurity() string {
+	security := a.security
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		PaddingLeft(1).
+		PaddingRight(1)
+	rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+
+	var content strings.Builder
+	content.WriteString(sectionHeader(i18n.T("Failed SSH Logins (last 24h)")))
+	content.WriteString("\n")
+	switch {
+	case security.FailedLoginsSource == "":
+		content.WriteString(WarningStyle.Render(" " + i18n.T("Neither the journal nor /var/log/auth.log is readable")))
+		content.WriteString("\n")
+	case len(security.FailedLogins) == 0:
+		content.WriteString(SuccessStyle.Render(" " + i18n.Sprintf("None found in %s", security.FailedLoginsSource)))
+		content.WriteString("\n")
+	default:
+		total := 0
+		for _, login := range security.FailedLogins {
+			total += login.Count
+		}
+		content.WriteString(" " + i18n.Sprintf("%d attempts from %d sources (%s)", total, len(security.FailedLogins), security.FailedLoginsSource) + "\n")
+		content.WriteString(columnHeader(headerStyle.Render(fmt.Sprintf("%-39s %7s %-19s %s", "SOURCE", "COUNT", "LAST", "USERS"))))
+		content.WriteString("\n")
+		for _, login := range security.FailedLogins[:min(maxSwapRows, len(security.FailedLogins))] {
+			row := fmt.Sprintf("%-39s %7d %-19s %s", login.Source, login.Count,
+				login.Last.Format("2006-01-02 15:04:05"),
+				truncateString(strings.Join(login.Users, ","), max(10, a.layout.Content-72)))
+			content.WriteString(rowStyle.Render(row))
+			content.WriteString("\n")
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(sectionHeader(i18n.T("Listening Services")))
+	content.WriteString("\n")
+	content.WriteString(columnHeader(headerStyle.Render(fmt.Sprintf("%-5s %-39s %6s %-8s %-16s %-12s", "PROTO", "ADDRESS", "PORT", "PID", "PROCESS", "USER"))))
+	content.WriteString("\n")
+	for _, listener := range security.Listeners {
+		pid, process := "-", "-"
+		if listener.PID != 0 {
+			pid, process = fmt.Sprint(listener.PID), listener.Process
+		}
+		row := fmt.Sprintf("%-5s %-39s %6d %-8s %-16s %-12s", listener.Protocol, listener.Address,
+			listener.Port, pid, truncateString(process, 16), truncateString(listener.User, 12))
+
+		// Services reachable from other machines deserve a second look
+		style := rowStyle
+		if listener.Address != "127.0.0.1" && listener.Address != "::1" {
+			style = rowStyle.Foreground(WarningStyle.GetForeground())
+		}
+		content.WriteString(style.Render(row))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(sectionHeader(i18n.T("Sudo Sessions")))
+	content.WriteString("\n")
+	if len(security.SudoSessions) == 0 {
+		content.WriteString(" " + i18n.T("No active sudo sessions") + "\n")
+	}
+	for _, session := range security.SudoSessions {
+		var row string
+		if session.PID != 0 {
+			row = fmt.Sprintf("%-16s %-9s pid %-8d %s", truncateString(session.User, 16), i18n.T("running"), session.PID, session.Command)
+		} else {
+			row = fmt.Sprintf("%-16s %-9s %s", truncateString(session.User, 16), i18n.T("cached"),
+				i18n.Sprintf("authenticated at %s", session.LastAuth.Format("15:04:05")))
+		}
+		content.WriteString(rowStyle.Render(row))
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+// lockedNotice explains why a feature has no data when croptop lacks the
+// permissions for it, and is empty otherwise
+func (a *App) lockedNotice(feature string) string {
+	privilege, ok := a.locked[feature]
+	if !ok {
+		return ""
+	}
+	return WarningStyle.Render(i18n.Sprintf("🔒 %s needs more permissions: %s", i18n.T(privilege.Description), i18n.T(privilege.Hint)))
+}
+
+// alertKeys edits the alert rules (t)
+func (a *App) alertKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if msg.String() != "t" {
+		return nil, false
+	}
+
+	rules := a.alerts.Rules()
+	options := make([]string, 0, len(rules)+1)
+	for _, rule := range rules {
+		options = append(options, rule.Source)
+	}
+	options = append(options, i18n.T("New rule…"))
+	a.openDialog(newPickerDialog(i18n.T("Edit the alert rule"), options, 0, func(i int) tea.Cmd {
+		a.promptAlertRule(i)
+		return nil
+	}))
+	return nil, true
+}
+
+// promptAlertRule edits rule i of the alert engine, or adds a rule past the
+// end. The change lasts until the config file is reloaded.
+func (a *App) promptAlertRule(i int) {
+	rules := a.alerts.Rules()
+	source := ""
+	if i < len(rules) {
+		source = rules[i].Source
+	}
+
+	validate := func(value string) error {
+		if value == "" {
+			return nil
+		}
+		_, err := alert.ParseRule(value)
+		return err
+	}
+	prompt := i18n.T("Alert rule, e.g. cpu.usage > 90 for 60s; empty removes it:")
+	a.openDialog(newInputDialog(prompt, source, validate, func(value string) tea.Cmd {
+		updated := append([]alert.Rule(nil), rules[:min(i, len(rules))]...)
+		if value != "" {
+			rule, _ := alert.ParseRule(value)
+			updated = append(updated, rule)
+		}
+		if i < len(rules) {
+			updated = append(updated, rules[i+1:]...)
+		}
+		a.alerts.SetRules(updated)
+		slog.Info("alert rule edited", "old", source, "new", value)
+		a.toast(toastSuccess, i18n.T("✓ Alert rules updated until the config file is reloaded"))
+		return nil
+	}))
+}
+
+// renderAlerts lists the alert firings and clears of the session, newest first
+func (a *App) renderAlerts() string {
+	var content strings.Builder
+
+	content.WriteString(sectionHeader(i18n.T("Alert History")))
+	content.WriteString("\n\n")
+	content.WriteString(i18n.Sprintf("%d rule(s) • t: edit thresholds", len(a.alerts.Rules())))
+	content.WriteString("\n\n")
+
+	history := a.alerts.History()
+	if a.alertLogErr != nil {
+		content.WriteString(WarningStyle.Render(i18n.Sprintf("⚠ Alert history not read: %v", a.alertLogErr)))
+		content.WriteString("\n\n")
+	}
+	if len(history) == 0 && len(a.alertRecorded) == 0 {
+		if a.alertLog != "" {
+			content.WriteString(i18n.T("No alerts fired this session or recorded in the history"))
+		} else {
+			content.WriteString(i18n.T("No alerts fired this session"))
+		}
+		return content.String()
+	}
+	if a.alertLog != "" {
+		content.WriteString(i18n.Sprintf("%d event(s) this session • %d recorded in the history • %d firing",
+			len(history), len(a.alertRecorded), len(a.alerts.Active())))
+	} else {
+		content.WriteString(i18n.Sprintf("%d event(s) this session • %d firing", len(history), len(a.alerts.Active())))
+	}
+	content.WriteString("\n\n")
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		PaddingLeft(1).
+		PaddingRight(1)
+
+	header := fmt.Sprintf("%-8s %-7s %-8s %10s %10s %9s  %s",
+		"TIME", "EVENT", "SEVERITY", "VALUE", "PEAK", "LASTED", "RULE")
+	content.WriteString(columnHeader(headerStyle.Render(header)))
+	content.WriteString("\n")
+
+	rows := make([]alertRow, 0, len(history)+len(a.alertRecorded))
+	for _, event := range history {
+		rows = append(rows, sessionAlertRow(event))
+	}
+	for _, event := range a.alertRecorded {
+		rows = append(rows, recordedAlertRow(event))
+	}
+	// Newest first, the history interleaved with this session
+	slices.SortStableFunc(rows, func(x, y alertRow) int { return y.time.Compare(x.time) })
+
+	rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+	today := time.Now().Format(time.DateOnly)
+	for _, r := range rows {
+		// Recorded events may be days old
+		when := r.time.Format("15:04:05")
+		if r.time.Format(time.DateOnly) != today {
+			when = r.time.Format("Jan 02")
+		}
+		row := fmt.Sprintf("%-8s %-7s %-8s %10.1f %10.1f %9s  %s",
+			when, r.kind, r.severity, r.value, r.peak, r.lasted,
+			truncateString(r.rule, max(10, a.layout.Content-62)))
+
+		content.WriteString(rowStyle.Render(r.style.Render(row)))
+		content.WriteString("\n")
+	}
+
+	return content.String()
 }
 
 func min(a, b int) int {
@@ -713,6 +2479,13 @@ func min(a, b int) int {
 	return b
 }
 
+func min64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
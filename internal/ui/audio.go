@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/i18n"
+)
+
+// renderAudio shows the sound server of the CPU tab: its processes' CPU
+// usage, the rate and quantum it runs at and the streams playing or
+// recording, to tell whether crackling comes from load
+func (a *App) renderAudio() []string {
+	audio := a.audio
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	content := []string{sectionHeader(i18n.Sprintf("Audio (%s)", audio.Server))}
+
+	processes := make([]string, len(audio.Processes))
+	for i, proc := range audio.Processes {
+		processes[i] = fmt.Sprintf("%s %.1f%%", proc.Name, a.shownCPU(proc.CPUPercent))
+	}
+	content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Server:")), strings.Join(processes, " • ")))
+
+	if audio.SampleRate > 0 {
+		timing := fmt.Sprintf("%d Hz", audio.SampleRate)
+		// Each cycle has to be done within the quantum's time, or the
+		// device runs dry and crackles
+		if audio.Quantum > 0 {
+			timing += i18n.Sprintf(" • quantum %d frames (%.1fms per cycle)", audio.Quantum,
+				float64(audio.Quantum)/float64(audio.SampleRate)*1000)
+		}
+		content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Rate:")), timing))
+	}
+
+	if len(audio.Streams) == 0 {
+		return append(content, " "+i18n.T("No streams playing or recording"))
+	}
+	content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%-4s %-20s %-24s %-8s %-16s %-10s %-12s %s",
+		"DIR", "APPLICATION", "MEDIA", "PID", "PROCESS", "STATE", "FORMAT", "LATENCY"))))
+	for _, stream := range audio.Streams {
+		direction, pid, format := "out", "-", "-"
+		if stream.Capture {
+			direction = "in"
+		}
+		if stream.PID > 0 {
+			pid = fmt.Sprint(stream.PID)
+		}
+		if stream.SampleRate > 0 {
+			format = fmt.Sprintf("%dHz %dch", stream.SampleRate, stream.Channels)
+		}
+		row := fmt.Sprintf("%-4s %-20s %-24s %-8s %-16s %-10s %-12s %s", direction,
+			truncateString(stream.Application, 20), truncateString(stream.Media, 24), pid,
+			truncateString(stream.Process, 16), stream.State, format, stream.Latency)
+		if stream.State != "running" {
+			row = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(row)
+		}
+		content = append(content, row)
+	}
+	return content
+}
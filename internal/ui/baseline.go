@@ -0,0 +1,73 @@
+package ui
+
+import "math"
+
+// Baseline parameters: the norm follows roughly the last baselineWindow
+// samples, is trusted after baselineWarmup of them, and a value is unusual
+// baselineSigmas standard deviations away from it
+const (
+	baselineWindow = 300
+	baselineWarmup = 30
+	baselineSigmas = 4
+	// How much less an unusual sample moves the norm
+	baselineDamping = 10
+)
+
+// Baseline is the recent norm of a metric, an exponentially weighted mean
+// and standard deviation, to flag values far from it whatever the alert
+// thresholds
+type Baseline struct {
+	// Smallest deviation worth flagging, so an idle metric's noise, such
+	// as 2 KB/s against a usual 100 B/s, is not
+	floor    float64
+	mean     float64
+	variance float64
+	samples  int
+}
+
+func NewBaseline(floor float64) *Baseline {
+	return &Baseline{floor: floor}
+}
+
+// Anomaly is a value far from its baseline
+type Anomaly struct {
+	Value float64
+	// Usual is the baseline mean the value is compared to
+	Usual float64
+}
+
+// High reports whether the value is above the norm
+func (a Anomaly) High() bool {
+	return a.Value > a.Usual
+}
+
+// Check reports whether value is unusual against the samples added so far
+func (b *Baseline) Check(value float64) (Anomaly, bool) {
+	if b.samples < baselineWarmup {
+		return Anomaly{}, false
+	}
+	deviation := math.Abs(value - b.mean)
+	if deviation < b.floor || deviation < baselineSigmas*math.Sqrt(b.variance) {
+		return Anomaly{}, false
+	}
+	return Anomaly{Value: value, Usual: b.mean}, true
+}
+
+// Add moves the baseline towards value
+func (b *Baseline) Add(value float64) {
+	b.samples++
+	if b.samples == 1 {
+		b.mean = value
+		return
+	}
+	// Faster at first, so the warmup does not lean on the first sample
+	alpha := math.Max(2.0/(baselineWindow+1), 1/float64(b.samples))
+	// Unusual values count for less, so a lasting change stays flagged
+	// for a while before it becomes the norm
+	if _, unusual := b.Check(value); unusual {
+		alpha /= baselineDamping
+	}
+	diff := value - b.mean
+	b.mean += alpha * diff
+	b.variance = (1 - alpha) * (b.variance + alpha*diff*diff)
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// maxBootUnits is how many of the slowest units the Boot tab lists
+const maxBootUnits = 30
+
+// slowBootUnit is how long a unit may take to start before it is
+// highlighted
+const slowBootUnit = 5 * time.Second
+
+// bootTab is the boot analysis, with a unit of the slowest ones selected
+// to start, stop, restart, enable or disable it
+type bootTab struct {
+	app      *App
+	selected int
+	scroll   scrollView
+	// The selection moved and is scrolled into view on the next render
+	follow bool
+}
+
+func (t *bootTab) Init() tea.Cmd {
+	return nil
+}
+
+func (t *bootTab) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	units := t.app.bootUnits()
+	switch key.String() {
+	case "up", "k":
+		t.selected--
+	case "down", "j":
+		t.selected++
+	case "home":
+		t.selected = 0
+	case "end":
+		t.selected = len(units) - 1
+	case "enter":
+		if t.selected < len(units) && t.app.allowAction(i18n.T("Managing systemd units")) {
+			t.app.pickUnitAction(units[t.selected].Name)
+		}
+		return nil
+	default:
+		t.scroll.Update(key)
+		return nil
+	}
+	t.selected = max(0, min(t.selected, len(units)-1))
+	t.follow = true
+	return nil
+}
+
+func (t *bootTab) View(width, height int) string {
+	t.selected = max(0, min(t.selected, len(t.app.bootUnits())-1))
+	content, row := t.app.renderBoot(t.selected)
+	if t.follow && row >= 0 {
+		// Below the pinned headers and above the indicator of more content
+		t.scroll.offset = max(min(t.scroll.offset, row-1), row-height+4)
+		t.follow = false
+	}
+	return t.scroll.View(content, height)
+}
+
+// bootUnits are the slowest units the Boot tab lists
+func (a *App) bootUnits() []models.BootUnit {
+	return a.boot.Units[:min(maxBootUnits, len(a.boot.Units))]
+}
+
+// unitActionMsg reports the result of a systemd unit action
+type unitActionMsg struct {
+	unit   string
+	action string
+	err    error
+}
+
+// unitActionTexts name collector.UnitActions in the picker and the toast
+// of their success
+var unitActionTexts = map[string]struct{ name, done string }{
+	"start":   {"Start", "Started %s"},
+	"stop":    {"Stop", "Stopped %s"},
+	"restart": {"Restart", "Restarted %s"},
+	"enable":  {"Enable at boot", "Enabled %s at boot"},
+	"disable": {"Disable at boot", "Disabled %s at boot"},
+}
+
+// pickUnitAction asks what to do to unit, then confirms it
+func (a *App) pickUnitAction(unit string) {
+	names := make([]string, len(collector.UnitActions))
+	for i, action := range collector.UnitActions {
+		names[i] = i18n.T(unitActionTexts[action].name)
+	}
+	a.openDialog(newPickerDialog(i18n.Sprintf("Action on %s", unit), names, 0, func(i int) tea.Cmd {
+		action := collector.UnitActions[i]
+		question := i18n.Sprintf("%s %s?", i18n.T(unitActionTexts[action].name), unit)
+		a.openDialog(newConfirmDialog(question, func() tea.Cmd {
+			return func() tea.Msg {
+				return unitActionMsg{unit: unit, action: action, err: a.collector.RunUnitAction(unit, action)}
+			}
+		}))
+		return nil
+	}))
+}
+
+// renderBoot shows how long the last boot took, the units that slowed it
+// down with what they use now, and the critical chain. It returns the line
+// of the selected unit as well, -1 when there is none.
+func (a *App) renderBoot(selected int) (string, int) {
+	boot := a.boot
+	content := []string{sectionHeader(i18n.T("Last Boot"))}
+	if boot.Error != "" {
+		content = append(content, WarningStyle.Render(boot.Error))
+		return lipgloss.JoinVertical(lipgloss.Left, content...), -1
+	}
+
+	content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Total:")), ValueStyle.Render(formatDuration(boot.Total))))
+	for _, phase := range boot.Phases {
+		var share float64
+		if boot.Total > 0 {
+			share = float64(phase.Duration) / float64(boot.Total) * 100
+		}
+		content = append(content, fmt.Sprintf("  %-10s %8s %s %3.0f%%", phase.Name, formatDuration(phase.Duration),
+			RenderProgressBar(share, 30), share))
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	content = append(content, "", sectionHeader(i18n.T("Slowest Units")),
+		columnHeader(headerStyle.Render(fmt.Sprintf("%9s %-40s %7s %10s %12s", "TIME", "UNIT", "CPU", "MEMORY", "PSI C/M/IO"))))
+	selectedRow := -1
+	for i, unit := range a.bootUnits() {
+		cpu, memory := "-", "-"
+		if unit.Running {
+			cpu, memory = fmt.Sprintf("%.1f%%", unit.CPUPercent), formatBytes(unit.MemoryUsed*1024)
+		}
+		pressure, stalled := formatPressure(unit.Pressure)
+		row := fmt.Sprintf("%9s %-40s %7s %10s %12s", formatDuration(unit.StartTime), truncateString(unit.Name, 40), cpu, memory, pressure)
+		style := lipgloss.NewStyle()
+		if unit.StartTime >= slowBootUnit || stalled {
+			style = style.Foreground(WarningStyle.GetForeground())
+		}
+		if i == selected {
+			style = style.Background(lipgloss.Color("240")).Bold(true)
+			selectedRow = len(content)
+		}
+		content = append(content, style.Render(row))
+	}
+	if len(boot.Units) > maxBootUnits {
+		content = append(content, i18n.Sprintf("… and %d more", len(boot.Units)-maxBootUnits))
+	}
+
+	// Each unit waited for the one below it; @ is when it was up after
+	// userspace started, + how long it took to start itself
+	content = append(content, "", sectionHeader(i18n.T("Critical Chain")))
+	for _, unit := range boot.Chain {
+		row := fmt.Sprintf("%s%s @%s", strings.Repeat("  ", unit.Depth), unit.Name, formatDuration(unit.Active))
+		if unit.Took > 0 {
+			row += " +" + formatDuration(unit.Took)
+		}
+		if unit.Took >= slowBootUnit {
+			row = WarningStyle.Render(row)
+		}
+		content = append(content, row)
+	}
+
+	content = append(content, "", lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+		i18n.T("↑↓ j/k: select unit • Enter: start, stop, restart, enable or disable it")))
+	return lipgloss.JoinVertical(lipgloss.Left, content...), selectedRow
+}
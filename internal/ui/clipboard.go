@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardMsg reports what was copied to the clipboard
+type clipboardMsg struct {
+	what string
+	err  error
+}
+
+// copyToClipboard copies text to the system clipboard with an OSC 52 escape
+// sequence, which the terminal carries out, so it also works over SSH.
+// Terminals that do not support OSC 52 ignore it silently. what describes
+// text in the notice confirming the copy.
+func copyToClipboard(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		// Multiplexers swallow the sequence unless it is wrapped for them
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+			seq = seq.Screen()
+		}
+		_, err := seq.WriteTo(os.Stdout)
+		return clipboardMsg{what: what, err: err}
+	}
+}
@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// processColumn is a column of the process table. The same columns drive
+// the table and its CSV/JSON export.
+type processColumn struct {
+	// Key names the column in exports
+	Key   string
+	Title string
+	// Width in the table; 0 takes the rest of the line
+	Width int
+	Right bool
+	// Text renders a table cell, Value the exported value
+	Text  func(proc models.Process) string
+	Value func(proc models.Process) any
+}
+
+// processColumns are the columns of the process table, in order
+var processColumns = []processColumn{
+	{
+		Key: "pid", Title: "PID", Width: 8,
+		Text:  func(p models.Process) string { return fmt.Sprint(p.PID) },
+		Value: func(p models.Process) any { return p.PID },
+	},
+	{
+		Key: "name", Title: "NAME", Width: 20,
+		Text:  func(p models.Process) string { return p.Name },
+		Value: func(p models.Process) any { return p.Name },
+	},
+	{
+		Key: "cpu_percent", Title: "CPU%", Width: 8, Right: true,
+		Text:  func(p models.Process) string { return fmt.Sprintf("%.1f%%", p.CPUPercent) },
+		Value: func(p models.Process) any { return p.CPUPercent },
+	},
+	{
+		Key: "memory_percent", Title: "MEM%", Width: 8, Right: true,
+		Text:  func(p models.Process) string { return fmt.Sprintf("%.1f%%", p.MemPercent) },
+		Value: func(p models.Process) any { return p.MemPercent },
+	},
+	{
+		Key: "status", Title: "STATUS", Width: 12,
+		Text:  func(p models.Process) string { return p.Status },
+		Value: func(p models.Process) any { return p.Status },
+	},
+	{
+		Key: "runtime_seconds", Title: "TIME", Width: 9, Right: true,
+		Text:  func(p models.Process) string { return formatDuration(p.Runtime) },
+		Value: func(p models.Process) any { return int64(p.Runtime.Seconds()) },
+	},
+	{
+		Key: "command", Title: "COMMAND",
+		// Arguments may contain newlines, which would break the row count
+		Text:  func(p models.Process) string { return strings.Join(strings.Fields(p.Command), " ") },
+		Value: func(p models.Process) any { return p.Command },
+	},
+}
+
+// memoryDetailColumns follow MEM% in accurate memory mode. A dash is a
+// process whose smaps_rollup could not be read.
+var memoryDetailColumns = []processColumn{
+	{
+		Key: "pss_kb", Title: "PSS", Width: 9, Right: true,
+		Text:  func(p models.Process) string { return formatKB(p.PSS) },
+		Value: func(p models.Process) any { return p.PSS },
+	},
+	{
+		Key: "uss_kb", Title: "USS", Width: 9, Right: true,
+		Text:  func(p models.Process) string { return formatKB(p.USS) },
+		Value: func(p models.Process) any { return p.USS },
+	},
+}
+
+// optionalColumns are the columns process_columns and O add before COMMAND,
+// by config.ProcessColumns key
+var optionalColumns = map[string]processColumn{
+	config.ColumnVoluntarySwitches: {
+		Key: config.ColumnVoluntarySwitches, Title: "VCSW/s", Width: 8, Right: true,
+		Text:  func(p models.Process) string { return formatPerSecond(p.VoluntaryCtxRate) },
+		Value: func(p models.Process) any { return p.VoluntaryCtxRate },
+	},
+	config.ColumnInvoluntarySwitches: {
+		Key: config.ColumnInvoluntarySwitches, Title: "ICSW/s", Width: 8, Right: true,
+		Text:  func(p models.Process) string { return formatPerSecond(p.NonvoluntaryCtxRate) },
+		Value: func(p models.Process) any { return p.NonvoluntaryCtxRate },
+	},
+	config.ColumnMinorFaults: {
+		Key: config.ColumnMinorFaults, Title: "MINFLT/s", Width: 9, Right: true,
+		Text:  func(p models.Process) string { return formatPerSecond(p.MinorFaultRate) },
+		Value: func(p models.Process) any { return p.MinorFaultRate },
+	},
+	config.ColumnMajorFaults: {
+		Key: config.ColumnMajorFaults, Title: "MAJFLT/s", Width: 9, Right: true,
+		Text:  func(p models.Process) string { return formatPerSecond(p.MajorFaultRate) },
+		Value: func(p models.Process) any { return p.MajorFaultRate },
+	},
+	config.ColumnRunDelay: {
+		Key: config.ColumnRunDelay, Title: "WAIT%", Width: 7, Right: true,
+		Text:  func(p models.Process) string { return fmt.Sprintf("%.1f%%", p.RunDelayPercent) },
+		Value: func(p models.Process) any { return p.RunDelayPercent },
+	},
+	config.ColumnWaitChannel: {
+		Key: config.ColumnWaitChannel, Title: "WCHAN", Width: 18,
+		Text:  func(p models.Process) string { return p.WaitChannel },
+		Value: func(p models.Process) any { return p.WaitChannel },
+	},
+	config.ColumnMemoryGrowth: {
+		Key: config.ColumnMemoryGrowth, Title: "GROWTH", Width: 10, Right: true,
+		Text:  func(p models.Process) string { return formatGrowth(p.RSSGrowth) },
+		Value: func(p models.Process) any { return p.RSSGrowth },
+	},
+}
+
+// optionalColumnNames describe the optional columns in the O picker
+var optionalColumnNames = map[string]string{
+	config.ColumnVoluntarySwitches:   "voluntary context switches, waiting for I/O or locks",
+	config.ColumnInvoluntarySwitches: "involuntary context switches, preempted by the scheduler",
+	config.ColumnMinorFaults:         "minor page faults, served from memory",
+	config.ColumnMajorFaults:         "major page faults, read from disk",
+	config.ColumnRunDelay:            "time waiting for a CPU, of the main thread",
+	config.ColumnWaitChannel:         "kernel function a blocked (D) process waits in",
+	config.ColumnMemoryGrowth:        "RSS growth over the last 10 minutes, to spot leaks",
+}
+
+// tableColumns are the process columns in use, with TAG when tags are
+// configured, PSS and USS in accurate memory mode and the optional columns
+// turned on
+func (a *App) tableColumns() []processColumn {
+	columns := processColumns
+	if len(a.tags) > 0 {
+		i := slices.IndexFunc(columns, func(column processColumn) bool { return column.Key == "name" }) + 1
+		columns = slices.Concat(columns[:i], []processColumn{tagColumn}, columns[i:])
+	}
+	if a.accurateMemory {
+		i := slices.IndexFunc(columns, func(column processColumn) bool { return column.Key == "memory_percent" }) + 1
+		columns = slices.Concat(columns[:i], memoryDetailColumns, columns[i:])
+	}
+	if len(a.processColumns) > 0 {
+		// In the order of config.ProcessColumns, however they were turned on
+		var optional []processColumn
+		for _, key := range config.ProcessColumns {
+			if slices.Contains(a.processColumns, key) {
+				optional = append(optional, optionalColumns[key])
+			}
+		}
+		i := len(columns) - 1
+		columns = slices.Concat(columns[:i], optional, columns[i:])
+	}
+	return columns
+}
+
+// pickColumn turns an optional column of the process table on or off
+func (a *App) pickColumn(done func()) {
+	options := make([]string, len(config.ProcessColumns))
+	for i, key := range config.ProcessColumns {
+		mark := "[ ]"
+		if slices.Contains(a.processColumns, key) {
+			mark = "[x]"
+		}
+		options[i] = fmt.Sprintf("%s %-8s %s", mark, optionalColumns[key].Title, i18n.T(optionalColumnNames[key]))
+	}
+	a.openDialog(newPickerDialog(i18n.T("Show or hide a column:"), options, 0, func(index int) tea.Cmd {
+		key := config.ProcessColumns[index]
+		if i := slices.Index(a.processColumns, key); i >= 0 {
+			a.processColumns = slices.Delete(slices.Clone(a.processColumns), i, i+1)
+		} else {
+			a.processColumns = append(slices.Clone(a.processColumns), key)
+		}
+		done()
+		return nil
+	}))
+}
+
+// formatPerSecond renders a rate compactly, with a decimal only while small
+func formatPerSecond(rate float64) string {
+	if rate < 100 {
+		return fmt.Sprintf("%.1f", rate)
+	}
+	return fmt.Sprintf("%.0f", rate)
+}
+
+func formatKB(kb uint64) string {
+	if kb == 0 {
+		return "-"
+	}
+	return formatBytes(float64(kb) * 1024)
+}
+
+// formatGrowth renders an RSS change in KB with its sign
+func formatGrowth(kb int64) string {
+	switch {
+	case kb > 0:
+		return "+" + formatBytes(float64(kb)*1024)
+	case kb < 0:
+		return "-" + formatBytes(float64(-kb)*1024)
+	default:
+		return "0"
+	}
+}
+
+// minCommandWidth is the narrowest the last column gets
+const minCommandWidth = 10
+
+// formatColumns lays out one table line. The last column gets whatever
+// width the others leave, but at least minCommandWidth.
+func formatColumns(columns []processColumn, width int, cell func(processColumn) string) string {
+	used := 0
+	for _, column := range columns {
+		used += column.Width + 1
+	}
+
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		columnWidth := column.Width
+		if columnWidth == 0 {
+			columnWidth = max(minCommandWidth, width-used)
+		}
+		text := truncateString(cell(column), columnWidth)
+		switch {
+		case column.Width == 0 && i == len(columns)-1:
+			cells[i] = text
+		case column.Right:
+			cells[i] = fmt.Sprintf("%*s", columnWidth, text)
+		default:
+			cells[i] = fmt.Sprintf("%-*s", columnWidth, text)
+		}
+	}
+	return strings.Join(cells, " ")
+}
+
+// scrolledColumns returns columns with the first offset columns after PID
+// scrolled out of view. PID stays as the row's anchor.
+func scrolledColumns(columns []processColumn, offset int) []processColumn {
+	offset = max(0, min(offset, maxColumnOffset(columns)))
+	return append([]processColumn{columns[0]}, columns[1+offset:]...)
+}
+
+// maxColumnOffset scrolls until only PID and the last column are left
+func maxColumnOffset(columns []processColumn) int {
+	return len(columns) - 2
+}
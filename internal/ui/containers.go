@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// containerLogLines is how much of a container's output the log pane tails
+const containerLogLines = 500
+
+// containerLogsMsg delivers the tail of the output of the container whose
+// logs are shown
+type containerLogsMsg struct {
+	id    string
+	lines []string
+	err   error
+}
+
+// containerActionMsg reports the result of stopping or restarting a container
+type containerActionMsg struct {
+	name string
+	// action and done describe the action for the toast, e.g. "Stopping"
+	// and "Stopped %s"
+	action string
+	done   string
+	err    error
+}
+
+// fetchContainerLogs reads the tail of the shown container's output, off
+// the UI goroutine
+func (a *App) fetchContainerLogs() tea.Cmd {
+	id := a.containerLogsID
+	if id == "" || a.containerLogsBusy {
+		return nil
+	}
+	a.containerLogsBusy = true
+	return func() tea.Msg {
+		lines, err := a.collector.GetContainerLogs(id, containerLogLines)
+		return containerLogsMsg{id: id, lines: lines, err: err}
+	}
+}
+
+// confirmContainerAction asks before stopping or restarting a container
+func (a *App) confirmContainerAction(container models.Container, restart bool) {
+	if !a.allowAction(i18n.T("Stopping and restarting containers")) {
+		return
+	}
+	question := i18n.Sprintf("Stop container %s?", container.Name)
+	action, done, run := i18n.T("Stopping"), "Stopped %s", a.collector.StopContainer
+	if restart {
+		question = i18n.Sprintf("Restart container %s?", container.Name)
+		action, done, run = i18n.T("Restarting"), "Restarted %s", a.collector.RestartContainer
+	}
+	a.openDialog(newConfirmDialog(question, func() tea.Cmd {
+		return func() tea.Msg {
+			return containerActionMsg{name: container.Name, action: action, done: done, err: run(container.ID)}
+		}
+	}))
+}
+
+// containersTab lists the Docker or Podman containers with one selected,
+// and Enter tails the selected one's output in place of the list
+type containersTab struct {
+	app      *App
+	selected int
+	scroll   scrollView
+}
+
+func (t *containersTab) Init() tea.Cmd {
+	return t.app.fetchContainerLogs()
+}
+
+func (t *containersTab) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	a := t.app
+	if a.containerLogsID != "" {
+		switch key.String() {
+		case "esc", "enter", "backspace":
+			a.containerLogsID, a.containerLogs, a.containerLogsErr = "", nil, nil
+		case "f":
+			a.containerLogsFollow = !a.containerLogsFollow
+		case "s", "r":
+			for _, container := range a.containers {
+				if container.ID == a.containerLogsID {
+					a.confirmContainerAction(container, key.String() == "r")
+				}
+			}
+		case "up", "k", "pgup", "ctrl+u", "home", "ctrl+home":
+			// Reading back through the output pauses following it
+			a.containerLogsFollow = false
+			t.scroll.Update(key)
+		default:
+			t.scroll.Update(key)
+		}
+		return nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		t.selected--
+	case "down", "j":
+		t.selected++
+	case "home":
+		t.selected = 0
+	case "end":
+		t.selected = len(a.containers) - 1
+	case "enter":
+		if t.selected < len(a.containers) {
+			a.containerLogsID = a.containers[t.selected].ID
+			a.containerLogsFollow = true
+			t.scroll = scrollView{}
+			return a.fetchContainerLogs()
+		}
+	case "s", "r":
+		if t.selected < len(a.containers) {
+			a.confirmContainerAction(a.containers[t.selected], key.String() == "r")
+		}
+	}
+	t.selected = max(0, min(t.selected, len(a.containers)-1))
+	return nil
+}
+
+func (t *containersTab) View(width, height int) string {
+	a := t.app
+	t.selected = max(0, min(t.selected, len(a.containers)-1))
+	if a.containerLogsID != "" {
+		t.scroll.follow = a.containerLogsFollow
+		return t.scroll.View(a.renderContainerLogs(), height)
+	}
+	return t.scroll.View(a.renderContainers(t.selected), height)
+}
+
+// renderContainers lists the containers of the runtime
+func (a *App) renderContainers(selected int) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).PaddingLeft(1).PaddingRight(1)
+	content := []string{
+		sectionHeader(i18n.T("Containers")),
+		fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("API:")), collector.ContainerSocket()),
+		"",
+	}
+	if a.containersErr != nil {
+		content = append(content, ErrorStyle.Render(" "+a.containersErr.Error()))
+		return strings.Join(content, "\n")
+	}
+
+	content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%-24s %-12s %-32s %12s  %s", "NAME", "STATE", "IMAGE", "PSI C/M/IO", "STATUS"))))
+	for i, container := range a.containers {
+		pressure, stalled := formatPressure(container.Pressure)
+		row := fmt.Sprintf("%-24s %-12s %-32s %12s  %s", truncateString(container.Name, 24), container.State,
+			truncateString(container.Image, 32), pressure, container.Status)
+		style := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+		switch container.State {
+		case "running":
+			if stalled {
+				style = style.Foreground(WarningStyle.GetForeground())
+			}
+		case "restarting", "paused":
+			style = style.Foreground(WarningStyle.GetForeground())
+		default:
+			style = style.Foreground(lipgloss.Color("241"))
+		}
+		if i == selected {
+			style = style.Background(lipgloss.Color("240")).Bold(true)
+		}
+		content = append(content, style.Render(truncateString(row, max(10, a.layout.Content-2))))
+	}
+	if len(a.containers) == 0 {
+		content = append(content, " "+i18n.T("No containers"))
+	}
+
+	content = append(content, "", lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+		" "+i18n.T("↑↓ j/k: select • Enter: logs • s: stop • r: restart")))
+	return strings.Join(content, "\n")
+}
+
+// renderContainerLogs shows the tail of the output of the container whose
+// logs are open
+func (a *App) renderContainerLogs() string {
+	name := shortID(a.containerLogsID)
+	for _, container := range a.containers {
+		if container.ID == a.containerLogsID {
+			name = container.Name
+		}
+	}
+	follow := i18n.T("paused")
+	if a.containerLogsFollow {
+		follow = i18n.T("following")
+	}
+	content := []string{
+		sectionHeader(i18n.Sprintf("Logs of %s (last %d lines, %s)", name, containerLogLines, follow)),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+			i18n.T("Esc/Enter: back • ↑↓ j/k: scroll • f: follow • s: stop • r: restart")),
+		"",
+	}
+	if a.containerLogsErr != nil {
+		content = append(content, ErrorStyle.Render(a.containerLogsErr.Error()))
+	}
+	for _, line := range a.containerLogs {
+		content = append(content, truncateString(line, max(10, a.layout.Content-2)))
+	}
+	return strings.Join(content, "\n")
+}
+
+// shortID shortens a container ID the way docker ps does
+func shortID(id string) string {
+	return id[:min(12, len(id))]
+}
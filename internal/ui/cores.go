@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// physicalCore is a core of a package with the SMT siblings running on it,
+// as indexes into CPUStats.Cores
+type physicalCore struct {
+	id   int
+	cpus []int
+}
+
+// cpuPackage is a physical package (socket) with its cores in order
+type cpuPackage struct {
+	id    int
+	cores []physicalCore
+}
+
+// cpuPackages groups the cores of cpu by package and physical core. Without
+// topology every CPU is a core of its own in one package.
+func cpuPackages(cpu models.CPUStats) []cpuPackage {
+	if len(cpu.Topology) != len(cpu.Cores) {
+		cores := make([]physicalCore, len(cpu.Cores))
+		for i := range cpu.Cores {
+			cores[i] = physicalCore{id: cpu.CoreID(i), cpus: []int{i}}
+		}
+		return []cpuPackage{{cores: cores}}
+	}
+
+	var packages []cpuPackage
+	for i, place := range cpu.Topology {
+		p := slices.IndexFunc(packages, func(pkg cpuPackage) bool { return pkg.id == place.Package })
+		if p < 0 {
+			packages = append(packages, cpuPackage{id: place.Package})
+			p = len(packages) - 1
+		}
+		pkg := &packages[p]
+		c := slices.IndexFunc(pkg.cores, func(core physicalCore) bool { return core.id == place.Core })
+		if c < 0 {
+			pkg.cores = append(pkg.cores, physicalCore{id: place.Core})
+			c = len(pkg.cores) - 1
+		}
+		pkg.cores[c].cpus = append(pkg.cores[c].cpus, i)
+	}
+
+	slices.SortFunc(packages, func(a, b cpuPackage) int { return a.id - b.id })
+	for _, pkg := range packages {
+		slices.SortFunc(pkg.cores, func(a, b physicalCore) int { return a.id - b.id })
+	}
+	return packages
+}
+
+// hasSMT reports whether any core runs more than one CPU
+func hasSMT(packages []cpuPackage) bool {
+	for _, pkg := range packages {
+		for _, core := range pkg.cores {
+			if len(core.cpus) > 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// usage averages the usage of the core's CPUs
+func (c physicalCore) usage(cpu models.CPUStats) float64 {
+	var total float64
+	for _, i := range c.cpus {
+		total += cpu.Cores[i]
+	}
+	return total / float64(len(c.cpus))
+}
+
+// cpuList names the CPUs of the core, e.g. "3,35"
+func (c physicalCore) cpuList(cpu models.CPUStats) string {
+	ids := make([]string, len(c.cpus))
+	for i, index := range c.cpus {
+		ids[i] = strconv.Itoa(cpu.CoreID(index))
+	}
+	return strings.Join(ids, ",")
+}
+
+// coreGridThreshold is the CPU count above which the CPU tab starts with
+// the grid instead of a bar per CPU
+const coreGridThreshold = 16
+
+// coreCell is a cell of the core grid: a CPU, or a whole physical core when
+// SMT siblings are collapsed, with cpu -1
+type coreCell struct {
+	pkg  int
+	core physicalCore
+	cpu  int
+}
+
+// coreCells lists the grid cells of the packages in order
+func coreCells(packages []cpuPackage, collapse bool) []coreCell {
+	var cells []coreCell
+	for _, pkg := range packages {
+		for _, core := range pkg.cores {
+			if collapse && len(core.cpus) > 1 {
+				cells = append(cells, coreCell{pkg: pkg.id, core: core, cpu: -1})
+				continue
+			}
+			for _, i := range core.cpus {
+				cells = append(cells, coreCell{pkg: pkg.id, core: core, cpu: i})
+			}
+		}
+	}
+	return cells
+}
+
+// label is the CPU number, or "c" and the core ID for a collapsed core
+func (c coreCell) label(cpu models.CPUStats) string {
+	if c.cpu < 0 {
+		return "c" + strconv.Itoa(c.core.id)
+	}
+	return strconv.Itoa(cpu.CoreID(c.cpu))
+}
+
+func (c coreCell) usage(cpu models.CPUStats) float64 {
+	if c.cpu < 0 {
+		return c.core.usage(cpu)
+	}
+	return cpu.Cores[c.cpu]
+}
+
+// gauge is the index of the core gauge the cell's details use
+func (c coreCell) gauge() int {
+	if c.cpu < 0 {
+		return c.core.cpus[0]
+	}
+	return c.cpu
+}
+
+// coreSelectable reports whether ←/→ and h/l move the grid selection
+// instead of switching tabs
+func (a *App) coreSelectable() bool {
+	return a.coreGrid && a.coreSelecting
+}
+
+// cpuKeys toggles collapsing SMT siblings (t) and the grid (g). In the grid
+// Enter selects a cell to show its details, moved with the arrow keys until
+// Esc.
+func (a *App) cpuKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "t":
+		a.collapseSMT = !a.collapseSMT
+		return nil, true
+	case "g":
+		a.coreGrid = !a.coreGrid
+		a.coreSelecting = false
+		return nil, true
+	case "enter":
+		if a.coreGrid {
+			a.coreSelecting = true
+			return nil, true
+		}
+		return nil, false
+	}
+	if !a.coreSelectable() {
+		return nil, false
+	}
+
+	cells := len(coreCells(cpuPackages(a.stats.CPU), a.collapseSMT))
+	columns := max(1, a.coreColumns)
+	switch msg.String() {
+	case "esc":
+		a.coreSelecting = false
+	case "left", "h":
+		a.coreSelected--
+	case "right", "l":
+		a.coreSelected++
+	case "up", "k":
+		a.coreSelected -= columns
+	case "down", "j":
+		a.coreSelected += columns
+	case "home":
+		a.coreSelected = 0
+	case "end":
+		a.coreSelected = cells - 1
+	default:
+		return nil, false
+	}
+	a.coreSelected = max(0, min(a.coreSelected, cells-1))
+	return nil, true
+}
+
+// coreHints is the key help of the per-core section
+func (a *App) coreHints(smt bool) string {
+	var hints []string
+	switch {
+	case a.coreSelectable():
+		hints = append(hints, i18n.T("arrows: select a core • Esc: done"))
+	case a.coreGrid:
+		hints = append(hints, i18n.T("g: bars • Enter: select a core"))
+	default:
+		hints = append(hints, i18n.T("g: grid"))
+	}
+	if smt {
+		if a.collapseSMT {
+			hints = append(hints, i18n.T("t: one bar per hyperthread"))
+		} else {
+			hints = append(hints, i18n.T("t: one bar per physical core"))
+		}
+	}
+	return strings.Join(hints, " • ")
+}
+
+// renderCores draws the per-core usage, as bars or as the grid
+func (a *App) renderCores() []string {
+	packages := cpuPackages(a.stats.CPU)
+	smt := hasSMT(packages)
+	content := []string{a.coreHints(smt), ""}
+	if a.coreGrid {
+		return append(content, a.renderCoreGrid(packages)...)
+	}
+	return append(content, a.renderCoreBars(packages, smt)...)
+}
+
+// renderCoreBars draws a bar per CPU, grouped by package with SMT siblings
+// next to each other, or a bar per physical core when collapsed
+func (a *App) renderCoreBars(packages []cpuPackage, smt bool) []string {
+	cpu := a.stats.CPU
+	var content []string
+	for _, pkg := range packages {
+		if len(packages) > 1 {
+			content = append(content, LabelStyle.Render(i18n.Sprintf("Package %d", pkg.id)))
+		}
+		for _, core := range pkg.cores {
+			if a.collapseSMT && len(core.cpus) > 1 {
+				usage := core.usage(cpu)
+				content = append(content,
+					i18n.Sprintf("Core %d (CPUs %s): %.1f%%", core.id, core.cpuList(cpu), usage),
+					a.coreGauges[core.cpus[0]].View(usage),
+					"",
+				)
+				continue
+			}
+			for _, i := range core.cpus {
+				label := i18n.Sprintf("Core %d: %.1f%%", cpu.CoreID(i), cpu.Cores[i])
+				if smt {
+					label = i18n.Sprintf("CPU %d (core %d): %.1f%%", cpu.CoreID(i), core.id, cpu.Cores[i])
+				}
+				content = append(content, label, a.coreGauges[i].View(cpu.Cores[i]), "")
+			}
+		}
+	}
+	return content
+}
+
+// Grid cells are the label, a small bar and the percentage
+const (
+	coreCellBar = 6
+	coreCellGap = 2
+)
+
+// renderCoreGrid draws the cells in as many columns as fit the width, the
+// selected cell's details above them
+func (a *App) renderCoreGrid(packages []cpuPackage) []string {
+	cpu := a.stats.CPU
+	cells := coreCells(packages, a.collapseSMT)
+	if len(cells) == 0 {
+		return nil
+	}
+
+	labelWidth := 0
+	for _, cell := range cells {
+		labelWidth = max(labelWidth, len(cell.label(cpu)))
+	}
+	cellWidth := labelWidth + 1 + coreCellBar + 5
+	a.coreColumns = max(1, (a.layout.Content+coreCellGap)/(cellWidth+coreCellGap))
+
+	var content []string
+	selected := max(0, min(a.coreSelected, len(cells)-1))
+	if a.coreSelectable() {
+		content = append(content, a.renderCoreDetails(cells[selected])...)
+	}
+
+	// Rows restart at every package, keeping the columns aligned in each
+	var row []string
+	flush := func() {
+		if len(row) > 0 {
+			content = append(content, strings.Join(row, strings.Repeat(" ", coreCellGap)))
+			row = nil
+		}
+	}
+	for i, cell := range cells {
+		if len(packages) > 1 && (i == 0 || cells[i-1].pkg != cell.pkg) {
+			flush()
+			if i > 0 {
+				content = append(content, "")
+			}
+			content = append(content, LabelStyle.Render(i18n.Sprintf("Package %d", cell.pkg)))
+		}
+		label := fmt.Sprintf("%*s", labelWidth, cell.label(cpu))
+		if a.coreSelectable() && i == selected {
+			label = SelectedRowStyle.Render(label)
+		}
+		usage := cell.usage(cpu)
+		row = append(row, fmt.Sprintf("%s %s %3.0f%%", label, RenderProgressBar(usage, coreCellBar), usage))
+		if len(row) == a.coreColumns {
+			flush()
+		}
+	}
+	flush()
+	return content
+}
+
+// renderCoreDetails describes the selected grid cell
+func (a *App) renderCoreDetails(cell coreCell) []string {
+	cpu := a.stats.CPU
+	usage := cell.usage(cpu)
+
+	var name string
+	if cell.cpu < 0 {
+		name = i18n.Sprintf("Core %d (CPUs %s)", cell.core.id, cell.core.cpuList(cpu))
+	} else {
+		name = i18n.Sprintf("CPU %d", cpu.CoreID(cell.cpu))
+	}
+	content := []string{i18n.Sprintf("%s %s", LabelStyle.Render(i18n.T("Selected:")), ValueStyle.Render(name))}
+	if len(cpu.Topology) == len(cpu.Cores) {
+		content = append(content, i18n.Sprintf("%s %s", LabelStyle.Render(i18n.T("Location:")),
+			i18n.Sprintf("package %d, core %d", cell.pkg, cell.core.id)))
+		if cell.cpu >= 0 && len(cell.core.cpus) > 1 {
+			content = append(content, i18n.Sprintf("%s %s", LabelStyle.Render(i18n.T("SMT Siblings:")), cell.core.cpuList(cpu)))
+		}
+	}
+	return append(content,
+		i18n.Sprintf("%s %.1f%%", LabelStyle.Render(i18n.T("Usage:")), usage),
+		a.coreGauges[cell.gauge()].View(usage),
+		"",
+	)
+}
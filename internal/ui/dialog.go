@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/prabalesh/croptop/internal/i18n"
+)
+
+// dialog is a modal prompt over the active tab. While one is open App
+// passes it every key but Ctrl+C, so tabs don't switch under it. All
+// dialogs confirm with Enter and cancel with Esc.
+type dialog interface {
+	// Update handles a key and reports whether the dialog is finished
+	Update(msg tea.KeyMsg) (tea.Cmd, bool)
+	// View renders the inside of the dialog box, at most width wide
+	View(width int) string
+}
+
+// maxDialogWidth keeps dialogs readable in wide terminals
+const maxDialogWidth = 64
+
+// openDialog shows d until it is confirmed or cancelled
+func (a *App) openDialog(d dialog) {
+	a.dialog = d
+}
+
+// renderDialog draws the open dialog centered over view
+func (a *App) renderDialog(view string) string {
+	if a.dialog == nil {
+		return view
+	}
+	// Border and padding take 6 columns
+	width := max(minContent, min(maxDialogWidth, a.layout.Width-4)-6)
+	box := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205")).
+		Padding(1, 2).
+		Render(a.dialog.View(width))
+	x := max(0, (a.layout.Width-lipgloss.Width(box))/2)
+	y := max(0, (lipgloss.Height(view)-lipgloss.Height(box))/2)
+	return overlay(view, box, x, y)
+}
+
+// dialogHint is the key help line at the bottom of a dialog
+func dialogHint(text string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(text)
+}
+
+// confirmDialog asks a yes/no question. No is preselected, so an Enter
+// typed ahead does not run the action.
+type confirmDialog struct {
+	question string
+	yes      bool
+	onYes    func() tea.Cmd
+}
+
+func newConfirmDialog(question string, onYes func() tea.Cmd) *confirmDialog {
+	return &confirmDialog{question: question, onYes: onYes}
+}
+
+func (d *confirmDialog) Update(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "left", "right", "h", "l", "tab", "shift+tab":
+		d.yes = !d.yes
+	case "y":
+		return d.onYes(), true
+	case "n", "esc":
+		return nil, true
+	case "enter":
+		if d.yes {
+			return d.onYes(), true
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+func (d *confirmDialog) View(width int) string {
+	yes, no := InactiveTabStyle.Render(i18n.T("Yes")), ActiveTabStyle.Render(i18n.T("No"))
+	if d.yes {
+		yes, no = ActiveTabStyle.Render(i18n.T("Yes")), InactiveTabStyle.Render(i18n.T("No"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Width(width).Render(d.question),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Left, yes, no),
+		"",
+		dialogHint(ansi.Truncate(i18n.T("y/n • ←/→: choose • Enter: confirm • Esc: cancel"), width, "…")),
+	)
+}
+
+// inputDialog asks for a line of text. validate rejects a value with the
+// reason shown in the dialog, which then stays open.
+type inputDialog struct {
+	prompt   string
+	value    []rune
+	err      error
+	validate func(string) error
+	onSubmit func(string) tea.Cmd
+}
+
+func newInputDialog(prompt, value string, validate func(string) error, onSubmit func(string) tea.Cmd) *inputDialog {
+	return &inputDialog{prompt: prompt, value: []rune(value), validate: validate, onSubmit: onSubmit}
+}
+
+func (d *inputDialog) Update(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		return nil, true
+	case tea.KeyEnter:
+		value := strings.TrimSpace(string(d.value))
+		if d.validate != nil {
+			if d.err = d.validate(value); d.err != nil {
+				return nil, false
+			}
+		}
+		return d.onSubmit(value), true
+	case tea.KeyBackspace:
+		if len(d.value) > 0 {
+			d.value = d.value[:len(d.value)-1]
+		}
+	case tea.KeyCtrlU:
+		d.value = nil
+	case tea.KeyRunes, tea.KeySpace:
+		d.value = append(d.value, msg.Runes...)
+	}
+	d.err = nil
+	return nil, false
+}
+
+func (d *inputDialog) View(width int) string {
+	// Long values scroll, the end being where the cursor is
+	field := ansi.TruncateLeft(string(d.value)+"█", max(0, ansi.StringWidth(string(d.value))+1-width), "")
+	lines := []string{
+		lipgloss.NewStyle().Width(width).Render(d.prompt),
+		"",
+		ValueStyle.Render(field),
+	}
+	if d.err != nil {
+		lines = append(lines, ErrorStyle.Width(width).Render(d.err.Error()))
+	}
+	lines = append(lines, "", dialogHint(ansi.Truncate(i18n.T("Enter: confirm • Ctrl+U: clear • Esc: cancel"), width, "…")))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// pickerDialog picks one of a list of options. 1-9 pick directly.
+type pickerDialog struct {
+	title    string
+	options  []string
+	selected int
+	onPick   func(index int) tea.Cmd
+}
+
+func newPickerDialog(title string, options []string, selected int, onPick func(index int) tea.Cmd) *pickerDialog {
+	return &pickerDialog{title: title, options: options, selected: max(0, min(selected, len(options)-1)), onPick: onPick}
+}
+
+func (d *pickerDialog) Update(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch key := msg.String(); key {
+	case "up", "k", "shift+tab":
+		d.selected = (d.selected + len(d.options) - 1) % len(d.options)
+	case "down", "j", "tab":
+		d.selected = (d.selected + 1) % len(d.options)
+	case "home":
+		d.selected = 0
+	case "end":
+		d.selected = len(d.options) - 1
+	case "enter":
+		return d.onPick(d.selected), true
+	case "esc":
+		return nil, true
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if index := int(key[0] - '1'); index < len(d.options) {
+				return d.onPick(index), true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (d *pickerDialog) View(width int) string {
+	lines := []string{lipgloss.NewStyle().Width(width).Render(d.title), ""}
+	for i, option := range d.options {
+		line := ansi.Truncate(fmt.Sprintf("%d  %s", i+1, option), width-2, "…")
+		if i == d.selected {
+			lines = append(lines, SelectedRowStyle.Render("› "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "", dialogHint(ansi.Truncate(i18n.T("↑/↓: choose • 1-9/Enter: pick • Esc: cancel"), width, "…")))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// dataDomain is data that is expensive to collect (container runtimes,
+// journals, every process' oom_score) and refreshes on its own schedule,
+// only while a tab shows it
+type dataDomain struct {
+	name  string
+	shown func(a *App) bool
+	// collect runs off the UI goroutine and returns what to store
+	collect func(c *collector.StatsCollector, processes models.ProcessList) func(a *App)
+}
+
+var dataDomains = []dataDomain{
+	{
+		name:  config.DomainPods,
+		shown: func(a *App) bool { return a.hasTab("Pods") },
+		collect: func(c *collector.StatsCollector, _ models.ProcessList) func(a *App) {
+			pods := c.GetPodStats()
+			return func(a *App) { a.pods = pods }
+		},
+	},
+	{
+		name:  config.DomainVMs,
+		shown: func(a *App) bool { return a.hasTab("VMs") },
+		collect: func(c *collector.StatsCollector, _ models.ProcessList) func(a *App) {
+			vms := c.GetVMStats()
+			return func(a *App) { a.vms = vms }
+		},
+	},
+	{
+		name:  config.DomainContainers,
+		shown: func(a *App) bool { return a.currentTab() == "Containers" },
+		collect: func(c *collector.StatsCollector, _ models.ProcessList) func(a *App) {
+			containers, err := c.GetContainers()
+			return func(a *App) { a.containers, a.containersErr = containers, err }
+		},
+	},
+	{
+		name:  config.DomainSessions,
+		shown: func(a *App) bool { return a.currentTab() == "Users" },
+		collect: func(c *collector.StatsCollector, processes models.ProcessList) func(a *App) {
+			desktop := c.GetDesktopStats(processes)
+			return func(a *App) { a.desktop = desktop }
+		},
+	},
+	{
+		name:  config.DomainPressure,
+		shown: func(a *App) bool { return a.currentTab() == "Swap" },
+		collect: func(c *collector.StatsCollector, processes models.ProcessList) func(a *App) {
+			pressure := c.GetMemoryPressure(processes)
+			return func(a *App) { a.pressure = pressure }
+		},
+	},
+	{
+		name:  config.DomainSecurity,
+		shown: func(a *App) bool { return a.currentTab() == "Security" },
+		collect: func(c *collector.StatsCollector, processes models.ProcessList) func(a *App) {
+			security := c.GetSecurityStats(processes)
+			return func(a *App) { a.security = security }
+		},
+	},
+	{
+		name:  config.DomainNeighbors,
+		shown: func(a *App) bool { return a.currentTab() == "Network" },
+		collect: func(c *collector.StatsCollector, _ models.ProcessList) func(a *App) {
+			neighbors := c.GetNeighbors()
+			dns := c.GetDNSStats()
+			return func(a *App) { a.neighbors, a.dns = neighbors, dns }
+		},
+	},
+	{
+		name:  config.DomainShm,
+		shown: func(a *App) bool { return a.currentTab() == "Memory" },
+		collect: func(c *collector.StatsCollector, processes models.ProcessList) func(a *App) {
+			shm := c.GetSharedMemory(processes)
+			return func(a *App) { a.shm = shm }
+		},
+	},
+	{
+		name:  config.DomainSlab,
+		shown: func(a *App) bool { return a.currentTab() == "Memory" },
+		collect: func(c *collector.StatsCollector, _ models.ProcessList) func(a *App) {
+			slab := c.GetSlabStats()
+			return func(a *App) { a.slab = slab }
+		},
+	},
+	{
+		name:  config.DomainThermal,
+		shown: func(a *App) bool { return a.currentTab() == "CPU" },
+		collect: func(c *collector.StatsCollector, _ models.ProcessList) func(a *App) {
+			zones := c.GetThermalZones()
+			return func(a *App) { a.thermal = zones }
+		},
+	},
+	{
+		name:  config.DomainAudio,
+		shown: func(a *App) bool { return a.currentTab() == "CPU" },
+		collect: func(c *collector.StatsCollector, processes models.ProcessList) func(a *App) {
+			audio := c.GetAudioStats(processes)
+			return func(a *App) { a.audio = audio }
+		},
+	},
+	{
+		name:  config.DomainBoot,
+		shown: func(a *App) bool { return a.currentTab() == "Boot" },
+		collect: func(c *collector.StatsCollector, _ models.ProcessList) func(a *App) {
+			boot := c.GetBootStats()
+			return func(a *App) { a.boot = boot }
+		},
+	},
+}
+
+// domainMsg delivers a collected data domain to the UI goroutine
+type domainMsg struct {
+	name  string
+	apply func(a *App)
+}
+
+// updateDomains starts collecting every shown data domain whose interval has
+// passed. Each runs in a command of its own, so a slow one holds up neither
+// the main refresh nor the other domains, and is never started twice.
+func (a *App) updateDomains() []tea.Cmd {
+	now := time.Now()
+	processes := a.processes
+	var cmds []tea.Cmd
+	for _, domain := range dataDomains {
+		if !domain.shown(a) || a.domainBusy[domain.name] {
+			continue
+		}
+		// Domains without an interval follow the main refresh
+		if now.Sub(a.domainUpdated[domain.name]) < time.Duration(a.refresh[domain.name]) {
+			continue
+		}
+		a.domainBusy[domain.name] = true
+		a.domainUpdated[domain.name] = now
+
+		collect := domain.collect
+		name := domain.name
+		cmds = append(cmds, func() tea.Msg {
+			return domainMsg{name: name, apply: collect(a.collector, processes)}
+		})
+	}
+	return cmds
+}
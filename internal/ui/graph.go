@@ -0,0 +1,377 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GraphStyle selects the characters graphs are drawn with
+type GraphStyle int
+
+const (
+	// GraphBraille draws with braille dots, 2x4 per cell
+	GraphBraille GraphStyle = iota
+	// GraphBlock draws one bar of eighth blocks per cell, for terminals and
+	// fonts without braille
+	GraphBlock
+)
+
+// DetectGraphStyle picks block graphs on the Linux console and with non
+// UTF-8 locales, where braille characters don't render
+func DetectGraphStyle() GraphStyle {
+	if os.Getenv("TERM") == "linux" {
+		return GraphBlock
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			if strings.Contains(value, "utf-8") || strings.Contains(value, "utf8") {
+				return GraphBraille
+			}
+			return GraphBlock
+		}
+	}
+	return GraphBraille
+}
+
+// brailleDots maps a dot position within a cell, [row][column], to its bit
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// Canvas is a grid of braille cells addressed in dots, (0, 0) being the top
+// left. Each cell holds 2 dots across and 4 down.
+type Canvas struct {
+	width  int // in cells
+	height int
+	cells  []rune
+}
+
+func NewCanvas(width, height int) *Canvas {
+	return &Canvas{
+		width:  width,
+		height: height,
+		cells:  make([]rune, width*height),
+	}
+}
+
+// Size returns the canvas size in dots
+func (c *Canvas) Size() (int, int) {
+	return c.width * 2, c.height * 4
+}
+
+// Set turns a dot on, ignoring dots outside the canvas
+func (c *Canvas) Set(x, y int) {
+	if x < 0 || y < 0 || x >= c.width*2 || y >= c.height*4 {
+		return
+	}
+	c.cells[(y/4)*c.width+x/2] |= brailleDots[y%4][x%2]
+}
+
+// Line draws a straight line between two dots
+func (c *Canvas) Line(x0, y0, x1, y1 int) {
+	dx, dy := x1-x0, y1-y0
+	steps := max(abs(dx), abs(dy))
+	if steps == 0 {
+		c.Set(x0, y0)
+		return
+	}
+	for i := 0; i <= steps; i++ {
+		c.Set(x0+int(math.Round(float64(dx*i)/float64(steps))), y0+int(math.Round(float64(dy*i)/float64(steps))))
+	}
+}
+
+// Rows renders the canvas, one string per row of cells
+func (c *Canvas) Rows() []string {
+	rows := make([]string, c.height)
+	for row := range rows {
+		var line strings.Builder
+		for _, cell := range c.cells[row*c.width : (row+1)*c.width] {
+			if cell == 0 {
+				line.WriteRune(' ')
+			} else {
+				line.WriteRune(0x2800 + cell)
+			}
+		}
+		rows[row] = line.String()
+	}
+	return rows
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// LineGraph plots the newest values at the right edge of a width x height
+// cell area, scaled so that maxValue reaches the top
+func LineGraph(values []float64, maxValue float64, width, height int, style GraphStyle) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	if maxValue <= 0 {
+		maxValue = 1
+	}
+
+	if style == GraphBlock {
+		return blockGraph(values, maxValue, width, height)
+	}
+
+	canvas := NewCanvas(width, height)
+	canvas.Plot(values, maxValue)
+	return canvas.Rows()
+}
+
+// Plot draws values as a line, the newest at the right edge, scaled so that
+// maxValue reaches the top
+func (c *Canvas) Plot(values []float64, maxValue float64) {
+	dotsX, dotsY := c.Size()
+	if len(values) > dotsX {
+		values = values[len(values)-dotsX:]
+	}
+
+	// One dot column per value, joined into a line
+	offset := dotsX - len(values)
+	prevX, prevY := -1, 0
+	for i, value := range values {
+		x := offset + i
+		y := dotsY - 1 - scale(value, maxValue, dotsY-1)
+		if prevX >= 0 {
+			c.Line(prevX, prevY, x, y)
+		} else {
+			c.Set(x, y)
+		}
+		prevX, prevY = x, y
+	}
+}
+
+// OverlayGraph plots two series over the same time span, each scaled to a
+// maximum of its own, so that they can be read against two axes. Where both
+// cross a cell the front series is drawn; with block graphs the back series
+// is a dotted line over the front's bars.
+func OverlayGraph(front, back []float64, frontMax, backMax float64, width, height int, style GraphStyle, frontColor, backColor lipgloss.Color) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	if frontMax <= 0 {
+		frontMax = 1
+	}
+	if backMax <= 0 {
+		backMax = 1
+	}
+	frontStyle := lipgloss.NewStyle().Foreground(frontColor)
+	backStyle := lipgloss.NewStyle().Foreground(backColor)
+
+	var frontRows, backRows [][]rune
+	if style == GraphBlock {
+		for _, row := range blockGraph(front, frontMax, width, height) {
+			frontRows = append(frontRows, []rune(row))
+		}
+		// One dot per column at the row of the value
+		backRows = make([][]rune, height)
+		for row := range backRows {
+			backRows[row] = []rune(strings.Repeat(" ", width))
+		}
+		if len(back) > width {
+			back = back[len(back)-width:]
+		}
+		offset := width - len(back)
+		for i, value := range back {
+			backRows[height-1-scale(value, backMax, height-1)][offset+i] = '•'
+		}
+	} else {
+		frontCanvas, backCanvas := NewCanvas(width, height), NewCanvas(width, height)
+		frontCanvas.Plot(front, frontMax)
+		backCanvas.Plot(back, backMax)
+		for _, row := range frontCanvas.Rows() {
+			frontRows = append(frontRows, []rune(row))
+		}
+		for _, row := range backCanvas.Rows() {
+			backRows = append(backRows, []rune(row))
+		}
+	}
+
+	rows := make([]string, height)
+	for row := range rows {
+		var line strings.Builder
+		for x := range width {
+			switch {
+			case frontRows[row][x] != ' ' && (style != GraphBlock || backRows[row][x] == ' '):
+				line.WriteString(frontStyle.Render(string(frontRows[row][x])))
+			case backRows[row][x] != ' ':
+				line.WriteString(backStyle.Render(string(backRows[row][x])))
+			default:
+				line.WriteRune(' ')
+			}
+		}
+		rows[row] = line.String()
+	}
+	return rows
+}
+
+// axisStyle draws the scales next to and below graphs
+var axisStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// axisLabels are the labels of a scale from 0 at the bottom row to maxValue
+// at the top one, height rows high, and the widest label's width. Graphs
+// of an even height of 4 or more get maxValue/2 at the middle, the top
+// edge of the row below it.
+func axisLabels(maxValue float64, height int, format func(float64) string) ([]string, int) {
+	labels := make([]string, height)
+	if height == 0 {
+		return labels, 0
+	}
+	labels[0] = format(maxValue)
+	if height > 1 {
+		labels[height-1] = format(0)
+	}
+	// Small scales round the middle to the bottom's label
+	if middle := format(maxValue / 2); height >= 4 && height%2 == 0 && middle != labels[height-1] {
+		labels[height/2] = middle
+	}
+	width := 0
+	for _, label := range labels {
+		width = max(width, lipgloss.Width(label))
+	}
+	return labels, width
+}
+
+// withYAxis puts the labels, right-aligned to width, left of the graph rows,
+// with a tick at each labelled row
+func withYAxis(rows, labels []string, width int) []string {
+	axis := make([]string, len(rows))
+	for i, row := range rows {
+		tick := "│"
+		if labels[i] != "" {
+			tick = "┤"
+		}
+		axis[i] = axisStyle.Render(fmt.Sprintf("%*s %s", width, labels[i], tick)) + row
+	}
+	return axis
+}
+
+// timeAxis spans the cells of a graph width wide below it, indent columns
+// in: how long ago its left edge is with samples taken interval apart, and
+// "now" at the right edge
+func timeAxis(indent, width, samples int, interval time.Duration) string {
+	ago := "-" + formatDuration(time.Duration(samples)*interval)
+	gap := max(1, width-lipgloss.Width(ago)-len("now"))
+	return axisStyle.Render(strings.Repeat(" ", indent) + ago + strings.Repeat(" ", gap) + "now")
+}
+
+// blockLevels are the eighth blocks from empty to full
+var blockLevels = []rune(" ▁▂▃▄▅▆▇█")
+
+func blockGraph(values []float64, maxValue float64, width, height int) []string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	offset := width - len(values)
+
+	rows := make([]string, height)
+	for row := range rows {
+		// Eighths below this row
+		floor := (height - 1 - row) * 8
+		var line strings.Builder
+		line.WriteString(strings.Repeat(" ", offset))
+		for _, value := range values {
+			fill := scale(value, maxValue, height*8) - floor
+			line.WriteRune(blockLevels[max(0, min(fill, 8))])
+		}
+		rows[row] = line.String()
+	}
+	return rows
+}
+
+// scale maps value within [0, maxValue] to [0, steps]
+func scale(value, maxValue float64, steps int) int {
+	if value <= 0 {
+		return 0
+	}
+	if value >= maxValue {
+		return steps
+	}
+	return int(math.Round(value / maxValue * float64(steps)))
+}
+
+// History holds the latest samples of a metric for graphs
+type History struct {
+	values []float64
+	size   int
+	// Flags the latest sample when it is far from the norm, if set
+	baseline  *Baseline
+	anomaly   Anomaly
+	anomalous bool
+}
+
+func NewHistory(size int) *History {
+	return &History{size: size}
+}
+
+// WithBaseline makes the history follow the norm of its samples and flag
+// the ones at least floor away from it, see Baseline
+func (h *History) WithBaseline(floor float64) *History {
+	h.baseline = NewBaseline(floor)
+	return h
+}
+
+// Add appends a sample, dropping the oldest beyond the history size
+func (h *History) Add(value float64) {
+	h.values = append(h.values, value)
+	if len(h.values) > h.size {
+		h.values = h.values[len(h.values)-h.size:]
+	}
+	if h.baseline != nil {
+		h.anomaly, h.anomalous = h.baseline.Check(value)
+		h.baseline.Add(value)
+	}
+}
+
+// Anomaly returns the latest sample if it is far from the norm
+func (h *History) Anomaly() (Anomaly, bool) {
+	return h.anomaly, h.anomalous
+}
+
+// Values returns the samples, oldest first
+func (h *History) Values() []float64 {
+	return h.values
+}
+
+// Min returns the smallest of the last n samples, 0 without any
+func (h *History) Min(n int) float64 {
+	values := h.values
+	if len(values) > n {
+		values = values[len(values)-n:]
+	}
+	if len(values) == 0 {
+		return 0
+	}
+	low := values[0]
+	for _, value := range values[1:] {
+		low = math.Min(low, value)
+	}
+	return low
+}
+
+// Max returns the largest of the last n samples
+func (h *History) Max(n int) float64 {
+	values := h.values
+	if len(values) > n {
+		values = values[len(values)-n:]
+	}
+	var peak float64
+	for _, value := range values {
+		peak = math.Max(peak, value)
+	}
+	return peak
+}
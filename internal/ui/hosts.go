@@ -0,0 +1,381 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/agent"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/mdns"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// hostFetchTimeout bounds the snapshot request to one agent, so a host that
+// is down does not hold up the others for long
+const hostFetchTimeout = 3 * time.Second
+
+// hostTopProcesses is how many processes the detail of a host lists
+const hostTopProcesses = 10
+
+// discoverTimeout is how long agents have to answer an mDNS browse
+const discoverTimeout = 2 * time.Second
+
+// hostStatus is what the last poll of an agent returned. A failed poll keeps
+// the snapshot of the last one that worked.
+type hostStatus struct {
+	snapshot schema.Snapshot
+	err      error
+}
+
+// hostsMsg delivers a poll of every agent, by URL
+type hostsMsg map[string]hostStatus
+
+// hostsDue reports whether the agents should be polled again
+func (a *App) hostsDue() bool {
+	return !a.hostsPolling && time.Since(a.lastHostPoll) >= time.Duration(a.refresh[config.DomainHosts])
+}
+
+// pollHosts fetches the snapshot of every agent at once, off the UI goroutine
+func (a *App) pollHosts() tea.Cmd {
+	a.hostsPolling = true
+	a.lastHostPoll = time.Now()
+
+	hosts := a.hosts
+	return func() tea.Msg {
+		var mutex sync.Mutex
+		var wg sync.WaitGroup
+		statuses := make(hostsMsg, len(hosts))
+		for _, host := range hosts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), hostFetchTimeout)
+				defer cancel()
+				snapshot, err := agent.FetchSnapshot(ctx, host)
+				mutex.Lock()
+				statuses[host.URL] = hostStatus{snapshot: snapshot, err: err}
+				mutex.Unlock()
+			}()
+		}
+		wg.Wait()
+		return statuses
+	}
+}
+
+// applyHosts stores a poll, keeping the last snapshot of agents that failed
+func (a *App) applyHosts(msg hostsMsg) {
+	a.hostsPolling = false
+	for url, status := range msg {
+		if status.err != nil {
+			status.snapshot = a.hostStatus[url].snapshot
+		}
+		a.hostStatus[url] = status
+	}
+}
+
+// hostsDiscoveredMsg delivers the agents that answered a browse
+type hostsDiscoveredMsg struct {
+	services []mdns.Service
+	err      error
+}
+
+// discoverHosts looks for agents announcing themselves on the LAN
+func (a *App) discoverHosts() tea.Cmd {
+	if a.discovering {
+		a.toast(toastInfo, i18n.T("Already looking for agents"))
+		return nil
+	}
+	a.discovering = true
+	a.toast(toastInfo, i18n.T("Looking for agents on the LAN…"))
+	return func() tea.Msg {
+		services, err := mdns.Browse(context.Background(), discoverTimeout)
+		return hostsDiscoveredMsg{services: services, err: err}
+	}
+}
+
+// pickDiscoveredHost offers the discovered agents that are not hosts yet;
+// the one picked is added and saved to the config file
+func (a *App) pickDiscoveredHost(msg hostsDiscoveredMsg) {
+	a.discovering = false
+	if msg.err != nil {
+		slog.Warn("mDNS browse failed", "err", msg.err)
+		a.toast(toastError, i18n.Sprintf("✗ Looking for agents failed: %v", msg.err))
+		return
+	}
+	var found []config.Host
+	for _, service := range msg.services {
+		url := service.URL()
+		if !slices.ContainsFunc(a.hosts, func(host config.Host) bool { return host.URL == url }) {
+			found = append(found, config.Host{Name: service.Name, URL: url})
+		}
+	}
+	if len(found) == 0 {
+		if len(msg.services) > 0 {
+			a.toast(toastInfo, i18n.Sprintf("No new agents, the %d found are hosts already", len(msg.services)))
+		} else {
+			a.toast(toastWarning, i18n.T("No agents found; start them with croptop serve -announce"))
+		}
+		return
+	}
+
+	options := make([]string, len(found))
+	for i, host := range found {
+		options[i] = fmt.Sprintf("%-16s %s", host.Name, host.URL)
+	}
+	a.openDialog(newPickerDialog(i18n.T("Add a host:"), options, 0, func(index int) tea.Cmd {
+		hosts := append(slices.Clone(a.hosts), found[index])
+		written, err := config.SaveHosts(a.configPath, hosts)
+		if err != nil {
+			a.toast(toastError, i18n.Sprintf("Hosts not saved: %v", err))
+			return nil
+		}
+		a.hosts = hosts
+		a.configWritten = written
+		a.toast(toastSuccess, i18n.Sprintf("✓ Added %s", found[index].Name))
+		// Polled right away rather than at the next interval
+		if a.hostsPolling {
+			return nil
+		}
+		return a.pollHosts()
+	}))
+}
+
+// fullestDisk returns the disk of a snapshot closest to full
+func fullestDisk(system schema.System) (schema.Disk, bool) {
+	if len(system.Disks) == 0 {
+		return schema.Disk{}, false
+	}
+	return slices.MaxFunc(system.Disks, func(a, b schema.Disk) int {
+		return int(a.UsagePercent*100) - int(b.UsagePercent*100)
+	}), true
+}
+
+// alertSummary counts the alerts firing on an agent, styled by the worst
+func alertSummary(alerts []schema.Alert) (string, lipgloss.Style) {
+	critical := 0
+	for _, alert := range alerts {
+		if alert.Severity == schema.SeverityCritical {
+			critical++
+		}
+	}
+	switch {
+	case critical > 0:
+		return i18n.Sprintf("%d firing, %d critical", len(alerts), critical), ErrorStyle
+	case len(alerts) > 0:
+		return i18n.Sprintf("%d firing", len(alerts)), WarningStyle
+	}
+	return "-", ValueStyle
+}
+
+// hostsTab summarizes the remote agents one line each; Enter shows the
+// snapshot of the selected one
+type hostsTab struct {
+	app      *App
+	selected int
+	detail   bool
+	scroll   scrollView
+}
+
+func (t *hostsTab) Init() tea.Cmd {
+	if t.app.hostsDue() {
+		return t.app.pollHosts()
+	}
+	return nil
+}
+
+func (t *hostsTab) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	if t.detail {
+		switch key.String() {
+		case "esc", "enter", "backspace":
+			t.detail = false
+		default:
+			t.scroll.Update(key)
+		}
+		return nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		t.selected--
+	case "down", "j":
+		t.selected++
+	case "home":
+		t.selected = 0
+	case "end":
+		t.selected = len(t.app.hosts) - 1
+	case "enter":
+		if len(t.app.hosts) > 0 {
+			t.detail = true
+			t.scroll = scrollView{}
+		}
+	case "D":
+		return t.app.discoverHosts()
+	}
+	t.selected = max(0, min(t.selected, len(t.app.hosts)-1))
+	return nil
+}
+
+func (t *hostsTab) View(width, height int) string {
+	a := t.app
+	t.selected = max(0, min(t.selected, len(a.hosts)-1))
+	if t.detail && len(a.hosts) > 0 {
+		return t.scroll.View(a.renderHostDetail(a.hosts[t.selected]), height)
+	}
+	return a.renderHosts(t.selected)
+}
+
+// renderHosts lists the agents with their CPU, memory, fullest disk and
+// firing alerts
+func (a *App) renderHosts(selected int) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).PaddingLeft(1).PaddingRight(1)
+	content := []string{
+		sectionHeader(i18n.T("Hosts")),
+		"",
+		headerStyle.Render(fmt.Sprintf("%-16s %-8s %6s %6s %-20s %6s %10s  %s",
+			"HOST", "STATUS", "CPU%", "MEM%", "FULLEST DISK", "LOAD", "UPTIME", "ALERTS")),
+	}
+
+	for i, host := range a.hosts {
+		status, polled := a.hostStatus[host.URL]
+		system := status.snapshot.System
+		row := fmt.Sprintf("%-16s ", truncateString(host.Name, 16))
+		style := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1).Foreground(lipgloss.Color("252"))
+		switch {
+		case !polled:
+			row += fmt.Sprintf("%-8s", "polling")
+			style = style.Foreground(lipgloss.Color("241"))
+		case status.err != nil:
+			row += fmt.Sprintf("%-8s %s", "down", status.err)
+			style = style.Foreground(ErrorStyle.GetForeground())
+		default:
+			disk := "-"
+			if fullest, ok := fullestDisk(system); ok {
+				disk = fmt.Sprintf("%3.0f%% %s", fullest.UsagePercent, fullest.Mountpoint)
+			}
+			load := 0.0
+			if len(system.CPU.LoadAverage) > 0 {
+				load = system.CPU.LoadAverage[0]
+			}
+			alerts, alertStyle := alertSummary(status.snapshot.Alerts)
+			row += fmt.Sprintf("%-8s %5.1f%% %5.1f%% %-20s %6.2f %10s  %s",
+				"up", system.CPU.UsagePercent, system.Memory.UsagePercent,
+				truncateString(disk, 20), load,
+				formatDuration(time.Duration(system.UptimeSeconds*float64(time.Second))), alerts)
+			if len(status.snapshot.Alerts) > 0 {
+				style = style.Foreground(alertStyle.GetForeground())
+			}
+		}
+
+		if i == selected {
+			style = style.Background(lipgloss.Color("240")).Bold(true)
+		}
+		content = append(content, style.Render(truncateString(row, max(10, a.layout.Content-2))))
+	}
+
+	if len(a.hosts) == 0 {
+		content = append(content, " "+i18n.T("No hosts yet. Press D to look for agents on the LAN, or list them under \"hosts\" in the config file."))
+	}
+
+	content = append(content, "", lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+		" "+i18n.Sprintf("Polled every %s • ↑↓ j/k: select • Enter: details • D: discover agents", formatDuration(time.Duration(a.refresh[config.DomainHosts])))))
+	return strings.Join(content, "\n")
+}
+
+// renderHostDetail shows the last snapshot of an agent: its system stats,
+// disks, firing alerts and busiest processes
+func (a *App) renderHostDetail(host config.Host) string {
+	status, polled := a.hostStatus[host.URL]
+	snapshot := status.snapshot
+	system := snapshot.System
+
+	title := HeaderStyle.Render(i18n.Sprintf("Host %s · %s", host.Name, host.URL))
+	if status.err != nil {
+		title += " " + ErrorStyle.Render(i18n.Sprintf("unreachable: %v", status.err))
+	}
+	lines := []string{title, ""}
+	if !polled || snapshot.Time.IsZero() {
+		lines = append(lines, i18n.T("No snapshot received from this agent yet"))
+		return strings.Join(lines, "\n")
+	}
+
+	cpu := i18n.Sprintf("%.1f%% of %d CPUs, %.0f MHz", system.CPU.UsagePercent, len(system.CPU.CoreUsagePercent), system.CPU.FrequencyMHz)
+	if temp := system.CPU.TemperatureCelsius; temp != nil {
+		cpu += fmt.Sprintf(", %.0f°C", *temp)
+	}
+	load := make([]string, len(system.CPU.LoadAverage))
+	for i, value := range system.CPU.LoadAverage {
+		load[i] = fmt.Sprintf("%.2f", value)
+	}
+	lines = append(lines,
+		a.detailLine(i18n.T("Hostname:"), snapshot.Host),
+		a.detailLine(i18n.T("Collected:"), i18n.Sprintf("%s, %s ago", snapshot.Time.Local().Format("15:04:05"), formatDuration(time.Since(snapshot.Time)))),
+		a.detailLine(i18n.T("Uptime:"), formatDuration(time.Duration(system.UptimeSeconds*float64(time.Second)))),
+		a.detailLine(i18n.T("CPU:"), cpu),
+		a.detailLine(i18n.T("Model:"), system.CPU.Model),
+		a.detailLine(i18n.T("Load average:"), strings.Join(load, " ")),
+		a.detailLine(i18n.T("Memory:"), i18n.Sprintf("%.1f%%, %s of %s used, %s available", system.Memory.UsagePercent,
+			formatBytes(float64(system.Memory.UsedBytes)), formatBytes(float64(system.Memory.TotalBytes)), formatBytes(float64(system.Memory.AvailableBytes)))),
+		a.detailLine(i18n.T("Swap:"), i18n.Sprintf("%s of %s used", formatBytes(float64(system.Memory.SwapUsedBytes)), formatBytes(float64(system.Memory.SwapTotalBytes)))),
+		a.detailLine(i18n.T("Network:"), i18n.Sprintf("↓ %s/s ↑ %s/s", formatBytes(system.Network.ReceiveBytesPerSecond), formatBytes(system.Network.TransmitBytesPerSecond))),
+	)
+	if battery := system.Battery; battery != nil {
+		lines = append(lines, a.detailLine(i18n.T("Battery:"), fmt.Sprintf("%d%%, %s", battery.LevelPercent, battery.Status)))
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	lines = append(lines, "", sectionHeader(i18n.T("Disks")),
+		headerStyle.Render(fmt.Sprintf(" %-24s %7s %10s %10s", "MOUNTPOINT", "USED%", "USED", "SIZE")))
+	for _, disk := range system.Disks {
+		row := fmt.Sprintf(" %-24s %6.1f%% %10s %10s", truncateString(disk.Mountpoint, 24), disk.UsagePercent,
+			formatBytes(float64(disk.UsedBytes)), formatBytes(float64(disk.TotalBytes)))
+		switch {
+		case disk.Stalled:
+			row = ErrorStyle.Render(row + " " + i18n.T("not responding"))
+		case disk.UsagePercent >= 90:
+			row = WarningStyle.Render(row)
+		}
+		lines = append(lines, row)
+	}
+
+	lines = append(lines, "", sectionHeader(i18n.T("Alerts")))
+	if len(snapshot.Alerts) == 0 {
+		lines = append(lines, " "+i18n.T("None firing"))
+	}
+	for _, alert := range snapshot.Alerts {
+		style := WarningStyle
+		if alert.Severity == schema.SeverityCritical {
+			style = ErrorStyle
+		}
+		lines = append(lines, style.Render(" "+i18n.Sprintf("%s, now %.1f, for %s", alert.Rule, alert.Value, formatDuration(snapshot.Time.Sub(alert.Since)))))
+	}
+
+	if snapshot.Processes != nil {
+		processes := slices.Clone(snapshot.Processes.List)
+		slices.SortFunc(processes, func(a, b schema.Process) int {
+			return int(b.CPUPercent*100) - int(a.CPUPercent*100)
+		})
+		lines = append(lines, "", sectionHeader(i18n.Sprintf("Top Processes (%d in total)", snapshot.Processes.Total)),
+			headerStyle.Render(fmt.Sprintf(" %-8s %-20s %-12s %6s %6s %10s", "PID", "NAME", "USER", "CPU%", "MEM%", "RSS")))
+		for _, proc := range processes[:min(hostTopProcesses, len(processes))] {
+			lines = append(lines, fmt.Sprintf(" %-8d %-20s %-12s %5.1f%% %5.1f%% %10s", proc.PID,
+				truncateString(proc.Name, 20), truncateString(proc.User, 12), proc.CPUPercent, proc.MemoryPercent,
+				formatBytes(float64(proc.RSSBytes))))
+		}
+	}
+
+	lines = append(lines, "", lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+		" "+i18n.T("Esc/Enter: back • ↑↓ j/k: scroll")))
+	return strings.Join(lines, "\n")
+}
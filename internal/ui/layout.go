@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Layout is the geometry every view sizes itself from. App recomputes it on
+// each tea.WindowSizeMsg, so gauges, graphs and tables agree on the width
+// instead of each view working out its own margins.
+type Layout struct {
+	// Width and Height of the terminal
+	Width, Height int
+	// Content is the usable width inside a content box
+	Content int
+	// ContentHeight is the rows inside the content box left to the active
+	// tab
+	ContentHeight int
+}
+
+const (
+	// boxFrame is what BaseStyle's border and padding take of the width
+	boxFrame = 8
+	// boxFrameHeight is what BaseStyle's border and padding take of the
+	// height
+	boxFrameHeight = 4
+	// chromeHeight is what the title, tabs, alert bar and help take of the
+	// height, with their margins
+	chromeHeight = 8
+	// minContent keeps views drawable in tiny terminals
+	minContent = 10
+	// narrowWidth is the terminal width below which views stack or drop
+	// secondary columns
+	narrowWidth = 60
+)
+
+func newLayout(width, height int) Layout {
+	return Layout{
+		Width:         width,
+		Height:        height,
+		Content:       max(minContent, width-boxFrame),
+		ContentHeight: max(1, height-chromeHeight-boxFrameHeight),
+	}
+}
+
+// Narrow reports whether the terminal is too narrow for side by side widgets
+// and wide tables
+func (l Layout) Narrow() bool {
+	return l.Width < narrowWidth
+}
+
+// Box renders a view's content in BaseStyle at the full content width. Lines
+// that would not fit are cut, a wrapped table row would shift every row
+// below it.
+func (l Layout) Box(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, l.Content, "…")
+	}
+	return BaseStyle.Width(l.Content + boxFrame/2).Render(strings.Join(lines, "\n"))
+}
+
+// overlay draws box over background with its top left corner at column x
+// of row y, for toasts and dialogs. Rows of box below the background are
+// dropped.
+func overlay(background, box string, x, y int) string {
+	lines := strings.Split(background, "\n")
+	for i, line := range strings.Split(box, "\n") {
+		row := y + i
+		if row < 0 || row >= len(lines) {
+			continue
+		}
+		left := ansi.Truncate(lines[row], x, "")
+		left += strings.Repeat(" ", max(0, x-ansi.StringWidth(left)))
+		right := ansi.TruncateLeft(lines[row], x+ansi.StringWidth(line), "")
+		lines[row] = left + line + right
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// namespaceGroup is the processes sharing one set of namespaces
+type namespaceGroup struct {
+	namespaces models.Namespaces
+	processes  []models.Process
+	cpu, mem   float64
+}
+
+// ownNamespaces names the namespaces of ns that are not the host's.
+// Unreadable ones count as the host's, they tell nothing.
+func ownNamespaces(ns, host models.Namespaces) []string {
+	var own []string
+	for _, n := range []struct {
+		kind      string
+		id, hosts uint64
+	}{{"pid", ns.PID, host.PID}, {"net", ns.Net, host.Net}, {"mnt", ns.Mnt, host.Mnt}, {"user", ns.User, host.User}} {
+		if n.id != 0 && n.id != n.hosts {
+			own = append(own, n.kind)
+		}
+	}
+	return own
+}
+
+// namespaceKind says what a set of own namespaces makes of its processes: a
+// container has its own PID and mount namespace, a sandbox such as a
+// browser's or a systemd service's only some of them
+func namespaceKind(own []string) string {
+	switch {
+	case len(own) == 0:
+		return "Host"
+	case slices.Contains(own, "pid") && slices.Contains(own, "mnt"):
+		return "Container"
+	default:
+		return "Sandbox"
+	}
+}
+
+// renderNamespaces groups the processes by their namespaces in place of the
+// process table: containers and sandboxes first, the biggest first, then
+// the host and the processes whose namespaces could not be read
+func (a *App) renderNamespaces() string {
+	host := collector.HostNamespaces()
+	byNamespaces := make(map[models.Namespaces]*namespaceGroup)
+	var groups []*namespaceGroup
+	for _, proc := range a.processes.Processes {
+		group, ok := byNamespaces[proc.Namespaces]
+		if !ok {
+			group = &namespaceGroup{namespaces: proc.Namespaces}
+			byNamespaces[proc.Namespaces] = group
+			groups = append(groups, group)
+		}
+		group.processes = append(group.processes, proc)
+		group.cpu += proc.CPUPercent
+		group.mem += proc.MemPercent
+	}
+	rank := func(group *namespaceGroup) int {
+		switch {
+		case group.namespaces == models.Namespaces{}:
+			return 2
+		case len(ownNamespaces(group.namespaces, host)) == 0:
+			return 1
+		default:
+			return 0
+		}
+	}
+	slices.SortStableFunc(groups, func(x, y *namespaceGroup) int {
+		return cmp.Or(cmp.Compare(rank(x), rank(y)), cmp.Compare(len(y.processes), len(x.processes)))
+	})
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).PaddingLeft(1).PaddingRight(1)
+	rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+	hint := i18n.T("A container has its own PID and mount namespace, a sandbox only some namespaces of its own • N/Esc: back to the table")
+	content := []string{HeaderStyle.Render(i18n.T("Processes by Namespace")), ""}
+	if len(groups) == 1 && groups[0].namespaces == (models.Namespaces{}) {
+		content = append(content, hintStyle.Render(" "+hint), "", " "+i18n.T("Reading namespaces…"))
+		return strings.Join(content, "\n")
+	}
+	containers := 0
+	for _, group := range groups {
+		if namespaceKind(ownNamespaces(group.namespaces, host)) == "Container" {
+			containers++
+		}
+	}
+	content = append(content, " "+i18n.Sprintf("%d namespace sets, %d of them containers", len(groups), containers),
+		hintStyle.Render(" "+hint))
+
+	for _, group := range groups {
+		own := ownNamespaces(group.namespaces, host)
+		title := i18n.Sprintf("%s · %d processes · CPU %s · MEM %s", i18n.T(namespaceKind(own)), len(group.processes),
+			fmt.Sprintf("%.1f%%", a.shownCPU(group.cpu)), fmt.Sprintf("%.1f%%", group.mem))
+		ids := fmt.Sprintf("pid:[%d] net:[%d] mnt:[%d] user:[%d]",
+			group.namespaces.PID, group.namespaces.Net, group.namespaces.Mnt, group.namespaces.User)
+		switch {
+		case group.namespaces == models.Namespaces{}:
+			title = i18n.Sprintf("Not readable · %d processes", len(group.processes))
+			ids = i18n.T("Reading the namespaces of other users' processes needs root")
+		case len(own) > 0:
+			ids += " · " + i18n.Sprintf("own: %s", strings.Join(own, ", "))
+		}
+		content = append(content, "", sectionHeader(title), " "+ids,
+			columnHeader(headerStyle.Render(fmt.Sprintf("%-8s %-20s %8s %8s %s", "PID", "NAME", "CPU%", "MEM%", "COMMAND"))))
+		for _, proc := range group.processes {
+			row := fmt.Sprintf("%-8d %-20s %7.1f%% %7.1f%% %s", proc.PID, truncateString(proc.Name, 20),
+				a.shownCPU(proc.CPUPercent), proc.MemPercent,
+				truncateString(strings.Join(strings.Fields(proc.Command), " "), max(10, a.layout.Content-52)))
+			content = append(content, rowStyle.Render(row))
+		}
+	}
+	return strings.Join(content, "\n")
+}
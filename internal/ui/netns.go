@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// netnsMsg delivers what was read of the network namespace the Network tab
+// shows
+type netnsMsg struct {
+	id      uint64
+	network models.NamespaceNetwork
+	err     error
+}
+
+// fetchNetNamespace reads the interfaces and sockets of the shown network
+// namespace, off the UI goroutine; nil while the tab shows croptop's own
+func (a *App) fetchNetNamespace() tea.Cmd {
+	ns := a.netns
+	if ns.ID == 0 || a.netnsBusy {
+		return nil
+	}
+	a.netnsBusy = true
+	return func() tea.Msg {
+		network, err := a.collector.GetNamespaceNetwork(ns)
+		return netnsMsg{id: ns.ID, network: network, err: err}
+	}
+}
+
+// pickNetNamespace lets the user switch the Network tab to another network
+// namespace, such as a container's, or back to croptop's own
+func (a *App) pickNetNamespace() tea.Cmd {
+	namespaces := a.collector.NetNamespaces(a.processes.Processes)
+	if len(namespaces) == 0 && a.netns.ID == 0 {
+		a.toast(toastInfo, i18n.T("No other network namespaces found; other users' processes need root"))
+		return nil
+	}
+	options := []string{i18n.T("Host (croptop's own namespace)")}
+	selected := 0
+	for i, ns := range namespaces {
+		options = append(options, i18n.Sprintf("net:[%s] · PID %s %s · %d processes",
+			strconv.FormatUint(ns.ID, 10), strconv.Itoa(ns.PID), ns.Process, ns.Processes))
+		if ns.ID == a.netns.ID {
+			selected = i + 1
+		}
+	}
+	a.openDialog(newPickerDialog(i18n.T("Show the network namespace of"), options, selected, func(i int) tea.Cmd {
+		a.netns, a.netnsNetwork, a.netnsErr = models.NetNamespace{}, nil, nil
+		if i > 0 {
+			a.netns = namespaces[i-1]
+		}
+		return a.fetchNetNamespace()
+	}))
+	return nil
+}
+
+// renderNetNamespace shows the interfaces, addresses and sockets of the
+// picked network namespace in place of the Network tab's host view
+func (a *App) renderNetNamespace() string {
+	ns := a.netns
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	content := []string{
+		sectionHeader(i18n.Sprintf("Network Namespace net:[%s]", strconv.FormatUint(ns.ID, 10))),
+		fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Seen through:")),
+			ValueStyle.Render(i18n.Sprintf("PID %s %s, %d processes in the namespace", strconv.Itoa(ns.PID), ns.Process, ns.Processes))),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(i18n.T("n: pick another namespace or the host's")),
+		"",
+	}
+	if a.netnsErr != nil {
+		return strings.Join(append(content, ErrorStyle.Render(a.netnsErr.Error())), "\n")
+	}
+	network := a.netnsNetwork
+	if network == nil {
+		return strings.Join(append(content, i18n.T("Reading the namespace…")), "\n")
+	}
+
+	addresses := "-"
+	if len(network.Addresses) > 0 {
+		addresses = strings.Join(network.Addresses, ", ")
+	}
+	content = append(content,
+		fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Addresses:")), ValueStyle.Render(addresses)),
+		"",
+		sectionHeader(i18n.T("Interfaces")),
+		columnHeader(headerStyle.Render(fmt.Sprintf("%-16s %12s %12s %12s %12s", "INTERFACE", "RX", "TX", "RX PACKETS", "TX PACKETS"))),
+	)
+	for _, iface := range network.Interfaces {
+		content = append(content, fmt.Sprintf("%-16s %12s %12s %12d %12d", truncateString(iface.Name, 16),
+			formatBytes(float64(iface.RxBytes)), formatBytes(float64(iface.TxBytes)), iface.RxPackets, iface.TxPackets))
+	}
+
+	content = append(content, "", sectionHeader(i18n.Sprintf("Connections (%d)", len(network.Connections))))
+	if len(network.Connections) == 0 {
+		return strings.Join(append(content, i18n.T("No sockets")), "\n")
+	}
+	content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%-5s %-45s %-45s %s", "PROTO", "LOCAL", "REMOTE", "STATE"))))
+	for _, connection := range network.Connections {
+		row := fmt.Sprintf("%-5s %-45s %-45s %s", connection.Protocol, truncateString(connection.Local, 45),
+			truncateString(connection.Remote, 45), connection.State)
+		if connection.State == "LISTEN" || connection.State == "UNCONN" {
+			row = SuccessStyle.Render(row)
+		}
+		content = append(content, row)
+	}
+	return strings.Join(content, "\n")
+}
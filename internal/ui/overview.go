@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/i18n"
+)
+
+// overviewGap separates the widgets of a row
+const overviewGap = 4
+
+// Rows of the overview widgets that list things
+const (
+	overviewProcessRows = 5
+	overviewDiskRows    = 4
+)
+
+// renderOverview lays the configured widgets out in rows. Widgets without
+// data on this machine, such as the battery of a desktop, are left out.
+func (a *App) renderOverview() string {
+	width := a.layout.Content
+
+	content := []string{sectionHeader(i18n.T("System Overview"))}
+	for _, row := range a.overview {
+		var widgets []string
+		// Narrow terminals stack the widgets of a row
+		stacked := a.layout.Narrow()
+		columns := max(1, len(row))
+		if stacked {
+			columns = 1
+		}
+		columnWidth := max(minContent, (width-(columns-1)*overviewGap)/columns)
+		for _, name := range row {
+			if widget := a.renderWidget(name, columnWidth); widget != "" {
+				widgets = append(widgets, lipgloss.NewStyle().Width(columnWidth).Render(widget))
+			}
+		}
+		if len(widgets) == 0 {
+			continue
+		}
+		if stacked {
+			for _, widget := range widgets {
+				content = append(content, "", widget)
+			}
+			continue
+		}
+
+		for i := 1; i < len(widgets); i += 2 {
+			widgets = append(widgets[:i], append([]string{strings.Repeat(" ", overviewGap)}, widgets[i:]...)...)
+		}
+		content = append(content, "", lipgloss.JoinHorizontal(lipgloss.Top, widgets...))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderWidget renders an overview widget at most width columns wide, or ""
+// when there is nothing to show
+func (a *App) renderWidget(name string, width int) string {
+	switch name {
+	case config.WidgetCPU:
+		cpu := i18n.Sprintf("CPU: %.1f%%", a.stats.CPU.Usage)
+		// Inside a limited cgroup, also show usage relative to its limit
+		if cgroup := a.stats.Cgroup; cgroup.CPULimit > 0 {
+			cpu += i18n.Sprintf(" (host) • %.1f%% of %.1f-core limit", cgroup.CPUUsage, cgroup.CPULimit)
+		}
+		if anomaly, ok := a.cpuHistory.Anomaly(); ok {
+			cpu += " " + anomalyNote(anomaly, func(usage float64) string { return i18n.Sprintf("%.1f%%", usage) })
+		}
+		return a.cpuGauge.Fit(width).ViewLabeled(cpu, a.stats.CPU.Usage)
+
+	case config.WidgetMemory:
+		memory := i18n.Sprintf("Memory: %.1f%%", a.stats.Memory.UsagePercent)
+		if cgroup := a.stats.Cgroup; cgroup.MemoryLimit > 0 {
+			memory += i18n.Sprintf(" (host) • %.1f%% of %.1f GB limit", cgroup.MemoryPercent, cgroup.MemoryLimit/KBToGB)
+		}
+		return a.memoryGauge.Fit(width).ViewLabeled(memory, a.stats.Memory.UsagePercent)
+
+	case config.WidgetLoad:
+		load := a.stats.CPU.Load
+		style := ValueStyle
+		// Warn once runnable tasks outnumber the cores
+		if cores := float64(len(a.stats.CPU.Cores)); cores > 0 && load[0] > cores {
+			style = WarningStyle
+		}
+		return LabelStyle.Render(i18n.T("Load Average")) + "\n" +
+			style.Render(i18n.Sprintf("%.2f %.2f %.2f", load[0], load[1], load[2]))
+
+	case config.WidgetTemperature:
+		if a.stats.CPU.Temp <= 0 {
+			return ""
+		}
+		return LabelStyle.Render(i18n.T("Temperature")) + "\n" +
+			ValueStyle.Render(i18n.Sprintf("%.1f°C", a.stats.CPU.Temp))
+
+	case config.WidgetBattery:
+		battery := a.stats.Battery
+		if battery.Status == "Not Available" {
+			return ""
+		}
+		return LabelStyle.Render(i18n.Sprintf("Battery: %d%% (%s)", battery.Level, battery.Status)) + "\n" +
+			RenderProgressBar(float64(battery.Level), min(20, width))
+
+	case config.WidgetNetwork:
+		network := a.stats.Network
+		widget := LabelStyle.Render(i18n.T("Network")) + "\n" +
+			i18n.Sprintf("↓ %s/s  ↑ %s/s", formatBytes(network.RxRate), formatBytes(network.TxRate))
+		rate := func(rate float64) string { return formatBytes(rate) + "/s" }
+		if anomaly, ok := a.netRxHistory.Anomaly(); ok {
+			widget += "\n↓ " + anomalyNote(anomaly, rate)
+		}
+		if anomaly, ok := a.netTxHistory.Anomaly(); ok {
+			widget += "\n↑ " + anomalyNote(anomaly, rate)
+		}
+		return widget
+
+	case config.WidgetDisk:
+		if len(a.stats.Disk) == 0 {
+			return ""
+		}
+		lines := []string{LabelStyle.Render(i18n.T("Disks"))}
+		for _, disk := range a.stats.Disk[:min(overviewDiskRows, len(a.stats.Disk))] {
+			// Percentage and bar take 17 columns
+			line := fmt.Sprintf("%-*s %s %5.1f%%",
+				max(8, width-18), truncateString(disk.Mountpoint, max(8, width-18)),
+				RenderProgressBar(disk.UsagePercent, 10), disk.UsagePercent)
+			if disk.Stalled {
+				line = WarningStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+		if hidden := len(a.stats.Disk) - overviewDiskRows; hidden > 0 {
+			lines = append(lines, i18n.Sprintf("… %d more in the Disk tab", hidden))
+		}
+		return strings.Join(lines, "\n")
+
+	case config.WidgetProcesses:
+		processes := a.processes.Processes
+		if len(processes) == 0 {
+			return ""
+		}
+		// The list is in the Processes tab's order, which may not be CPU%
+		if a.processSortBy != collector.SortByCPU || !a.processSortDesc {
+			processes = slices.Clone(processes)
+			collector.SortProcesses(processes, collector.SortByCPU, true)
+		}
+		lines := []string{LabelStyle.Render(i18n.T("Top Processes"))}
+		for _, proc := range processes[:min(overviewProcessRows, len(processes))] {
+			lines = append(lines, fmt.Sprintf("%-8d %-*s %5.1f%%",
+				proc.PID, max(8, width-16), truncateString(proc.Name, max(8, width-16)), a.shownCPU(proc.CPUPercent)))
+		}
+		return strings.Join(lines, "\n")
+
+	case config.WidgetLimits:
+		limits := a.stats.Limits
+		lines := []string{LabelStyle.Render(i18n.T("System Limits"))}
+		for _, meter := range []struct {
+			name        string
+			used, limit int
+		}{
+			{i18n.T("Processes"), limits.Processes, limits.PIDMax},
+			{i18n.T("Threads"), limits.Threads, limits.ThreadsMax},
+			{i18n.T("Open files"), limits.Files, limits.FilesMax},
+		} {
+			if meter.limit <= 0 {
+				continue
+			}
+			percent := float64(meter.used) / float64(meter.limit) * 100
+			// Percentage and bar take 17 columns
+			name := fmt.Sprintf("%s %d/%d", meter.name, meter.used, meter.limit)
+			line := fmt.Sprintf("%-*s %s %5.1f%%",
+				max(8, width-18), truncateString(name, max(8, width-18)), RenderProgressBar(percent, 10), percent)
+			if percent >= limitWarning*100 {
+				line = WarningStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 1 {
+			return ""
+		}
+		return strings.Join(lines, "\n")
+
+	case config.WidgetServices:
+		return a.renderServicesWidget(width)
+
+	case config.WidgetSystem:
+		return strings.Join([]string{
+			LabelStyle.Render(i18n.T("Quick Stats")),
+			i18n.Sprintf("Processes: %d", a.processes.Total),
+			i18n.Sprintf("Uptime: %s", formatDuration(a.stats.Uptime)),
+			i18n.Sprintf("CPU Cores: %d", len(a.stats.CPU.Cores)),
+			i18n.Sprintf("Memory Total: %.1f GB", float64(a.stats.Memory.Total)/float64(KBToGB)),
+			i18n.Sprintf("Network Interfaces: %d", len(a.stats.Network.Interfaces)),
+		}, "\n")
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pinMatches reports whether a pin, a PID or a name pattern, selects proc
+func pinMatches(pin string, proc models.Process) bool {
+	if pid, err := strconv.Atoi(pin); err == nil {
+		return proc.PID == pid
+	}
+	matched, _ := path.Match(pin, proc.Name)
+	return matched
+}
+
+func (a *App) isPinned(proc models.Process) bool {
+	return slices.ContainsFunc(a.pinned, func(pin string) bool { return pinMatches(pin, proc) })
+}
+
+// pinnedFirst moves the pinned processes to the front, keeping the order of
+// both parts, and returns how many there are. Pinned rows leave the tree, so
+// their depth becomes 0.
+func (a *App) pinnedFirst(processes []models.Process, depths []int) ([]models.Process, []int, int) {
+	if len(a.pinned) == 0 {
+		return processes, depths, 0
+	}
+	ordered := make([]models.Process, 0, len(processes))
+	var orderedDepths, restDepths []int
+	var rest []models.Process
+	for i, proc := range processes {
+		if a.isPinned(proc) {
+			ordered = append(ordered, proc)
+			if depths != nil {
+				orderedDepths = append(orderedDepths, 0)
+			}
+			continue
+		}
+		rest = append(rest, proc)
+		if depths != nil {
+			restDepths = append(restDepths, depths[i])
+		}
+	}
+	pinned := len(ordered)
+	if depths != nil {
+		depths = append(orderedDepths, restDepths...)
+	}
+	return append(ordered, rest...), depths, pinned
+}
+
+// togglePin unpins proc if a pin selects it, and otherwise asks whether to
+// pin the process itself or every process of its name
+func (a *App) togglePin(proc models.Process) tea.Cmd {
+	if a.isPinned(proc) {
+		pinned := slices.DeleteFunc(slices.Clone(a.pinned), func(pin string) bool { return pinMatches(pin, proc) })
+		a.savePinned(pinned, i18n.Sprintf("Unpinned %s", proc.Name))
+		return nil
+	}
+
+	pid := strconv.Itoa(proc.PID)
+	options := []string{
+		i18n.Sprintf("PID %s only", pid),
+		i18n.Sprintf("Every process named %s", proc.Name),
+	}
+	a.openDialog(newPickerDialog(i18n.Sprintf("Pin %s (PID %s) to the top", proc.Name, pid), options, 0, func(i int) tea.Cmd {
+		pin := pid
+		if i == 1 {
+			pin = proc.Name
+		}
+		a.savePinned(append(slices.Clone(a.pinned), pin), i18n.Sprintf("Pinned %s", pin))
+		return nil
+	}))
+	return nil
+}
+
+// savePinned applies the pins at once and persists them in the config file
+func (a *App) savePinned(pinned []string, done string) {
+	a.pinned = pinned
+	written, err := config.SavePinned(a.configPath, pinned)
+	if err != nil {
+		a.toast(toastError, i18n.Sprintf("Pins not saved: %v", err))
+		return
+	}
+	a.configWritten = written
+	a.toast(toastSuccess, done)
+}
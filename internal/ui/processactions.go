@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// processSignals are the signals offered for a process, most used first
+var processSignals = []struct {
+	name   string
+	signal syscall.Signal
+}{
+	{"SIGTERM", syscall.SIGTERM},
+	{"SIGKILL", syscall.SIGKILL},
+	{"SIGHUP", syscall.SIGHUP},
+	{"SIGINT", syscall.SIGINT},
+	{"SIGSTOP", syscall.SIGSTOP},
+	{"SIGCONT", syscall.SIGCONT},
+	{"SIGUSR1", syscall.SIGUSR1},
+	{"SIGUSR2", syscall.SIGUSR2},
+}
+
+// processActionMsg reports the result of signalling or renicing a process
+type processActionMsg struct {
+	// action and done describe the action for the toast, e.g. "Sending
+	// SIGTERM to 1234" and "SIGTERM sent to 1234"
+	action string
+	done   string
+	err    error
+}
+
+// PIDs are passed to i18n.Sprintf as strings, %d would group their digits
+
+// confirmSignal asks before sending signal to proc
+func (a *App) confirmSignal(proc models.Process, name string, signal syscall.Signal) {
+	question := i18n.Sprintf("Send %s to %s (PID %s)?", name, proc.Name, strconv.Itoa(proc.PID))
+	a.openDialog(newConfirmDialog(question, func() tea.Cmd {
+		return func() tea.Msg {
+			return processActionMsg{
+				action: i18n.Sprintf("Sending %s to %s", name, strconv.Itoa(proc.PID)),
+				done:   i18n.Sprintf("%s sent to %s", name, strconv.Itoa(proc.PID)),
+				err:    a.collector.SignalProcess(proc.PID, signal),
+			}
+		}
+	}))
+}
+
+// pickSignal asks which signal to send to proc, then confirms it
+func (a *App) pickSignal(proc models.Process) {
+	names := make([]string, len(processSignals))
+	for i, signal := range processSignals {
+		names[i] = fmt.Sprintf("%-8s %s", signal.name, signal.signal)
+	}
+	title := i18n.Sprintf("Signal to send to %s (PID %s)", proc.Name, strconv.Itoa(proc.PID))
+	a.openDialog(newPickerDialog(title, names, 0, func(i int) tea.Cmd {
+		a.confirmSignal(proc, processSignals[i].name, processSignals[i].signal)
+		return nil
+	}))
+}
+
+// promptNice asks for the new nice value of proc
+func (a *App) promptNice(proc models.Process) {
+	// The priority of a normal process is 20 plus its nice value
+	current := strconv.Itoa(proc.Priority - 20)
+	prompt := i18n.Sprintf("Nice value for %s (PID %s), -20 (highest priority) to 19:", proc.Name, strconv.Itoa(proc.PID))
+	a.openDialog(newInputDialog(prompt, current, validNice, func(value string) tea.Cmd {
+		nice, _ := strconv.Atoi(value)
+		return func() tea.Msg {
+			return processActionMsg{
+				action: i18n.Sprintf("Renicing %s", strconv.Itoa(proc.PID)),
+				done:   i18n.Sprintf("Nice value of %s set to %d", strconv.Itoa(proc.PID), nice),
+				err:    a.collector.ReniceProcess(proc.PID, nice),
+			}
+		}
+	}))
+}
+
+func validNice(value string) error {
+	nice, err := strconv.Atoi(value)
+	if err != nil || nice < -20 || nice > 19 {
+		return errors.New(i18n.T("enter a whole number from -20 to 19"))
+	}
+	return nil
+}
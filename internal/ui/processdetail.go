@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// processDetailMsg delivers the detail of the process the detail view shows
+type processDetailMsg struct {
+	detail models.ProcessDetail
+	err    error
+}
+
+// loadProcessDetail reads the detail of the process the detail view shows,
+// off the UI goroutine
+func (a *App) loadProcessDetail() tea.Cmd {
+	pid := a.detailPID
+	if pid == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		detail, err := a.collector.GetProcessDetail(pid)
+		return processDetailMsg{detail: detail, err: err}
+	}
+}
+
+// waitChannelHints say what a process probably waits for by the kernel
+// function it sleeps in; the first one the function contains wins
+var waitChannelHints = []struct{ match, hint string }{
+	{"nfs", "the NFS server"},
+	{"rpc_", "an RPC server, usually NFS"},
+	{"cifs", "the SMB server"},
+	{"fuse", "a FUSE filesystem"},
+	{"futex", "a futex, a lock held by another thread"},
+	{"jbd2", "the filesystem journal"},
+	{"xlog", "the filesystem journal"},
+	{"io_schedule", "disk I/O"},
+	{"folio_wait", "disk I/O"},
+	{"wait_on_page", "disk I/O"},
+	{"blk_", "disk I/O"},
+	{"bio_", "disk I/O"},
+	{"ep_poll", "events, an idle event loop"},
+	{"do_select", "file descriptors, select"},
+	{"do_sys_poll", "file descriptors, poll"},
+	{"do_wait", "a child process to exit"},
+	{"pipe_", "a pipe"},
+	{"sk_wait", "the network"},
+	{"unix_stream", "a Unix socket"},
+	{"nanosleep", "a timer, sleeping"},
+}
+
+// waitChannelHint explains a wait channel, empty when it is not a known one
+func waitChannelHint(wchan string) string {
+	for _, h := range waitChannelHints {
+		if strings.Contains(wchan, h.match) {
+			return h.hint
+		}
+	}
+	return ""
+}
+
+// detailLabelWidth aligns the values of the detail view
+const detailLabelWidth = 20
+
+// detailLine renders one label and value of the detail view. Values wrap
+// beside the label rather than under it.
+func (a *App) detailLine(label, value string) string {
+	width := max(10, a.layout.Content-detailLabelWidth-2)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		" "+LabelStyle.Width(detailLabelWidth).Render(label),
+		lipgloss.NewStyle().Width(width).Render(value))
+}
+
+// renderProcessDetail renders the detail view of proc, the table's last
+// sample of it, with the detail read for it if it has arrived
+func (a *App) renderProcessDetail(proc models.Process, exited bool) string {
+	detail := a.detail
+	if detail.PID != proc.PID {
+		detail = models.ProcessDetail{}
+	}
+	dash := func(text string) string {
+		if text == "" {
+			return "-"
+		}
+		return text
+	}
+
+	title := HeaderStyle.Render(i18n.Sprintf("Process %s · %s", strconv.Itoa(proc.PID), proc.Name))
+	if exited {
+		title += " " + WarningStyle.Render(i18n.T("exited, showing its last sample"))
+	}
+	command := proc.Command
+	if len(detail.Args) > 0 {
+		command = strings.Join(detail.Args, " ")
+	}
+	parent := fmt.Sprint(proc.PPID)
+	for _, p := range a.processes.Processes {
+		if p.PID == proc.PPID {
+			parent += " (" + p.Name + ")"
+			break
+		}
+	}
+	started := "-"
+	if !detail.Started.IsZero() {
+		started = i18n.Sprintf("%s, %s ago", detail.Started.Format("2006-01-02 15:04:05"), formatDuration(time.Since(detail.Started)))
+	}
+	waiting := "-"
+	if wchan := detail.WaitChannel; wchan != "" {
+		waiting = wchan
+		if hint := waitChannelHint(wchan); hint != "" {
+			waiting = i18n.Sprintf("%s, probably waiting for %s", wchan, i18n.T(hint))
+		}
+		if proc.Status == "D" {
+			waiting = WarningStyle.Render(waiting + " " + i18n.T("(uninterruptible)"))
+		}
+	}
+	// Nothing installed a binary under /tmp or a home directory
+	pkg := "-"
+	switch {
+	case detail.Package != "":
+		pkg = detail.Package
+	case detail.PackageKnown:
+		pkg = WarningStyle.Render(i18n.T("not installed by a package"))
+	}
+	namespaces := "-"
+	if ns := detail.Namespaces; ns != (models.Namespaces{}) {
+		namespaces = fmt.Sprintf("pid:[%d] net:[%d] mnt:[%d] user:[%d]", ns.PID, ns.Net, ns.Mnt, ns.User)
+		if own := ownNamespaces(ns, collector.HostNamespaces()); len(own) > 0 {
+			namespaces += ", " + ValueStyle.Render(i18n.Sprintf("%s, own: %s", i18n.T(namespaceKind(own)), strings.Join(own, ", ")))
+		}
+	}
+	threads := "-"
+	if detail.Threads > 0 {
+		threads = fmt.Sprint(detail.Threads)
+	}
+
+	lines := []string{
+		title,
+		"",
+		a.detailLine(i18n.T("Command:"), command),
+		a.detailLine(i18n.T("Executable:"), dash(detail.Executable)),
+		a.detailLine(i18n.T("Package:"), pkg),
+		a.detailLine("SHA256:", dash(detail.SHA256)),
+		a.detailLine(i18n.T("Working directory:"), dash(detail.Cwd)),
+		a.detailLine(i18n.T("User:"), fmt.Sprintf("%s (%s)", collector.LookupUsername(proc.User), proc.User)),
+		a.detailLine(i18n.T("Parent:"), parent),
+		a.detailLine(i18n.T("State:"), proc.Status),
+		a.detailLine(i18n.T("Waiting in:"), waiting),
+		a.detailLine(i18n.T("Threads:"), threads),
+		a.detailLine(i18n.T("Started:"), started),
+		a.detailLine(i18n.T("Cgroup:"), dash(detail.Cgroup)),
+		a.detailLine(i18n.T("Namespaces:"), namespaces),
+		"",
+		sectionHeader(i18n.T("Resources")),
+		a.detailLine(i18n.T("CPU:"), fmt.Sprintf("%.1f%%", a.shownCPU(proc.CPUPercent))),
+		a.detailLine(i18n.T("Memory:"), i18n.Sprintf("%.1f%%, RSS %s, swap %s",
+			proc.MemPercent, formatBytes(float64(proc.MemRSS)*1024), formatBytes(float64(proc.Swap)*1024))),
+		a.detailLine(i18n.T("Disk I/O:"), i18n.Sprintf("read %s/s, write %s/s", formatBytes(proc.ReadRate), formatBytes(proc.WriteRate))),
+		"",
+		sectionHeader(i18n.T("Scheduling")),
+		a.detailLine(i18n.T("Context switches:"), i18n.Sprintf("%d voluntary (%s/s), %d involuntary (%s/s)",
+			proc.VoluntaryCtxSwitches, formatPerSecond(proc.VoluntaryCtxRate),
+			proc.NonvoluntaryCtxSwitches, formatPerSecond(proc.NonvoluntaryCtxRate))),
+		a.detailLine(i18n.T("Page faults:"), i18n.Sprintf("%d minor (%s/s), %d major (%s/s)",
+			proc.MinorFaults, formatPerSecond(proc.MinorFaultRate),
+			proc.MajorFaults, formatPerSecond(proc.MajorFaultRate))),
+		a.detailLine(i18n.T("Run queue wait:"), i18n.Sprintf("%.1f%% of the time, %s in total (main thread)",
+			proc.RunDelayPercent, formatDuration(proc.RunDelay))),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+			" " + i18n.T("Voluntary switches wait for I/O or locks, involuntary ones lost the CPU to other work; major faults read from disk; run queue wait is time ready to run without a free CPU")),
+	}
+	if len(detail.Limits) > 0 {
+		lines = append(lines, "", sectionHeader(i18n.T("Limits")))
+		for _, limit := range detail.Limits {
+			lines = append(lines, a.detailLine(i18n.T(limitLabels[limit.Name]), formatLimit(limit)))
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+			" "+i18n.T("Soft limits apply, a process may raise them up to the hard limit; processes counts the threads of all processes of the user")))
+	}
+	if detail.Security.Known {
+		lines = append(lines, "", sectionHeader(i18n.T("Security")))
+		lines = append(lines, a.securityLines(detail)...)
+	}
+	lines = append(lines, "",
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(
+			" "+i18n.T("Esc/Enter: back • ↑↓ j/k: scroll • x/X: signal • n: nice • y/Y: copy PID/command • s: copy SHA256")))
+	return strings.Join(lines, "\n")
+}
+
+// securityLines show the capabilities and seccomp mode of a process and
+// how it is confined: seccomp, no_new_privs, dropped capabilities and
+// namespaces of its own. A process keeping every capability unconfined is
+// root with nothing in its way.
+func (a *App) securityLines(detail models.ProcessDetail) []string {
+	security := detail.Security
+	capabilities := i18n.T("none")
+	switch {
+	case len(security.Capabilities) == 0:
+	case len(security.MissingCapabilities) == 0:
+		capabilities = WarningStyle.Render(i18n.Sprintf("all (%s)", strconv.Itoa(len(security.Capabilities))))
+	case len(security.Capabilities) > len(security.MissingCapabilities):
+		capabilities = i18n.Sprintf("all but %s", strings.Join(security.MissingCapabilities, ", "))
+	default:
+		capabilities = strings.Join(security.Capabilities, ", ")
+	}
+	seccomp := i18n.T(security.Seccomp)
+	if security.Seccomp == "filter" {
+		seccomp = i18n.Sprintf("filter, %s installed", strconv.Itoa(security.SeccompFilters))
+	}
+
+	var confinement []string
+	if security.Seccomp == "filter" || security.Seccomp == "strict" {
+		confinement = append(confinement, "seccomp")
+	}
+	if security.NoNewPrivs {
+		confinement = append(confinement, "no_new_privs")
+	}
+	if len(security.Capabilities) > 0 && len(security.MissingCapabilities) > 0 {
+		confinement = append(confinement, i18n.T("dropped capabilities"))
+	}
+	if detail.Namespaces != (models.Namespaces{}) {
+		if own := ownNamespaces(detail.Namespaces, collector.HostNamespaces()); len(own) > 0 {
+			confinement = append(confinement, i18n.Sprintf("own %s namespaces", strings.Join(own, ", ")))
+		}
+	}
+	sandbox := SuccessStyle.Render(strings.Join(confinement, ", "))
+	switch {
+	case len(confinement) > 0:
+	case len(security.Capabilities) > 0:
+		sandbox = WarningStyle.Render(i18n.T("none, privileged and unconfined"))
+	default:
+		sandbox = i18n.T("none, but unprivileged")
+	}
+	noNewPrivs := i18n.T("no")
+	if security.NoNewPrivs {
+		noNewPrivs = i18n.T("yes")
+	}
+
+	return []string{
+		a.detailLine(i18n.T("Sandboxing:"), sandbox),
+		a.detailLine(i18n.T("Capabilities:"), capabilities),
+		a.detailLine(i18n.T("Seccomp:"), seccomp),
+		a.detailLine(i18n.T("No new privileges:"), noNewPrivs),
+	}
+}
+
+// limitLabels label the limits of the detail view by their ulimit name
+var limitLabels = map[string]string{
+	"nofile":  "Open files:",
+	"nproc":   "Processes:",
+	"memlock": "Locked memory:",
+}
+
+// limitWarning is the share of a soft limit in use that is highlighted
+const limitWarning = 0.8
+
+// formatLimit renders what a process uses of a limit against the limit,
+// highlighted when close to it
+func formatLimit(limit models.ResourceLimit) string {
+	value := func(v int64) string {
+		switch {
+		case v < 0:
+			return i18n.T("unlimited")
+		case limit.Unit == "bytes":
+			return formatBytes(float64(v))
+		default:
+			return strconv.FormatInt(v, 10)
+		}
+	}
+	used := "?"
+	if limit.Used >= 0 {
+		used = value(limit.Used)
+	}
+	text := i18n.Sprintf("%s of %s (hard %s)", used, value(limit.Soft), value(limit.Hard))
+	if limit.Used >= 0 && limit.Soft > 0 && float64(limit.Used) >= limitWarning*float64(limit.Soft) {
+		return WarningStyle.Render(text)
+	}
+	return text
+}
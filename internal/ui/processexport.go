@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prabalesh/croptop/internal/models"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// Process export formats
+const (
+	exportCSV  = "csv"
+	exportJSON = "json"
+)
+
+// processExportMsg reports where the process list was exported to
+type processExportMsg struct {
+	path string
+	rows int
+	err  error
+}
+
+// exportProcesses writes the rows the process table shows, filtered and in
+// its current order with pinned processes first, and with its columns, to
+// a timestamped file in the export directory
+func (a *App) exportProcesses(format string, processes []models.Process) tea.Cmd {
+	columns := a.tableColumns()
+	now := time.Now()
+	path := filepath.Join(a.exportDir, fmt.Sprintf("croptop-processes-%s.%s", now.Format("20060102-150405"), format))
+
+	return func() tea.Msg {
+		var err error
+		if format == exportJSON {
+			err = writeProcessJSON(path, now, columns, processes)
+		} else {
+			err = writeProcessCSV(path, columns, processes)
+		}
+		return processExportMsg{path: path, rows: len(processes), err: err}
+	}
+}
+
+func writeProcessCSV(path string, columns []processColumn, processes []models.Process) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	out := csv.NewWriter(file)
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.Key
+	}
+	out.Write(record)
+	for _, proc := range processes {
+		for i, column := range columns {
+			record[i] = fmt.Sprint(column.Value(proc))
+		}
+		out.Write(record)
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// processExport is the JSON process export, following the schema package's
+// conventions
+type processExport struct {
+	SchemaVersion int              `json:"schema_version"`
+	Time          time.Time        `json:"time"`
+	Columns       []string         `json:"columns"`
+	Processes     []map[string]any `json:"processes"`
+}
+
+func writeProcessJSON(path string, t time.Time, columns []processColumn, processes []models.Process) error {
+	export := processExport{
+		SchemaVersion: schema.Version,
+		Time:          t,
+		Columns:       make([]string, len(columns)),
+		Processes:     make([]map[string]any, 0, len(processes)),
+	}
+	for i, column := range columns {
+		export.Columns[i] = column.Key
+	}
+	for _, proc := range processes {
+		row := make(map[string]any, len(columns))
+		for _, column := range columns {
+			row[column.Key] = column.Value(proc)
+		}
+		export.Processes = append(export.Processes, row)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
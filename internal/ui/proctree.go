@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// processTree orders processes depth first below their parents, siblings by
+// CPU%, and returns the depth of each. With totals every process carries the
+// CPU%, memory, PSS, USS and swap of its whole subtree instead of its own.
+func processTree(processes []models.Process, totals bool) ([]models.Process, []int) {
+	index := make(map[int]int, len(processes))
+	for i, proc := range processes {
+		index[proc.PID] = i
+	}
+	// Processes whose parent is gone, or not visible to us, are roots
+	children := make(map[int][]int)
+	var roots []int
+	for i, proc := range processes {
+		if parent, ok := index[proc.PPID]; ok && proc.PPID != proc.PID {
+			children[parent] = append(children[parent], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	nodes := slices.Clone(processes)
+	if totals {
+		var sum func(i int)
+		sum = func(i int) {
+			for _, child := range children[i] {
+				sum(child)
+				nodes[i].CPUPercent += nodes[child].CPUPercent
+				nodes[i].MemPercent += nodes[child].MemPercent
+				nodes[i].MemRSS += nodes[child].MemRSS
+				nodes[i].Swap += nodes[child].Swap
+				nodes[i].PSS += nodes[child].PSS
+				nodes[i].USS += nodes[child].USS
+			}
+		}
+		for _, root := range roots {
+			sum(root)
+		}
+	}
+
+	byCPU := func(a, b int) int {
+		if c := cmp.Compare(nodes[b].CPUPercent, nodes[a].CPUPercent); c != 0 {
+			return c
+		}
+		return cmp.Compare(nodes[a].PID, nodes[b].PID)
+	}
+	ordered := make([]models.Process, 0, len(nodes))
+	depths := make([]int, 0, len(nodes))
+	var walk func(i, depth int)
+	walk = func(i, depth int) {
+		ordered = append(ordered, nodes[i])
+		depths = append(depths, depth)
+		slices.SortFunc(children[i], byCPU)
+		for _, child := range children[i] {
+			walk(child, depth+1)
+		}
+	}
+	slices.SortFunc(roots, byCPU)
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return ordered, depths
+}
+
+// treePrefix indents a process name by its depth in the tree, deep trees
+// being capped so the name stays readable
+func treePrefix(depth int) string {
+	if depth == 0 {
+		return ""
+	}
+	return strings.Repeat("  ", min(depth, 8)-1) + "└ "
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// serviceSlow is the connect latency from which a service shows as slow
+const serviceSlow = 200 * time.Millisecond
+
+// serviceCheckMsg carries the results of a round of service checks
+type serviceCheckMsg []models.ServiceCheck
+
+// checkServices connects to the configured services, off the UI goroutine;
+// nil while the last round is still running or there are none
+func (a *App) checkServices() tea.Cmd {
+	if len(a.services) == 0 || a.serviceChecking {
+		return nil
+	}
+	a.serviceChecking = true
+
+	services := a.services
+	return func() tea.Msg {
+		addresses := make([]string, len(services))
+		for i, service := range services {
+			addresses[i] = service.Address
+		}
+		checks := a.collector.CheckServices(addresses)
+		for i := range checks {
+			checks[i].Name = services[i].Name
+		}
+		return serviceCheckMsg(checks)
+	}
+}
+
+// serviceStatus is the mark, name and outcome of a service check, styled by
+// whether the service is up, slow or down
+func serviceStatus(check models.ServiceCheck, nameWidth int) string {
+	name := truncateString(check.Name, nameWidth)
+	switch {
+	case !check.Up:
+		return ErrorStyle.Render(fmt.Sprintf("✗ %-*s %s", nameWidth, name, i18n.Sprintf("down: %s", check.Error)))
+	case check.Latency >= serviceSlow:
+		return WarningStyle.Render(fmt.Sprintf("✓ %-*s %s", nameWidth, name, check.Latency.Round(time.Millisecond)))
+	default:
+		return SuccessStyle.Render(fmt.Sprintf("✓ %-*s %s", nameWidth, name, check.Latency.Round(100*time.Microsecond)))
+	}
+}
+
+// renderServices shows whether each configured service accepts TCP
+// connections and how fast, checked every refresh
+func (a *App) renderServices() []string {
+	if len(a.services) == 0 {
+		return nil
+	}
+	content := []string{sectionHeader(i18n.T("Services"))}
+	if len(a.serviceChecks) == 0 {
+		return append(content, i18n.T("checking..."), "")
+	}
+
+	up := 0
+	for _, check := range a.serviceChecks {
+		if check.Up {
+			up++
+		}
+	}
+	content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("Up:")),
+		ValueStyle.Render(i18n.Sprintf("%d of %d, checked at %s", up, len(a.serviceChecks),
+			a.serviceChecks[0].Time.Format("15:04:05")))))
+	for _, check := range a.serviceChecks {
+		status := serviceStatus(check, 24)
+		if check.Name != check.Address {
+			status += " " + LabelStyle.Render(check.Address)
+		}
+		content = append(content, "  "+status)
+	}
+	return append(content, "")
+}
+
+// renderServicesWidget is the Overview's summary of the service checks,
+// empty without services
+func (a *App) renderServicesWidget(width int) string {
+	if len(a.services) == 0 {
+		return ""
+	}
+	lines := []string{LabelStyle.Render(i18n.T("Services"))}
+	if len(a.serviceChecks) == 0 {
+		lines = append(lines, i18n.T("checking..."))
+	}
+	for _, check := range a.serviceChecks {
+		// The mark and the latency take about 12 columns
+		lines = append(lines, serviceStatus(check, max(8, width-12)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/i18n"
+)
+
+// renderSessions shows the logind sessions of the Users tab, with the type
+// and idle time of each, and the load of the compositor against the
+// applications'
+func (a *App) renderSessions() string {
+	desktop := a.desktop
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).PaddingLeft(1).PaddingRight(1)
+	rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+	content := []string{sectionHeader(i18n.T("Sessions"))}
+
+	current := "-"
+	for _, session := range desktop.Sessions {
+		if session.Current {
+			current = session.Type
+			if session.Desktop != "" {
+				current += " (" + session.Desktop + ")"
+			}
+		}
+	}
+	content = append(content, fmt.Sprintf("%s %s", LabelStyle.Render(i18n.T("This session:")), ValueStyle.Render(current)))
+	if len(desktop.Sessions) > 0 {
+		content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%-6s %-16s %-12s %-12s %-10s %-12s %-8s %s",
+			"ID", "USER", "TYPE", "CLASS", "SEAT/TTY", "DESKTOP", "STATE", "IDLE"))))
+	}
+	for _, session := range desktop.Sessions {
+		where := session.Seat
+		if where == "" {
+			where = session.TTY
+		}
+		if session.Remote {
+			where = "remote"
+		}
+		idle := "-"
+		if !session.IdleSince.IsZero() {
+			idle = formatDuration(time.Since(session.IdleSince))
+		}
+		id := session.ID
+		if session.Current {
+			id += "*"
+		}
+		row := fmt.Sprintf("%-6s %-16s %-12s %-12s %-10s %-12s %-8s %s", id, truncateString(session.User, 16),
+			session.Type, session.Class, truncateString(where, 10), truncateString(session.Desktop, 12), session.State, idle)
+		content = append(content, rowStyle.Render(row))
+	}
+
+	content = append(content, "", sectionHeader(i18n.T("Compositor Load")))
+	gpu := func(percent float64) string {
+		if !desktop.GPUKnown {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", percent)
+	}
+	if len(desktop.Compositors) == 0 {
+		content = append(content, " "+i18n.T("No display server or compositor running"))
+	}
+	for _, compositor := range desktop.Compositors {
+		content = append(content, fmt.Sprintf(" %-16s %-8d CPU %6.1f%%  GPU %6s", compositor.Name, compositor.PID,
+			a.shownCPU(compositor.CPUPercent), gpu(compositor.GPUPercent)))
+	}
+	content = append(content, fmt.Sprintf(" %-25s CPU %6.1f%%  GPU %6s", i18n.T("Applications"),
+		a.shownCPU(desktop.AppsCPUPercent), gpu(desktop.AppsGPUPercent)))
+
+	hint := i18n.T("A busy compositor beside idle applications is compositing overhead (effects, animations, many or large windows), not application load")
+	if !desktop.GPUKnown {
+		hint += "; " + i18n.T("GPU usage needs DRM usage statistics (Linux 5.19+) and permission to read the processes' file descriptors")
+	}
+	content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true).Render(" "+hint))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/i18n"
+)
+
+// renderSharedMemory lists tmpfs usage, the biggest tmpfs files and the
+// System V segments of the Memory tab, with the processes using them
+func (a *App) renderSharedMemory() []string {
+	shm := a.shm
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+
+	content := []string{sectionHeader(i18n.T("Shared Memory & tmpfs"))}
+	for _, mount := range shm.Mounts {
+		content = append(content, i18n.Sprintf("%s %s of %s", LabelStyle.Render(fmt.Sprintf("%-16s", mount.Mountpoint)),
+			formatBytes(float64(mount.Used)), formatBytes(float64(mount.Size))))
+	}
+	if len(shm.Mounts) == 0 {
+		content = append(content, i18n.T("No tmpfs mounted"))
+	}
+
+	// Less the size column and its gap
+	pathWidth := max(20, (a.layout.Content-11)/2)
+	content = append(content, "", LabelStyle.Render(i18n.T("Biggest tmpfs Files")))
+	if len(shm.Files) == 0 {
+		content = append(content, i18n.T("No files on tmpfs"))
+	} else {
+		content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%10s %-*s %s", "SIZE", pathWidth, "PATH", "PROCESSES"))))
+		for _, file := range shm.Files {
+			content = append(content, fmt.Sprintf("%10s %-*s %s", formatBytes(float64(file.Size)),
+				pathWidth, truncateString(file.Path, pathWidth), a.processNames(file.PIDs, a.layout.Content-pathWidth-12)))
+		}
+	}
+	if shm.Truncated {
+		content = append(content, WarningStyle.Render(i18n.Sprintf("Stopped after %d files, bigger files may be missing", collector.MaxTmpfsWalk)))
+	}
+
+	content = append(content, "", LabelStyle.Render(i18n.T("System V Segments")))
+	if len(shm.Segments) == 0 {
+		content = append(content, i18n.T("No System V shared memory segments"))
+		return content
+	}
+	content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%-10s %8s %10s %10s %6s %s", "KEY", "ID", "SIZE", "RSS", "ATTACH", "PROCESSES"))))
+	for _, segment := range shm.Segments {
+		users := a.processNames(segment.PIDs, a.layout.Content-50)
+		if len(segment.PIDs) == 0 {
+			// Nobody maps it any more, the creator may be long gone
+			users = i18n.Sprintf("created by PID %s", strconv.Itoa(segment.Creator))
+		}
+		content = append(content, fmt.Sprintf("%-10s %8d %10s %10s %6d %s", segment.Key, segment.ID,
+			formatBytes(float64(segment.Size)), formatBytes(float64(segment.RSS)), segment.Attached, users))
+	}
+	return content
+}
+
+// processNames lists PIDs as name(pid), cut to width
+func (a *App) processNames(pids []int, width int) string {
+	if len(pids) == 0 {
+		return "-"
+	}
+	names := make(map[int]string, len(a.processes.Processes))
+	for _, proc := range a.processes.Processes {
+		names[proc.PID] = proc.Name
+	}
+	parts := make([]string, len(pids))
+	for i, pid := range pids {
+		parts[i] = fmt.Sprintf("%s(%d)", names[pid], pid)
+	}
+	return truncateString(strings.Join(parts, ", "), max(10, width))
+}
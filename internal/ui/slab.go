@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// renderSlab shows the kernel's slab memory of the Memory tab, with the
+// biggest caches when /proc/slabinfo is readable
+func (a *App) renderSlab() []string {
+	slab := a.slab
+	content := []string{
+		sectionHeader(i18n.T("Slab Caches")),
+		i18n.Sprintf("%s %s  %s %s",
+			LabelStyle.Render(i18n.T("Reclaimable:")), formatBytes(float64(slab.Reclaimable)*1024),
+			LabelStyle.Render(i18n.T("Unreclaimable:")), formatBytes(float64(slab.Unreclaimable)*1024)),
+	}
+
+	if len(slab.Caches) == 0 {
+		if notice := a.lockedNotice(models.FeatureSlabinfo); notice != "" {
+			content = append(content, notice)
+		}
+		return content
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	content = append(content, "", columnHeader(headerStyle.Render(fmt.Sprintf("%-24s %10s %12s %8s %6s", "CACHE", "SIZE", "OBJECTS", "OBJSIZE", "USED"))))
+	for _, cache := range slab.Caches {
+		// Free objects in partially used slabs, a fragmented cache
+		var used float64
+		if cache.Objects > 0 {
+			used = float64(cache.ActiveObjects) / float64(cache.Objects) * 100
+		}
+		content = append(content, fmt.Sprintf("%-24s %10s %12d %8d %5.0f%%", truncateString(cache.Name, 24),
+			formatBytes(float64(cache.Size)), cache.Objects, cache.ObjectSize, used))
+	}
+	return content
+}
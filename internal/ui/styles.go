@@ -79,25 +79,3 @@ var (
 				Background(lipgloss.Color("240")).
 				Foreground(lipgloss.Color("230"))
 )
-
-func RenderProgressBar(percent float64, width int) string {
-	if width <= 0 {
-		width = 20
-	}
-
-	filled := int(percent / 100 * float64(width))
-	if filled > width {
-		filled = width
-	}
-
-	bar := ""
-	for i := 0; i < filled; i++ {
-		bar += "█"
-	}
-	for i := filled; i < width; i++ {
-		bar += "░"
-	}
-
-	return ProgressCompleteStyle.Render(bar[:filled]) +
-		ProgressEmptyStyle.Render(bar[filled:])
-}
@@ -0,0 +1,789 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// tabModel is the view model of a tab. App handles the global keys (quit,
+// switching tabs) and passes every other key to the active tab, which owns
+// its scrolling and selection state.
+type tabModel interface {
+	// Init is called whenever the tab becomes active
+	Init() tea.Cmd
+	Update(msg tea.Msg) tea.Cmd
+	// View renders the inside of the content box, width x height
+	View(width, height int) string
+}
+
+// horizontalScroller is a tab that takes ←/→ and h/l over from switching
+// tabs while it scrolls sideways
+type horizontalScroller interface {
+	ScrollsHorizontally() bool
+}
+
+// newTabView creates the view model of a tab. New tabs are added here and to
+// the tab list in NewApp.
+func (a *App) newTabView(name string) tabModel {
+	switch name {
+	case "Overview":
+		return &pageTab{render: a.renderOverview}
+	case "CPU":
+		return &pageTab{render: a.renderCPU, keys: a.cpuKeys, horizontal: a.coreSelectable}
+	case "Memory":
+		return &pageTab{render: a.renderMemory}
+	case "Swap":
+		return &pageTab{render: a.renderSwap, init: a.updateStats}
+	case "Processes":
+		return &processTab{app: a}
+	case "Users":
+		return &pageTab{render: a.renderUsers, keys: a.userKeys}
+	case "Pods":
+		return &pageTab{render: a.renderPods}
+	case "VMs":
+		return &pageTab{render: a.renderVMs}
+	case "Containers":
+		return &containersTab{app: a}
+	case "Network":
+		return &pageTab{render: a.renderNetwork, keys: a.networkKeys, init: a.updateStats}
+	case "Disk":
+		return &pageTab{render: a.renderDisk, keys: a.diskKeys}
+	case "I/O":
+		return &pageTab{render: a.renderIO, keys: a.ioKeys}
+	case "Battery":
+		return &pageTab{render: a.renderBattery, keys: a.batteryKeys}
+	case "Kernel":
+		return &kernelTab{app: a, level: 7} // debug, show everything
+	case "Boot":
+		return &bootTab{app: a}
+	case "Security":
+		return &pageTab{render: a.renderSecurity, init: a.updateStats}
+	case "Alerts":
+		return &pageTab{render: a.renderAlerts, keys: a.alertKeys}
+	case "Hosts":
+		return &hostsTab{app: a}
+	}
+	return &pageTab{render: func() string { return "" }}
+}
+
+// pageTab is a tab whose content scrolls as a whole
+type pageTab struct {
+	render func() string
+	// keys handles the tab's own keys, the others scroll
+	keys func(msg tea.KeyMsg) (tea.Cmd, bool)
+	// init runs when the tab becomes active, e.g. to collect data that is
+	// only gathered for the active tab
+	init func() tea.Cmd
+	// horizontal reports whether keys takes ←/→ and h/l over
+	horizontal func() bool
+	scroll     scrollView
+}
+
+func (t *pageTab) ScrollsHorizontally() bool {
+	return t.horizontal != nil && t.horizontal()
+}
+
+func (t *pageTab) Init() tea.Cmd {
+	if t.init == nil {
+		return nil
+	}
+	return t.init()
+}
+
+func (t *pageTab) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	if t.keys != nil {
+		if cmd, handled := t.keys(key); handled {
+			return cmd
+		}
+	}
+	t.scroll.Update(key)
+	return nil
+}
+
+func (t *pageTab) View(width, height int) string {
+	return t.scroll.View(t.render(), height)
+}
+
+// kernelTab is the kernel log, filtered by severity. In follow mode it stays
+// scrolled to the newest messages.
+type kernelTab struct {
+	app    *App
+	level  int
+	scroll scrollView
+}
+
+func (t *kernelTab) Init() tea.Cmd {
+	return t.app.updateStats()
+}
+
+func (t *kernelTab) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	switch key.String() {
+	case "v":
+		// Cycle the lowest severity shown
+		t.level--
+		if t.level < 3 {
+			t.level = 7
+		}
+		return nil
+	case "f":
+		t.app.kernelFollow = !t.app.kernelFollow
+		if t.app.kernelFollow {
+			return t.app.updateStats()
+		}
+		return nil
+	case "up", "k", "pgup", "ctrl+u", "home", "ctrl+home":
+		// Reading back through the log pauses it
+		t.app.kernelFollow = false
+	}
+	t.scroll.Update(key)
+	return nil
+}
+
+func (t *kernelTab) View(width, height int) string {
+	t.scroll.follow = t.app.kernelFollow
+	return t.scroll.View(t.app.renderKernelLog(t.level), height)
+}
+
+// processTab is the process table with a selected row. The selection stays
+// on the same process while the table re-sorts, and the table is windowed
+// around it to fit the content area. In column scroll mode ←/→ scroll the
+// columns after PID, for terminals too narrow for the whole table. In tree
+// mode children follow their parents, optionally with subtree totals. A
+// filter expression narrows the table down, and Enter opens the detail view
+// of the selected process in place of the table.
+type processTab struct {
+	app      *App
+	selected int
+	// PID of the selected process, to find it again after a refresh
+	pid    int
+	offset int
+	// Rows shown by the last render, to page by
+	rows int
+	// Column scroll mode and the columns scrolled out of view
+	columnScroll bool
+	columnOffset int
+	// Tree mode and whether parents show the totals of their subtree
+	tree   bool
+	totals bool
+	filter collector.ProcessFilter
+	// Scrolling of the detail view and the last sample of its process, kept
+	// after it exits
+	detailScroll  scrollView
+	detailProcess models.Process
+	// Scrolling of the namespace view
+	namespaceScroll scrollView
+}
+
+// list is the table's processes in the order shown, with their depths in
+// tree mode and how many pinned processes lead the list
+func (t *processTab) list() ([]models.Process, []int, int) {
+	processes, depths := t.filter.Apply(t.app.processes.Processes), []int(nil)
+	if t.tree {
+		processes, depths = processTree(processes, t.totals)
+	}
+	return t.app.pinnedFirst(processes, depths)
+}
+
+func (t *processTab) ScrollsHorizontally() bool {
+	return t.columnScroll
+}
+
+func (t *processTab) Init() tea.Cmd {
+	return nil
+}
+
+func (t *processTab) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	processes, _, _ := t.list()
+	if t.app.detailPID != 0 {
+		switch key.String() {
+		case "esc", "enter", "backspace":
+			t.app.detailPID = 0
+			return nil
+		case "s":
+			detail := t.app.detail
+			if detail.PID != t.app.detailPID || detail.SHA256 == "" {
+				t.app.toast(toastWarning, i18n.T("Nothing to copy, the executable could not be read"))
+				return nil
+			}
+			return copyToClipboard(i18n.Sprintf("the SHA256 of PID %s", strconv.Itoa(detail.PID)), detail.SHA256)
+		case "x", "X", "n", "y", "Y":
+			// Act on the process shown, which stays selected unless it
+			// exited
+			if t.selected >= len(processes) || processes[t.selected].PID != t.app.detailPID {
+				return nil
+			}
+		default:
+			t.detailScroll.Update(key)
+			return nil
+		}
+	}
+	if t.app.namespaceView {
+		switch key.String() {
+		case "esc", "N":
+			t.app.namespaceView = false
+		default:
+			t.namespaceScroll.Update(key)
+		}
+		return nil
+	}
+	switch key.String() {
+	case "up", "k":
+		t.selected--
+	case "down", "j":
+		t.selected++
+	case "pgup", "ctrl+u":
+		t.selected -= max(1, t.rows)
+	case "pgdown", "ctrl+d":
+		t.selected += max(1, t.rows)
+	case "home", "ctrl+home":
+		t.selected = 0
+	case "end", "ctrl+end":
+		t.selected = len(processes) - 1
+	case "enter":
+		if t.selected < len(processes) {
+			t.detailProcess = processes[t.selected]
+			t.detailScroll = scrollView{}
+			t.app.detailPID = t.detailProcess.PID
+			return t.app.loadProcessDetail()
+		}
+		return nil
+	case "O":
+		t.app.pickColumn(func() {
+			t.columnOffset = min(t.columnOffset, maxColumnOffset(t.app.tableColumns()))
+		})
+		return nil
+	case "e":
+		return t.app.exportProcesses(exportCSV, processes)
+	case "E":
+		return t.app.exportProcesses(exportJSON, processes)
+	case "y":
+		if t.selected < len(processes) {
+			pid := processes[t.selected].PID
+			return copyToClipboard(i18n.Sprintf("PID %s", strconv.Itoa(pid)), strconv.Itoa(pid))
+		}
+		t.app.toast(toastWarning, i18n.T("Nothing to copy, no process selected"))
+		return nil
+	case "Y":
+		if t.selected < len(processes) {
+			proc := processes[t.selected]
+			return copyToClipboard(i18n.Sprintf("the command line of PID %s", strconv.Itoa(proc.PID)), proc.Command)
+		}
+		t.app.toast(toastWarning, i18n.T("Nothing to copy, no process selected"))
+		return nil
+	case "x", "X", "n":
+		if t.selected >= len(processes) {
+			return nil
+		}
+		proc := processes[t.selected]
+		switch {
+		case key.String() == "n":
+			if t.app.allowAction(i18n.T("Renicing processes")) {
+				t.app.promptNice(proc)
+			}
+		case t.app.allowAction(i18n.T("Signalling processes")):
+			if key.String() == "x" {
+				t.app.confirmSignal(proc, "SIGTERM", syscall.SIGTERM)
+			} else {
+				t.app.pickSignal(proc)
+			}
+		}
+		return nil
+	case "p":
+		if t.selected < len(processes) {
+			return t.app.togglePin(processes[t.selected])
+		}
+		return nil
+	case "s":
+		t.app.processSortBy = (t.app.processSortBy + 1) % (collector.SortByGrowth + 1)
+		collector.SortProcesses(t.app.processes.Processes, t.app.processSortBy, t.app.processSortDesc)
+		return nil
+	case "r":
+		t.app.processSortDesc = !t.app.processSortDesc
+		collector.SortProcesses(t.app.processes.Processes, t.app.processSortBy, t.app.processSortDesc)
+		return nil
+	case "I":
+		t.app.solarisMode = !t.app.solarisMode
+		return nil
+	case "T":
+		t.tree = !t.tree
+		return nil
+	case "N":
+		t.app.namespaceView = true
+		t.namespaceScroll = scrollView{}
+		return t.app.updateStats()
+	case "M":
+		t.app.accurateMemory = !t.app.accurateMemory
+		t.columnOffset = min(t.columnOffset, maxColumnOffset(t.app.tableColumns()))
+		if t.app.accurateMemory {
+			t.app.toast(toastInfo, i18n.T("Reading PSS and USS of every process, refreshes get slower"))
+			return t.app.updateStats()
+		}
+		t.app.toast(toastInfo, i18n.T("PSS and USS off"))
+		return nil
+	case "A":
+		// Totals only make sense in the tree, so they switch to it
+		t.totals = !t.totals
+		t.tree = t.tree || t.totals
+		return nil
+	case "c":
+		t.columnScroll = !t.columnScroll
+		t.columnOffset = 0
+		return nil
+	case "/":
+		t.editFilter()
+		return nil
+	case "esc":
+		// Leaves column scroll mode first, then clears the filter
+		if !t.columnScroll {
+			t.filter = collector.ProcessFilter{}
+		}
+		t.columnScroll = false
+		t.columnOffset = 0
+		return nil
+	case "left", "h":
+		t.columnOffset = max(0, t.columnOffset-1)
+		return nil
+	case "right", "l":
+		t.columnOffset = min(maxColumnOffset(t.app.tableColumns()), t.columnOffset+1)
+		return nil
+	default:
+		return nil
+	}
+	t.selected = max(0, min(t.selected, len(processes)-1))
+	if t.selected < len(processes) {
+		t.pid = processes[t.selected].PID
+	}
+	return nil
+}
+
+// editFilter asks for the filter expression; an empty one shows every
+// process again
+func (t *processTab) editFilter() {
+	prompt := i18n.T("Filter processes, e.g. user:www-data cpu>5 name~nginx.* (empty shows all):")
+	t.app.openDialog(newInputDialog(prompt, t.filter.Source, func(value string) error {
+		_, err := collector.ParseProcessFilter(value)
+		return err
+	}, func(value string) tea.Cmd {
+		t.filter, _ = collector.ParseProcessFilter(value)
+		t.offset = 0
+		return nil
+	}))
+}
+
+// processTableLines is what the processes tab shows besides the table rows:
+// title, stats, column header and the position line
+const processTableLines = 8
+
+func (t *processTab) View(width, height int) string {
+	a := t.app
+	if a.detailPID != 0 {
+		exited := true
+		for _, proc := range a.processes.Processes {
+			if proc.PID == a.detailPID {
+				t.detailProcess, exited = proc, false
+				break
+			}
+		}
+		return t.detailScroll.View(a.renderProcessDetail(t.detailProcess, exited), height)
+	}
+	if a.namespaceView {
+		return t.namespaceScroll.View(a.renderNamespaces(), height)
+	}
+	processes, depths, pinned := t.list()
+
+	// Follow the selected process to wherever the refresh sorted it; if it
+	// exited the selection stays at its row
+	for i, proc := range processes {
+		if proc.PID == t.pid {
+			t.selected = i
+			break
+		}
+	}
+	t.selected = max(0, min(t.selected, len(processes)-1))
+
+	// Reserve room for the crash loop and short-lived process sections
+	// below the table
+	crashLoops := a.processes.CrashLoops[:min(maxCrashLoopRows, len(a.processes.CrashLoops))]
+	shortLived := a.execs.ShortLived[:min(maxShortLivedRows, len(a.execs.ShortLived))]
+	visibleRows := height - processTableLines
+	if len(crashLoops) > 0 {
+		visibleRows -= len(crashLoops) + 2
+	}
+	if len(shortLived) > 0 {
+		visibleRows -= len(shortLived) + 2
+	}
+	if !t.filter.Empty() {
+		visibleRows--
+	}
+	hidden, restricted := a.locked[models.FeatureAllProcesses]
+	if restricted {
+		visibleRows--
+	}
+	visibleRows = max(1, visibleRows)
+	t.rows = visibleRows
+
+	// Pinned rows stay put above a rule, the rest scrolls beneath them
+	if pinned > 0 {
+		visibleRows = max(1, visibleRows-pinned-1)
+	}
+
+	// Move the window only as far as needed to keep the selection in view
+	if selected := t.selected - pinned; selected >= 0 {
+		if selected < t.offset {
+			t.offset = selected
+		}
+		if selected >= t.offset+visibleRows {
+			t.offset = selected - visibleRows + 1
+		}
+	}
+	t.offset = max(0, min(t.offset, len(processes)-pinned-visibleRows))
+	startIdx := pinned + t.offset
+	endIdx := min(startIdx+visibleRows, len(processes))
+
+	var content strings.Builder
+
+	// Header section
+	content.WriteString(HeaderStyle.Render(i18n.T("Process List")))
+	content.WriteString("\n\n")
+
+	// Stats
+	order := "↓"
+	if !a.processSortDesc {
+		order = "↑"
+	}
+	stats := i18n.Sprintf("Total: %d | Running: %d | Sleeping: %d | Zombie: %d | %s | Sort: %s %s",
+		a.processes.Total, a.processes.Running, a.processes.Sleeping, a.processes.Zombie, a.cpuModeInfo(),
+		a.processSortBy, order)
+	if a.accurateMemory {
+		stats += " | " + WarningStyle.Render(i18n.T("PSS/USS: slower refresh (M)"))
+	}
+	content.WriteString(stats)
+	content.WriteString("\n")
+
+	execActivity := i18n.Sprintf("Exec activity: %.1f forks/s", a.execs.ForkRate)
+	if a.execs.Source == "proc connector" {
+		execActivity += i18n.Sprintf(" | %.1f execs/s | %d short-lived recently", a.execs.ExecRate, len(a.execs.ShortLived))
+	} else if privilege, ok := a.locked[models.FeatureExecEvents]; ok {
+		// Kept on one line, the table height is fixed
+		execActivity += " | " + WarningStyle.Render(i18n.Sprintf("🔒 execs: %s", i18n.T(privilege.Hint)))
+	}
+	if len(a.processes.CrashLoops) > 0 {
+		execActivity += " | " + ErrorStyle.Render(i18n.Sprintf("↻ %d crash looping", len(a.processes.CrashLoops)))
+	}
+	content.WriteString(execActivity)
+	content.WriteString("\n")
+	if restricted {
+		// Says why the list is short, which otherwise looks like a bug
+		banner := i18n.Sprintf("🔒 Listing only the %d processes you may see. %s; %s", len(a.processes.Processes), i18n.T(hidden.Description), i18n.T(hidden.Hint))
+		content.WriteString(WarningStyle.Render(truncateString(banner, a.layout.Content)))
+		content.WriteString("\n")
+	}
+	if !t.filter.Empty() {
+		content.WriteString(i18n.Sprintf("Filter: %s • %d of %d match • /: edit • Esc: clear",
+			ValueStyle.Render(t.filter.Source), len(processes), len(a.processes.Processes)))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	// Table header with proper styling
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")). // Bright blue
+		PaddingLeft(1).
+		PaddingRight(1)
+
+	// Less the box and the row padding
+	tableWidth := a.layout.Content - 2
+	allColumns := a.tableColumns()
+	columns := scrolledColumns(allColumns, t.columnOffset)
+	header := formatColumns(columns, tableWidth, func(column processColumn) string {
+		// Σ marks the columns summed over subtrees
+		if t.tree && t.totals && (column.Key == "cpu_percent" || column.Key == "memory_percent" ||
+			column.Key == "pss_kb" || column.Key == "uss_kb") {
+			return column.Title + "Σ"
+		}
+		return column.Title
+	})
+	content.WriteString(headerStyle.Render(header))
+	content.WriteString("\n")
+
+	// Process rows with proper alignment, the pinned ones first
+	rows := make([]int, 0, pinned+endIdx-startIdx)
+	for i := range pinned {
+		rows = append(rows, i)
+	}
+	for i := startIdx; i < endIdx; i++ {
+		rows = append(rows, i)
+	}
+	for n, i := range rows {
+		if pinned > 0 && n == pinned {
+			content.WriteString(" " + lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(strings.Repeat("─", max(0, tableWidth))) + "\n")
+		}
+		proc := processes[i]
+		shown := proc
+		shown.CPUPercent = a.shownCPU(proc.CPUPercent)
+		if proc.Restarts > 0 {
+			shown.Name = fmt.Sprintf("↻%d %s", proc.Restarts, shown.Name)
+		}
+		if depths != nil {
+			shown.Name = treePrefix(depths[i]) + shown.Name
+		}
+		row := formatColumns(columns, tableWidth, func(column processColumn) string { return column.Text(shown) })
+
+		// Style the row
+		rowStyle := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+
+		// Heavy processes keep their color whatever the sort column, and
+		// tagged ones take the color of their tag otherwise
+		heavy, isHeavy := a.processHighlight(proc)
+		if !isHeavy {
+			heavy, isHeavy = a.tagColor(proc)
+		}
+
+		// Highlight selected row
+		if i == t.selected {
+			rowStyle = rowStyle.
+				Background(lipgloss.Color("240")). // Light gray background
+				Foreground(lipgloss.Color("15")).  // White text
+				Bold(true)
+			if isHeavy {
+				rowStyle = rowStyle.Foreground(heavy)
+			}
+		} else if isHeavy {
+			rowStyle = rowStyle.Foreground(heavy)
+		} else {
+			// Alternate row colors for better readability
+			if n%2 == 0 {
+				rowStyle = rowStyle.Foreground(lipgloss.Color("252")) // Light gray text
+			} else {
+				rowStyle = rowStyle.Foreground(lipgloss.Color("245")) // Slightly darker gray text
+			}
+		}
+
+		content.WriteString(rowStyle.Render(row))
+		content.WriteString("\n")
+	}
+
+	// Add some spacing and scroll indicator
+	if len(processes)-pinned > visibleRows || t.columnScroll {
+		content.WriteString("\n")
+		scrollInfo := fmt.Sprintf("Showing %d-%d of %d processes • ↑↓ j/k: select • PgUp/PgDn: page • Home/End: first/last • Enter: details • s/r: sort/reverse • x/X: signal • n: nice • p: pin • /: filter • O: columns • T/A: tree/subtree totals • N: namespaces • e/E: export CSV/JSON • y/Y: copy PID/command",
+			startIdx+1, endIdx, len(processes))
+		if t.columnScroll {
+			// Narrow terminals are what the mode is for, so its hint comes first
+			scrollInfo = fmt.Sprintf("Columns %d-%d of %d • ←/→ h/l: scroll • c/Esc: done • showing %d-%d of %d",
+				t.columnOffset+2, len(allColumns), len(allColumns), startIdx+1, endIdx, len(processes))
+		} else if a.layout.Narrow() {
+			// The hint would be cut off at the end of the line
+			scrollInfo = fmt.Sprintf("Showing %d-%d of %d • c: scroll columns • ↑↓ j/k: select", startIdx+1, endIdx, len(processes))
+		}
+		scrollStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Italic(true).
+			PaddingLeft(1)
+		content.WriteString(scrollStyle.Render(scrollInfo))
+	}
+
+	// Commands that keep failing, the running instance is usually gone
+	if len(crashLoops) > 0 {
+		content.WriteString("\n\n")
+		content.WriteString(HeaderStyle.Render(i18n.T("Crash Loops")))
+		for _, loop := range crashLoops {
+			exit := i18n.Sprintf("exit %d", loop.ExitCode)
+			if loop.Signal > 0 {
+				exit = i18n.Sprintf("signal %d", loop.Signal)
+			}
+			content.WriteString("\n")
+			content.WriteString(ErrorStyle.Render(fmt.Sprintf(" ↻%-3d %s %-10s %-16s %s",
+				loop.Restarts, loop.LastExit.Format("15:04:05"), exit, truncateString(loop.Name, 16),
+				truncateString(loop.Command, max(10, a.layout.Content-48)))))
+		}
+	}
+
+	// Processes that started and exited between ticks
+	if len(shortLived) > 0 {
+		content.WriteString("\n\n")
+		content.WriteString(HeaderStyle.Render(i18n.T("Recent Short-Lived Processes")))
+		for _, proc := range shortLived {
+			command := proc.Command
+			if command == "" {
+				command = proc.Name
+			}
+			content.WriteString("\n")
+			content.WriteString(fmt.Sprintf(" %s %-8d %-8s %-8s %s",
+				proc.ExitedAt.Format("15:04:05"), proc.PID, formatDuration(proc.Lifetime),
+				i18n.Sprintf("exit %d", proc.ExitCode), truncateString(command, max(10, a.layout.Content-42))))
+		}
+	}
+
+	return content.String()
+}
+
+// Prefixes marking the lines of a scrolled tab that stay pinned at the top
+// once scrolled past. scrollView removes them before rendering.
+const (
+	sectionMark = "\x1e"
+	columnsMark = "\x1f"
+)
+
+// sectionHeader renders the title of a section. Scrolled into the section,
+// the title stays pinned at the top of the tab.
+func sectionHeader(title string) string {
+	return sectionMark + HeaderStyle.Render(title)
+}
+
+// columnHeader marks the column header line of a table, pinned below its
+// section title while the rows scroll
+func columnHeader(line string) string {
+	return columnsMark + line
+}
+
+// scrollView shows the part of a content taller than the content area that
+// the user scrolled to
+type scrollView struct {
+	offset int
+	// Size of the last render, to clamp scrolling
+	lines  int
+	height int
+	// Rows taken by a pinned column header, which the content scrolls under
+	pinned int
+	// Keep the end in view as the content grows, e.g. a followed log
+	follow bool
+}
+
+// Update handles the scrolling keys and reports whether msg was one
+func (s *scrollView) Update(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "up", "k":
+		s.offset--
+	case "down", "j":
+		s.offset++
+	case "pgup", "ctrl+u":
+		// Page by half the available height
+		s.offset -= max(1, s.height/2)
+	case "pgdown", "ctrl+d":
+		s.offset += max(1, s.height/2)
+	case "home", "ctrl+home":
+		s.offset = 0
+	case "end", "ctrl+end":
+		s.ScrollToEnd()
+	default:
+		return false
+	}
+	s.clamp()
+	return true
+}
+
+// ScrollToEnd scrolls to the bottom of the content
+func (s *scrollView) ScrollToEnd() {
+	s.offset = s.maxOffset()
+}
+
+func (s *scrollView) maxOffset() int {
+	return max(0, s.lines-(s.height-s.pinned))
+}
+
+func (s *scrollView) clamp() {
+	s.offset = max(0, min(s.offset, s.maxOffset()))
+}
+
+// View returns the visible lines of content with indicators for the parts
+// scrolled out of view. The title of the section scrolled into replaces the
+// indicator above, and its table's column header stays below it.
+func (s *scrollView) View(content string, height int) string {
+	lines := strings.Split(content, "\n")
+	var sections, columns []int
+	for i, line := range lines {
+		if strings.Contains(line, sectionMark) {
+			sections = append(sections, i)
+		}
+		if strings.Contains(line, columnsMark) {
+			columns = append(columns, i)
+		}
+		lines[i] = strings.NewReplacer(sectionMark, "", columnsMark, "").Replace(line)
+	}
+	s.lines = len(lines)
+	s.height = height
+
+	// If content fits entirely, return as-is
+	if len(lines) <= height {
+		s.offset, s.pinned = 0, 0
+		return strings.Join(lines, "\n")
+	}
+
+	// Pinning the column header takes a row, which moves the end
+	title, table := -1, -1
+	for range 2 {
+		if s.follow {
+			s.ScrollToEnd()
+		}
+		s.clamp()
+		title, table = pinnedHeaders(sections, columns, s.offset)
+		s.pinned = 0
+		if table >= 0 {
+			s.pinned = 1
+		}
+	}
+
+	var result []string
+	indicator := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("12")).
+		Bold(true)
+	if s.offset > 0 {
+		if title >= 0 {
+			// Without the padding of lines joined to the widest one
+			result = append(result, indicator.Render("▲ ")+strings.TrimRight(lines[title], " "))
+		} else {
+			result = append(result, indicator.Render(i18n.T("▲ More content above")))
+		}
+	}
+	if table >= 0 {
+		result = append(result, lines[table])
+	}
+	result = append(result, lines[s.offset:min(s.offset+height-s.pinned, len(lines))]...)
+	if s.offset < s.maxOffset() {
+		result = append(result, indicator.Render(i18n.T("▼ More content below")))
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// pinnedHeaders returns the line of the last section title scrolled past
+// and of the column header of its table, if that was scrolled past as well,
+// or -1
+func pinnedHeaders(sections, columns []int, offset int) (int, int) {
+	title, table := -1, -1
+	for _, i := range sections {
+		if i < offset {
+			title = i
+		}
+	}
+	for _, i := range columns {
+		if i > title && i < offset {
+			table = i
+		}
+	}
+	return title, table
+}
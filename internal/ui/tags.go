@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/config"
+	"github.com/prabalesh/croptop/internal/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// processTag is a compiled tag of the config. Unset patterns are nil and
+// match anything.
+type processTag struct {
+	label   string
+	color   lipgloss.Color
+	name    *regexp.Regexp
+	user    *regexp.Regexp
+	cgroup  *regexp.Regexp
+	command *regexp.Regexp
+}
+
+// compileTags compiles the patterns of the configured tags
+func compileTags(tags []config.Tag) ([]processTag, error) {
+	compiled := make([]processTag, 0, len(tags))
+	for i, tag := range tags {
+		if tag.Label == "" {
+			return nil, fmt.Errorf("tag %d: missing label", i+1)
+		}
+		if tag.Name == "" && tag.User == "" && tag.Cgroup == "" && tag.Command == "" {
+			return nil, fmt.Errorf("tag %q: set at least one of name, user, cgroup or command", tag.Label)
+		}
+		t := processTag{label: tag.Label, color: lipgloss.Color(tag.Color)}
+		for _, pattern := range []struct {
+			field  string
+			source string
+			regexp **regexp.Regexp
+		}{
+			{"name", tag.Name, &t.name},
+			{"user", tag.User, &t.user},
+			{"cgroup", tag.Cgroup, &t.cgroup},
+			{"command", tag.Command, &t.command},
+		} {
+			if pattern.source == "" {
+				continue
+			}
+			re, err := regexp.Compile(pattern.source)
+			if err != nil {
+				return nil, fmt.Errorf("tag %q: %s: %w", tag.Label, pattern.field, err)
+			}
+			*pattern.regexp = re
+		}
+		compiled = append(compiled, t)
+	}
+	return compiled, nil
+}
+
+func (t processTag) matches(proc models.Process) bool {
+	match := func(re *regexp.Regexp, value string) bool {
+		return re == nil || re.MatchString(value)
+	}
+	// The user pattern may name the user or its UID
+	user := t.user == nil || t.user.MatchString(proc.User) || t.user.MatchString(collector.LookupUsername(proc.User))
+	return user && match(t.name, proc.Name) && match(t.cgroup, proc.Cgroup) && match(t.command, proc.Command)
+}
+
+// tagsNeedCgroup reports whether a tag matches on cgroups, which are only
+// read for the process list then
+func tagsNeedCgroup(tags []processTag) bool {
+	for _, tag := range tags {
+		if tag.cgroup != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// tagProcesses sets the tag of each process to the first tag matching it
+func tagProcesses(tags []processTag, processes []models.Process) {
+	for i := range processes {
+		for _, tag := range tags {
+			if tag.matches(processes[i]) {
+				processes[i].Tag = tag.label
+				break
+			}
+		}
+	}
+}
+
+// tagColor returns the color of the tag of proc, if it has one with a color
+func (a *App) tagColor(proc models.Process) (lipgloss.TerminalColor, bool) {
+	if proc.Tag == "" {
+		return nil, false
+	}
+	for _, tag := range a.tags {
+		if tag.label == proc.Tag {
+			return tag.color, tag.color != ""
+		}
+	}
+	return nil, false
+}
+
+// tagColumn follows NAME when tags are configured
+var tagColumn = processColumn{
+	Key: "tag", Title: "TAG", Width: 10,
+	Text:  func(p models.Process) string { return p.Tag },
+	Value: func(p models.Process) any { return p.Tag },
+}
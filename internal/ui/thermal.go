@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/i18n"
+	"github.com/prabalesh/croptop/internal/models"
+)
+
+// thermalWarnMargin is how close to its critical trip point a zone gets
+// before it is flagged, in °C
+const thermalWarnMargin = 10
+
+// thermalState tells how close a zone is to throttling or shutting down
+func thermalState(zone models.ThermalZone) (string, lipgloss.Style, bool) {
+	if critical, ok := zone.Trip("critical"); ok && zone.Temp >= critical-thermalWarnMargin {
+		return i18n.Sprintf("%.0f°C below critical", critical-zone.Temp), ErrorStyle, true
+	}
+	if passive, ok := zone.Trip("passive"); ok && zone.Temp >= passive {
+		return i18n.T("throttling"), WarningStyle, true
+	}
+	return "", ValueStyle, false
+}
+
+// renderThermalZones lists the thermal zones of the CPU tab with their trip
+// points, flagging those close to throttling or a critical shutdown
+func (a *App) renderThermalZones() []string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	content := []string{
+		sectionHeader(i18n.T("Thermal Zones")),
+		columnHeader(headerStyle.Render(fmt.Sprintf("%-16s %-20s %8s %8s %8s  %s", "ZONE", "TYPE", "TEMP", "PASSIVE", "CRITICAL", "STATE"))),
+	}
+	trip := func(zone models.ThermalZone, kind string) string {
+		if temp, ok := zone.Trip(kind); ok {
+			return fmt.Sprintf("%.0f°C", temp)
+		}
+		return "-"
+	}
+
+	warnings := 0
+	for _, zone := range a.thermal {
+		state, style, warn := thermalState(zone)
+		if warn {
+			warnings++
+		}
+		content = append(content, style.Render(fmt.Sprintf("%-16s %-20s %7.1f°C %8s %8s  %s",
+			zone.Name, truncateString(zone.Type, 20), zone.Temp,
+			trip(zone, "passive"), trip(zone, "critical"), state)))
+	}
+	if warnings > 0 {
+		content = append(content, ErrorStyle.Render(i18n.Sprintf("⚠ %d zone(s) throttling or near their critical temperature, where the machine shuts down", warnings)))
+	}
+	return content
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// toastDuration is how long a toast stays up
+	toastDuration = 4 * time.Second
+	// maxToasts stack up in the corner, older ones are dropped
+	maxToasts = 3
+	// maxToastWidth keeps a long path from covering the whole view
+	maxToastWidth = 60
+)
+
+// toastKind picks the color of a toast
+type toastKind int
+
+const (
+	toastInfo toastKind = iota
+	toastSuccess
+	toastWarning
+	toastError
+)
+
+// toast is a message in the bottom right corner that dismisses itself.
+// Toasts report the result of something the user did; notices of the
+// alert bar report what happened on their own (config reloads, resume).
+type toast struct {
+	text  string
+	kind  toastKind
+	until time.Time
+}
+
+// toast shows text in a toast, after the ones already up
+func (a *App) toast(kind toastKind, text string) {
+	a.toasts = append(a.toasts, toast{text: text, kind: kind, until: time.Now().Add(toastDuration)})
+	if len(a.toasts) > maxToasts {
+		a.toasts = a.toasts[len(a.toasts)-maxToasts:]
+	}
+}
+
+// renderToasts draws the toasts that are still up over the bottom right
+// corner of view, above the help line
+func (a *App) renderToasts(view string) string {
+	now := time.Now()
+	active := a.toasts[:0]
+	for _, t := range a.toasts {
+		if now.Before(t.until) {
+			active = append(active, t)
+		}
+	}
+	a.toasts = active
+	if len(active) == 0 {
+		return view
+	}
+
+	var boxes []string
+	for _, t := range active {
+		color := lipgloss.Color("12")
+		switch t.kind {
+		case toastSuccess:
+			color = lipgloss.Color("46")
+		case toastWarning:
+			color = lipgloss.Color("226")
+		case toastError:
+			color = lipgloss.Color("196")
+		}
+		// Border and padding take 4 columns
+		width := max(minContent, min(maxToastWidth, a.layout.Width/2)-4)
+		boxes = append(boxes, lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Foreground(color).
+			Padding(0, 1).
+			Render(ansi.Truncate(t.text, width, "…")))
+	}
+	// Stacked upwards from above the help line and the blank line before it,
+	// each box right aligned on its own
+	y := lipgloss.Height(view) - 2
+	for i := len(boxes) - 1; i >= 0; i-- {
+		y -= lipgloss.Height(boxes[i])
+		view = overlay(view, boxes[i], a.layout.Width-lipgloss.Width(boxes[i])-1, y)
+	}
+	return view
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/prabalesh/croptop/internal/i18n"
+)
+
+// renderTalkers ranks the remote endpoints by the bandwidth of the captured
+// packets, iftop-like, when -capture-traffic asked for it
+func (a *App) renderTalkers() []string {
+	if !a.captureTraffic {
+		return nil
+	}
+	content := []string{sectionHeader(i18n.T("Top Talkers"))}
+	if a.captureErr != nil {
+		return append(content,
+			ErrorStyle.Render(i18n.Sprintf("Capture unavailable: %v", a.captureErr)),
+			i18n.T("Run with sudo or grant CAP_NET_RAW (croptop grant-caps -capture)"), "")
+	}
+	talkers := a.stats.Network.Talkers
+	if len(talkers) == 0 {
+		return append(content, i18n.T("No traffic captured"), "")
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	content = append(content, columnHeader(headerStyle.Render(fmt.Sprintf("%-47s %-6s %12s %12s", "REMOTE", "PROTO", "RX/s", "TX/s"))))
+	for _, talker := range talkers {
+		remote := talker.Host
+		if talker.Port != 0 {
+			remote = net.JoinHostPort(talker.Host, strconv.Itoa(talker.Port))
+		}
+		content = append(content, fmt.Sprintf("%-47s %-6s %12s %12s", truncateString(remote, 47), talker.Protocol,
+			formatBytes(talker.RxRate), formatBytes(talker.TxRate)))
+	}
+	return append(content, "")
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/prabalesh/croptop/internal/alert"
+	"github.com/prabalesh/croptop/internal/i18n"
+)
+
+// renderWatches is the footer strip of the configured watch expressions,
+// evaluated against the latest sample. It is empty without watches.
+func (a *App) renderWatches() string {
+	if len(a.watches) == 0 {
+		return ""
+	}
+	parts := make([]string, len(a.watches))
+	for i, watch := range a.watches {
+		value := i18n.T("n/a")
+		if v, ok := watch.Expr.Eval(a.stats, a.processes); ok {
+			value = formatWatchValue(v, watch.Expr.Unit)
+		}
+		parts[i] = LabelStyle.Render(watch.Label) + " " + ValueStyle.Render(value)
+	}
+	return ansi.Truncate(strings.Join(parts, " • "), a.layout.Width, "…")
+}
+
+// formatWatchValue renders a value in its unit
+func formatWatchValue(value float64, unit string) string {
+	switch unit {
+	case alert.UnitBytes:
+		return formatBytes(value)
+	case alert.UnitBytesRate:
+		return formatBytes(value) + "/s"
+	case alert.UnitNone:
+		return fmt.Sprintf("%g", value)
+	}
+	return fmt.Sprintf("%.1f%s", value, unit)
+}
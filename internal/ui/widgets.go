@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Gauge is a gradient progress bar that sizes itself to the terminal
+type Gauge struct {
+	bar      progress.Model
+	maxWidth int
+	margin   int
+}
+
+// NewGauge returns a gauge at most maxWidth wide that leaves margin columns
+// of the content width to the rest of the line
+func NewGauge(maxWidth, margin int) Gauge {
+	return Gauge{
+		bar:      progress.New(progress.WithDefaultGradient()),
+		maxWidth: maxWidth,
+		margin:   margin,
+	}
+}
+
+// Resize fits the gauge to the content width of the Layout
+func (g *Gauge) Resize(contentWidth int) {
+	g.bar.Width = max(minContent, min(g.maxWidth, contentWidth-g.margin))
+}
+
+// Fit returns a copy of the gauge narrowed to at most width columns, for
+// gauges sharing a row with other widgets
+func (g Gauge) Fit(width int) Gauge {
+	g.bar.Width = max(10, min(g.bar.Width, width))
+	return g
+}
+
+// View renders the gauge filled to percent (0-100)
+func (g Gauge) View(percent float64) string {
+	return g.bar.ViewAs(clampPercent(percent) / 100)
+}
+
+// ViewLabeled renders the gauge below a label line such as "CPU: 12.5%"
+func (g Gauge) ViewLabeled(label string, percent float64) string {
+	return lipgloss.JoinVertical(lipgloss.Left, LabelStyle.Render(label), g.View(percent))
+}
+
+// RenderProgressBar renders a plain fixed-width bar, for rows of a table
+// where a gradient gauge would be too busy
+func RenderProgressBar(percent float64, width int) string {
+	if width <= 0 {
+		width = 20
+	}
+
+	filled := int(clampPercent(percent) / 100 * float64(width))
+
+	return ProgressCompleteStyle.Render(strings.Repeat("█", filled)) +
+		ProgressEmptyStyle.Render(strings.Repeat("░", width-filled))
+}
+
+// Segment is one part of a segmented bar
+type Segment struct {
+	Label string
+	Value float64
+	Color lipgloss.Color
+}
+
+// SegmentedBar renders the segments side by side, scaled so that total fills
+// the width, followed by a legend line. Whatever total leaves is drawn empty.
+func SegmentedBar(segments []Segment, total float64, width int) string {
+	if total <= 0 || width <= 0 {
+		return ""
+	}
+
+	var bar, legend strings.Builder
+	used := 0
+	for _, segment := range segments {
+		cells := min(width-used, int(segment.Value/total*float64(width)+0.5))
+		if cells < 0 {
+			cells = 0
+		}
+		style := lipgloss.NewStyle().Foreground(segment.Color)
+		bar.WriteString(style.Render(strings.Repeat("█", cells)))
+		used += cells
+
+		if legend.Len() > 0 {
+			legend.WriteString("  ")
+		}
+		legend.WriteString(style.Render("■") + " " + segment.Label)
+	}
+	bar.WriteString(ProgressEmptyStyle.Render(strings.Repeat("░", width-used)))
+
+	return bar.String() + "\n" + legend.String()
+}
+
+var ledOffStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+
+// LEDMeter renders percent as a row of lights that turn from green to yellow
+// to red along the row, the way hardware level meters do
+func LEDMeter(percent float64, leds int) string {
+	lit := int(clampPercent(percent)/100*float64(leds) + 0.5)
+
+	var meter strings.Builder
+	for i := 0; i < leds; i++ {
+		if i >= lit {
+			meter.WriteString(ledOffStyle.Render("■"))
+			continue
+		}
+		position := float64(i+1) / float64(leds)
+		switch {
+		case position > 0.85:
+			meter.WriteString(ErrorStyle.Render("■"))
+		case position > 0.6:
+			meter.WriteString(WarningStyle.Render("■"))
+		default:
+			meter.WriteString(SuccessStyle.Render("■"))
+		}
+	}
+	return meter.String()
+}
+
+func clampPercent(percent float64) float64 {
+	if percent < 0 {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
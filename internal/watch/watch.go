@@ -0,0 +1,182 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prabalesh/croptop/internal/collector"
+	"github.com/prabalesh/croptop/internal/models"
+	"github.com/prabalesh/croptop/internal/schema"
+)
+
+// clock ticks per second used by /proc/[pid]/stat cpu times
+const clockTicks = 100
+
+type Options struct {
+	Interval time.Duration
+	Output   string // optional path the JSON profile is written to
+}
+
+// Run launches the command, samples its process tree until it exits and
+// returns the collected resource profile
+func Run(command []string, opts Options) (models.WatchProfile, error) {
+	if len(command) == 0 {
+		return models.WatchProfile{}, errors.New("no command given")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	profile := models.WatchProfile{
+		Command:   command,
+		ExitCode:  -1,
+		StartedAt: time.Now(),
+	}
+
+	if err := cmd.Start(); err != nil {
+		return profile, err
+	}
+
+	// The child shares our terminal and receives ^C itself, so croptop only
+	// needs to survive long enough to report
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	stats := collector.NewStatsCollector()
+	stats.GetSystemStats() // prime the cpu usage baseline
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	var lastTicks uint64
+	lastSample := profile.StartedAt
+
+	var waitErr error
+loop:
+	for {
+		select {
+		case waitErr = <-done:
+			break loop
+		case sig := <-signals:
+			if sig == syscall.SIGTERM {
+				cmd.Process.Signal(sig)
+			}
+		case now := <-ticker.C:
+			tree := stats.GetProcessTree(cmd.Process.Pid)
+			if tree.Processes == 0 {
+				continue
+			}
+
+			var cpuPercent float64
+			if elapsed := now.Sub(lastSample).Seconds(); elapsed > 0 && tree.CPUTicks >= lastTicks {
+				cpuPercent = float64(tree.CPUTicks-lastTicks) / clockTicks / elapsed * 100
+			}
+			lastTicks = tree.CPUTicks
+			lastSample = now
+
+			system := stats.GetSystemStats()
+			profile.Samples = append(profile.Samples, models.WatchSample{
+				Elapsed:      now.Sub(profile.StartedAt),
+				CPUPercent:   cpuPercent,
+				MemRSS:       tree.MemRSS,
+				ReadBytes:    tree.ReadBytes,
+				WriteBytes:   tree.WriteBytes,
+				Processes:    tree.Processes,
+				SystemCPU:    system.CPU.Usage,
+				SystemMemory: system.Memory.UsagePercent,
+				Suspended:    system.SuspendedFor,
+			})
+		}
+	}
+
+	profile.Duration = time.Since(profile.StartedAt)
+	summarize(&profile, cmd.ProcessState)
+
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		return profile, waitErr
+	}
+
+	if opts.Output != "" {
+		if err := writeProfile(opts.Output, profile); err != nil {
+			return profile, err
+		}
+	}
+
+	return profile, nil
+}
+
+func summarize(profile *models.WatchProfile, state *os.ProcessState) {
+	var totalCPU float64
+	for _, sample := range profile.Samples {
+		totalCPU += sample.CPUPercent
+		profile.PeakCPUPercent = max(profile.PeakCPUPercent, sample.CPUPercent)
+		profile.PeakRSS = max(profile.PeakRSS, sample.MemRSS)
+		profile.PeakProcesses = max(profile.PeakProcesses, sample.Processes)
+		profile.ReadBytes = max(profile.ReadBytes, sample.ReadBytes)
+		profile.WriteBytes = max(profile.WriteBytes, sample.WriteBytes)
+	}
+	if len(profile.Samples) > 0 {
+		profile.AvgCPUPercent = totalCPU / float64(len(profile.Samples))
+	}
+
+	if state == nil {
+		return
+	}
+
+	profile.ExitCode = state.ExitCode()
+	// Killed by a signal, ExitCode is -1; report 128+signal the way shells do
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		profile.ExitCode = 128 + int(status.Signal())
+	}
+	profile.UserTime = state.UserTime()
+	profile.SystemTime = state.SystemTime()
+
+	// Rusage covers every reaped descendant, so it also catches processes
+	// too short-lived to show up in a sample
+	if rusage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		profile.PeakRSS = max(profile.PeakRSS, uint64(rusage.Maxrss))
+	}
+}
+
+func writeProfile(path string, profile models.WatchProfile) error {
+	data, err := json.MarshalIndent(schema.NewWatchProfile(profile), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// PrintProfile writes a human readable summary of the profile
+func PrintProfile(w io.Writer, profile models.WatchProfile) {
+	fmt.Fprintf(w, "\n── croptop watch ──────────────────────────\n")
+	fmt.Fprintf(w, "Command:        %v\n", profile.Command)
+	fmt.Fprintf(w, "Exit code:      %d\n", profile.ExitCode)
+	fmt.Fprintf(w, "Wall time:      %v\n", profile.Duration.Round(time.Millisecond))
+	fmt.Fprintf(w, "User time:      %v\n", profile.UserTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "System time:    %v\n", profile.SystemTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "CPU avg/peak:   %.1f%% / %.1f%%\n", profile.AvgCPUPercent, profile.PeakCPUPercent)
+	fmt.Fprintf(w, "Peak RSS:       %.1f MB\n", float64(profile.PeakRSS)/1024)
+	fmt.Fprintf(w, "Peak processes: %d\n", profile.PeakProcesses)
+	fmt.Fprintf(w, "Disk read:      %.1f MB\n", float64(profile.ReadBytes)/(1024*1024))
+	fmt.Fprintf(w, "Disk written:   %.1f MB\n", float64(profile.WriteBytes)/(1024*1024))
+	fmt.Fprintf(w, "Samples:        %d\n", len(profile.Samples))
+}